@@ -1,15 +1,24 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/sourceradar/outline/internal/cli"
+	"github.com/sourceradar/outline/internal/config"
+	"github.com/sourceradar/outline/internal/confine"
 	"github.com/sourceradar/outline/internal/detector"
 	"github.com/sourceradar/outline/internal/server"
+	"github.com/sourceradar/outline/pkg/outline"
 )
 
 var (
@@ -21,11 +30,105 @@ var (
 func main() {
 	var mcpMode bool
 	var language string
+	var headerLanguage string
+	var recursive bool
+	var noIgnore bool
+	var excludes []string
+	var configFile string
+	var mappings []string
+	var jobs int
+	var noDocs bool
+	var maxTokens int
+	var header string
+	var relativeTo string
+	var verbose bool
+	var withMetrics bool
+	var stats bool
+	var withTodos bool
+	var merge bool
+	var failFast bool
+	var noPager bool
+	var watchMode bool
+	var events bool
+	var httpAddr string
+	var root string
+	var token string
+	var base string
+	var maxLines int
+	var maxComplexity int
+	var out string
+	var db string
+	var tagsFile string
+	var regex bool
+	var chunkTokens int
+	var detail string
+	var kinds string
+	var positions string
+	var sortOrder string
+	var format string
+	var color string
+	var summarize bool
+	var redact bool
+	var timeout time.Duration
+	var maxMemory uint64
+	var maxFileSize uint64
 	var help bool
 	var showVersion bool
 
 	flag.BoolVar(&mcpMode, "mcp", false, "Run in MCP server mode")
+	flag.StringVar(&httpAddr, "http", "", "Address the serve command, or the MCP server with --mcp, listens on (e.g. :9090, on localhost)")
+	flag.StringVar(&root, "root", "", "Directory the paths of serve and HTTP MCP requests must lie in (default the current directory); stdio MCP requests are only confined when it is given")
+	flag.StringVar(&token, "token", "", "Bearer token clients of the MCP server must send over HTTP")
 	flag.StringVar(&language, "language", "", fmt.Sprintf("Override language detection (%s)", strings.Join(detector.GetLanguageNames(), ", ")))
+	flag.StringVar(&headerLanguage, "header-language", "", "Language used for .h headers (c, cpp, objc); detected from content by default")
+	flag.BoolVar(&recursive, "recursive", false, "Outline every supported file in a directory")
+	flag.BoolVar(&recursive, "r", false, "Outline every supported file in a directory")
+	flag.BoolVar(&noIgnore, "no-ignore", false, "Outline the files .gitignore and .ignore files exclude too")
+	flag.Func("exclude", "Gitignore-style pattern of files left out of directory walks (repeatable)", func(pattern string) error {
+		excludes = append(excludes, pattern)
+		return nil
+	})
+	flag.IntVar(&jobs, "jobs", 0, "Number of files parsed concurrently in directories (default GOMAXPROCS)")
+	flag.IntVar(&jobs, "j", 0, "Shorthand for --jobs")
+	flag.Func("map", "Outline the files of an extension as a language, as in .inc=c (repeatable)", func(mapping string) error {
+		mappings = append(mappings, mapping)
+		return nil
+	})
+	flag.StringVar(&configFile, "config", "", "Configuration file used instead of the .outline.yaml of the project")
+	flag.StringVar(&header, "header", cli.DefaultHeader, "Header above each file of several: {path}, {name} and {dir} are replaced, empty for none")
+	flag.StringVar(&relativeTo, "relative-to", "", "Directory the file paths of output are made relative to")
+	flag.BoolVar(&verbose, "verbose", false, "Report the detected encoding of each file")
+	flag.StringVar(&detail, "detail", "compact", fmt.Sprintf("Level of detail of the outline (%s)", strings.Join(outline.DetailNames(), ", ")))
+	flag.StringVar(&kinds, "kinds", "", "Comma-separated symbol kinds to include, such as func,type,class")
+	flag.StringVar(&positions, "positions", "line", fmt.Sprintf("Line annotations of declarations (%s)", strings.Join(outline.PositionNames(), ", ")))
+	flag.StringVar(&sortOrder, "sort", "line", fmt.Sprintf("Order of the symbols of outlines (%s)", strings.Join(outline.SortNames(), ", ")))
+	flag.StringVar(&format, "format", "text", fmt.Sprintf("Output format (%s)", strings.Join(cli.Formats(), ", ")))
+	flag.StringVar(&color, "color", "auto", fmt.Sprintf("When the tree format is colored (%s)", strings.Join(cli.ColorModes, ", ")))
+	flag.BoolVar(&summarize, "summarize", false, "Collapse long runs of similar members, such as generated getters, into a summary")
+	flag.BoolVar(&redact, "redact", false, "Replace string and number literal values with placeholders")
+	flag.BoolVar(&noDocs, "no-docs", false, "Leave doc comments and docstrings out of outlines")
+	flag.IntVar(&maxTokens, "max-tokens", 0, "Estimated tokens each outline must fit in, dropping docs, private symbols and nesting as needed (0 disables)")
+	flag.BoolVar(&noPager, "no-pager", false, "Don't pipe output longer than the terminal through $PAGER")
+	flag.BoolVar(&failFast, "fail-fast", false, "Stop at the first file that can't be outlined instead of reporting failures at the end")
+	flag.BoolVar(&merge, "merge", false, "With -r, merge declarations of one symbol spread across files")
+	flag.BoolVar(&watchMode, "watch", false, "Outline the file or directory again every time it changes")
+	flag.BoolVar(&events, "events", false, "With --watch, print JSON events describing symbol changes instead of outlines")
+	flag.BoolVar(&stats, "stats", false, "Print the line and symbol counts of each file instead of its outline")
+	flag.BoolVar(&withMetrics, "with-metrics", false, "Append the complexity and size of every function")
+	flag.StringVar(&base, "base", "", "Git revision the github command compares the public API with")
+	flag.IntVar(&maxLines, "max-lines", 80, "Line count over which the github command reports a function (0 disables)")
+	flag.IntVar(&maxComplexity, "max-complexity", 15, "Complexity over which the github command reports a function (0 disables)")
+	flag.StringVar(&out, "out", "", "File the outline is written to instead of stdout, or directory with -r; directory the docs command writes the site to (default site), or file the bundle, cscope and lsif commands write to")
+	flag.StringVar(&out, "output", "", "Same as --out")
+	flag.StringVar(&out, "o", "", "Shorthand for --out")
+	flag.StringVar(&db, "db", "symbols.db", "SQLite symbol index used by the index, query and daemon commands")
+	flag.StringVar(&tagsFile, "tags", "", "Tags file the daemon command maintains instead of the index")
+	flag.BoolVar(&regex, "regex", false, "Match the pattern of the search command as a regular expression")
+	flag.IntVar(&chunkTokens, "chunk-tokens", 512, "Estimated tokens the chunks command aims for in each chunk")
+	flag.BoolVar(&withTodos, "with-todos", false, "Append the TODO, FIXME and HACK markers found in comments")
+	flag.DurationVar(&timeout, "timeout", 0, "Maximum time spent parsing one file; a partial outline is returned when it runs out")
+	flag.Uint64Var(&maxFileSize, "max-file-size", 4, "Largest file in MB directory walks outline; larger ones are skipped (0 disables)")
+	flag.Uint64Var(&maxMemory, "max-memory", 0, "Maximum memory growth in MB while parsing one file")
 	flag.BoolVar(&help, "help", false, "Show help message")
 	flag.BoolVar(&help, "h", false, "Show help message")
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
@@ -36,22 +139,277 @@ func main() {
 		fmt.Fprintf(os.Stderr, `outline - A code analysis tool that generates structured outlines
 
 USAGE:
-    outline [OPTIONS] <file>
+    outline [OPTIONS] <file|url>...
+    outline -r [OPTIONS] <dir>
+    outline apidiff <from> <to> [dir]
+    outline bundle [OPTIONS] <dir> -o <file>
+    outline chunks [--chunk-tokens <n>] [OPTIONS] <file|dir>
+    outline completion bash|zsh|fish|powershell
+    outline cscope [OPTIONS] <dir> [-o <file>]
+    outline daemon [--db <file> | --tags <file>] [OPTIONS] <dir>
+    outline detect <file>...
+    outline doc-coverage [OPTIONS] <file|dir>
+    outline docs [OPTIONS] <dir> --out <dir>
+    outline github [--base <ref>] [OPTIONS] <file|dir>
+    outline gtags [OPTIONS] <dir|->
+    outline index [--db <file>] [OPTIONS] <dir>
+    outline languages
+    outline lsif [OPTIONS] <dir> [-o <file>]
+    outline query [--db <file>] <terms>
+    outline refs [OPTIONS] <name> <file|dir>
+    outline search [--regex] [OPTIONS] <pattern> <file|dir>...
+    outline todos [OPTIONS] <file|dir>
+    outline untested [OPTIONS] <dir>
+    outline view <bundle> [path]
+    outline serve --http <addr>
     outline --mcp
 
+COMMANDS:
+    apidiff             Compare the public API between two git revisions,
+                        classify changes as breaking or additive, and
+                        fail when any is breaking
+    bundle              Write the tree, outlines, symbols and statistics
+                        of a project to one JSON file (default
+                        <dir>.outline.json)
+    chunks              Split code into chunks aligned to symbols, sized
+                        for embedding, as JSON lines
+    completion          Print the completion script of a shell, covering
+                        flags, commands and the values of --language,
+                        --format, --detail and --color
+    cscope              Write the symbol definitions of a project as a
+                        cscope cross-reference (default cscope.out) for
+                        cscope -d
+    daemon              Watch a directory and keep a SQLite index or a
+                        ctags file up to date, re-parsing changed files
+    detect              Print the language a file is detected as, from its
+                        name, modeline or #! line, and nothing else
+    doc-coverage        Report which exported symbols have doc comments
+                        and the comment-to-code ratio of each file
+    docs                Generate linked Markdown and HTML pages, one per
+                        package, from exported symbols and doc comments
+    github              Report parse failures, oversized functions and,
+                        with --base, public API changes as GitHub Actions
+                        annotations and a job summary
+    gtags               Print definitions and references as GNU Global
+                        tag records; given -, act as the ctags parser
+                        gtags runs, reading file paths from stdin
+    index               Write every symbol to a SQLite index with full-text
+                        search, re-parsing only files that changed
+    languages           List the supported languages with their
+                        extensions and whether their symbols have docs,
+                        nesting and visibility
+    lsif                Write the symbol definitions of a project as an
+                        LSIF dump (default dump.lsif) for code
+                        intelligence tools such as Sourcegraph
+    query               Search the index by name, signature or doc comment
+                        (SQLite FTS syntax: word*, OR, "phrases")
+    refs                List the identifiers spelled like a name, grouped
+                        by the symbol they are in, skipping comments and
+                        strings
+    search              List the symbols whose name contains a pattern,
+                        ignoring case, or matches it with --regex, as
+                        file:line and signature
+    todos               List TODO, FIXME, HACK, XXX and BUG markers with
+                        their author and enclosing symbol
+    untested            Link tests (Go TestX, pytest test_, JUnit @Test,
+                        XCTest testX) to the functions they mention and
+                        list the exported ones no test mentions
+    view                Show the summary and tree of a bundle, or the
+                        outlines of a file or directory in it
+    serve               Serve a JSON HTTP API: POST /outline (path or
+                        content), GET /languages, POST /project-map
+
 OPTIONS:
     --language <lang>   Override language detection
                         Supported: %s
+    --header-language <lang>
+                        Language used for .h headers instead of detecting
+                        C, C++ or Objective-C from their content
+    --recursive, -r     Outline every supported file in a directory
+    --no-ignore         Walk the files .gitignore and .ignore files
+                        exclude too
+    --exclude <pattern> Leave the files matching a gitignore-style
+                        pattern out of directory walks (repeatable)
+    --jobs, -j <n>      Number of files parsed concurrently in directories
+                        (default: the number of CPUs)
+    --header <template> Line above each file when outlining several, where
+                        {path}, {name} and {dir} are replaced (default
+                        "File: {path}"; empty for none)
+    --relative-to <dir> Write file paths relative to dir, so that output
+                        doesn't depend on where outline is run
+    --map <.ext=lang>   Outline the files of an extension as a language,
+                        such as --map .inc=c (repeatable)
+    --verbose           Report the encoding each file was read in (UTF-8,
+                        UTF-16 and Latin-1 are transcoded automatically)
+    --merge             With -r, show one entry per symbol with every file
+                        it is declared in: C prototypes and definitions,
+                        and Go methods across the files of a package
+    --no-pager          Write output longer than the terminal to it
+                        directly instead of through $PAGER
+    --fail-fast         Stop at the first file that can't be outlined
+                        instead of listing the failures at the end
+    --detail <level>    How much of each symbol to show:
+                          signatures  one line per exported symbol
+                          compact     pseudo-source with bodies elided
+                                      (default)
+                          full        every symbol, including private
+                                      members and fields, with docs
+    --positions <mode>  How declarations are annotated with their lines:
+                        line (// line 12, the default), range
+                        (// lines 12-30) or none
+    --kinds <kinds>     Only show symbols of these kinds, comma-separated,
+                        and the types enclosing them (func, type, class,
+                        method, interface, field, ...)
+    --sort <order>      Order of symbols and their members: line (the
+                        declaration order, default), name or kind
+    --format <format>   How to write each file:
+                          text      the outline (default)
+                          csv, tsv  one row per symbol: file, kind, name,
+                                    signature, line, end line, public
+                          dot       a GraphViz graph of packages, types
+                                    and methods, with inheritance edges
+                          flat      one path:line: kind name signature
+                                    line per symbol, for grep and fzf
+                          json      the symbol tree, with kinds,
+                                    signatures, docs, positions and
+                                    visibility
+                          jsonl     one JSON object per symbol and line,
+                                    streamed as each file is outlined
+                          markdown  headings, code-fenced signatures
+                                    and docs, for docs and PRs
+                          mermaid   a class diagram of the types, their
+                                    members and their inheritance
+                          plantuml  the same diagram in PlantUML, with
+                                    the full signature of every member
+                          tree      the symbols drawn as a tree, with
+                                    colored kinds and dimmed docs
+    --color <when>      Color the tree format: auto (when the output is a
+                        terminal and NO_COLOR is unset), always or never
+    --summarize         Collapse long runs of similar members (getters,
+                        setters, enum cases, tests) into one comment
+    --redact            Replace the values of string and number literals
+                        (constants, default parameters) with ***
+    --no-docs           Leave doc comments and docstrings out of outlines
+    --max-tokens <n>    Fit each outline in about n tokens, leaving out
+                        docs, then private symbols and fields, then nested
+                        members, and cutting what is still too long
+    --watch             Outline again whenever the file, or a file of the
+                        directory, changes, until interrupted
+    --events            With --watch, print newline-delimited JSON events
+                        (ready, symbols_changed, error) instead of outlines
+    --stats             Print the lines (code, comments, blank) and the
+                        symbols (by kind, public and private) of each
+                        file instead of its outline, with totals for
+                        directories and several files
+    --with-metrics      Append the cyclomatic complexity and line count of
+                        every function
+    --with-todos        Append the TODO-style markers found in comments
+    --output, --out, -o <path>
+                        File the outline is written to instead of stdout,
+                        replaced only once complete; with -r, directory
+                        getting one file per source file. Directory the
+                        docs command writes the site to (default site),
+                        or file the bundle, cscope and lsif commands
+                        write to
+    --chunk-tokens <n>  Estimated tokens per chunk of the chunks command
+                        (default 512)
+    --db <file>         Symbol index of the index, query and daemon
+                        commands (default symbols.db)
+    --tags <file>       Tags file the daemon command maintains instead of
+                        the index
+    --base <ref>        Git revision the github command compares the
+                        public API with
+    --max-lines <n>     Function length the github command warns about
+                        beyond (default 80, 0 disables)
+    --max-complexity <n>
+                        Cyclomatic complexity the github command warns
+                        about beyond (default 15, 0 disables)
+    --timeout <dur>     Stop parsing a file after this long (e.g. 2s) and
+                        return a partial outline
+    --max-file-size <MB>
+                        Skip the files of directories larger than this
+                        without reading them (default 4, 0 disables)
+    --max-memory <MB>   Stop parsing a file once memory grew by this much
+                        and return a partial outline
+    --config <file>     Configuration file read instead of the
+                        .outline.yaml of the project
     --mcp               Run in MCP (Model Context Protocol) server mode
+    --http <addr>       Address the serve command listens on, or with --mcp
+                        the MCP server, over Streamable HTTP and SSE; :port
+                        listens on localhost, 0.0.0.0:port on every interface
+    --root <dir>        Directory the paths of serve and HTTP MCP requests
+                        must lie in (default the current directory); stdio
+                        MCP requests are only confined when it is given
+    --token <token>     Bearer token clients of the MCP server must send
+                        over HTTP
     --version, -v       Show version information
     --help, -h          Show this help message
 
 EXAMPLES:
     outline main.go                      # Analyze a Go file
+    outline a.go b.py c.ts               # Analyze several files
+    outline https://raw.githubusercontent.com/owner/repo/main/main.go
+                                         # Analyze a file on the web
     outline --language go script.txt     # Force Go parsing
+    outline detect scripts/deploy        # Language of a file, for scripts
+    outline -r ./src                     # Outline a whole directory
+    outline -r --merge ./src             # Merge declarations across files
+    outline --watch ./src                # Live project map in a terminal
+    outline -r --watch --events ./src    # Stream symbol changes as JSON
+    outline --detail signatures main.go  # One line per exported symbol
+    outline --format json main.go        # Symbol tree as JSON
+    outline --format markdown -r ./api   # Outline to paste into docs
+    outline --format mermaid -r ./model  # Class diagram of a package
+    outline --format csv -r . > symbols.csv
+                                         # Symbol table for a spreadsheet
+    outline --format dot -r . | dot -Tsvg > map.svg
+                                         # Render the project structure
+    outline --kinds func,type ./main.go  # Only functions and types
+    outline --positions none main.go     # Outline without line numbers
+    outline --summarize Generated.java   # Elide repetitive members
+    outline --redact config.py           # Hide literal values
+    outline --with-metrics main.go       # Include function complexity
+    outline --stats -r ./src             # Line and symbol counts
+    outline --max-tokens 2000 main.go    # Outline sized for an LLM prompt
+    outline apidiff v1.2.0 HEAD          # Breaking changes since a release
+    outline bundle . -o snapshot.outline.json
+                                         # Snapshot the project structure
+    outline view snapshot.outline.json   # Browse a snapshot offline
+    outline chunks ./src > chunks.jsonl  # Chunks for an embedding index
+    outline doc-coverage ./src           # Documentation coverage report
+    outline docs ./src --out site        # Generate a documentation site
+    outline todos ./src                  # List TODO and FIXME comments
+    outline index --db symbols.db ./src  # Index every symbol in SQLite
+    outline query --db symbols.db parse* # Search the index
+    outline lsif . -o dump.lsif          # Index for code intelligence
+    outline cscope .                     # Cross-reference for cscope -d
+    outline gtags ./src                  # Tag records for GNU Global
+    outline daemon --tags tags .         # Keep a tags file up to date
+    outline refs ParseConfig ./src       # Where a name is used
+    outline search --regex '^New' ./src  # Find symbols by name
+    outline github --base origin/main .  # Annotate a pull request
+    outline untested .                   # Functions no test mentions
+    outline serve --http :9090           # Serve the HTTP API
+    source <(outline completion bash)    # Enable tab completion
     outline --mcp                        # Run as MCP server
+    outline --mcp --timeout 5s           # Bound the work of each request
+    outline --mcp --http :8080           # Serve MCP to remote clients
     outline --version                    # Show version
 
+EXIT STATUS:
+    0   every file was outlined
+    1   error, such as a missing or unsupported file given alone
+    2   invalid flags
+    3   some of several files could not be outlined
+    4   none of the files could be outlined
+    5   no supported file was found to outline
+
+CONFIGURATION:
+    Defaults for format, detail, color, docs, summarize, redact, exclude,
+    extension mappings and per-language settings are read from
+    ~/.outline.yaml and from the nearest .outline.yaml of the project,
+    which overrides it. Flags given on the command line override both.
+
 For MCP server mode, add to your MCP client configuration:
 {
   "mcpServers": {
@@ -64,7 +422,14 @@ For MCP server mode, add to your MCP client configuration:
 `, supportedLangs)
 	}
 
-	flag.Parse()
+	// Commands take the place of the file argument, with their own flags
+	// following them
+	args := os.Args[1:]
+	var command string
+	if len(args) > 0 && cli.IsCommand(args[0]) {
+		command, args = args[0], args[1:]
+	}
+	positional := parseInterleaved(flag.CommandLine, args)
 
 	if help {
 		flag.Usage()
@@ -78,14 +443,266 @@ For MCP server mode, add to your MCP client configuration:
 		return
 	}
 
+	// Configuration files set the defaults of the flags not given
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	cfg, err := loadConfig(configFile, positional)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !set["format"] && cfg.Format != "" {
+		format = cfg.Format
+	}
+	if !set["detail"] && cfg.Detail != "" {
+		detail = cfg.Detail
+	}
+	if !set["color"] && cfg.Color != "" {
+		color = cfg.Color
+	}
+	if !set["summarize"] && cfg.Summarize != nil {
+		summarize = *cfg.Summarize
+	}
+	if !set["redact"] && cfg.Redact != nil {
+		redact = *cfg.Redact
+	}
+	if !set["no-docs"] && cfg.Docs != nil {
+		noDocs = !*cfg.Docs
+	}
+	excludes = append(cfg.Exclude, excludes...)
+	if err := registerMappings(cfg.Map, mappings); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if jobs < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --jobs cannot be negative\n")
+		os.Exit(1)
+	}
+
+	if headerLanguage != "" {
+		resolved, ok := detector.LookupLanguage(headerLanguage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unsupported header language: %s\n", headerLanguage)
+			os.Exit(1)
+		}
+		headerLanguage = resolved
+	}
+
+	level, err := outline.ParseDetail(detail)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	outlineOpts := outline.DefaultOptions()
+	outlineOpts.Detail = level
+	outlineOpts.HeaderLanguage = headerLanguage
+	outlineOpts.Positions, err = outline.ParsePositions(positions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	outlineOpts.Sort, err = outline.ParseSort(sortOrder)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if kinds != "" {
+		outlineOpts.Kinds, err = outline.ParseKinds(kinds)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if !slices.Contains(cli.ColorModes, color) {
+		fmt.Fprintf(os.Stderr, "Error: unknown color mode: %s (expected %s)\n", color, strings.Join(cli.ColorModes, ", "))
+		os.Exit(1)
+	}
+	if !slices.Contains(cli.Formats(), format) {
+		fmt.Fprintf(os.Stderr, "Error: unknown format: %s (expected %s)\n", format, strings.Join(cli.Formats(), ", "))
+		os.Exit(1)
+	}
+	outlineOpts.Summarize = summarize
+	outlineOpts.Redact = redact
+	outlineOpts.Timeout = timeout
+	outlineOpts.MaxMemory = maxMemory << 20
+	outlineOpts.NoDocs = noDocs
+	outlineOpts.MaxTokens = maxTokens
+	outlineOpts.Languages, err = languageOptions(cfg.Languages, set)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if mcpMode {
-		if err := server.Run(); err != nil {
+		// Clients over HTTP only reach the current directory by default,
+		// while those over stdio, which run on the machine anyway, are only
+		// confined by an explicit --root
+		var mcpRoot confine.Root
+		if httpAddr != "" || root != "" {
+			mcpRoot, err = confine.NewRoot(cmp.Or(root, "."))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --root: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := server.Run(httpAddr, mcpRoot, token, outlineOpts); err != nil {
 			log.Fatal(err)
 		}
 	} else {
-		if err := cli.Run(flag.Args(), language); err != nil {
+		opts := cli.Options{
+			Language:      language,
+			Outline:       outlineOpts,
+			Recursive:     recursive,
+			NoIgnore:      noIgnore,
+			Exclude:       excludes,
+			Jobs:          jobs,
+			MaxFileSize:   int64(maxFileSize << 20),
+			Header:        header,
+			RelativeTo:    relativeTo,
+			Verbose:       verbose,
+			Format:        format,
+			Color:         color,
+			Stats:         stats,
+			WithMetrics:   withMetrics,
+			WithTodos:     withTodos,
+			Merge:         merge,
+			FailFast:      failFast,
+			NoPager:       noPager,
+			Watch:         watchMode,
+			Events:        events,
+			HTTP:          httpAddr,
+			Root:          root,
+			Base:          base,
+			MaxLines:      maxLines,
+			MaxComplexity: maxComplexity,
+			Out:           out,
+			DB:            db,
+			Tags:          tagsFile,
+			ChunkTokens:   chunkTokens,
+			Regex:         regex,
+		}
+		run := cli.Run
+		if command != "" {
+			run = func(args []string, opts cli.Options) error {
+				return cli.RunCommand(command, args, opts)
+			}
+		}
+		if err := run(positional, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			var exitErr *cli.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.Code)
+			}
 			os.Exit(1)
 		}
 	}
 }
+
+// loadConfig reads the configuration of the user and that of the project
+// of the first path given, or of the current directory, or only path when
+// set
+func loadConfig(path string, args []string) (config.Config, error) {
+	if path != "" {
+		return config.ReadFile(path)
+	}
+	dir := "."
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil {
+			dir = arg
+			if !info.IsDir() {
+				dir = filepath.Dir(arg)
+			}
+			break
+		}
+	}
+	return config.Load(dir)
+}
+
+// registerMappings routes the extensions the configuration maps, then those
+// of --map flags, which take precedence, to their language
+func registerMappings(configured map[string]string, flags []string) error {
+	var pairs [][2]string
+	for _, ext := range slices.Sorted(maps.Keys(configured)) {
+		pairs = append(pairs, [2]string{ext, configured[ext]})
+	}
+	for _, mapping := range flags {
+		ext, language, ok := strings.Cut(mapping, "=")
+		if !ok {
+			return fmt.Errorf("invalid --map %q (expected .ext=language)", mapping)
+		}
+		pairs = append(pairs, [2]string{ext, language})
+	}
+
+	for _, pair := range pairs {
+		ext := strings.TrimSpace(pair[0])
+		if strings.TrimPrefix(ext, ".") == "" {
+			return fmt.Errorf("invalid mapping %s=%s: missing extension", pair[0], pair[1])
+		}
+		language, ok := detector.LookupLanguage(pair[1])
+		if !ok {
+			return fmt.Errorf("invalid mapping %s=%s: unsupported language: %s", pair[0], pair[1], pair[1])
+		}
+		if err := detector.Register(detector.DetectionRule{Language: language, Extensions: []string{ext}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// languageOptions returns the extraction options of the languages the
+// configuration has settings for, which are layered on those of the command
+// line. Settings whose flag was given on the command line are left out.
+func languageOptions(languages map[string]config.Language, set map[string]bool) (map[string]outline.LanguageOptions, error) {
+	if len(languages) == 0 {
+		return nil, nil
+	}
+	options := make(map[string]outline.LanguageOptions, len(languages))
+	for name, lang := range languages {
+		resolved, ok := detector.LookupLanguage(name)
+		if !ok {
+			return nil, fmt.Errorf("configuration: unsupported language: %s", name)
+		}
+		var opts outline.LanguageOptions
+		if !set["detail"] && lang.Detail != "" {
+			level, err := outline.ParseDetail(lang.Detail)
+			if err != nil {
+				return nil, fmt.Errorf("configuration: %s: %v", name, err)
+			}
+			opts.Detail = &level
+		}
+		if !set["no-docs"] && lang.Docs != nil {
+			noDocs := !*lang.Docs
+			opts.NoDocs = &noDocs
+		}
+		if !set["summarize"] {
+			opts.Summarize = lang.Summarize
+		}
+		if !set["redact"] {
+			opts.Redact = lang.Redact
+		}
+		options[resolved] = opts
+	}
+	return options, nil
+}
+
+// parseInterleaved parses flags given before, between or after positional
+// arguments, as in "outline docs ./src --out site", and returns the
+// positional arguments. Everything after "--" is positional.
+func parseInterleaved(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		rest := flags.Args()
+		if len(rest) == 0 {
+			return positional
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
@@ -0,0 +1,317 @@
+// Package api serves outlines over HTTP as JSON, for services and tools that
+// would rather not spawn a process per file.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sourceradar/outline/internal/confine"
+	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// maxRequestSize bounds the body of a request, which holds at most the
+// content of one source file
+const maxRequestSize = 16 << 20
+
+// OutlineRequest outlines either a file on the server or content sent with
+// the request
+type OutlineRequest struct {
+	Path    string `json:"path,omitempty"`
+	Content string `json:"content,omitempty"`
+	// Filename helps detect the language of Content when Language is unset
+	Filename string `json:"filename,omitempty"`
+	Language string `json:"language,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// OutlineResponse is the outline and symbol tree of one file
+type OutlineResponse struct {
+	Language string               `json:"language"`
+	Outline  string               `json:"outline"`
+	Symbols  []outline.SymbolInfo `json:"symbols,omitempty"`
+}
+
+// Language is a supported language and the file names it is detected from
+type Language struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Extensions  []string `json:"extensions"`
+	Filenames   []string `json:"filenames,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// ProjectMapRequest maps every supported file below a directory on the
+// server
+type ProjectMapRequest struct {
+	Path string `json:"path"`
+	// Language forces a language for every file instead of detecting it
+	Language string `json:"language,omitempty"`
+}
+
+// ProjectMapResponse lists the files of a project in directory order
+type ProjectMapResponse struct {
+	Files []ProjectFile `json:"files"`
+}
+
+// ProjectFile is the symbol tree of one file of a project, or the error that
+// prevented it
+type ProjectFile struct {
+	Path     string               `json:"path"`
+	Language string               `json:"language,omitempty"`
+	Symbols  []outline.SymbolInfo `json:"symbols,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// requestError is an error caused by the request rather than the server
+type requestError struct {
+	status int
+	err    error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func badRequest(format string, args ...any) error {
+	return &requestError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+// Handler returns the HTTP handler serving the API, which outlines files
+// with opts unless a request asks otherwise. The paths of requests must lie
+// within root.
+func Handler(root confine.Root, opts outline.Options) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /outline", handle(outlineHandler(root, opts)))
+	mux.HandleFunc("GET /languages", handle(languagesHandler))
+	mux.HandleFunc("POST /project-map", handle(projectMapHandler(root, opts)))
+	return mux
+}
+
+// ListenAndServe serves the API on addr until ctx is done. Addresses without
+// a host, such as :9090, listen on localhost alone.
+func ListenAndServe(ctx context.Context, addr string, root confine.Root, opts outline.Options) error {
+	server := &http.Server{
+		Addr:              confine.LocalAddr(addr),
+		Handler:           Handler(root, opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdown)
+	}
+}
+
+// resolvePath returns the path a request names within root, refusing those
+// outside it. What is missing is reported as described.
+func resolvePath(root confine.Root, path, described string) (string, error) {
+	resolved, err := root.Resolve(path)
+	if errors.Is(err, confine.ErrOutside) {
+		return "", &requestError{status: http.StatusForbidden, err: err}
+	}
+	if err != nil {
+		return "", &requestError{status: http.StatusNotFound, err: fmt.Errorf("%s not found: %v", described, err)}
+	}
+	return resolved, nil
+}
+
+// handle adapts a handler returning a value to encode as JSON, or an error
+func handle(fn func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+
+		result, err := fn(r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var reqErr *requestError
+			if errors.As(err, &reqErr) {
+				status = reqErr.status
+			}
+			writeJSON(w, status, errorResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func decode(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// outlineHandler outlines one file within root or the content sent, with
+// base unless the request asks for another level of detail
+func outlineHandler(root confine.Root, base outline.Options) func(r *http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var req OutlineRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+		if (req.Path == "") == (req.Content == "") {
+			return nil, badRequest("expected exactly one of path or content")
+		}
+
+		opts := base
+		if req.Detail != "" {
+			var err error
+			if opts.Detail, err = outline.ParseDetail(req.Detail); err != nil {
+				return nil, badRequest("%v", err)
+			}
+		}
+
+		content := []byte(req.Content)
+		filename := req.Filename
+		if req.Path != "" {
+			path, err := resolvePath(root, req.Path, "file")
+			if err != nil {
+				return nil, err
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, &requestError{status: http.StatusNotFound, err: fmt.Errorf("file not found: %v", err)}
+			}
+			if info.IsDir() {
+				return nil, badRequest("expected a file, got directory (use /project-map for directories)")
+			}
+			if content, err = os.ReadFile(path); err != nil {
+				return nil, fmt.Errorf("error reading file: %v", err)
+			}
+			content, _ = outline.Decode(content)
+			filename = req.Path
+		}
+
+		language, err := resolveLanguage(filename, content, req.Language, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := outline.ExtractOutlineWithOptions(content, language, opts)
+		if err != nil {
+			return nil, &requestError{status: http.StatusUnprocessableEntity, err: fmt.Errorf("error extracting outline: %v", err)}
+		}
+
+		// Languages without a symbol tree, such as HTML, only have an outline
+		symbols, _ := outline.ExtractSymbolsWithOptions(content, language, opts)
+		return OutlineResponse{Language: language, Outline: result, Symbols: symbols}, nil
+	}
+}
+
+// resolveLanguage returns the language named by override, which may be an
+// alias, and otherwise detects it from the file name and content, settling
+// the language of headers with opts
+func resolveLanguage(filename string, content []byte, override string, opts outline.Options) (string, error) {
+	if override != "" {
+		language, ok := detector.LookupLanguage(override)
+		if !ok {
+			return "", badRequest("unsupported language: %s", override)
+		}
+		return language, nil
+	}
+
+	language, ok := detector.Detect(filename, content)
+	if !ok {
+		return "", badRequest("could not detect language (set language or filename)")
+	}
+	if detector.IsHeader(filename) {
+		language = outline.HeaderLanguage(language, opts)
+	}
+	return language, nil
+}
+
+func languagesHandler(r *http.Request) (any, error) {
+	var languages []Language
+	for name, info := range detector.SupportedLanguages() {
+		languages = append(languages, Language{
+			Name:        name,
+			Description: info.Description,
+			Extensions:  info.Extensions,
+			Filenames:   info.Filenames,
+			Aliases:     info.Aliases,
+		})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Name < languages[j].Name
+	})
+	return languages, nil
+}
+
+// projectMapHandler lists the symbols of every supported file below a
+// directory within root, extracted with opts
+func projectMapHandler(root confine.Root, opts outline.Options) func(r *http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		var req ProjectMapRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+		if req.Path == "" {
+			return nil, badRequest("expected a path")
+		}
+
+		path, err := resolvePath(root, req.Path, "directory")
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, &requestError{status: http.StatusNotFound, err: fmt.Errorf("directory not found: %v", err)}
+		}
+		if !info.IsDir() {
+			return nil, badRequest("expected a directory, got file (use /outline for files)")
+		}
+
+		language := req.Language
+		if language != "" {
+			resolved, ok := detector.LookupLanguage(language)
+			if !ok {
+				return nil, badRequest("unsupported language: %s", language)
+			}
+			language = resolved
+		}
+
+		response := ProjectMapResponse{Files: []ProjectFile{}}
+		err = scanner.Scan(r.Context(), path, scanner.Options{Language: language, Outline: opts, Symbols: true}, func(result scanner.Result) error {
+			file := ProjectFile{Path: result.Path, Language: result.Language, Symbols: result.Symbols}
+			if result.Err != nil {
+				file.Error = result.Err.Error()
+			}
+			response.Files = append(response.Files, file)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourceradar/outline/internal/confine"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func request(t *testing.T, method, path, body string, v any) int {
+	t.Helper()
+	return requestWithin(t, "/", method, path, body, v)
+}
+
+// requestWithin is request to a server confined to dir
+func requestWithin(t *testing.T, dir, method, path, body string, v any) int {
+	t.Helper()
+	root, err := confine.NewRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	Handler(root, outline.DefaultOptions()).ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); rec.Code != http.StatusMethodNotAllowed && ct != "application/json" {
+		t.Errorf("Expected a JSON response, got %q", ct)
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("Invalid JSON response %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestOutlineContent(t *testing.T) {
+	var resp OutlineResponse
+	status := request(t, "POST", "/outline", `{"content": "package main\n\nfunc Run() {}\n", "filename": "main.go"}`, &resp)
+	if status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	if resp.Language != "go" || !strings.Contains(resp.Outline, "func Run()") {
+		t.Errorf("Expected a Go outline with Run, got %+v", resp)
+	}
+	if len(resp.Symbols) != 1 || resp.Symbols[0].Name != "Run" {
+		t.Errorf("Expected the Run symbol, got %+v", resp.Symbols)
+	}
+}
+
+func TestOutlinePath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shapes.py")
+	if err := os.WriteFile(path, []byte("class Circle:\n    pass\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp OutlineResponse
+	body, _ := json.Marshal(OutlineRequest{Path: path, Detail: "signatures"})
+	if status := request(t, "POST", "/outline", string(body), &resp); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	if resp.Language != "python" || !strings.Contains(resp.Outline, "class Circle") {
+		t.Errorf("Expected a Python outline with Circle, got %+v", resp)
+	}
+}
+
+func TestOutlineErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"neither path nor content", `{}`, http.StatusBadRequest},
+		{"unknown field", `{"content": "x", "file": "a.go"}`, http.StatusBadRequest},
+		{"undetectable language", `{"content": "x"}`, http.StatusBadRequest},
+		{"unsupported language", `{"content": "x", "language": "cobol"}`, http.StatusBadRequest},
+		{"missing file", `{"path": "/does/not/exist.go"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp errorResponse
+			if status := request(t, "POST", "/outline", tt.body, &resp); status != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, status)
+			}
+			if resp.Error == "" {
+				t.Error("Expected an error message")
+			}
+		})
+	}
+
+	if status := request(t, "GET", "/outline", "", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET /outline to be rejected, got %d", status)
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	var languages []Language
+	if status := request(t, "GET", "/languages", "", &languages); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	for _, language := range languages {
+		if language.Name == "go" && len(language.Extensions) > 0 {
+			return
+		}
+	}
+	t.Errorf("Expected go with its extensions, got %+v", languages)
+}
+
+func TestProjectMap(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":     "package main\n\nfunc main() {}\n",
+		"lib/util.js": "function util() {}\n",
+		"README.md":   "# Not source\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var resp ProjectMapResponse
+	body, _ := json.Marshal(ProjectMapRequest{Path: dir})
+	if status := request(t, "POST", "/project-map", string(body), &resp); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	if len(resp.Files) != 2 {
+		t.Fatalf("Expected 2 source files, got %+v", resp.Files)
+	}
+	if util := resp.Files[0]; util.Language != "javascript" || len(util.Symbols) != 1 || util.Symbols[0].Name != "util" {
+		t.Errorf("Expected lib/util.js first with util, got %+v", util)
+	}
+}
+
+func TestPathsConfinedToRoot(t *testing.T) {
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.java")
+	if err := os.WriteFile(secret, []byte("class Secret {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Shop.java"), []byte("class Shop {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp OutlineResponse
+	if status := requestWithin(t, dir, "POST", "/outline", `{"path": "Shop.java"}`, &resp); status != http.StatusOK {
+		t.Fatalf("Expected 200 for a file within the root, got %d", status)
+	}
+	if !strings.Contains(resp.Outline, "class Shop") {
+		t.Errorf("Expected the outline of Shop, got %+v", resp)
+	}
+
+	for _, body := range []string{
+		fmt.Sprintf(`{"path": %q}`, secret),
+		`{"path": "../` + filepath.Base(outside) + `/secret.java"}`,
+		`{"path": "escape/secret.java"}`,
+	} {
+		var resp errorResponse
+		if status := requestWithin(t, dir, "POST", "/outline", body, &resp); status != http.StatusForbidden {
+			t.Errorf("Expected 403 for %s, got %d", body, status)
+		}
+		if strings.Contains(resp.Error, "Secret") {
+			t.Errorf("Expected nothing of the file outside, got %q", resp.Error)
+		}
+	}
+
+	body, _ := json.Marshal(ProjectMapRequest{Path: outside})
+	if status := requestWithin(t, dir, "POST", "/project-map", string(body), nil); status != http.StatusForbidden {
+		t.Errorf("Expected 403 for a directory outside the root, got %d", status)
+	}
+}
@@ -0,0 +1,120 @@
+// Package apidiff compares the public API of two versions of a source file.
+package apidiff
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// Kind is how a public symbol changed
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is a public symbol that was added, removed, or whose signature
+// changed
+type Change struct {
+	Kind Kind
+	// Name is qualified with the names of the enclosing types, and with the
+	// receiver of Go methods
+	Name string
+	Type string
+	// Old is the signature before the change, unset for added symbols
+	Old string
+	// New is the signature after the change, unset for removed symbols
+	New string
+	// Line is in the new version, or in the old one for removed symbols
+	Line int
+	// Container is the kind of the enclosing symbol, such as "interface",
+	// and unset for top-level symbols
+	Container string
+	// Breaking tells whether code using the old API may stop compiling or
+	// working, as opposed to additive changes
+	Breaking bool
+}
+
+// api is the public symbols of a file by qualified name
+type api struct {
+	names      []string
+	symbols    map[string]outline.SymbolInfo
+	containers map[string]string
+}
+
+// Compare lists the changes to the public API of a file in the given
+// language from old to new, with removed symbols first and the rest in the
+// order of new
+func Compare(language string, old, new []outline.SymbolInfo) []Change {
+	before, after := publicAPI(old), publicAPI(new)
+
+	var changes []Change
+	for _, name := range before.names {
+		if _, ok := after.symbols[name]; !ok {
+			symbol := before.symbols[name]
+			changes = append(changes, Change{Kind: Removed, Name: name, Type: symbol.Type, Old: signature(symbol), Line: symbol.Line, Container: before.containers[name]})
+		}
+	}
+	for _, name := range after.names {
+		symbol := after.symbols[name]
+		previous, ok := before.symbols[name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: Added, Name: name, Type: symbol.Type, New: signature(symbol), Line: symbol.Line, Container: after.containers[name]})
+		case signature(previous) != signature(symbol):
+			changes = append(changes, Change{Kind: Changed, Name: name, Type: symbol.Type, Old: signature(previous), New: signature(symbol), Line: symbol.Line, Container: after.containers[name]})
+		}
+	}
+
+	for i := range changes {
+		changes[i].Breaking = isBreaking(language, changes[i])
+	}
+	return changes
+}
+
+func publicAPI(symbols []outline.SymbolInfo) api {
+	result := api{symbols: make(map[string]outline.SymbolInfo), containers: make(map[string]string)}
+	result.collect(symbols, "", "")
+	return result
+}
+
+func (a *api) collect(symbols []outline.SymbolInfo, scope, container string) {
+	for _, symbol := range symbols {
+		if !symbol.IsPublic {
+			continue
+		}
+
+		name := scope + symbol.Name
+		if receiver := outline.MethodReceiver(symbol); receiver != "" {
+			name = receiver + "." + name
+		}
+		// Overloads share a name, so tell them apart by their position
+		// among symbols of that name
+		key := name
+		for i := 2; ; i++ {
+			if _, ok := a.symbols[key]; !ok {
+				break
+			}
+			key = name + "#" + strconv.Itoa(i)
+		}
+
+		a.names = append(a.names, key)
+		a.symbols[key] = symbol
+		a.containers[key] = container
+		a.collect(symbol.Children, name+".", symbol.Type)
+	}
+}
+
+// signature returns the signature of symbol with its whitespace and any
+// trailing semicolon normalized, so reformatting a declaration does not
+// count as a change
+func signature(symbol outline.SymbolInfo) string {
+	if symbol.Signature.IsZero() {
+		return symbol.Type + " " + symbol.Name
+	}
+	return strings.TrimSuffix(strings.Join(strings.Fields(symbol.Signature.String()), " "), ";")
+}
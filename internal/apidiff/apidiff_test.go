@@ -0,0 +1,142 @@
+package apidiff
+
+import (
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func symbols(t *testing.T, language, code string) []outline.SymbolInfo {
+	t.Helper()
+	result, err := outline.ExtractSymbols([]byte(code), language)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestCompareGo(t *testing.T) {
+	old := symbols(t, "go", `package pkg
+
+func Parse(s string) int { return 0 }
+
+func Removed() {}
+
+func helper() {}
+
+type A struct{}
+
+func (a A) Run() {}
+
+type B struct{}
+
+func (b *B) Run() {}
+`)
+	new := symbols(t, "go", `package pkg
+
+// Parse changed its signature
+func Parse(s string,
+	strict bool) int { return 0 }
+
+func helper(x int) {}
+
+type A struct{}
+
+func (a A) Run() {}
+
+type B struct{}
+
+func (b *B) Run(n int) {}
+
+func Added() {}
+`)
+
+	changes := Compare("go", old, new)
+	expected := []Change{
+		{Kind: Removed, Name: "Removed", Type: "function", Old: "func Removed()", Line: 5, Breaking: true},
+		{Kind: Changed, Name: "Parse", Type: "function", Old: "func Parse(s string) int", New: "func Parse(s string, strict bool) int", Line: 4, Breaking: true},
+		{Kind: Changed, Name: "B.Run", Type: "method", Old: "func (b *B) Run()", New: "func (b *B) Run(n int)", Line: 15, Breaking: true},
+		{Kind: Added, Name: "Added", Type: "function", New: "func Added()", Line: 17},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], changes[i])
+		}
+	}
+}
+
+func TestCompareMembersAndOverloads(t *testing.T) {
+	old := symbols(t, "java", `public class Stack {
+    public void push(int x) {}
+    public void push(long x) {}
+    private void grow() {}
+}
+`)
+	new := symbols(t, "java", `public class Stack {
+    public void push(int x) {}
+    public void push(long x) {}
+    private void grow(int by) {}
+    public int size() { return 0; }
+}
+`)
+
+	changes := Compare("java", old, new)
+	if len(changes) != 1 || changes[0].Kind != Added || changes[0].Name != "Stack.size" {
+		t.Errorf("Expected only Stack.size to be added, got %+v", changes)
+	}
+}
+
+func TestClassifyBreakingChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		old, new string
+		breaking []bool
+	}{
+		{
+			name:     "python parameters with defaults",
+			language: "python",
+			old:      "def load(path):\n    pass\n\ndef save(path):\n    pass\n",
+			new:      "def load(path, mode='r', *args, **kwargs):\n    pass\n\ndef save(path, mode):\n    pass\n",
+			breaking: []bool{false, true},
+		},
+		{
+			name:     "typescript optional parameters and members",
+			language: "typescript",
+			old:      "export interface Opts {\n  size: number;\n}\nexport function run(cb: () => void): void {}\n",
+			new:      "export interface Opts {\n  size: number;\n  name?: string;\n  mode: string;\n}\nexport function run(cb: () => void, retries?: number): void {}\n",
+			breaking: []bool{false, true, false},
+		},
+		{
+			name:     "go interface methods and struct fields",
+			language: "go",
+			old:      "package p\n\ntype Reader interface {\n\tRead() error\n}\n\ntype Config struct {\n\tPath string\n}\n",
+			new:      "package p\n\ntype Reader interface {\n\tRead() error\n\tClose() error\n}\n\ntype Config struct {\n\tPath string\n\tMode int\n}\n",
+			breaking: []bool{true, false},
+		},
+		{
+			name:     "java default methods",
+			language: "java",
+			old:      "public interface Shape {\n    double area();\n}\n",
+			new:      "public interface Shape {\n    double area();\n    double perimeter();\n    default String name() { return \"\"; }\n}\n",
+			breaking: []bool{true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Compare(tt.language, symbols(t, tt.language, tt.old), symbols(t, tt.language, tt.new))
+			if len(changes) != len(tt.breaking) {
+				t.Fatalf("Expected %d changes, got %+v", len(tt.breaking), changes)
+			}
+			for i, change := range changes {
+				if change.Breaking != tt.breaking[i] {
+					t.Errorf("Expected %s %s to have breaking=%v", change.Kind, change.Name, tt.breaking[i])
+				}
+			}
+		})
+	}
+}
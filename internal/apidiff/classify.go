@@ -0,0 +1,138 @@
+package apidiff
+
+import "strings"
+
+// isBreaking tells whether a change may break code written against the old
+// API. Removals always do. Additions only do when they add a requirement,
+// such as a method every implementation of an interface must now provide.
+// Signature changes only don't when they append parameters callers may
+// omit, in languages with optional parameters.
+func isBreaking(language string, change Change) bool {
+	switch change.Kind {
+	case Removed:
+		return true
+	case Added:
+		return addsRequirement(language, change)
+	}
+	return !appendsOptionalParameters(language, change.Old, change.New)
+}
+
+// addsRequirement reports whether an added member must be provided by every
+// implementation of its interface or protocol
+func addsRequirement(language string, change Change) bool {
+	switch change.Container {
+	case "interface":
+		switch language {
+		case "java":
+			// Default and static methods come with an implementation
+			return change.Type == "method" && !hasModifier(change.New, "default", "static")
+		case "typescript":
+			return !isOptionalMember(change.New)
+		}
+		// Go interfaces
+		return change.Type == "method"
+	case "protocol":
+		return true
+	}
+	return false
+}
+
+func hasModifier(signature string, modifiers ...string) bool {
+	for _, word := range strings.Fields(signature) {
+		for _, modifier := range modifiers {
+			if word == modifier {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isOptionalMember reports whether a TypeScript member is marked optional,
+// as in "name?: string" or "run?(): void"
+func isOptionalMember(signature string) bool {
+	end := strings.IndexAny(signature, ":(")
+	if end < 0 {
+		return strings.HasSuffix(signature, "?")
+	}
+	return strings.HasSuffix(strings.TrimSpace(signature[:end]), "?")
+}
+
+// appendsOptionalParameters reports whether new only differs from old by
+// parameters added after the existing ones that callers may omit: Python
+// and JavaScript parameters with defaults, TypeScript optional parameters,
+// and variadic parameters
+func appendsOptionalParameters(language, old, new string) bool {
+	switch language {
+	case "python", "javascript", "typescript":
+	default:
+		return false
+	}
+
+	oldHead, oldParams, oldTail, ok := splitParameters(old)
+	if !ok {
+		return false
+	}
+	newHead, newParams, newTail, ok := splitParameters(new)
+	if !ok || oldHead != newHead || oldTail != newTail || len(newParams) <= len(oldParams) {
+		return false
+	}
+
+	for i, param := range oldParams {
+		if newParams[i] != param {
+			return false
+		}
+	}
+	for _, param := range newParams[len(oldParams):] {
+		if !isOptionalParameter(param) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitParameters splits a signature into what precedes its parameter list,
+// the parameters, and what follows the list, such as a return type
+func splitParameters(signature string) (head string, params []string, tail string, ok bool) {
+	open := strings.IndexByte(signature, '(')
+	if open < 0 {
+		return "", nil, "", false
+	}
+
+	depth, start := 0, open+1
+	for i := open; i < len(signature); i++ {
+		switch signature[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			if signature[i] == '>' && i > 0 && signature[i-1] == '=' {
+				// The arrow of a function type
+				continue
+			}
+			depth--
+			if depth == 0 {
+				if param := strings.TrimSpace(signature[start:i]); param != "" {
+					params = append(params, param)
+				}
+				return signature[:open], params, signature[i+1:], true
+			}
+		case ',':
+			if depth == 1 {
+				params = append(params, strings.TrimSpace(signature[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return "", nil, "", false
+}
+
+func isOptionalParameter(param string) bool {
+	if strings.HasPrefix(param, "...") || strings.HasPrefix(param, "*") {
+		return true
+	}
+	if strings.Contains(param, "=") {
+		return true
+	}
+	name, _, _ := strings.Cut(param, ":")
+	return strings.HasSuffix(strings.TrimSpace(name), "?")
+}
@@ -0,0 +1,82 @@
+package apidiff
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// VerifyRevision checks that ref names a commit of the repository containing
+// dir
+func VerifyRevision(dir, ref string) error {
+	cmd := exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unknown git revision: %s", ref)
+	}
+	return nil
+}
+
+// ReadRevision returns the content of the file at path as of the git
+// revision ref, and false when the file did not exist then
+func ReadRevision(ref, path string) ([]byte, bool, error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	// A ./ prefix makes git resolve the path relative to dir rather than to
+	// the root of the repository
+	object := ref + ":./" + name
+
+	if err := exec.Command("git", "-C", dir, "cat-file", "-e", object).Run(); err != nil {
+		return nil, false, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "-C", dir, "show", object)
+	cmd.Stderr = &stderr
+	content, err := cmd.Output()
+	if err != nil {
+		return nil, false, fmt.Errorf("git show %s: %s", object, strings.TrimSpace(stderr.String()))
+	}
+	return content, true, nil
+}
+
+// listTree returns the blob hash of every file below dir as of the git
+// revision ref, by path relative to dir
+func listTree(dir, ref string) (map[string]string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "-C", dir, "ls-tree", "-r", "-z", ref, "--", ".")
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-tree %s: %s", ref, strings.TrimSpace(stderr.String()))
+	}
+
+	files := make(map[string]string)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		// Each entry is "<mode> <type> <hash>\t<path>"
+		meta, path, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) == 3 && fields[1] == "blob" {
+			files[path] = fields[2]
+		}
+	}
+	return files, nil
+}
+
+// readBlob returns the content of a blob of the repository containing dir
+func readBlob(dir, hash string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "-C", dir, "cat-file", "blob", hash)
+	cmd.Stderr = &stderr
+	content, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file %s: %s", hash, strings.TrimSpace(stderr.String()))
+	}
+	return content, nil
+}
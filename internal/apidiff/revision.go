@@ -0,0 +1,139 @@
+package apidiff
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/internal/testlink"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// FileChanges is the public API changes of one file
+type FileChanges struct {
+	// Path is relative to the directory the revisions were compared in
+	Path     string
+	Language string
+	Changes  []Change
+}
+
+// Breaking counts the breaking changes
+func (f FileChanges) Breaking() int {
+	count := 0
+	for _, change := range f.Changes {
+		if change.Breaking {
+			count++
+		}
+	}
+	return count
+}
+
+// CompareRevisions lists the public API changes of the files below dir
+// between the git revisions from and to, in path order. Only files whose
+// content differs are parsed, and test files are left out since their
+// symbols are not API.
+func CompareRevisions(dir, from, to string) ([]FileChanges, error) {
+	for _, ref := range []string{from, to} {
+		if err := VerifyRevision(dir, ref); err != nil {
+			return nil, err
+		}
+	}
+
+	before, err := listTree(dir, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := listTree(dir, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for path, hash := range after {
+		if before[path] != hash {
+			paths = append(paths, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var result []FileChanges
+	for _, path := range paths {
+		old, err := readSymbols(dir, path, before[path])
+		if err != nil {
+			return nil, err
+		}
+		new, err := readSymbols(dir, path, after[path])
+		if err != nil {
+			return nil, err
+		}
+		if old == nil && new == nil {
+			continue
+		}
+
+		var language string
+		if new != nil {
+			language = new.language
+		} else {
+			language = old.language
+		}
+		if testlink.IsTestFile(path, language) {
+			continue
+		}
+		if changes := Compare(language, old.symbolsOrNil(), new.symbolsOrNil()); len(changes) > 0 {
+			result = append(result, FileChanges{Path: path, Language: language, Changes: changes})
+		}
+	}
+	return result, nil
+}
+
+// parsedFile is a file version with a supported language
+type parsedFile struct {
+	language string
+	symbols  []outline.SymbolInfo
+}
+
+func (f *parsedFile) symbolsOrNil() []outline.SymbolInfo {
+	if f == nil {
+		return nil
+	}
+	return f.symbols
+}
+
+// readSymbols parses the blob of a file, returning nil when the file did
+// not exist or is not in a language with symbols
+func readSymbols(dir, path, hash string) (*parsedFile, error) {
+	if hash == "" {
+		return nil, nil
+	}
+	// Like directory scans, only sniff the content of files without an
+	// extension
+	if _, ok := detector.DetectLanguage(path); !ok {
+		if _, known := detector.IdentifyFilename(path); known || filepath.Ext(path) != "" || strings.HasPrefix(filepath.Base(path), ".") {
+			return nil, nil
+		}
+	}
+
+	content, err := readBlob(dir, hash)
+	if err != nil {
+		return nil, err
+	}
+	language, ok := detector.Detect(path, content)
+	if !ok {
+		return nil, nil
+	}
+	if detector.IsHeader(path) {
+		language = outline.HeaderLanguage(language, outline.Options{})
+	}
+	symbols, err := outline.ExtractSymbols(content, language)
+	if err != nil {
+		// Languages without symbols, such as HTML, have no API to compare
+		return nil, nil
+	}
+	return &parsedFile{language: language, symbols: symbols}, nil
+}
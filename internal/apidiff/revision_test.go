@@ -0,0 +1,64 @@
+package apidiff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
+
+func TestCompareRevisions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib/api.py":         "def load(path):\n    pass\n",
+		"lib/gone.py":        "def old():\n    pass\n",
+		"lib/same.go":        "package lib\n\nfunc Same() {}\n",
+		"lib/lib_test.go":    "package lib\n\nfunc TestSame() {}\n",
+		"docs/notes.txt":     "not code\n",
+		"other/untouched.js": "export function untouched() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(content), 0o644)
+	}
+	git(t, dir, "init", "-q")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-qm", "first")
+
+	os.WriteFile(filepath.Join(dir, "lib/api.py"), []byte("def load(path, mode='r'):\n    pass\n\ndef save(path):\n    pass\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "lib/lib_test.go"), []byte("package lib\n\nfunc TestOther() {}\n"), 0o644)
+	os.Remove(filepath.Join(dir, "lib/gone.py"))
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-qm", "second")
+
+	result, err := CompareRevisions(filepath.Join(dir, "lib"), "HEAD~1", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result[0].Path != "api.py" || result[1].Path != "gone.py" {
+		t.Fatalf("Expected changes in api.py and gone.py only, got %+v", result)
+	}
+	if api := result[0]; len(api.Changes) != 2 || api.Breaking() != 0 {
+		t.Errorf("Expected two additive changes to api.py, got %+v", api.Changes)
+	}
+	if gone := result[1]; gone.Language != "python" || gone.Breaking() != 1 || gone.Changes[0].Kind != Removed {
+		t.Errorf("Expected the removal of old to be breaking, got %+v", gone)
+	}
+
+	if _, err := CompareRevisions(dir, "missing", "HEAD"); err == nil {
+		t.Error("Expected an error for an unknown revision")
+	}
+}
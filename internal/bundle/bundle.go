@@ -0,0 +1,296 @@
+// Package bundle captures the structure of a project in a single
+// self-contained JSON document: its directory tree, the outline, symbols and
+// owners of every file, statistics and where the snapshot was taken. Bundles can be
+// attached to issues and browsed later without the source.
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sourceradar/outline/internal/codeowners"
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// FormatVersion is the version of the bundle format written by Build. It is
+// raised when a change would make older readers misread a bundle.
+const FormatVersion = 1
+
+// Bundle is a snapshot of the structure of a project
+type Bundle struct {
+	Format   int      `json:"format"`
+	Metadata Metadata `json:"metadata"`
+	Stats    Stats    `json:"stats"`
+	Tree     *Dir     `json:"tree"`
+	Files    []File   `json:"files"`
+}
+
+// Metadata records where and when a bundle was taken
+type Metadata struct {
+	// Root is the name of the directory the bundle was built from
+	Root    string    `json:"root"`
+	Created time.Time `json:"created"`
+	// Commit and Branch are only set when the directory is in a git
+	// repository, and Dirty when it has uncommitted changes
+	Commit string `json:"commit,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Dirty  bool   `json:"dirty,omitempty"`
+	// CodeOwners is the CODEOWNERS file the owners of files come from,
+	// relative to the root
+	CodeOwners string `json:"codeowners,omitempty"`
+}
+
+// Stats totals the files of a bundle
+type Stats struct {
+	Files   int `json:"files"`
+	Failed  int `json:"failed"`
+	Lines   int `json:"lines"`
+	Bytes   int `json:"bytes"`
+	Symbols int `json:"symbols"`
+	// Public counts the exported symbols
+	Public    int                      `json:"public"`
+	Languages map[string]LanguageStats `json:"languages"`
+}
+
+// LanguageStats totals the files of one language
+type LanguageStats struct {
+	Files   int `json:"files"`
+	Lines   int `json:"lines"`
+	Symbols int `json:"symbols"`
+}
+
+// File is the outline of one file. Path is relative to the root and uses
+// forward slashes.
+type File struct {
+	Path     string               `json:"path"`
+	Language string               `json:"language,omitempty"`
+	Lines    int                  `json:"lines"`
+	Bytes    int                  `json:"bytes"`
+	Outline  string               `json:"outline,omitempty"`
+	Symbols  []outline.SymbolInfo `json:"symbols,omitempty"`
+	Owners   []string             `json:"owners,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// Dir is a directory of the tree, holding the names of its files
+type Dir struct {
+	Name  string   `json:"name"`
+	Dirs  []*Dir   `json:"dirs,omitempty"`
+	Files []string `json:"files,omitempty"`
+}
+
+// Build outlines every supported file below root into a bundle. Files that
+// fail to outline are kept with their error.
+func Build(ctx context.Context, root string, opts scanner.Options) (*Bundle, error) {
+	absolute, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		Format: FormatVersion,
+		Metadata: Metadata{
+			Root:    filepath.Base(absolute),
+			Created: time.Now().UTC().Truncate(time.Second),
+		},
+		Stats: Stats{Languages: make(map[string]LanguageStats)},
+	}
+	b.Metadata.Commit, b.Metadata.Branch, b.Metadata.Dirty = gitState(root)
+
+	owners, err := codeowners.Find(root)
+	if err != nil {
+		return nil, err
+	}
+	if owners != nil {
+		if rel, err := filepath.Rel(absolute, owners.Path); err == nil {
+			b.Metadata.CodeOwners = filepath.ToSlash(rel)
+		}
+	}
+
+	opts.Symbols = true
+	opts.Content = true
+	err = scanner.Scan(ctx, root, opts, func(result scanner.Result) error {
+		b.add(root, result, owners.Owners(result.Path))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b.Tree = buildTree(b.Metadata.Root, b.Files)
+	return b, nil
+}
+
+// add records one scanned file and adds it to the totals
+func (b *Bundle) add(root string, result scanner.Result, owners []string) {
+	path := result.Path
+	if rel, err := filepath.Rel(root, result.Path); err == nil {
+		path = filepath.ToSlash(rel)
+	}
+
+	file := File{Path: path, Language: result.Language, Owners: owners}
+	if result.Err != nil {
+		file.Error = result.Err.Error()
+		b.Stats.Failed++
+		b.Files = append(b.Files, file)
+		return
+	}
+
+	file.Bytes = len(result.Content)
+	file.Lines = countLines(result.Content)
+	file.Outline = result.Outline
+	file.Symbols = result.Symbols
+	b.Files = append(b.Files, file)
+
+	symbols, public := countSymbols(result.Symbols)
+	b.Stats.Files++
+	b.Stats.Lines += file.Lines
+	b.Stats.Bytes += file.Bytes
+	b.Stats.Symbols += symbols
+	b.Stats.Public += public
+
+	language := b.Stats.Languages[file.Language]
+	language.Files++
+	language.Lines += file.Lines
+	language.Symbols += symbols
+	b.Stats.Languages[file.Language] = language
+}
+
+// File returns the file of the bundle at path, relative to the root
+func (b *Bundle) File(path string) (*File, bool) {
+	path = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+	for i := range b.Files {
+		if b.Files[i].Path == path {
+			return &b.Files[i], true
+		}
+	}
+	return nil, false
+}
+
+// Save writes the bundle to path as indented JSON
+func (b *Bundle) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding bundle: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing bundle: %v", err)
+	}
+	return nil
+}
+
+// Load reads a bundle written by Save
+func Load(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle: %v", err)
+	}
+
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%s is not an outline bundle: %v", path, err)
+	}
+	if b.Format == 0 || b.Tree == nil {
+		return nil, fmt.Errorf("%s is not an outline bundle", path)
+	}
+	if b.Format > FormatVersion {
+		return nil, fmt.Errorf("%s uses bundle format %d, newer than the supported %d", path, b.Format, FormatVersion)
+	}
+	return &b, nil
+}
+
+// buildTree nests the files of a bundle under their directories, sorted by
+// name
+func buildTree(name string, files []File) *Dir {
+	root := &Dir{Name: name}
+	dirs := map[string]*Dir{"": root}
+
+	var dirFor func(path string) *Dir
+	dirFor = func(path string) *Dir {
+		if dir, ok := dirs[path]; ok {
+			return dir
+		}
+		parentPath, base := "", path
+		if i := strings.LastIndexByte(path, '/'); i >= 0 {
+			parentPath, base = path[:i], path[i+1:]
+		}
+		dir := &Dir{Name: base}
+		parent := dirFor(parentPath)
+		parent.Dirs = append(parent.Dirs, dir)
+		dirs[path] = dir
+		return dir
+	}
+
+	for _, file := range files {
+		dirPath, base := "", file.Path
+		if i := strings.LastIndexByte(file.Path, '/'); i >= 0 {
+			dirPath, base = file.Path[:i], file.Path[i+1:]
+		}
+		dir := dirFor(dirPath)
+		dir.Files = append(dir.Files, base)
+	}
+
+	var sortDir func(dir *Dir)
+	sortDir = func(dir *Dir) {
+		sort.Slice(dir.Dirs, func(i, j int) bool { return dir.Dirs[i].Name < dir.Dirs[j].Name })
+		sort.Strings(dir.Files)
+		for _, sub := range dir.Dirs {
+			sortDir(sub)
+		}
+	}
+	sortDir(root)
+	return root
+}
+
+func countLines(content []byte) int {
+	lines := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+func countSymbols(symbols []outline.SymbolInfo) (total, public int) {
+	for _, symbol := range symbols {
+		total++
+		if symbol.IsPublic {
+			public++
+		}
+		childTotal, childPublic := countSymbols(symbol.Children)
+		total += childTotal
+		public += childPublic
+	}
+	return total, public
+}
+
+// gitState returns the commit and branch checked out in the repository
+// containing dir, and whether it has uncommitted changes. Everything is
+// empty outside a repository or when git is not installed.
+func gitState(dir string) (commit, branch string, dirty bool) {
+	git := func(args ...string) string {
+		output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	commit = git("rev-parse", "HEAD")
+	if commit == "" {
+		return "", "", false
+	}
+	if branch = git("rev-parse", "--abbrev-ref", "HEAD"); branch == "HEAD" {
+		branch = ""
+	}
+	dirty = git("status", "--porcelain", "--", ".") != ""
+	return commit, branch, dirty
+}
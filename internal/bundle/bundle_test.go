@@ -0,0 +1,107 @@
+package bundle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildSaveLoad(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "demo")
+	writeFile(t, filepath.Join(root, "main.go"), "package main\n\n// Run runs\nfunc Run() {}\n\nfunc helper() {}\n")
+	writeFile(t, filepath.Join(root, "server", "app.py"), "class App:\n    def serve(self):\n        pass\n")
+	writeFile(t, filepath.Join(root, "server", "util", "util.go"), "package util\n\nconst Limit = 1\n")
+	writeFile(t, filepath.Join(root, "README"), "not code\n")
+
+	b, err := Build(context.Background(), root, scanner.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Format != FormatVersion || b.Metadata.Root != "demo" {
+		t.Errorf("Unexpected header: format %d, root %q", b.Format, b.Metadata.Root)
+	}
+	if b.Stats.Files != 3 || b.Stats.Lines != 12 || b.Stats.Symbols != 5 || b.Stats.Public != 4 {
+		t.Errorf("Unexpected stats: %+v", b.Stats)
+	}
+	if stats := b.Stats.Languages["go"]; stats.Files != 2 || stats.Symbols != 3 {
+		t.Errorf("Unexpected Go stats: %+v", stats)
+	}
+
+	expected := &Dir{Name: "demo", Files: []string{"main.go"}, Dirs: []*Dir{
+		{Name: "server", Files: []string{"app.py"}, Dirs: []*Dir{
+			{Name: "util", Files: []string{"util.go"}},
+		}},
+	}}
+	if !reflect.DeepEqual(b.Tree, expected) {
+		t.Errorf("Unexpected tree: %+v", b.Tree)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.outline.json")
+	if err := b.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, ok := loaded.File("./server/app.py")
+	if !ok {
+		t.Fatal("Expected server/app.py in the loaded bundle")
+	}
+	if file.Language != "python" || file.Outline == "" || len(file.Symbols) != 1 || file.Symbols[0].Children[0].Name != "serve" {
+		t.Errorf("Unexpected file after loading: %+v", file)
+	}
+	if !reflect.DeepEqual(loaded.Stats, b.Stats) {
+		t.Errorf("Expected stats to round-trip, got %+v", loaded.Stats)
+	}
+}
+
+func TestLoadRejectsOtherJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "other.json")
+	writeFile(t, path, `{"name": "not a bundle"}`)
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error loading a file that is not a bundle")
+	}
+
+	writeFile(t, path, `{"format": 99, "tree": {"name": "x"}}`)
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error loading a newer bundle format")
+	}
+}
+
+func TestBuildOwners(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "CODEOWNERS"), "* @core\n/api/ @api\n")
+	writeFile(t, filepath.Join(root, "api", "api.go"), "package api\n")
+	writeFile(t, filepath.Join(root, "main.go"), "package main\n")
+
+	b, err := Build(context.Background(), root, scanner.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Metadata.CodeOwners != "CODEOWNERS" {
+		t.Errorf("Expected owners from CODEOWNERS, got %q", b.Metadata.CodeOwners)
+	}
+	for path, owner := range map[string]string{"api/api.go": "@api", "main.go": "@core"} {
+		file, ok := b.File(path)
+		if !ok || len(file.Owners) != 1 || file.Owners[0] != owner {
+			t.Errorf("Expected %s to be owned by %s, got %+v", path, owner, file)
+		}
+	}
+}
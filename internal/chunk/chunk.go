@@ -0,0 +1,260 @@
+// Package chunk splits source files into chunks aligned to symbol
+// boundaries, sized for embedding models, for retrieval-augmented
+// generation pipelines.
+package chunk
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// DefaultTokens is the chunk size aimed for when none is given
+const DefaultTokens = 512
+
+// Chunk is a contiguous range of a file, holding whole symbols where they
+// fit the token target and parts of a symbol where it does not
+type Chunk struct {
+	ID        string `json:"id"`
+	Path      string `json:"path"`
+	Language  string `json:"language"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	// Symbols are the qualified names of the symbols the chunk holds in full
+	Symbols []string `json:"symbols,omitempty"`
+	// Kind and Signature are those of the first symbol of the chunk, or of
+	// the symbol a part was split from
+	Kind      string `json:"kind,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	// Context is the signatures of the symbols enclosing the chunk, from the
+	// outermost
+	Context []string `json:"context,omitempty"`
+	// Part and Parts number the pieces of a symbol too large for one chunk
+	Part    int    `json:"part,omitempty"`
+	Parts   int    `json:"parts,omitempty"`
+	Tokens  int    `json:"tokens"`
+	Content string `json:"content"`
+}
+
+// EstimateTokens approximates the number of tokens text takes in the
+// tokenizers of common embedding and language models, as outlines do
+func EstimateTokens(text []byte) int {
+	return outline.EstimateTokens(text)
+}
+
+// unit is a symbol, or the code between symbols
+type unit struct {
+	start, end int
+	symbol     *outline.SymbolInfo
+	name       string
+}
+
+type chunker struct {
+	path     string
+	language string
+	content  []byte
+	target   int
+	// lineStarts are the byte offsets where lines begin
+	lineStarts []int
+	chunks     []Chunk
+}
+
+// Split chunks a file. Consecutive symbols and the code between them are
+// packed into chunks of up to target tokens. A symbol larger than that is
+// split into its members, with the signatures of the symbols enclosing them
+// as context, and symbols without members are split at line boundaries.
+// Every byte of content that is not blank ends up in exactly one chunk.
+func Split(path, language string, content []byte, symbols []outline.SymbolInfo, target int) []Chunk {
+	if target <= 0 {
+		target = DefaultTokens
+	}
+	c := &chunker{path: path, language: language, content: content, target: target, lineStarts: []int{0}}
+	for i, b := range content {
+		if b == '\n' {
+			c.lineStarts = append(c.lineStarts, i+1)
+		}
+	}
+
+	c.splitRange(0, len(content), symbols, "", nil)
+	return c.chunks
+}
+
+// splitRange chunks content[start:end], which holds symbols
+func (c *chunker) splitRange(start, end int, symbols []outline.SymbolInfo, scope string, context []string) {
+	var pending []unit
+	pendingTokens := 0
+	flush := func() {
+		if len(pending) > 0 {
+			c.emit(pending, context)
+			pending, pendingTokens = nil, 0
+		}
+	}
+
+	for _, u := range c.units(start, end, symbols, scope) {
+		tokens := EstimateTokens(c.content[u.start:u.end])
+		if tokens > c.target {
+			flush()
+			c.splitUnit(u, context)
+			continue
+		}
+		if pendingTokens+tokens > c.target {
+			flush()
+		}
+		pending = append(pending, u)
+		pendingTokens += tokens
+	}
+	flush()
+}
+
+// units covers content[start:end] with its symbols and the code around them
+func (c *chunker) units(start, end int, symbols []outline.SymbolInfo, scope string) []unit {
+	sorted := make([]*outline.SymbolInfo, len(symbols))
+	for i := range symbols {
+		sorted[i] = &symbols[i]
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Source.Start() < sorted[j].Source.Start()
+	})
+
+	var units []unit
+	pos := start
+	for _, symbol := range sorted {
+		symbolStart, symbolEnd := c.span(symbol)
+		symbolStart = max(symbolStart, pos)
+		symbolEnd = min(symbolEnd, end)
+		if symbolEnd <= symbolStart {
+			continue
+		}
+		if symbolStart > pos {
+			units = append(units, unit{start: pos, end: symbolStart})
+		}
+
+		name := scope + symbol.Name
+		if receiver := outline.MethodReceiver(*symbol); receiver != "" {
+			name = receiver + "." + name
+		}
+		units = append(units, unit{start: symbolStart, end: symbolEnd, symbol: symbol, name: name})
+		pos = symbolEnd
+	}
+	if pos < end {
+		units = append(units, unit{start: pos, end: end})
+	}
+	return units
+}
+
+// span returns the byte range of a symbol, including the doc comment
+// preceding it
+func (c *chunker) span(symbol *outline.SymbolInfo) (int, int) {
+	start, end := symbol.Source.Start(), symbol.Source.End()
+	if doc := symbol.Documentation; !doc.IsZero() && doc.End() <= start {
+		start = doc.Start()
+	}
+	return start, end
+}
+
+// splitUnit chunks a unit too large for one chunk
+func (c *chunker) splitUnit(u unit, context []string) {
+	if u.symbol == nil {
+		c.splitLines(u, context)
+		return
+	}
+	if len(u.symbol.Children) > 0 {
+		c.splitRange(u.start, u.end, u.symbol.Children, u.name+".", append(context[:len(context):len(context)], signature(u.symbol)))
+		return
+	}
+	c.splitLines(u, context)
+}
+
+// splitLines chunks a unit at line boundaries, numbering the parts when the
+// unit is a symbol
+func (c *chunker) splitLines(u unit, context []string) {
+	var parts []unit
+	start := u.start
+	for start < u.end {
+		end := start
+		for end < u.end {
+			next := bytes.IndexByte(c.content[end:u.end], '\n')
+			lineEnd := u.end
+			if next >= 0 {
+				lineEnd = end + next + 1
+			}
+			// A single line over the target still makes a chunk of its own
+			if end > start && EstimateTokens(c.content[start:lineEnd]) > c.target {
+				break
+			}
+			end = lineEnd
+		}
+		parts = append(parts, unit{start: start, end: end, symbol: u.symbol, name: u.name})
+		start = end
+	}
+
+	first := len(c.chunks)
+	for _, part := range parts {
+		c.emit([]unit{part}, context)
+	}
+	if u.symbol == nil {
+		return
+	}
+	for i := first; i < len(c.chunks); i++ {
+		c.chunks[i].Symbols = nil
+		c.chunks[i].Part = i - first + 1
+		c.chunks[i].Parts = len(c.chunks) - first
+	}
+}
+
+// emit adds a chunk spanning units, unless it is blank
+func (c *chunker) emit(units []unit, context []string) {
+	start, end := units[0].start, units[len(units)-1].end
+	for start < end && isSpace(c.content[start]) {
+		start++
+	}
+	for end > start && isSpace(c.content[end-1]) {
+		end--
+	}
+	if start == end {
+		return
+	}
+
+	startLine, endLine := c.line(start), c.line(end-1)
+	chunk := Chunk{
+		ID:        fmt.Sprintf("%s#L%d-L%d", c.path, startLine, endLine),
+		Path:      c.path,
+		Language:  c.language,
+		StartLine: startLine,
+		EndLine:   endLine,
+		Context:   context,
+		Tokens:    EstimateTokens(c.content[start:end]),
+		Content:   string(c.content[start:end]),
+	}
+	for _, u := range units {
+		if u.symbol == nil {
+			continue
+		}
+		if chunk.Kind == "" {
+			chunk.Kind = u.symbol.Type
+			chunk.Signature = signature(u.symbol)
+		}
+		chunk.Symbols = append(chunk.Symbols, u.name)
+	}
+	c.chunks = append(c.chunks, chunk)
+}
+
+// line returns the 1-based line holding the byte at offset
+func (c *chunker) line(offset int) int {
+	return sort.Search(len(c.lineStarts), func(i int) bool {
+		return c.lineStarts[i] > offset
+	})
+}
+
+func signature(symbol *outline.SymbolInfo) string {
+	if signature := symbol.Signature.String(); signature != "" {
+		return signature
+	}
+	return symbol.Type + " " + symbol.Name
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
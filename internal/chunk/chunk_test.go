@@ -0,0 +1,87 @@
+package chunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func split(t *testing.T, language, code string, target int) []Chunk {
+	t.Helper()
+	symbols, err := outline.ExtractSymbols([]byte(code), language)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Split("file", language, []byte(code), symbols, target)
+}
+
+// covered checks that every non-blank line of code is in exactly one chunk
+func covered(t *testing.T, code string, chunks []Chunk) {
+	t.Helper()
+	seen := make(map[int]int)
+	for _, c := range chunks {
+		for line := c.StartLine; line <= c.EndLine; line++ {
+			seen[line]++
+		}
+	}
+	for i, line := range strings.Split(code, "\n") {
+		if strings.TrimSpace(line) != "" && seen[i+1] != 1 {
+			t.Errorf("Expected line %d (%q) in exactly one chunk, found in %d", i+1, line, seen[i+1])
+		}
+	}
+}
+
+func TestSplitPacksSmallSymbols(t *testing.T) {
+	code := `package demo
+
+import "fmt"
+
+// Version is the release
+const Version = "1.0"
+
+// Hello greets
+func Hello() { fmt.Println("hi") }
+`
+	chunks := split(t, "go", code, DefaultTokens)
+	if len(chunks) != 1 {
+		t.Fatalf("Expected one chunk, got %+v", chunks)
+	}
+	c := chunks[0]
+	if c.StartLine != 1 || c.EndLine != 9 || c.ID != "file#L1-L9" || strings.Join(c.Symbols, ",") != "Version,Hello" {
+		t.Errorf("Expected the whole file with both symbols, got %+v", c)
+	}
+	if c.Kind != "constant" || c.Signature != `const Version = "1.0"` || c.Tokens != EstimateTokens([]byte(strings.TrimSpace(code))) {
+		t.Errorf("Expected the first symbol to describe the chunk, got %+v", c)
+	}
+}
+
+func TestSplitLargeSymbols(t *testing.T) {
+	body := strings.Repeat("        total += 1\n", 12)
+	code := "class Counter:\n    \"\"\"Counts things\"\"\"\n\n    def small(self):\n        return 1\n\n    def large(self):\n        total = 0\n" + body + "        return total\n"
+
+	chunks := split(t, "python", code, 60)
+	covered(t, code, chunks)
+
+	if head := chunks[0]; head.StartLine != 1 || len(head.Symbols) != 1 || head.Symbols[0] != "Counter.small" {
+		t.Errorf("Expected the class header packed with its small method, got %+v", head)
+	}
+	for _, c := range chunks {
+		if len(c.Context) != 1 || c.Context[0] != "class Counter" {
+			t.Errorf("Expected members to have the class as context, got %+v", c)
+		}
+		if c.Tokens > 60 {
+			t.Errorf("Expected chunks within the target, got %d tokens", c.Tokens)
+		}
+	}
+
+	parts := chunks[1:]
+	if len(parts) < 2 {
+		t.Fatalf("Expected the large method to be split, got %+v", chunks)
+	}
+	for i, part := range parts {
+		if part.Part != i+1 || part.Parts != len(parts) || part.Signature != "def large(self)" || part.Symbols != nil {
+			t.Errorf("Expected part %d of %d of large, got %+v", i+1, len(parts), part)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sourceradar/outline/internal/apidiff"
+)
+
+// runAPIDiff compares the public API of a directory between two git
+// revisions and fails when any change is breaking, so it can gate releases
+func runAPIDiff(args []string, opts Options) error {
+	if len(args) != 2 && len(args) != 3 {
+		return fmt.Errorf("usage: outline apidiff <from> <to> [dir]")
+	}
+	dir := "."
+	if len(args) == 3 {
+		dir = args[2]
+	}
+
+	files, err := apidiff.CompareRevisions(dir, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Printf("No public API changes between %s and %s\n", args[0], args[1])
+		return nil
+	}
+
+	breaking, additive := 0, 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, file := range files {
+		fmt.Fprintf(w, "%s (%s)\n", file.Path, file.Language)
+		for _, change := range file.Changes {
+			severity := "additive"
+			if change.Breaking {
+				severity = "BREAKING"
+				breaking++
+			} else {
+				additive++
+			}
+
+			description := change.New
+			switch change.Kind {
+			case apidiff.Removed:
+				description = change.Old
+			case apidiff.Changed:
+				description = fmt.Sprintf("%s (was: %s)", change.New, change.Old)
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", severity, change.Kind, description)
+		}
+		fmt.Fprintln(w)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d breaking, %d additive change(s) between %s and %s\n", breaking, additive, args[0], args[1])
+	if breaking > 0 {
+		return fmt.Errorf("found %d breaking API change(s)", breaking)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sourceradar/outline/internal/bundle"
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+// runBundle writes the structure of a directory to a single bundle file
+func runBundle(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline bundle [--language <lang>] <dir> -o <file>")
+	}
+
+	dir := args[0]
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory not found: %v", err)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("expected a directory, got a file")
+	}
+
+	b, err := bundle.Build(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped})
+	if err != nil {
+		return err
+	}
+
+	out := opts.Out
+	if out == "" {
+		out = b.Metadata.Root + ".outline.json"
+	}
+	if err := b.Save(out); err != nil {
+		return err
+	}
+
+	for _, file := range b.Files {
+		if file.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s: %s\n", file.Path, file.Error)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d file(s) to %s\n", b.Stats.Files, out)
+
+	if b.Stats.Failed > 0 {
+		return fmt.Errorf("failed to outline %d file(s)", b.Stats.Failed)
+	}
+	return nil
+}
+
+// runView prints the summary and tree of a bundle, or the outlines of the
+// files below a path of it
+func runView(args []string, opts Options) error {
+	if len(args) != 1 && len(args) != 2 {
+		return fmt.Errorf("usage: outline view <bundle> [path]")
+	}
+
+	b, err := bundle.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		writeBundleSummary(os.Stdout, b)
+		fmt.Println()
+		owners := make(map[string][]string)
+		for _, file := range b.Files {
+			owners[file.Path] = file.Owners
+		}
+		writeBundleTree(os.Stdout, b.Tree, "", "", owners)
+		return nil
+	}
+
+	path := args[1]
+	if file, ok := b.File(path); ok {
+		if file.Error != "" {
+			return fmt.Errorf("%s failed to outline when the bundle was taken: %s", file.Path, file.Error)
+		}
+		writeOwners(os.Stdout, file.Owners)
+		fmt.Printf("Language: %s\n\n%s", file.Language, file.Outline)
+		return nil
+	}
+
+	prefix := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./") + "/"
+	if prefix == "./" {
+		prefix = ""
+	}
+	found := false
+	for _, file := range b.Files {
+		if !strings.HasPrefix(file.Path, prefix) || file.Error != "" {
+			continue
+		}
+		found = true
+		fmt.Printf("File: %s\n", file.Path)
+		writeOwners(os.Stdout, file.Owners)
+		fmt.Printf("Language: %s\n\n%s\n", file.Language, file.Outline)
+	}
+	if !found {
+		return fmt.Errorf("no file or directory %s in the bundle", path)
+	}
+	return nil
+}
+
+func writeBundleSummary(w io.Writer, b *bundle.Bundle) {
+	fmt.Fprintf(w, "Snapshot of %s taken %s\n", b.Metadata.Root, b.Metadata.Created.Format("2006-01-02 15:04:05 MST"))
+	if b.Metadata.Commit != "" {
+		state := b.Metadata.Branch
+		if b.Metadata.Dirty {
+			if state != "" {
+				state += ", "
+			}
+			state += "uncommitted changes"
+		}
+		if state != "" {
+			state = " (" + state + ")"
+		}
+		fmt.Fprintf(w, "Commit: %s%s\n", b.Metadata.Commit, state)
+	}
+	if b.Metadata.CodeOwners != "" {
+		fmt.Fprintf(w, "Owners from: %s\n", b.Metadata.CodeOwners)
+	}
+
+	failed := ""
+	if b.Stats.Failed > 0 {
+		failed = fmt.Sprintf(" (%d failed)", b.Stats.Failed)
+	}
+	fmt.Fprintf(w, "Files: %d%s, %d lines, %d symbols (%d public)\n\n", b.Stats.Files, failed, b.Stats.Lines, b.Stats.Symbols, b.Stats.Public)
+
+	languages := make([]string, 0, len(b.Stats.Languages))
+	for language := range b.Stats.Languages {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		x, y := b.Stats.Languages[languages[i]], b.Stats.Languages[languages[j]]
+		if x.Lines != y.Lines {
+			return x.Lines > y.Lines
+		}
+		return languages[i] < languages[j]
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Language\tFiles\tLines\tSymbols\t")
+	for _, language := range languages {
+		stats := b.Stats.Languages[language]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t\n", language, stats.Files, stats.Lines, stats.Symbols)
+	}
+	tw.Flush()
+}
+
+// writeBundleTree draws the directory tree of a bundle, directories first,
+// with the owners of files by path relative to the root
+func writeBundleTree(w io.Writer, dir *bundle.Dir, path, indent string, owners map[string][]string) {
+	if indent == "" {
+		fmt.Fprintf(w, "%s/\n", dir.Name)
+	}
+
+	count := len(dir.Dirs) + len(dir.Files)
+	i := 0
+	branch := func() (string, string) {
+		i++
+		if i == count {
+			return indent + "└── ", indent + "    "
+		}
+		return indent + "├── ", indent + "│   "
+	}
+	for _, sub := range dir.Dirs {
+		line, next := branch()
+		fmt.Fprintf(w, "%s%s/\n", line, sub.Name)
+		writeBundleTree(w, sub, path+sub.Name+"/", next, owners)
+	}
+	for _, name := range dir.Files {
+		line, _ := branch()
+		if fileOwners := owners[path+name]; len(fileOwners) > 0 {
+			fmt.Fprintf(w, "%s%s  (%s)\n", line, name, strings.Join(fileOwners, " "))
+			continue
+		}
+		fmt.Fprintf(w, "%s%s\n", line, name)
+	}
+}
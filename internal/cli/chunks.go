@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sourceradar/outline/internal/chunk"
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// runChunks splits a file, or every supported file below a directory, into
+// chunks aligned to symbol boundaries and prints them as JSON lines
+func runChunks(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline chunks [--chunk-tokens <n>] [--language <lang>] <file|dir>")
+	}
+
+	path := args[0]
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("file not found: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	write := func(path, language string, content []byte, symbols []outline.SymbolInfo) error {
+		for _, c := range chunk.Split(path, language, content, symbols, opts.ChunkTokens) {
+			if err := encoder.Encode(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !fileInfo.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading file: %v", err)
+		}
+		content, _ = outline.Decode(content)
+		language, err := detectLanguage(path, content, opts)
+		if err != nil {
+			return err
+		}
+		// Languages without symbols, such as HTML, are split at line
+		// boundaries only
+		symbols, _ := outline.ExtractSymbolsWithOptions(content, language, opts.Outline)
+		return write(path, language, content, symbols)
+	}
+
+	failures := 0
+	err = scanner.Scan(context.Background(), path, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true, Content: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		return write(result.Path, result.Language, result.Content, result.Symbols)
+	})
+	if err != nil {
+		return err
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to chunk %d file(s)", failures)
+	}
+	return nil
+}
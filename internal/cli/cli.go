@@ -1,56 +1,384 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/sourceradar/outline/internal/codeowners"
 	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/internal/scanner"
 	"github.com/sourceradar/outline/pkg/outline"
 )
 
+// Options holds the CLI settings derived from command-line flags
+type Options struct {
+	// Language overrides language detection when set
+	Language string
+	// Outline is how files are outlined: their level of detail, filters
+	// and limits
+	Outline outline.Options
+	// Recursive outlines every supported file below a directory argument
+	Recursive bool
+	// NoIgnore outlines the files .gitignore and .ignore files exclude too
+	NoIgnore bool
+	// Exclude lists gitignore-style patterns of files left out of directory
+	// walks, relative to the directory walked
+	Exclude []string
+	// Jobs is the number of files parsed concurrently in directories, or
+	// GOMAXPROCS when zero
+	Jobs int
+	// Header is the template of the line written above each file when
+	// outlining several, such as DefaultHeader; empty writes none
+	Header string
+	// RelativeTo is the directory the paths of output are made relative
+	// to, when set, rather than being written as given
+	RelativeTo string
+	// MaxFileSize is the size in bytes of the largest file directory walks
+	// outline, larger ones being skipped; zero means no limit
+	MaxFileSize int64
+	// Verbose adds the detected encoding of each file to its outline
+	Verbose bool
+	// Format is how files are written: text, the outline, or one of the
+	// structured formats of the symbol tree such as json
+	Format string
+	// Color is when the tree format is colored: auto, when standard output
+	// is a terminal, always or never
+	Color string
+	// NoPager writes output longer than the terminal straight to it rather
+	// than through the pager
+	NoPager bool
+	// Stats prints the line and symbol counts of each file instead of its
+	// outline, and their totals over several files
+	Stats bool
+	// WithMetrics appends the complexity and size of every function
+	WithMetrics bool
+	// WithTodos appends the TODO-style markers found in comments
+	WithTodos bool
+	// Merge consolidates declarations spread across files when outlining a
+	// directory
+	Merge bool
+	// FailFast stops runs over several files at the first file that can't
+	// be outlined, rather than outlining the others and reporting it at the
+	// end
+	FailFast bool
+	// Watch outlines the file or directory again every time it changes
+	Watch bool
+	// Events prints newline-delimited JSON events describing the symbols
+	// each change added, removed or changed, instead of outlines, in watch
+	// mode
+	Events bool
+	// HTTP is the address the serve command listens on
+	HTTP string
+	// Root is the directory the serve command confines the paths of
+	// requests to; the current directory when empty
+	Root string
+	// Base is the git revision the github command compares the public API
+	// with; API changes are not reported when unset
+	Base string
+	// MaxLines and MaxComplexity are the limits over which the github
+	// command reports a function as oversized; zero disables a limit
+	MaxLines      int
+	MaxComplexity int
+	// Out is the file outlines are written to instead of stdout, or with -r
+	// the directory each file's outline is written to. It is also the
+	// directory the docs command writes the site to, or the file the bundle,
+	// cscope and lsif commands write to; each has its own default when empty
+	Out string
+	// DB is the symbol index the index and query commands use
+	DB string
+	// Tags is the tags file the daemon command maintains instead of an index
+	Tags string
+	// ChunkTokens is the size the chunks command aims for
+	ChunkTokens int
+	// Regex matches the pattern of the search command as a regular
+	// expression rather than a substring
+	Regex bool
+}
+
+// commands are modes selected by the first argument in place of a file
+var commands = map[string]func(args []string, opts Options) error{
+	"apidiff":      runAPIDiff,
+	"bundle":       runBundle,
+	"chunks":       runChunks,
+	"cscope":       runCscope,
+	"daemon":       runDaemon,
+	"detect":       runDetect,
+	"doc-coverage": runDocCoverage,
+	"docs":         runDocs,
+	"github":       runGitHub,
+	"gtags":        runGTags,
+	"index":        runIndex,
+	"languages":    runLanguages,
+	"lsif":         runLSIF,
+	"query":        runQuery,
+	"refs":         runRefs,
+	"search":       runSearch,
+	"serve":        runServe,
+	"todos":        runTodos,
+	"untested":     runUntested,
+	"view":         runView,
+}
+
+// IsCommand reports whether name selects a mode rather than naming a file
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// RunCommand runs the mode selected by name on the remaining arguments
+func RunCommand(name string, args []string, opts Options) error {
+	command, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	return command(args, opts)
+}
+
 // Run executes the CLI application
-func Run(args []string, languageOverride string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: outline [--language <lang>] <file>")
+func Run(args []string, opts Options) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: outline [--language <lang>] [-r] <file|dir>...")
+	}
+	if opts.Out == "" {
+		if opts.NoPager || opts.Watch || !isTerminal(os.Stdout) {
+			return run(args, opts, os.Stdout)
+		}
+		return runPaged(args, opts)
+	}
+	if opts.Watch {
+		return fmt.Errorf("--output cannot be combined with --watch")
+	}
+	// A directory is written as a directory of outlines, one per file,
+	// unless it is merged into one
+	if fileInfo, err := os.Stat(args[0]); err == nil && fileInfo.IsDir() && opts.Recursive && len(args) == 1 && !opts.Merge && !opts.Stats {
+		return runToDirectory(args[0], opts)
+	}
+	return runToFile(args, opts)
+}
+
+// run outlines the paths given to w
+func run(args []string, opts Options, w io.Writer) error {
+	structured := opts.Format != "" && opts.Format != "text"
+	if opts.Stats {
+		if structured || opts.Watch || opts.Merge {
+			return fmt.Errorf("--stats cannot be combined with --format, --watch or --merge")
+		}
+		return runStats(w, args, opts)
+	}
+	if structured && (opts.Watch || opts.Merge) {
+		return fmt.Errorf("--format %s cannot be combined with --watch or --merge", opts.Format)
+	}
+	if len(args) > 1 {
+		if opts.Watch || opts.Merge {
+			return fmt.Errorf("--watch and --merge take a single file or directory")
+		}
+		if opts.Events {
+			return fmt.Errorf("--events requires --watch")
+		}
+		if structured {
+			return runFormatted(w, args, opts)
+		}
+		return runFiles(w, args, opts)
 	}
 
 	filePath := args[0]
+	// Watching a directory follows every file below it, -r or not
+	if opts.Watch {
+		opts.Recursive = true
+	}
 
 	// Check if file exists
-	fileInfo, err := os.Stat(filePath)
+	fileInfo, err := checkPath(filePath, opts)
 	if err != nil {
-		return fmt.Errorf("file not found: %v", err)
+		return err
+	}
+	if opts.Watch {
+		if isURL(filePath) {
+			return fmt.Errorf("--watch cannot follow a URL")
+		}
+		return runWatch(filePath, opts)
+	}
+	if opts.Events {
+		return fmt.Errorf("--events requires --watch")
+	}
+	if structured {
+		return runFormatted(w, args, opts)
 	}
 	if fileInfo.IsDir() {
-		return fmt.Errorf("expected a file, got directory")
+		if opts.Merge {
+			return runMerged(w, filePath, opts)
+		}
+		return runRecursive(w, filePath, opts)
 	}
+	return writeFileOutline(w, filePath, opts)
+}
 
+// checkPath returns the file info of a path given on the command line,
+// which must be a file or a URL, or a directory with -r
+func checkPath(path string, opts Options) (os.FileInfo, error) {
+	if isURL(path) {
+		return remoteFileInfo{url: path}, nil
+	}
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("file not found: %v", err)
+	}
+	if fileInfo.IsDir() && !opts.Recursive {
+		return nil, fmt.Errorf("expected a file, got directory (use -r to outline a directory)")
+	}
+	return fileInfo, nil
+}
+
+// runFiles outlines several files, and with -r directories, one after the
+// other with a header naming each file, as for a directory. A file that
+// can't be outlined is reported at the end and the others are still
+// outlined, unless --fail-fast stops at it.
+func runFiles(w io.Writer, paths []string, opts Options) error {
+	failures := &runFailures{failFast: opts.FailFast}
+	for _, path := range paths {
+		fileInfo, err := checkPath(path, opts)
+		if err == nil && fileInfo.IsDir() {
+			err = outlineDirectory(w, path, opts, failures)
+		} else if err == nil {
+			// The outline is buffered so that a failure doesn't leave a
+			// header without an outline
+			var b bytes.Buffer
+			if err = writeFileOutline(&b, path, opts); err == nil {
+				writeHeader(w, path, opts)
+				fmt.Fprintf(w, "%s\n", b.String())
+				failures.ok()
+			}
+		}
+		if errors.Is(err, errFailFast) {
+			break
+		}
+		if err != nil && failures.fail(path, err) != nil {
+			break
+		}
+	}
+	return failures.err()
+}
+
+// writeFileOutline writes the language and outline of a file, followed by
+// its metrics and markers when asked for
+func writeFileOutline(w io.Writer, filePath string, opts Options) error {
 	// Read file content
-	content, err := os.ReadFile(filePath)
+	content, err := readSource(filePath)
 	if err != nil {
 		return fmt.Errorf("error reading file: %v", err)
 	}
+	content, encoding := outline.Decode(content)
 
-	// Determine language
-	var language string
-	if languageOverride != "" {
-		language = languageOverride
-	} else {
-		var ok bool
-		language, ok = detector.DetectLanguage(filePath)
-		if !ok {
-			supportedExts := strings.Join(detector.SupportedExtensions(), ", ")
-			return fmt.Errorf("unsupported file extension. Supported extensions: %s\nOr use --language flag to override", supportedExts)
-		}
+	language, err := detectLanguage(filePath, content, opts)
+	if err != nil {
+		return err
 	}
 
 	// Extract outline
-	result, err := outline.ExtractOutline(content, language)
+	result, err := outline.ExtractOutlineWithOptions(content, language, opts.Outline)
 	if err != nil {
 		return fmt.Errorf("error extracting outline: %v", err)
 	}
 
-	fmt.Printf("Language: %s\n\n%s", language, result)
+	fmt.Fprintf(w, "Language: %s\n", language)
+	if opts.Verbose {
+		fmt.Fprintf(w, "Encoding: %s\n", encoding)
+	}
+	fmt.Fprintf(w, "\n%s", result)
+
+	if opts.WithMetrics {
+		symbols, err := outline.ExtractSymbolsWithOptions(content, language, opts.Outline)
+		if err != nil {
+			return fmt.Errorf("error computing metrics: %v", err)
+		}
+		fmt.Fprintln(w)
+		if err := writeMetrics(w, symbols); err != nil {
+			return err
+		}
+	}
+
+	if opts.WithTodos {
+		annotations, err := outline.ExtractAnnotations(content, language)
+		if err != nil {
+			return fmt.Errorf("error extracting annotations: %v", err)
+		}
+		fmt.Fprintln(w)
+		return writeAnnotations(w, "", annotations)
+	}
 	return nil
 }
+
+// detectLanguage returns the language of opts when set, and otherwise the
+// language detected for the file
+func detectLanguage(filePath string, content []byte, opts Options) (string, error) {
+	if opts.Language != "" {
+		return opts.Language, nil
+	}
+
+	name := filePath
+	var language string
+	var ok bool
+	if isURL(filePath) {
+		name = urlPath(filePath)
+		language, ok = detector.DetectName(name, content)
+	} else {
+		language, ok = detector.Detect(filePath, content)
+	}
+	if !ok {
+		if known, found := detector.IdentifyFilename(filePath); found {
+			return "", fmt.Errorf("%s is a %s file, which is not supported", filepath.Base(filePath), known)
+		}
+		supportedExts := strings.Join(detector.SupportedExtensions(), ", ")
+		return "", fmt.Errorf("could not detect language. Supported extensions: %s\nOr use --language flag to override", supportedExts)
+	}
+	if detector.IsHeader(name) {
+		language = outline.HeaderLanguage(language, opts.Outline)
+	}
+	return language, nil
+}
+
+// runRecursive outlines every supported file below dir, with its owners
+// when the project has a CODEOWNERS file
+func runRecursive(w io.Writer, dir string, opts Options) error {
+	failures := &runFailures{failFast: opts.FailFast}
+	if err := outlineDirectory(w, dir, opts, failures); err != nil && !errors.Is(err, errFailFast) {
+		return err
+	}
+	return failures.err()
+}
+
+// outlineDirectory outlines every supported file below dir for runRecursive
+// and runFiles, recording the files that can't be outlined in failures
+func outlineDirectory(w io.Writer, dir string, opts Options, failures *runFailures) error {
+	owners, err := codeowners.Find(dir)
+	if err != nil {
+		return err
+	}
+
+	return scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: opts.WithMetrics, Annotations: opts.WithTodos}, func(result scanner.Result) error {
+		if result.Err != nil {
+			return failures.fail(result.Path, result.Err)
+		}
+		failures.ok()
+		writeHeader(w, result.Path, opts)
+		return writeScanned(w, result, owners.Owners(result.Path), opts)
+	})
+}
+
+// noteSkipped tells that a file of a directory walk was left out, and why
+func noteSkipped(path, reason string) {
+	fmt.Fprintf(os.Stderr, "Skipped %s: %s\n", path, reason)
+}
+
+// writeOwners prints the owners line of a file or directory, if it has any
+func writeOwners(w io.Writer, owners []string) {
+	if len(owners) > 0 {
+		fmt.Fprintf(w, "Owners: %s\n", strings.Join(owners, " "))
+	}
+}
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// completionShells are the shells the completion command writes scripts
+// for, keyed to their writer
+var completionShells = map[string]func(w io.Writer, flags []completionFlag, commands []string){
+	"bash":       writeBashCompletion,
+	"fish":       writeFishCompletion,
+	"powershell": writePowerShellCompletion,
+	"zsh":        writeZshCompletion,
+}
+
+// fileFlags are the flags whose value is a path
+var fileFlags = map[string]bool{
+	"config": true,
+	"db":     true,
+	"o":      true,
+	"out":    true,
+	"output": true,
+	"tags":   true,
+}
+
+// completionFlag is a command-line flag as completion scripts offer it
+type completionFlag struct {
+	// name is the flag spelled as it is typed, with its dashes
+	name  string
+	usage string
+	// takesValue is set for flags that are not booleans; values lists the
+	// values they accept when there are few, and file is set when the
+	// value is a path
+	takesValue bool
+	values     []string
+	file       bool
+}
+
+func init() {
+	// Registered here rather than in commands, whose names it completes
+	commands["completion"] = runCompletion
+}
+
+// runCompletion writes the completion script of a shell to stdout
+func runCompletion(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline completion bash|zsh|fish|powershell")
+	}
+	write, ok := completionShells[args[0]]
+	if !ok {
+		return fmt.Errorf("unsupported shell: %s (expected bash, zsh, fish or powershell)", args[0])
+	}
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	write(os.Stdout, completionFlags(flag.CommandLine), names)
+	return nil
+}
+
+// completionFlags lists the flags of a flag set, sorted by name, with the
+// values of those that take one of a few
+func completionFlags(flags *flag.FlagSet) []completionFlag {
+	var result []completionFlag
+	flags.VisitAll(func(f *flag.Flag) {
+		cf := completionFlag{name: "--" + f.Name, usage: f.Usage, takesValue: true, file: fileFlags[f.Name]}
+		if len(f.Name) == 1 {
+			cf.name = "-" + f.Name
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			cf.takesValue = false
+		}
+		switch f.Name {
+		case "language":
+			cf.values = detector.GetLanguageNames()
+		case "header-language":
+			cf.values = []string{"c", "cpp", "objc"}
+		case "format":
+			cf.values = Formats()
+		case "detail":
+			cf.values = outline.DetailNames()
+		case "positions":
+			cf.values = outline.PositionNames()
+		case "sort":
+			cf.values = outline.SortNames()
+		case "kinds":
+			cf.values = outline.SymbolKinds()
+		case "color":
+			cf.values = ColorModes
+		}
+		result = append(result, cf)
+	})
+	return result
+}
+
+func writeBashCompletion(w io.Writer, flags []completionFlag, commands []string) {
+	var names, files, values []string
+	for _, f := range flags {
+		names = append(names, f.name)
+		switch {
+		case f.file:
+			files = append(files, f.name)
+		case len(f.values) > 0:
+			values = append(values, fmt.Sprintf("        %s)\n            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n            return\n            ;;\n", f.name, strings.Join(f.values, " ")))
+		case f.takesValue:
+			values = append(values, fmt.Sprintf("        %s)\n            return\n            ;;\n", f.name))
+		}
+	}
+
+	fmt.Fprintf(w, `# bash completion for outline
+# Load with: source <(outline completion bash)
+
+_outline() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "$prev" in
+%s        %s)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return
+            ;;
+    esac
+
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W %q -- "$cur"))
+        return
+    fi
+    if [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W %q -- "$cur"))
+    fi
+    COMPREPLY+=($(compgen -f -- "$cur"))
+}
+
+complete -o filenames -F _outline outline
+`, strings.Join(values, ""), strings.Join(files, "|"), strings.Join(names, " "), strings.Join(commands, " "))
+}
+
+func writeZshCompletion(w io.Writer, flags []completionFlag, commands []string) {
+	fmt.Fprint(w, `#compdef outline
+# zsh completion for outline
+# Load with: source <(outline completion zsh)
+
+_outline() {
+    _arguments \
+`)
+	for _, f := range flags {
+		spec := f.name + "[" + zshEscape(f.usage) + "]"
+		switch {
+		case f.file:
+			spec += ":file:_files"
+		case len(f.values) > 0:
+			spec += ":value:(" + strings.Join(f.values, " ") + ")"
+		case f.takesValue:
+			spec += ":value: "
+		}
+		fmt.Fprintf(w, "        '%s' \\\n", spec)
+	}
+	fmt.Fprintf(w, `        '1: :{_alternative "commands:command:(%s)" "files:file:_files"}' \
+        '*:file:_files'
+}
+
+if [ "$funcstack[1]" = "_outline" ]; then
+    _outline "$@"
+else
+    compdef _outline outline
+fi
+`, strings.Join(commands, " "))
+}
+
+// zshEscape escapes a flag description for a single-quoted _arguments spec
+func zshEscape(s string) string {
+	return strings.NewReplacer(`'`, `'\''`, `[`, `\[`, `]`, `\]`, `:`, `\:`).Replace(s)
+}
+
+func writeFishCompletion(w io.Writer, flags []completionFlag, commands []string) {
+	fmt.Fprintf(w, `# fish completion for outline
+# Load with: outline completion fish | source
+
+complete -c outline -n __fish_use_subcommand -a %s
+`, fishQuote(strings.Join(commands, " ")))
+	for _, f := range flags {
+		option := "-l " + strings.TrimPrefix(f.name, "--")
+		if !strings.HasPrefix(f.name, "--") {
+			option = "-s " + strings.TrimPrefix(f.name, "-")
+		}
+		line := "complete -c outline " + option + " -d " + fishQuote(f.usage)
+		switch {
+		case f.file:
+			line += " -r -F"
+		case len(f.values) > 0:
+			line += " -x -a " + fishQuote(strings.Join(f.values, " "))
+		case f.takesValue:
+			line += " -x"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// fishQuote quotes s as a single-quoted fish string
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func writePowerShellCompletion(w io.Writer, flags []completionFlag, commands []string) {
+	var names, cases []string
+	for _, f := range flags {
+		names = append(names, psQuote(f.name))
+		if len(f.values) > 0 {
+			var values []string
+			for _, value := range f.values {
+				values = append(values, psQuote(value))
+			}
+			cases = append(cases, fmt.Sprintf("        %s { @(%s) }\n", psQuote(f.name), strings.Join(values, ", ")))
+		}
+	}
+	var quoted []string
+	for _, command := range commands {
+		quoted = append(quoted, psQuote(command))
+	}
+
+	fmt.Fprintf(w, `# PowerShell completion for outline
+# Load with: outline completion powershell | Out-String | Invoke-Expression
+
+Register-ArgumentCompleter -Native -CommandName outline -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })
+    $position = if ($wordToComplete) { $words.Count - 1 } else { $words.Count }
+    $prev = $words[$position - 1]
+
+    $candidates = switch ($prev) {
+%s        default { $null }
+    }
+    if ($null -eq $candidates) {
+        if ($wordToComplete -like '-*') {
+            $candidates = @(%s)
+        } elseif ($position -eq 1) {
+            $candidates = @(%s)
+        } else {
+            return
+        }
+    }
+
+    # Returning nothing falls back to completing paths
+    $candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`, strings.Join(cases, ""), strings.Join(names, ", "), strings.Join(quoted, ", "))
+}
+
+// psQuote quotes s as a single-quoted PowerShell string
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
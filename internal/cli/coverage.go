@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// runDocCoverage reports the documentation coverage of a file, or of every
+// supported file below a directory followed by the project total
+func runDocCoverage(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline doc-coverage [--language <lang>] <file|dir>")
+	}
+
+	path := args[0]
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("file not found: %v", err)
+	}
+
+	if !fileInfo.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading file: %v", err)
+		}
+		content, _ = outline.Decode(content)
+		language, err := detectLanguage(path, content, opts)
+		if err != nil {
+			return err
+		}
+		coverage, err := outline.ExtractCoverage(content, language)
+		if err != nil {
+			return fmt.Errorf("error extracting coverage: %v", err)
+		}
+		return writeCoverage(os.Stdout, path, coverage)
+	}
+
+	total := &outline.Coverage{}
+	files, failures := 0, 0
+	err = scanner.Scan(context.Background(), path, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Coverage: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		if result.Coverage == nil {
+			return nil
+		}
+
+		files++
+		total.Add(result.Coverage)
+		if err := writeCoverage(os.Stdout, result.Path, result.Coverage); err != nil {
+			return err
+		}
+		fmt.Println()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Total over %d files:\n", files)
+	writeCoverageSummary(os.Stdout, total)
+
+	if failures > 0 {
+		return fmt.Errorf("failed to analyze %d file(s)", failures)
+	}
+	return nil
+}
+
+// writeCoverage prints the coverage summary of one file followed by every
+// exported symbol and whether it is documented
+func writeCoverage(w io.Writer, path string, coverage *outline.Coverage) error {
+	fmt.Fprintf(w, "File: %s\n", path)
+	writeCoverageSummary(w, coverage)
+	if len(coverage.Symbols) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "line\tdocumented\tsymbol")
+	for _, symbol := range coverage.Symbols {
+		documented := "no"
+		if symbol.Documented {
+			documented = "yes"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s %s\n", symbol.Line, documented, symbol.Type, symbol.Name)
+	}
+	return tw.Flush()
+}
+
+// writeCoverageSummary prints the documented share of exported symbols and
+// the comment-to-code ratio
+func writeCoverageSummary(w io.Writer, coverage *outline.Coverage) {
+	documented, exported := coverage.Documented(), len(coverage.Symbols)
+	percent := 100.0
+	if exported > 0 {
+		percent = 100 * float64(documented) / float64(exported)
+	}
+	fmt.Fprintf(w, "Documented: %d/%d exported symbols (%.1f%%)\n", documented, exported, percent)
+
+	ratio := 0.0
+	if coverage.CodeLines > 0 {
+		ratio = float64(coverage.CommentLines) / float64(coverage.CodeLines)
+	}
+	fmt.Fprintf(w, "Comments: %d comment lines, %d code lines (ratio %.2f)\n", coverage.CommentLines, coverage.CodeLines, ratio)
+}
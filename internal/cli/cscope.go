@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sourceradar/outline/internal/cscope"
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+// runCscope writes the symbol definitions of every supported file below a
+// directory as a cscope cross-reference, for cscope -d to read without
+// rebuilding it
+func runCscope(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline cscope [--language <lang>] <dir> [-o <file>]")
+	}
+
+	dir := args[0]
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory not found: %v", err)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("expected a directory, got a file")
+	}
+
+	out := opts.Out
+	if out == "" {
+		out = "cscope.out"
+	}
+
+	w := cscope.NewWriter(dir)
+	files, failures := 0, 0
+	err = scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true, Content: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		files++
+		w.AddFile(result.Path, result.Content, result.Symbols)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := w.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %d file(s) to %s\n", files, out)
+	if failures > 0 {
+		return fmt.Errorf("failed to outline %d file(s)", failures)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/sourceradar/outline/internal/index"
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/internal/tags"
+	"github.com/sourceradar/outline/internal/watch"
+)
+
+// runDaemon keeps a SQLite index, or a tags file with --tags, up to date with
+// a directory until interrupted. Only the files that change are parsed again.
+func runDaemon(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline daemon [--db <file> | --tags <file>] [--language <lang>] <dir>")
+	}
+
+	dir := args[0]
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory not found: %v", err)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("expected a directory, got a file")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := opts.DB
+	var update func(ctx context.Context, paths []string) error
+	if opts.Tags != "" {
+		out = opts.Tags
+		update = tagsUpdater(opts)
+	} else {
+		db, err := index.Open(opts.DB)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		update = indexUpdater(db, opts)
+	}
+
+	if err := update(ctx, []string{dir}); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Watching %s, keeping %s up to date (Ctrl-C to stop)\n", dir, out)
+
+	// The output changing must not trigger another update
+	outPrefix, err := filepath.Abs(out)
+	if err != nil {
+		return err
+	}
+	return watch.Watch(ctx, dir, watch.DefaultDebounce, func(paths []string) error {
+		var changed []string
+		for _, path := range paths {
+			abs, err := filepath.Abs(path)
+			if err != nil || strings.HasPrefix(abs, outPrefix) || strings.HasPrefix(filepath.Base(path), ".tags-") {
+				continue
+			}
+			// Paths are sorted, so a new directory comes before the files
+			// created in it, which updating the directory covers
+			if n := len(changed); n > 0 && strings.HasPrefix(path, changed[n-1]+string(filepath.Separator)) {
+				continue
+			}
+			changed = append(changed, path)
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		return update(ctx, changed)
+	})
+}
+
+// indexUpdater re-indexes changed paths, skipping files whose size and
+// modification time did not change
+func indexUpdater(db *index.DB, opts Options) func(ctx context.Context, paths []string) error {
+	return func(ctx context.Context, paths []string) error {
+		var total index.Stats
+		for _, path := range paths {
+			stats, err := db.Update(ctx, path, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped}, func(path string, err error) {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+			})
+			if err != nil {
+				return err
+			}
+			total.Indexed += stats.Indexed
+			total.Removed += stats.Removed
+		}
+		if total.Indexed > 0 || total.Removed > 0 {
+			fmt.Fprintf(os.Stderr, "Indexed %d file(s), removed %d\n", total.Indexed, total.Removed)
+		}
+		return nil
+	}
+}
+
+// tagsUpdater re-tags changed paths and rewrites the tags file when any tag
+// changed
+func tagsUpdater(opts Options) func(ctx context.Context, paths []string) error {
+	set := tags.NewSet()
+	return func(ctx context.Context, paths []string) error {
+		indexed, removed := 0, 0
+		for _, path := range paths {
+			removed += set.Remove(path)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			err := scanner.Scan(ctx, path, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true}, func(result scanner.Result) error {
+				if result.Err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+					return nil
+				}
+				set.Replace(result.Path, result.Symbols)
+				indexed++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if indexed == 0 && removed == 0 {
+			return nil
+		}
+		if err := set.Save(opts.Tags); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Tagged %d file(s), %d in %s\n", indexed, set.Len(), opts.Tags)
+		return nil
+	}
+}
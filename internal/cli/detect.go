@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// runDetect prints the language each file given is detected as, from its
+// name and then from its content, such as a #! line. The name of the
+// language is printed alone for one file, for scripts, and after the path
+// of each file for several.
+func runDetect(args []string, opts Options) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: outline detect <file>...")
+	}
+
+	failures := 0
+	for _, path := range args {
+		language, err := detectFile(path, opts)
+		if err != nil && len(args) == 1 {
+			return err
+		}
+		if err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+			continue
+		}
+		if len(args) == 1 {
+			fmt.Println(language)
+		} else {
+			fmt.Printf("%s: %s\n", path, language)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to detect the language of %d of %d file(s)", failures, len(args))
+	}
+	return nil
+}
+
+// detectFile returns the language of one file
+func detectFile(path string, opts Options) (string, error) {
+	if _, err := checkPath(path, opts); err != nil {
+		return "", err
+	}
+	content, err := readSource(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading file: %v", err)
+	}
+	content, _ = outline.Decode(content)
+	return detectLanguage(path, content, opts)
+}
@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// diagramKinds are the symbol kinds drawn as types in class diagrams
+var diagramKinds = map[string]bool{
+	"class": true, "interface": true, "struct": true, "enum": true,
+	"protocol": true, "mixin": true, "record": true, "trait": true,
+}
+
+// diagramType is a type of a class diagram with the members it declares
+// and the types it extends and implements
+type diagramType struct {
+	id         string
+	kind       string
+	language   string
+	members    []outline.SymbolInfo
+	extends    []string
+	implements []string
+}
+
+// typeDiagram collects the types declared in the files outlined. Nested
+// types are drawn as types of their own, and Go methods are drawn as
+// members of their receiver.
+type typeDiagram struct {
+	types     []*diagramType
+	byID      map[string]*diagramType
+	goMethods map[string][]outline.SymbolInfo
+}
+
+func newTypeDiagram() *typeDiagram {
+	return &typeDiagram{byID: map[string]*diagramType{}, goMethods: map[string][]outline.SymbolInfo{}}
+}
+
+// add collects the types of a file
+func (d *typeDiagram) add(f outlinedFile) {
+	d.addSymbols(f.Symbols, f.Language)
+}
+
+func (d *typeDiagram) addSymbols(symbols []outline.SymbolInfo, language string) {
+	for _, symbol := range symbols {
+		if receiver := outline.MethodReceiver(symbol); receiver != "" {
+			d.goMethods[receiver] = append(d.goMethods[receiver], symbol)
+			continue
+		}
+		if !diagramKinds[symbol.Type] {
+			continue
+		}
+
+		id := diagramID(symbol.Name)
+		if d.byID[id] == nil {
+			t := &diagramType{id: id, kind: symbol.Type, language: language}
+			extends, implements := outline.Supertypes(symbol, language)
+			for _, name := range extends {
+				t.extends = append(t.extends, diagramID(name))
+			}
+			for _, name := range implements {
+				t.implements = append(t.implements, diagramID(name))
+			}
+			for _, member := range symbol.Children {
+				if !diagramKinds[member.Type] && !(language == "go" && member.Type == "field" && isGoEmbedding(member)) {
+					t.members = append(t.members, member)
+				}
+			}
+			d.types = append(d.types, t)
+			d.byID[id] = t
+		}
+		d.addSymbols(symbol.Children, language)
+	}
+}
+
+// resolve attaches the Go methods collected to their receiver, once every
+// file was added
+func (d *typeDiagram) resolve() {
+	for _, t := range d.types {
+		if t.language == "go" {
+			t.members = append(t.members, d.goMethods[t.id]...)
+			delete(d.goMethods, t.id)
+		}
+	}
+}
+
+// isGoEmbedding reports whether a struct field embeds a type, which is
+// drawn as an inheritance edge rather than a member
+func isGoEmbedding(field outline.SymbolInfo) bool {
+	signature := field.Signature.String()
+	return !strings.ContainsAny(signature, " \t") && strings.HasSuffix(signature, field.Name)
+}
+
+var nonIdentifier = regexp.MustCompile(`\W+`)
+
+// diagramID is the identifier of a type in a diagram: its name without
+// package qualifier, with anything a diagram identifier can't hold replaced
+func diagramID(name string) string {
+	if i := strings.LastIndexAny(name, ".:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return nonIdentifier.ReplaceAllString(name, "_")
+}
+
+// memberParameters returns the parameter list of a function member as its
+// signature declares it, or () when the signature has none
+func memberParameters(member outline.SymbolInfo) string {
+	signature := member.Signature.String()
+	i := strings.Index(signature, member.Name)
+	if i < 0 {
+		return "()"
+	}
+	rest := signature[i+len(member.Name):]
+	open := strings.IndexByte(rest, '(')
+	if open < 0 || strings.TrimSpace(stripAngles(rest[:open])) != "" {
+		return "()"
+	}
+	depth := 0
+	for j := open; j < len(rest); j++ {
+		switch rest[j] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return strings.Join(strings.Fields(rest[open:j+1]), " ")
+			}
+		}
+	}
+	return "()"
+}
+
+// stripAngles drops a type parameter list such as <T>
+func stripAngles(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">") {
+		return ""
+	}
+	return s
+}
+
+// isFunctionMember reports whether a member is drawn as an operation
+// rather than an attribute
+func isFunctionMember(member outline.SymbolInfo) bool {
+	switch member.Type {
+	case "method", "function", "constructor", "initializer", "destructor":
+		return true
+	}
+	return false
+}
+
+// mermaidWriter writes the types of every file outlined as one Mermaid
+// class diagram
+type mermaidWriter struct {
+	w       io.Writer
+	diagram *typeDiagram
+}
+
+func newMermaidWriter(w io.Writer, dir bool) symbolWriter {
+	return &mermaidWriter{w: w, diagram: newTypeDiagram()}
+}
+
+func (m *mermaidWriter) file(f outlinedFile) error {
+	m.diagram.add(f)
+	return nil
+}
+
+// mermaidUnsafe are the characters Mermaid reads as syntax in a member;
+// angle brackets become the tildes Mermaid writes generics with
+var mermaidUnsafe = strings.NewReplacer("<", "~", ">", "~", "{", "", "}", "", "\"", "'")
+
+func (m *mermaidWriter) close() error {
+	m.diagram.resolve()
+
+	var b strings.Builder
+	b.WriteString("classDiagram\n")
+	for _, t := range m.diagram.types {
+		if t.kind == "class" && len(t.members) == 0 {
+			fmt.Fprintf(&b, "    class %s\n", t.id)
+			continue
+		}
+		fmt.Fprintf(&b, "    class %s {\n", t.id)
+		switch t.kind {
+		case "class":
+		case "enum":
+			b.WriteString("        <<enumeration>>\n")
+		default:
+			fmt.Fprintf(&b, "        <<%s>>\n", t.kind)
+		}
+		for _, member := range t.members {
+			visibility := "-"
+			if member.IsPublic {
+				visibility = "+"
+			}
+			text := member.Name
+			if isFunctionMember(member) {
+				text += memberParameters(member)
+			}
+			fmt.Fprintf(&b, "        %s%s\n", visibility, mermaidUnsafe.Replace(text))
+		}
+		b.WriteString("    }\n")
+	}
+	for _, t := range m.diagram.types {
+		for _, parent := range t.extends {
+			fmt.Fprintf(&b, "    %s <|-- %s\n", parent, t.id)
+		}
+		for _, parent := range t.implements {
+			fmt.Fprintf(&b, "    %s <|.. %s\n", parent, t.id)
+		}
+	}
+	_, err := io.WriteString(m.w, b.String())
+	return err
+}
+
+// plantUMLWriter writes the types of every file outlined as one PlantUML
+// class diagram, with the full signature of every member
+type plantUMLWriter struct {
+	w       io.Writer
+	diagram *typeDiagram
+}
+
+func newPlantUMLWriter(w io.Writer, dir bool) symbolWriter {
+	return &plantUMLWriter{w: w, diagram: newTypeDiagram()}
+}
+
+func (p *plantUMLWriter) file(f outlinedFile) error {
+	p.diagram.add(f)
+	return nil
+}
+
+func (p *plantUMLWriter) close() error {
+	p.diagram.resolve()
+
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	for _, t := range p.diagram.types {
+		switch t.kind {
+		case "interface", "enum":
+			fmt.Fprintf(&b, "%s %s", t.kind, t.id)
+		case "class":
+			fmt.Fprintf(&b, "class %s", t.id)
+		default:
+			fmt.Fprintf(&b, "class %s <<%s>>", t.id, t.kind)
+		}
+		if len(t.members) == 0 {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(" {\n")
+		for _, member := range t.members {
+			text := strings.TrimRight(strings.Join(strings.Fields(member.Signature.String()), " "), " {;")
+			if text == "" || member.Type == "enum_member" {
+				text = member.Name
+			}
+			if member.Type == "enum_member" {
+				fmt.Fprintf(&b, "  %s\n", text)
+				continue
+			}
+			visibility := "-"
+			if member.IsPublic {
+				visibility = "+"
+			}
+			// PlantUML reads a member with parentheses as a method, so
+			// fields declared with a function type are marked as such
+			if !isFunctionMember(member) && strings.Contains(text, "(") {
+				text = "{field} " + text
+			}
+			fmt.Fprintf(&b, "  %s %s\n", visibility, text)
+		}
+		b.WriteString("}\n")
+	}
+	for _, t := range p.diagram.types {
+		for _, parent := range t.extends {
+			fmt.Fprintf(&b, "%s <|-- %s\n", parent, t.id)
+		}
+		for _, parent := range t.implements {
+			fmt.Fprintf(&b, "%s <|.. %s\n", parent, t.id)
+		}
+	}
+	b.WriteString("@enduml\n")
+	_, err := io.WriteString(p.w, b.String())
+	return err
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sourceradar/outline/internal/docgen"
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/internal/testlink"
+)
+
+// runDocs generates a documentation site for every supported file below a
+// directory
+func runDocs(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline docs [--language <lang>] <dir> --out <dir>")
+	}
+
+	dir := args[0]
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory not found: %v", err)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("expected a directory, got a file")
+	}
+
+	title := filepath.Base(dir)
+	if abs, err := filepath.Abs(dir); err == nil {
+		title = filepath.Base(abs)
+	}
+	site := docgen.NewSite(title)
+
+	failures := 0
+	err = scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		if testlink.IsTestFile(result.Path, result.Language) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, result.Path)
+		if err != nil {
+			rel = result.Path
+		}
+		site.Add(rel, result.Language, result.Symbols)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	out := opts.Out
+	if out == "" {
+		out = "site"
+	}
+	if err := site.Write(out); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d package page(s) to %s\n", len(site.Packages()), out)
+
+	if failures > 0 {
+		return fmt.Errorf("failed to document %d file(s)", failures)
+	}
+	return nil
+}
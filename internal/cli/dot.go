@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// dotWriter writes the files outlined as one GraphViz graph: the packages,
+// which are the directories of the files, contain their types and
+// functions, types contain their methods and nested types, and dashed and
+// dotted edges lead from a type to the types it extends and implements
+type dotWriter struct {
+	w        io.Writer
+	nodes    strings.Builder
+	edges    strings.Builder
+	packages map[string]bool
+	// types maps the names of the types declared to their node, for the
+	// inheritance edges, which name types without saying where they are
+	types     map[string]string
+	typeNodes map[string]bool
+	goMethods []dotMethod
+	inherits  []dotInheritance
+}
+
+// dotMethod is a Go method, contained by its receiver once every file of
+// the package was read
+type dotMethod struct {
+	pkg, receiver string
+	symbol        outline.SymbolInfo
+}
+
+type dotInheritance struct {
+	from, to   string
+	implements bool
+}
+
+func newDOTWriter(w io.Writer, dir bool) symbolWriter {
+	return &dotWriter{w: w, packages: map[string]bool{}, types: map[string]string{}, typeNodes: map[string]bool{}}
+}
+
+func (d *dotWriter) file(f outlinedFile) error {
+	pkg := filepath.ToSlash(filepath.Dir(f.Path))
+	if !d.packages[pkg] {
+		d.packages[pkg] = true
+		fmt.Fprintf(&d.nodes, "  %s [label=%s, shape=folder];\n", strconv.Quote(pkg), strconv.Quote(pkg))
+	}
+	d.addSymbols(pkg, pkg, f.Symbols, f.Language)
+	return nil
+}
+
+func (d *dotWriter) addSymbols(pkg, parent string, symbols []outline.SymbolInfo, language string) {
+	for _, symbol := range symbols {
+		if receiver := outline.MethodReceiver(symbol); receiver != "" {
+			d.goMethods = append(d.goMethods, dotMethod{pkg: pkg, receiver: receiver, symbol: symbol})
+			continue
+		}
+
+		id := parent + "." + symbol.Name
+		switch {
+		case diagramKinds[symbol.Type]:
+			label := symbol.Name
+			if symbol.Type != "class" {
+				label += "\n«" + symbol.Type + "»"
+			}
+			fmt.Fprintf(&d.nodes, "  %s [label=%s, shape=box];\n", strconv.Quote(id), strconv.Quote(label))
+			fmt.Fprintf(&d.edges, "  %s -> %s;\n", strconv.Quote(parent), strconv.Quote(id))
+			d.typeNodes[id] = true
+			if _, ok := d.types[symbol.Name]; !ok {
+				d.types[symbol.Name] = id
+			}
+
+			extends, implements := outline.Supertypes(symbol, language)
+			for _, name := range extends {
+				d.inherits = append(d.inherits, dotInheritance{from: id, to: name})
+			}
+			for _, name := range implements {
+				d.inherits = append(d.inherits, dotInheritance{from: id, to: name, implements: true})
+			}
+			d.addSymbols(pkg, id, symbol.Children, language)
+		case isFunctionMember(symbol):
+			d.addFunction(parent, id, symbol)
+		}
+	}
+}
+
+func (d *dotWriter) addFunction(parent, id string, symbol outline.SymbolInfo) {
+	fmt.Fprintf(&d.nodes, "  %s [label=%s, shape=ellipse];\n", strconv.Quote(id), strconv.Quote(symbol.Name+"()"))
+	fmt.Fprintf(&d.edges, "  %s -> %s;\n", strconv.Quote(parent), strconv.Quote(id))
+}
+
+func (d *dotWriter) close() error {
+	for _, method := range d.goMethods {
+		parent := method.pkg + "." + method.receiver
+		if !d.typeNodes[parent] {
+			parent = method.pkg
+		}
+		d.addFunction(parent, parent+"."+method.symbol.Name, method.symbol)
+	}
+
+	external := map[string]bool{}
+	for _, edge := range d.inherits {
+		name := edge.to
+		if i := strings.LastIndexAny(name, ".:"); i >= 0 {
+			name = name[i+1:]
+		}
+		to, ok := d.types[name]
+		if !ok {
+			// Types declared outside the files outlined, such as those of
+			// the standard library, are drawn once by the name used
+			to = edge.to
+			if !external[to] {
+				external[to] = true
+				fmt.Fprintf(&d.nodes, "  %s [shape=box, style=dashed];\n", strconv.Quote(to))
+			}
+		}
+		style := "dashed"
+		if edge.implements {
+			style = "dotted"
+		}
+		fmt.Fprintf(&d.edges, "  %s -> %s [style=%s, arrowhead=empty];\n", strconv.Quote(edge.from), strconv.Quote(to), style)
+	}
+
+	_, err := fmt.Fprintf(d.w, "digraph outline {\n  rankdir=LR;\n  node [fontname=\"Helvetica\"];\n%s%s}\n", d.nodes.String(), d.edges.String())
+	return err
+}
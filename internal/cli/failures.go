@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Exit statuses of runs over several files. Other errors, such as a file
+// argument that doesn't exist, exit with status 1, and invalid flags with 2.
+const (
+	// ExitPartial is the status when some of the files could not be
+	// outlined and the others were
+	ExitPartial = 3
+	// ExitAllFailed is the status when none of the files could be outlined
+	ExitAllFailed = 4
+	// ExitNoFiles is the status when no supported file was found to outline
+	ExitNoFiles = 5
+)
+
+// ExitError is an error the CLI exits with a particular status for
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// errFailFast stops a run at its first failure with --fail-fast
+var errFailFast = errors.New("stopped at the first failure")
+
+// fileFailure is a file that could not be outlined
+type fileFailure struct {
+	path string
+	err  error
+}
+
+// runFailures collects the files of a run over several files that could
+// not be outlined, so that the others are still outlined and the failures
+// are reported together at the end
+type runFailures struct {
+	failFast bool
+	outlined int
+	failed   []fileFailure
+}
+
+// ok records a file that was outlined
+func (r *runFailures) ok() {
+	r.outlined++
+}
+
+// fail records a file that could not be outlined. It returns errFailFast
+// with --fail-fast, for the caller to stop the run with.
+func (r *runFailures) fail(path string, err error) error {
+	r.failed = append(r.failed, fileFailure{path: path, err: err})
+	if r.failFast {
+		return errFailFast
+	}
+	return nil
+}
+
+// err returns the error the run ends with: nil when every file was
+// outlined, and otherwise an ExitError listing the failures
+func (r *runFailures) err() error {
+	if len(r.failed) == 0 {
+		if r.outlined == 0 {
+			return &ExitError{Code: ExitNoFiles, Err: errors.New("no supported files found")}
+		}
+		return nil
+	}
+
+	var b strings.Builder
+	if r.failFast {
+		b.WriteString("stopped at the first failure (--fail-fast)")
+	} else {
+		fmt.Fprintf(&b, "failed to outline %d of %d file(s)", len(r.failed), len(r.failed)+r.outlined)
+	}
+	for _, failure := range r.failed {
+		fmt.Fprintf(&b, "\n  %s: %v", failure.path, failure.err)
+	}
+	code := ExitPartial
+	if r.outlined == 0 {
+		code = ExitAllFailed
+	}
+	return &ExitError{Code: code, Err: errors.New(b.String())}
+}
@@ -0,0 +1,331 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// outlinedFile is the symbol tree of one file, as the structured output
+// formats render it
+type outlinedFile struct {
+	Path        string               `json:"file"`
+	Language    string               `json:"language"`
+	Symbols     []outline.SymbolInfo `json:"symbols"`
+	Annotations []outline.Annotation `json:"annotations,omitempty"`
+}
+
+// symbolWriter renders the symbol trees of the files outlined in place of
+// their text outlines. close is called once every file was written.
+type symbolWriter interface {
+	file(f outlinedFile) error
+	close() error
+}
+
+// symbolFormats are the --format values other than text, keyed to a
+// constructor of their writer; dir tells whether a directory is outlined
+var symbolFormats = map[string]func(w io.Writer, dir bool) symbolWriter{
+	"csv":      newCSVWriter,
+	"dot":      newDOTWriter,
+	"flat":     newFlatWriter,
+	"json":     newJSONWriter,
+	"jsonl":    newJSONLinesWriter,
+	"markdown": newMarkdownWriter,
+	"mermaid":  newMermaidWriter,
+	"plantuml": newPlantUMLWriter,
+	"tree":     newTreeWriter,
+	"tsv":      newTSVWriter,
+}
+
+// Formats returns the values --format accepts
+func Formats() []string {
+	names := []string{"text"}
+	for name := range symbolFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// runFormatted writes the symbols of the files given, and of every
+// supported file below the directories given, in a structured format.
+// Several paths are written like a directory, as one document.
+func runFormatted(out io.Writer, paths []string, opts Options) error {
+	newWriter, ok := symbolFormats[opts.Format]
+	if !ok {
+		return fmt.Errorf("unknown format: %s", opts.Format)
+	}
+	dir := len(paths) > 1
+	if !dir {
+		fileInfo, err := checkPath(paths[0], opts)
+		if err != nil {
+			return err
+		}
+		dir = fileInfo.IsDir()
+	}
+	w := newWriter(out, dir)
+	if tree, ok := w.(*treeWriter); ok {
+		tree.color = useColor(opts.Color, out)
+	}
+
+	failures := &runFailures{failFast: opts.FailFast}
+	for _, path := range paths {
+		fileInfo, err := checkPath(path, opts)
+		if err == nil && fileInfo.IsDir() {
+			err = formatDirectory(w, path, opts, failures)
+		} else if err == nil {
+			var file outlinedFile
+			if file, err = formatFile(path, opts); err == nil {
+				if err = w.file(file); err == nil {
+					failures.ok()
+				}
+			}
+		}
+		if errors.Is(err, errFailFast) {
+			break
+		}
+		if err != nil && len(paths) == 1 {
+			return err
+		}
+		if err != nil && failures.fail(path, err) != nil {
+			break
+		}
+	}
+	if err := w.close(); err != nil {
+		return err
+	}
+	return failures.err()
+}
+
+// formatFile reads the symbols of a file, and its markers with
+// --with-todos
+func formatFile(path string, opts Options) (outlinedFile, error) {
+	content, err := readSource(path)
+	if err != nil {
+		return outlinedFile{}, fmt.Errorf("error reading file: %v", err)
+	}
+	content, _ = outline.Decode(content)
+	language, err := detectLanguage(path, content, opts)
+	if err != nil {
+		return outlinedFile{}, err
+	}
+
+	file := outlinedFile{Path: displayPath(path, opts), Language: language}
+	if file.Symbols, err = outline.ExtractSymbolsWithOptions(content, language, opts.Outline); err != nil {
+		return outlinedFile{}, fmt.Errorf("error extracting symbols: %v", err)
+	}
+	if opts.WithTodos {
+		if file.Annotations, err = outline.ExtractAnnotations(content, language); err != nil {
+			return outlinedFile{}, fmt.Errorf("error extracting annotations: %v", err)
+		}
+	}
+	return file, nil
+}
+
+// formatDirectory writes every supported file below dir, recording the
+// files that can't be outlined in failures
+func formatDirectory(w symbolWriter, dir string, opts Options, failures *runFailures) error {
+	return scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true, Annotations: opts.WithTodos}, func(result scanner.Result) error {
+		if result.Err != nil {
+			return failures.fail(result.Path, result.Err)
+		}
+		failures.ok()
+		return w.file(outlinedFile{Path: displayPath(result.Path, opts), Language: result.Language, Symbols: result.Symbols, Annotations: result.Annotations})
+	})
+}
+
+// jsonWriter writes a file as one JSON object, and a directory as an array
+// of them
+type jsonWriter struct {
+	w     io.Writer
+	dir   bool
+	files []outlinedFile
+}
+
+func newJSONWriter(w io.Writer, dir bool) symbolWriter {
+	return &jsonWriter{w: w, dir: dir, files: []outlinedFile{}}
+}
+
+func (j *jsonWriter) file(f outlinedFile) error {
+	// Languages without a symbol tree, such as HTML, have no symbols
+	// rather than null ones
+	if f.Symbols == nil {
+		f.Symbols = []outline.SymbolInfo{}
+	}
+	j.files = append(j.files, f)
+	return nil
+}
+
+func (j *jsonWriter) close() error {
+	encoder := json.NewEncoder(j.w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if j.dir {
+		return encoder.Encode(j.files)
+	}
+	if len(j.files) == 0 {
+		return nil
+	}
+	return encoder.Encode(j.files[0])
+}
+
+// symbolLine is a line of the jsonl format: a symbol without its members,
+// which have lines of their own naming it as their container
+type symbolLine struct {
+	File      string `json:"file"`
+	Language  string `json:"language"`
+	Container string `json:"container,omitempty"`
+	outline.SymbolInfo
+}
+
+// jsonLinesWriter writes one JSON object per symbol and line as each file
+// is outlined, rather than one document once the scan is over
+type jsonLinesWriter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLinesWriter(w io.Writer, dir bool) symbolWriter {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &jsonLinesWriter{encoder: encoder}
+}
+
+func (j *jsonLinesWriter) file(f outlinedFile) error {
+	return j.writeSymbols(f, "", f.Symbols)
+}
+
+func (j *jsonLinesWriter) writeSymbols(f outlinedFile, container string, symbols []outline.SymbolInfo) error {
+	for _, symbol := range symbols {
+		line := symbolLine{File: f.Path, Language: f.Language, Container: container, SymbolInfo: symbol}
+		line.Children = nil
+		if err := j.encoder.Encode(line); err != nil {
+			return err
+		}
+
+		name := symbol.Name
+		if container != "" {
+			name = container + "." + name
+		}
+		if err := j.writeSymbols(f, name, symbol.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *jsonLinesWriter) close() error {
+	return nil
+}
+
+// flatWriter writes one line per symbol in the path:line: form of compiler
+// errors and grep -n, so that the output can be filtered with grep and
+// opened at the symbol by editors and fzf
+type flatWriter struct {
+	w io.Writer
+}
+
+func newFlatWriter(w io.Writer, dir bool) symbolWriter {
+	return &flatWriter{w: w}
+}
+
+func (fw *flatWriter) file(f outlinedFile) error {
+	var b strings.Builder
+	writeFlatSymbols(&b, f.Path, "", f.Symbols)
+	_, err := io.WriteString(fw.w, b.String())
+	return err
+}
+
+// writeFlatSymbols writes a line per symbol and member, members named after
+// their container as in Shape.area
+func writeFlatSymbols(b *strings.Builder, path, prefix string, symbols []outline.SymbolInfo) {
+	for _, symbol := range symbols {
+		name := prefix + symbol.Name
+		fmt.Fprintf(b, "%s:%d: %s %s", path, symbol.Line, symbol.Type, name)
+		if signature := strings.Join(strings.Fields(symbol.Signature.String()), " "); signature != "" {
+			fmt.Fprintf(b, " %s", signature)
+		}
+		b.WriteString("\n")
+		writeFlatSymbols(b, path, name+".", symbol.Children)
+	}
+}
+
+func (fw *flatWriter) close() error {
+	return nil
+}
+
+// markdownWriter writes each file as a section with a heading per
+// top-level symbol, its signature in a code block and its documentation,
+// and its members as nested bullets
+type markdownWriter struct {
+	w io.Writer
+}
+
+func newMarkdownWriter(w io.Writer, dir bool) symbolWriter {
+	return &markdownWriter{w: w}
+}
+
+func (m *markdownWriter) file(f outlinedFile) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", f.Path)
+	if len(f.Symbols) == 0 {
+		b.WriteString("No symbols.\n\n")
+	}
+	for _, symbol := range f.Symbols {
+		fmt.Fprintf(&b, "### %s (line %d)\n\n```%s\n%s\n```\n\n", symbol.Name, symbol.Line, f.Language, symbol.Signature.String())
+		if doc := symbol.Documentation.String(); doc != "" {
+			fmt.Fprintf(&b, "%s\n\n", doc)
+		}
+		if len(symbol.Children) > 0 {
+			writeMarkdownMembers(&b, symbol.Children, "")
+			b.WriteString("\n")
+		}
+	}
+	if len(f.Annotations) > 0 {
+		b.WriteString("### Annotations\n\n")
+		for _, annotation := range f.Annotations {
+			fmt.Fprintf(&b, "- **%s** %s (line %d)\n", annotation.Kind, annotation.Text, annotation.Line)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(m.w, b.String())
+	return err
+}
+
+func (m *markdownWriter) close() error {
+	return nil
+}
+
+// writeMarkdownMembers lists members as bullets with their signature as
+// inline code, nesting their own members below them
+func writeMarkdownMembers(b *strings.Builder, members []outline.SymbolInfo, indent string) {
+	for _, member := range members {
+		fmt.Fprintf(b, "%s- %s (line %d)\n", indent, inlineCode(member.Signature.String()), member.Line)
+		if doc := member.Documentation.String(); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				fmt.Fprintf(b, "%s\n", strings.TrimRight(indent+"  "+line, " "))
+			}
+		}
+		writeMarkdownMembers(b, member.Children, indent+"  ")
+	}
+}
+
+// inlineCode renders s on one line as Markdown code, delimited by enough
+// backticks not to be closed by the ones it contains
+func inlineCode(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sourceradar/outline/internal/apidiff"
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// githubReport collects the findings of a structural review
+type githubReport struct {
+	failures     int
+	syntaxErrors int
+	oversized    []oversizedFunction
+	apiChanges   []fileChange
+}
+
+type oversizedFunction struct {
+	path   string
+	name   string
+	symbol outline.SymbolInfo
+}
+
+type fileChange struct {
+	path string
+	apidiff.Change
+}
+
+// runGitHub reviews a file, or every supported file below a directory, and
+// prints the findings as GitHub Actions workflow commands so they show up as
+// annotations on the pull request. A Markdown summary is appended to the job
+// summary when the workflow provides one.
+func runGitHub(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline github [--base <ref>] [--max-lines <n>] [--max-complexity <n>] <file|dir>")
+	}
+
+	path := args[0]
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("file not found: %v", err)
+	}
+	if opts.Base != "" {
+		dir := path
+		if !fileInfo.IsDir() {
+			dir = filepath.Dir(path)
+		}
+		if err := apidiff.VerifyRevision(dir, opts.Base); err != nil {
+			return err
+		}
+	}
+
+	var report githubReport
+	w := os.Stdout
+	err = scanner.Scan(context.Background(), path, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true, SyntaxErrors: true}, func(result scanner.Result) error {
+		file := filepath.ToSlash(result.Path)
+		if result.Err != nil {
+			report.failures++
+			writeWorkflowCommand(w, "error", map[string]string{"file": file, "title": "Parse failure"}, result.Err.Error())
+			return nil
+		}
+
+		for _, syntaxErr := range result.SyntaxErrors {
+			report.syntaxErrors++
+			writeWorkflowCommand(w, "warning", map[string]string{
+				"file":      file,
+				"line":      fmt.Sprint(syntaxErr.Line),
+				"col":       fmt.Sprint(syntaxErr.Column),
+				"endLine":   fmt.Sprint(syntaxErr.EndLine),
+				"endColumn": fmt.Sprint(syntaxErr.EndColumn),
+				"title":     "Syntax error",
+			}, syntaxErr.Message+"; the outline may be missing declarations here")
+		}
+
+		oversized := findOversized(file, result.Symbols, "", opts)
+		report.oversized = append(report.oversized, oversized...)
+		for _, fn := range oversized {
+			writeWorkflowCommand(w, "warning", map[string]string{
+				"file":    file,
+				"line":    fmt.Sprint(fn.symbol.Line),
+				"endLine": fmt.Sprint(fn.symbol.EndLine),
+				"title":   "Oversized function",
+			}, fmt.Sprintf("%s has %d lines and a cyclomatic complexity of %d (limits: %d lines, complexity %d)", fn.name, fn.symbol.Lines, fn.symbol.Complexity, opts.MaxLines, opts.MaxComplexity))
+		}
+
+		if opts.Base == "" {
+			return nil
+		}
+		changes, err := compareWithRevision(opts.Base, result, opts)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			report.apiChanges = append(report.apiChanges, fileChange{path: file, Change: change})
+			level, properties := "notice", map[string]string{"file": file}
+			if change.Breaking {
+				level = "warning"
+			}
+			if change.Kind != apidiff.Removed {
+				properties["line"] = fmt.Sprint(change.Line)
+			}
+			message := change.New
+			switch change.Kind {
+			case apidiff.Removed:
+				message = change.Old
+			case apidiff.Changed:
+				message = fmt.Sprintf("%s (was: %s)", change.New, change.Old)
+			}
+			properties["title"] = "Public API " + string(change.Kind)
+			if change.Breaking {
+				properties["title"] = "Breaking API change"
+			}
+			writeWorkflowCommand(w, level, properties, message)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if summaryPath := os.Getenv("GITHUB_STEP_SUMMARY"); summaryPath != "" {
+		summary, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("error opening job summary: %v", err)
+		}
+		defer summary.Close()
+		if err := report.writeSummary(summary, opts); err != nil {
+			return fmt.Errorf("error writing job summary: %v", err)
+		}
+	}
+
+	if report.failures > 0 {
+		return fmt.Errorf("failed to analyze %d file(s)", report.failures)
+	}
+	return nil
+}
+
+// findOversized lists the functions over the configured size or
+// complexity, qualified with the names of their enclosing types
+func findOversized(path string, symbols []outline.SymbolInfo, scope string, opts Options) []oversizedFunction {
+	var oversized []oversizedFunction
+	for _, symbol := range symbols {
+		name := scope + symbol.Name
+		tooLong := opts.MaxLines > 0 && symbol.Lines > opts.MaxLines
+		tooComplex := opts.MaxComplexity > 0 && symbol.Complexity > opts.MaxComplexity
+		if tooLong || tooComplex {
+			oversized = append(oversized, oversizedFunction{path: path, name: name, symbol: symbol})
+		}
+		oversized = append(oversized, findOversized(path, symbol.Children, name+".", opts)...)
+	}
+	return oversized
+}
+
+// compareWithRevision lists the public API changes of a file since the git
+// revision ref. Files that did not exist then only add API.
+func compareWithRevision(ref string, result scanner.Result, opts Options) ([]apidiff.Change, error) {
+	content, ok, err := apidiff.ReadRevision(ref, result.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var old []outline.SymbolInfo
+	if ok {
+		// A file that no longer parses as its language had no API to speak of
+		old, _ = outline.ExtractSymbolsWithOptions(content, result.Language, opts.Outline)
+	}
+	return apidiff.Compare(result.Language, old, result.Symbols), nil
+}
+
+// writeWorkflowCommand prints a GitHub Actions workflow command such as
+// ::warning file=a.go,line=3::message
+func writeWorkflowCommand(w io.Writer, command string, properties map[string]string, message string) {
+	var props []string
+	for _, key := range []string{"file", "line", "col", "endLine", "endColumn", "title"} {
+		if value, ok := properties[key]; ok {
+			props = append(props, key+"="+escapeProperty(value))
+		}
+	}
+	fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(props, ","), escapeData(message))
+}
+
+var (
+	dataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	propertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func escapeData(s string) string {
+	return dataEscaper.Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return propertyEscaper.Replace(s)
+}
+
+// writeSummary renders the findings as the Markdown of a job summary
+func (r *githubReport) writeSummary(w io.Writer, opts Options) error {
+	fmt.Fprintln(w, "## Outline structural review")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Check | Findings |")
+	fmt.Fprintln(w, "|-------|----------|")
+	fmt.Fprintf(w, "| Parse failures | %d |\n", r.failures)
+	fmt.Fprintf(w, "| Syntax errors | %d |\n", r.syntaxErrors)
+	fmt.Fprintf(w, "| Oversized functions | %d |\n", len(r.oversized))
+	if opts.Base != "" {
+		fmt.Fprintf(w, "| Public API changes since `%s` | %d |\n", opts.Base, len(r.apiChanges))
+	}
+
+	if len(r.apiChanges) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Public API changes")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| File | Change | Symbol | Signature |")
+		fmt.Fprintln(w, "|------|--------|--------|-----------|")
+		for _, change := range r.apiChanges {
+			signature := change.New
+			if change.Kind == apidiff.Removed {
+				signature = change.Old
+			}
+			kind := string(change.Kind)
+			if change.Breaking {
+				kind += " (breaking)"
+			}
+			fmt.Fprintf(w, "| %s | %s | `%s` | `%s` |\n", change.path, kind, change.Name, escapeTableCell(signature))
+		}
+	}
+
+	if len(r.oversized) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Oversized functions")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Location | Function | Lines | Complexity |")
+		fmt.Fprintln(w, "|----------|----------|-------|------------|")
+		for _, fn := range r.oversized {
+			fmt.Fprintf(w, "| %s:%d | `%s` | %d | %d |\n", fn.path, fn.symbol.Line, fn.name, fn.symbol.Lines, fn.symbol.Complexity)
+		}
+	}
+
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// escapeTableCell keeps a signature on one row of a Markdown table
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "|", `\|`)
+}
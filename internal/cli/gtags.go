@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sourceradar/outline/internal/gtags"
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// runGTags writes the definitions and references of every supported file
+// below a directory as GNU Global tag records. Given -, it reads file paths
+// from standard input instead and answers each with its records and a
+// terminator line, as the ctags parser gtags runs does.
+func runGTags(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline gtags [--language <lang>] <dir|->")
+	}
+	if args[0] == "-" {
+		return filterGTags(os.Stdin, os.Stdout, opts)
+	}
+
+	dir := args[0]
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory not found: %v", err)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("expected a directory, got a file")
+	}
+
+	failures := 0
+	err = scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Content: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		tags, err := gtags.FromFile(result.Path, result.Content, result.Language)
+		if err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, err)
+			return nil
+		}
+		return gtags.Write(os.Stdout, tags)
+	})
+	if err != nil {
+		return err
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to outline %d file(s)", failures)
+	}
+	return nil
+}
+
+// filterGTags answers every path read from r with its tag records and the
+// terminator. Files that can't be read or outlined get no records, so that
+// gtags goes on with the next one; the errors are reported on stderr.
+func filterGTags(r io.Reader, w io.Writer, opts Options) error {
+	lines := bufio.NewScanner(r)
+	out := bufio.NewWriter(w)
+	for lines.Scan() {
+		path := lines.Text()
+		if tags, err := fileGTags(path, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+		} else if err := gtags.Write(out, tags); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, gtags.Terminator)
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+	return lines.Err()
+}
+
+func fileGTags(path string, opts Options) ([]gtags.Tag, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content, _ = outline.Decode(content)
+	language, err := detectLanguage(path, content, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gtags.FromFile(path, content, language)
+}
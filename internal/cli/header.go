@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultHeader is the --header template of the files of a run over several
+// files
+const DefaultHeader = "File: {path}"
+
+// writeHeader writes the header of a file from the --header template, in
+// which {path} is the path of the file, {name} its base name and {dir} its
+// directory. An empty template writes no header.
+func writeHeader(w io.Writer, path string, opts Options) {
+	if opts.Header == "" {
+		return
+	}
+	path = displayPath(path, opts)
+	replacer := strings.NewReplacer("{path}", path, "{name}", filepath.Base(path), "{dir}", filepath.Dir(path))
+	fmt.Fprintln(w, replacer.Replace(opts.Header))
+}
+
+// displayPath returns path as output shows it: relative to --relative-to
+// when set, so that it doesn't depend on the directory outline was run in,
+// and otherwise as given
+func displayPath(path string, opts Options) string {
+	if opts.RelativeTo == "" || isURL(path) {
+		return path
+	}
+	base, err := filepath.Abs(opts.RelativeTo)
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sourceradar/outline/internal/index"
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+// runIndex writes the symbols of every supported file below a directory to
+// a SQLite index, re-parsing only the files that changed since the last run
+func runIndex(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline index [--db <file>] [--language <lang>] <dir>")
+	}
+
+	dir := args[0]
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory not found: %v", err)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("expected a directory, got a file")
+	}
+
+	db, err := index.Open(opts.DB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stats, err := db.Update(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped}, func(path string, err error) {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Indexed %d file(s) into %s (%d unchanged, %d removed)\n", stats.Indexed, opts.DB, stats.Unchanged, stats.Removed)
+	if stats.Failed > 0 {
+		return fmt.Errorf("failed to index %d file(s)", stats.Failed)
+	}
+	return nil
+}
+
+// runQuery searches a symbol index and prints one match per line in
+// file:line:column form
+func runQuery(args []string, opts Options) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: outline query [--db <file>] <terms>")
+	}
+	if _, err := os.Stat(opts.DB); err != nil {
+		return fmt.Errorf("index not found (create it with outline index): %v", err)
+	}
+
+	db, err := index.Open(opts.DB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	matches, err := db.Search(strings.Join(args, " "), index.DefaultLimit)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no symbols match %q", strings.Join(args, " "))
+	}
+
+	for _, m := range matches {
+		fmt.Printf("%s:%d:%d: %s %s: %s\n", m.Path, m.Line, m.Column, m.Kind, m.FQN, strings.Join(strings.Fields(m.Signature), " "))
+	}
+	return nil
+}
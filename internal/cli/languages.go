@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// runLanguages prints the languages compiled into this build, with the
+// extensions they are detected from and the features of their extractor
+func runLanguages(args []string, opts Options) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: outline languages")
+	}
+
+	infos := detector.SupportedLanguages()
+	names := make([]string, 0, len(infos))
+	for name := range infos {
+		// Languages left out of slim builds are detected but not outlined
+		if outline.IsSupported(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "LANGUAGE\tEXTENSIONS\tDOCS\tNESTING\tVISIBILITY")
+	for _, name := range names {
+		features, _ := outline.LanguageFeatures(name)
+		extensions := strings.Join(infos[name].Extensions, " ")
+		if extensions == "" {
+			extensions = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, extensions, yesNo(features.Docs), yesNo(features.Nesting), yesNo(features.Visibility))
+	}
+	return tw.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sourceradar/outline/internal/lsif"
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+// runLSIF writes the symbol definitions of every supported file below a
+// directory as an LSIF dump, for code intelligence tools to load
+func runLSIF(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline lsif [--language <lang>] <dir> [-o <file>]")
+	}
+
+	dir := args[0]
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory not found: %v", err)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("expected a directory, got a file")
+	}
+
+	out := opts.Out
+	if out == "" {
+		out = "dump.lsif"
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	buffered := bufio.NewWriter(f)
+
+	w, err := lsif.NewWriter(buffered, dir)
+	if err != nil {
+		return err
+	}
+
+	documents, failures := 0, 0
+	err = scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true, Content: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		documents++
+		return w.AddDocument(result.Path, result.Language, result.Content, result.Symbols)
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %d document(s) to %s\n", documents, out)
+	if failures > 0 {
+		return fmt.Errorf("failed to outline %d file(s)", failures)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sourceradar/outline/internal/codeowners"
+	"github.com/sourceradar/outline/internal/merge"
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+// runMerged outlines every supported file below dir as one consolidated
+// entry per logical symbol, listing every file each one is declared in
+func runMerged(w io.Writer, dir string, opts Options) error {
+	owners, err := codeowners.Find(dir)
+	if err != nil {
+		return err
+	}
+
+	merger := merge.NewMerger()
+	failures := &runFailures{failFast: opts.FailFast}
+	err = scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			return failures.fail(result.Path, result.Err)
+		}
+		failures.ok()
+		merger.Add(result.Path, result.Language, result.Symbols)
+		return nil
+	})
+	if errors.Is(err, errFailFast) {
+		return failures.err()
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, group := range merger.Groups() {
+		if len(group.Entries) == 0 {
+			continue
+		}
+		// Groups shared across files, such as the C/C++ global namespace,
+		// are not paths and have no owners
+		var groupOwners []string
+		if _, err := os.Stat(group.Name); err == nil {
+			groupOwners = owners.Owners(group.Name)
+		}
+		if err := writeGroup(w, group, groupOwners); err != nil {
+			return err
+		}
+	}
+
+	return failures.err()
+}
+
+func writeGroup(w io.Writer, group *merge.Group, owners []string) error {
+	if _, err := fmt.Fprintf(w, "%s (%s)\n", group.Name, group.Language); err != nil {
+		return err
+	}
+	writeOwners(w, owners)
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	writeEntries(w, group.Entries, 1)
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// writeEntries prints one line per entry with all of its locations, and
+// marks the locations that only declare the symbol or extend it
+func writeEntries(w io.Writer, entries []*merge.Entry, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, entry := range entries {
+		signature := entry.Signature
+		if signature == "" {
+			signature = entry.Type + " " + entry.Name
+		}
+
+		locations := make([]string, len(entry.Locations))
+		for i, loc := range entry.Locations {
+			locations[i] = loc.Path + ":" + strconv.Itoa(loc.Line)
+			switch {
+			case loc.Extension:
+				locations[i] += " (extension)"
+			case !loc.Definition:
+				locations[i] += " (declaration)"
+			}
+		}
+
+		fmt.Fprintf(w, "%s%s // %s\n", indent, signature, strings.Join(locations, ", "))
+		writeEntries(w, entry.Children, depth+1)
+	}
+}
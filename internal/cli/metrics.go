@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// functionMetrics is one row of the metrics table
+type functionMetrics struct {
+	name string
+	outline.SymbolInfo
+}
+
+// collectMetrics flattens the functions of a symbol tree, qualifying
+// members with the name of their enclosing type
+func collectMetrics(symbols []outline.SymbolInfo, prefix string, rows []functionMetrics) []functionMetrics {
+	for _, symbol := range symbols {
+		name := prefix + symbol.Name
+		if symbol.Complexity > 0 {
+			rows = append(rows, functionMetrics{name: name, SymbolInfo: symbol})
+		}
+		rows = collectMetrics(symbol.Children, name+".", rows)
+	}
+	return rows
+}
+
+// writeMetrics prints the complexity and size of every function in
+// symbols, followed by a summary line
+func writeMetrics(w io.Writer, symbols []outline.SymbolInfo) error {
+	rows := collectMetrics(symbols, "", nil)
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(w, "Metrics: no functions found")
+		return err
+	}
+
+	fmt.Fprintln(w, "Metrics:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "line\tcomplexity\tlines\t\tfunction")
+
+	total, worst := 0, rows[0]
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t\t%s\n", row.Line, row.Complexity, row.Lines, row.name)
+		total += row.Complexity
+		if row.Complexity > worst.Complexity {
+			worst = row
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	noun := "functions"
+	if len(rows) == 1 {
+		noun = "function"
+	}
+	_, err := fmt.Fprintf(w, "\n%d %s, average complexity %.1f, highest %d (%s)\n",
+		len(rows), noun, float64(total)/float64(len(rows)), worst.Complexity, worst.name)
+	return err
+}
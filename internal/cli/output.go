@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sourceradar/outline/internal/codeowners"
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+// outputFile is written to a temporary file next to its path, which it
+// replaces once complete, so that readers never see a partial output
+type outputFile struct {
+	*os.File
+	path    string
+	written int
+}
+
+// createOutput starts writing the file at path, creating its directory
+func createOutput(path string) (*outputFile, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return nil, err
+	}
+	return &outputFile{File: tmp, path: path}, nil
+}
+
+func (f *outputFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.written += n
+	return n, err
+}
+
+// commit replaces the file at the output path with what was written
+func (f *outputFile) commit() error {
+	if err := f.Chmod(0o644); err != nil {
+		f.abort()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// abort drops what was written, leaving the output path untouched
+func (f *outputFile) abort() {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// runToFile writes the outlines of the paths given to the --output file
+// rather than stdout. Files that can't be outlined are reported as usual,
+// and the output is kept unless nothing could be written at all.
+func runToFile(args []string, opts Options) error {
+	f, err := createOutput(opts.Out)
+	if err != nil {
+		return fmt.Errorf("error writing output: %v", err)
+	}
+	runErr := run(args, opts, f)
+	if f.written == 0 && runErr != nil {
+		f.abort()
+		return runErr
+	}
+	if err := f.commit(); err != nil {
+		return fmt.Errorf("error writing output: %v", err)
+	}
+	return runErr
+}
+
+// formatExtensions are the extensions of the files --output writes for
+// each file of a directory, by format
+var formatExtensions = map[string]string{
+	"text": ".txt", "flat": ".txt", "tree": ".txt",
+	"csv": ".csv", "dot": ".dot", "json": ".json", "jsonl": ".jsonl",
+	"markdown": ".md", "mermaid": ".mmd", "plantuml": ".puml", "tsv": ".tsv",
+}
+
+// runToDirectory writes the outline of every supported file below dir to a
+// file of its own in the --output directory, at the file's path relative
+// to dir with the extension of the format added
+func runToDirectory(dir string, opts Options) error {
+	owners, err := codeowners.Find(dir)
+	if err != nil {
+		return err
+	}
+	format := opts.Format
+	if format == "" {
+		format = "text"
+	}
+
+	written, failures := 0, &runFailures{failFast: opts.FailFast}
+	err = scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true, Annotations: opts.WithTodos}, func(result scanner.Result) error {
+		if result.Err != nil {
+			return failures.fail(result.Path, result.Err)
+		}
+		rel, err := filepath.Rel(dir, result.Path)
+		if err != nil {
+			return err
+		}
+		f, err := createOutput(filepath.Join(opts.Out, rel+formatExtensions[format]))
+		if err != nil {
+			return fmt.Errorf("error writing output: %v", err)
+		}
+
+		if newWriter, ok := symbolFormats[format]; ok {
+			w := newWriter(f, false)
+			err = w.file(outlinedFile{Path: result.Path, Language: result.Language, Symbols: result.Symbols, Annotations: result.Annotations})
+			if err == nil {
+				err = w.close()
+			}
+		} else {
+			err = writeScanned(f, result, owners.Owners(result.Path), opts)
+		}
+		if err != nil {
+			f.abort()
+			return fmt.Errorf("error writing output: %v", err)
+		}
+		if err := f.commit(); err != nil {
+			return fmt.Errorf("error writing output: %v", err)
+		}
+		written++
+		failures.ok()
+		return nil
+	})
+	if err != nil && !errors.Is(err, errFailFast) {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %d file(s) to %s\n", written, opts.Out)
+	return failures.err()
+}
+
+// writeScanned writes the owners, language and outline of a scanned file,
+// followed by its metrics and markers when asked for
+func writeScanned(w io.Writer, result scanner.Result, owners []string, opts Options) error {
+	writeOwners(w, owners)
+	fmt.Fprintf(w, "Language: %s\n", result.Language)
+	if opts.Verbose {
+		fmt.Fprintf(w, "Encoding: %s\n", result.Encoding)
+	}
+	fmt.Fprintf(w, "\n%s\n", result.Outline)
+	if opts.WithMetrics && result.Symbols != nil {
+		if err := writeMetrics(w, result.Symbols); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+	if opts.WithTodos && len(result.Annotations) > 0 {
+		if err := writeAnnotations(w, "", result.Annotations); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
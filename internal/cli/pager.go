@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// runPaged outlines the paths given through the pager of the user, as git
+// does: OUTLINE_PAGER, PAGER or less. less is told to quit when the output
+// fits on one screen, so short outlines are printed as they would be
+// without a pager.
+func runPaged(args []string, opts Options) error {
+	// Without its pager, as when it isn't installed, output goes to the
+	// terminal. The shell running the pager starts either way, so the pager
+	// is looked up first.
+	pager := pagerCommand()
+	if pager == "" || !pagerInstalled(pager) {
+		return run(args, opts, os.Stdout)
+	}
+
+	cmd := shellCommand(pager)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	cmd.Env = pagerEnv()
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return run(args, opts, os.Stdout)
+	}
+	if err := cmd.Start(); err != nil {
+		return run(args, opts, os.Stdout)
+	}
+
+	// The colors the terminal would show are kept through the pager
+	if useColor(opts.Color, os.Stdout) {
+		opts.Color = "always"
+	} else {
+		opts.Color = "never"
+	}
+	runErr := run(args, opts, w)
+	w.Close()
+	waitErr := cmd.Wait()
+	// Quitting the pager before the end of the output is not an error, but
+	// a pager that failed has left the output unread
+	if runErr != nil && !errors.Is(runErr, syscall.EPIPE) {
+		return runErr
+	}
+	if waitErr != nil {
+		return fmt.Errorf("pager %s failed: %v", pager, waitErr)
+	}
+	return nil
+}
+
+// pagerCommand returns the pager command line to use, or "" when paging
+// is disabled by setting it empty or to cat
+func pagerCommand() string {
+	pager, ok := os.LookupEnv("OUTLINE_PAGER")
+	if !ok {
+		pager, ok = os.LookupEnv("PAGER")
+	}
+	if !ok {
+		pager = "less"
+	}
+	if pager == "cat" {
+		return ""
+	}
+	return pager
+}
+
+// pagerInstalled reports whether the program a pager command line starts
+// with can be found
+func pagerInstalled(pager string) bool {
+	fields := strings.Fields(pager)
+	if len(fields) == 0 {
+		return false
+	}
+	_, err := exec.LookPath(fields[0])
+	return err == nil
+}
+
+// shellCommand runs a command line through the shell, so that pagers can
+// be given with their arguments
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// pagerEnv is the environment of the pager: that of outline, with the
+// defaults git gives less and lv unless the user has their own
+func pagerEnv() []string {
+	env := os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		env = append(env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		env = append(env, "LV=-c")
+	}
+	return env
+}
+
+// isTerminal reports whether w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func TestPagerInstalled(t *testing.T) {
+	if !pagerInstalled("sh -c cat") {
+		t.Error("Expected sh to be found")
+	}
+	for _, pager := range []string{"outline-missing-pager -R", "  "} {
+		if pagerInstalled(pager) {
+			t.Errorf("Did not expect %q to be found", pager)
+		}
+	}
+}
+
+func TestRunPagedWithoutPager(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "greeter.cr")
+	if err := os.WriteFile(path, []byte("class Greeter\nend\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OUTLINE_PAGER", "outline-missing-pager -R")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := runPaged([]string{path}, Options{Outline: outline.DefaultOptions()})
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+
+	if runErr != nil {
+		t.Fatalf("runPaged failed: %v", runErr)
+	}
+	if !strings.Contains(string(out), "class Greeter") {
+		t.Errorf("Expected the outline on stdout without a pager, got:\n%s", out)
+	}
+}
+
+func TestRunPagedFailingPager(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "greeter.cr")
+	if err := os.WriteFile(path, []byte("class Greeter\nend\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OUTLINE_PAGER", "false")
+
+	if err := runPaged([]string{path}, Options{Outline: outline.DefaultOptions()}); err == nil {
+		t.Error("Expected an error for a pager that failed without reading the output")
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// topLevel stands for the enclosing symbol of references outside any
+// declaration
+const topLevel = "(top level)"
+
+// runRefs lists the identifiers spelled like a name in a file, or in every
+// supported file below a directory, grouped by the symbol they are in
+func runRefs(args []string, opts Options) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: outline refs [--language <lang>] <name> <file|dir>")
+	}
+
+	name, path := args[0], args[1]
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("file not found: %v", err)
+	}
+
+	var totals refTotals
+	if !fileInfo.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading file: %v", err)
+		}
+		content, _ = outline.Decode(content)
+		language, err := detectLanguage(path, content, opts)
+		if err != nil {
+			return err
+		}
+		references, err := outline.FindReferences(content, language, name)
+		if err != nil {
+			return fmt.Errorf("error finding references: %v", err)
+		}
+		if err := writeReferences(os.Stdout, path, references, &totals); err != nil {
+			return err
+		}
+		return totals.write(os.Stdout, name)
+	}
+
+	failures := 0
+	err = scanner.Scan(context.Background(), path, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, References: name}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		return writeReferences(os.Stdout, result.Path, result.References, &totals)
+	})
+	if err != nil {
+		return err
+	}
+	if err := totals.write(os.Stdout, name); err != nil {
+		return err
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to analyze %d file(s)", failures)
+	}
+	return nil
+}
+
+// refTotals counts the references written so far
+type refTotals struct {
+	references   int
+	declarations int
+	files        int
+	symbols      int
+}
+
+func (t refTotals) write(w io.Writer, name string) error {
+	if t.references == 0 {
+		_, err := fmt.Fprintf(w, "No references to %s found\n", name)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d reference(s) to %s, %d of them declarations, in %d symbol(s) across %d file(s)\n",
+		t.references, name, t.declarations, t.symbols, t.files)
+	return err
+}
+
+// writeReferences prints the references of a file under its path, grouped
+// by enclosing symbol in order of first appearance
+func writeReferences(w io.Writer, path string, references []outline.Reference, totals *refTotals) error {
+	if len(references) == 0 {
+		return nil
+	}
+
+	var order []string
+	groups := make(map[string][]outline.Reference)
+	for _, reference := range references {
+		symbol := reference.Symbol
+		if symbol == "" {
+			symbol = topLevel
+		}
+		if _, ok := groups[symbol]; !ok {
+			order = append(order, symbol)
+		}
+		groups[symbol] = append(groups[symbol], reference)
+	}
+
+	if _, err := fmt.Fprintln(w, path); err != nil {
+		return err
+	}
+	for _, symbol := range order {
+		fmt.Fprintf(w, "  %s\n", symbol)
+		for _, reference := range groups[symbol] {
+			declaration := ""
+			if reference.Declaration {
+				declaration = " (declaration)"
+				totals.declarations++
+			}
+			if _, err := fmt.Fprintf(w, "    %d:%d: %s%s\n", reference.Line, reference.Column, reference.Text, declaration); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Fprintln(w)
+
+	totals.references += len(references)
+	totals.symbols += len(order)
+	totals.files++
+	return nil
+}
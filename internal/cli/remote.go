@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxRemoteBytes bounds the content fetched for a URL given in place of a
+// file
+const maxRemoteBytes = 10 << 20
+
+// remoteClient fetches URLs given in place of files
+var remoteClient = &http.Client{Timeout: 30 * time.Second}
+
+// isURL reports whether a command-line argument is an http or https URL
+// rather than a path
+func isURL(arg string) bool {
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// readSource reads a file, or fetches the content of a URL
+func readSource(path string) ([]byte, error) {
+	if !isURL(path) {
+		return os.ReadFile(path)
+	}
+	resp, err := remoteClient.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	if resp.ContentLength > maxRemoteBytes {
+		return nil, fmt.Errorf("GET %s: %d bytes is over the %d MB limit", path, resp.ContentLength, maxRemoteBytes>>20)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxRemoteBytes {
+		return nil, fmt.Errorf("GET %s: content is over the %d MB limit", path, maxRemoteBytes>>20)
+	}
+	return content, nil
+}
+
+// urlPath returns the path of a URL, which its language is detected from
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+// remoteFileInfo stands for the file at a URL, which is checked only once
+// it is fetched
+type remoteFileInfo struct {
+	url string
+}
+
+func (f remoteFileInfo) Name() string       { return path.Base(urlPath(f.url)) }
+func (f remoteFileInfo) Size() int64        { return 0 }
+func (f remoteFileInfo) Mode() fs.FileMode  { return 0o444 }
+func (f remoteFileInfo) ModTime() time.Time { return time.Time{} }
+func (f remoteFileInfo) IsDir() bool        { return false }
+func (f remoteFileInfo) Sys() any           { return nil }
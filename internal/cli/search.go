@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// runSearch lists the symbols whose name matches a pattern in the files
+// given, and in every supported file below the directories given, as
+// path:line followed by their signature
+func runSearch(args []string, opts Options) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: outline search [--regex] <pattern> <file|dir>...")
+	}
+
+	pattern := args[0]
+	match, err := symbolMatcher(pattern, opts.Regex)
+	if err != nil {
+		return err
+	}
+
+	// Directories are always searched in full
+	opts.Recursive = true
+	hits, failures := 0, 0
+	for _, path := range args[1:] {
+		fileInfo, err := checkPath(path, opts)
+		if err == nil && fileInfo.IsDir() {
+			err = scanner.Scan(context.Background(), path, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true}, func(result scanner.Result) error {
+				if result.Err != nil {
+					failures++
+					fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+					return nil
+				}
+				hits += writeMatches(os.Stdout, displayPath(result.Path, opts), result.Symbols, match)
+				return nil
+			})
+		} else if err == nil {
+			var symbols []outline.SymbolInfo
+			if symbols, err = fileSymbols(path, opts); err == nil {
+				hits += writeMatches(os.Stdout, displayPath(path, opts), symbols, match)
+			}
+		}
+		if err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to search %d file(s)", failures)
+	}
+	if hits == 0 {
+		return fmt.Errorf("no symbols match %q", pattern)
+	}
+	return nil
+}
+
+// symbolMatcher returns whether a symbol name matches pattern, which is a
+// case-insensitive substring unless regex is set
+func symbolMatcher(pattern string, regex bool) (func(name string) bool, error) {
+	if regex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %v", err)
+		}
+		return re.MatchString, nil
+	}
+	pattern = strings.ToLower(pattern)
+	return func(name string) bool {
+		return strings.Contains(strings.ToLower(name), pattern)
+	}, nil
+}
+
+// fileSymbols returns the symbol tree of one file
+func fileSymbols(path string, opts Options) ([]outline.SymbolInfo, error) {
+	content, err := readSource(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+	content, _ = outline.Decode(content)
+	language, err := detectLanguage(path, content, opts)
+	if err != nil {
+		return nil, err
+	}
+	return outline.ExtractSymbolsWithOptions(content, language, opts.Outline)
+}
+
+// writeMatches prints the symbols of a file, members included, whose name
+// matches, and returns how many it printed
+func writeMatches(w io.Writer, path string, symbols []outline.SymbolInfo, match func(name string) bool) int {
+	hits := 0
+	for _, symbol := range symbols {
+		if match(symbol.Name) {
+			signature := strings.Join(strings.Fields(symbol.Signature.String()), " ")
+			if signature == "" {
+				signature = symbol.Type + " " + symbol.Name
+			}
+			fmt.Fprintf(w, "%s:%d: %s\n", path, symbol.Line, signature)
+			hits++
+		}
+		hits += writeMatches(w, path, symbol.Children, match)
+	}
+	return hits
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sourceradar/outline/internal/api"
+	"github.com/sourceradar/outline/internal/confine"
+)
+
+// runServe serves the JSON HTTP API until interrupted
+func runServe(args []string, opts Options) error {
+	if len(args) != 0 || opts.HTTP == "" {
+		return fmt.Errorf("usage: outline serve --http <addr>")
+	}
+
+	root, err := confine.NewRoot(cmp.Or(opts.Root, "."))
+	if err != nil {
+		return fmt.Errorf("invalid --root: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Serving the outline API for %s on %s\n", root.Dir(), confine.LocalAddr(opts.HTTP))
+	return api.ListenAndServe(ctx, opts.HTTP, root, opts.Outline)
+}
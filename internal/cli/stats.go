@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// fileStats counts the lines and symbols of a file, or of several files
+// added together
+type fileStats struct {
+	files    int
+	lines    int
+	code     int
+	comments int
+	public   int
+	private  int
+	// kinds counts symbols by kind, members included
+	kinds map[string]int
+}
+
+// computeStats counts the lines and symbols of a file. Languages without a
+// symbol tree only have their lines counted, as blank or code.
+func computeStats(content []byte, symbols []outline.SymbolInfo, coverage *outline.Coverage) fileStats {
+	stats := fileStats{files: 1, kinds: make(map[string]int)}
+	stats.lines = bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		stats.lines++
+	}
+	if coverage != nil {
+		stats.code, stats.comments = coverage.CodeLines, coverage.CommentLines
+	} else {
+		for _, line := range bytes.Split(content, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				stats.code++
+			}
+		}
+	}
+	stats.countSymbols(symbols)
+	return stats
+}
+
+func (s *fileStats) countSymbols(symbols []outline.SymbolInfo) {
+	for _, symbol := range symbols {
+		s.kinds[symbol.Type]++
+		if symbol.IsPublic {
+			s.public++
+		} else {
+			s.private++
+		}
+		s.countSymbols(symbol.Children)
+	}
+}
+
+// add adds the counts of other to s, for totals over a directory
+func (s *fileStats) add(other fileStats) {
+	s.files += other.files
+	s.lines += other.lines
+	s.code += other.code
+	s.comments += other.comments
+	s.public += other.public
+	s.private += other.private
+	for kind, count := range other.kinds {
+		s.kinds[kind] += count
+	}
+}
+
+// runStats prints the statistics of the files given, and of every supported
+// file below the directories given, followed by their totals when there
+// is more than one file
+func runStats(w io.Writer, paths []string, opts Options) error {
+	total := fileStats{kinds: make(map[string]int)}
+	failures := 0
+	for _, path := range paths {
+		fileInfo, err := checkPath(path, opts)
+		if err == nil && fileInfo.IsDir() {
+			var scanFailures int
+			scanFailures, err = statsDirectory(w, path, &total, opts)
+			failures += scanFailures
+		} else if err == nil {
+			var stats fileStats
+			var language string
+			if stats, language, err = statsFile(path, opts); err == nil {
+				total.add(stats)
+				err = writeStats(w, path, language, stats, opts)
+			}
+		}
+		if err != nil && len(paths) == 1 {
+			return err
+		}
+		if err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+		}
+	}
+
+	if total.files > 1 {
+		fmt.Fprintf(w, "Total over %d files:\n", total.files)
+		if err := writeStatsSummary(w, total); err != nil {
+			return err
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("failed to analyze %d file(s)", failures)
+	}
+	return nil
+}
+
+// statsFile counts the lines and symbols of one file
+func statsFile(path string, opts Options) (fileStats, string, error) {
+	content, err := readSource(path)
+	if err != nil {
+		return fileStats{}, "", fmt.Errorf("error reading file: %v", err)
+	}
+	content, _ = outline.Decode(content)
+	language, err := detectLanguage(path, content, opts)
+	if err != nil {
+		return fileStats{}, "", err
+	}
+	if !outline.IsSupported(language) {
+		return fileStats{}, "", fmt.Errorf("unsupported language: %s", language)
+	}
+	// Languages without a symbol tree have no symbols nor comment counts
+	symbols, _ := outline.ExtractSymbolsWithOptions(content, language, opts.Outline)
+	coverage, _ := outline.ExtractCoverage(content, language)
+	return computeStats(content, symbols, coverage), language, nil
+}
+
+// statsDirectory prints the statistics of every supported file below dir,
+// adding them to total, and returns how many files could not be analyzed
+func statsDirectory(w io.Writer, dir string, total *fileStats, opts Options) (int, error) {
+	failures := 0
+	err := scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true, Coverage: true, Content: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		stats := computeStats(result.Content, result.Symbols, result.Coverage)
+		total.add(stats)
+		return writeStats(w, result.Path, result.Language, stats, opts)
+	})
+	return failures, err
+}
+
+// writeStats prints the statistics of one file
+func writeStats(w io.Writer, path, language string, stats fileStats, opts Options) error {
+	writeHeader(w, path, opts)
+	fmt.Fprintf(w, "Language: %s\n", language)
+	if err := writeStatsSummary(w, stats); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// writeStatsSummary prints the line counts, the share of public symbols
+// and the number of symbols of each kind
+func writeStatsSummary(w io.Writer, stats fileStats) error {
+	blank := max(stats.lines-stats.code-stats.comments, 0)
+	fmt.Fprintf(w, "Lines: %d (%d code, %d comments, %d blank)\n", stats.lines, stats.code, stats.comments, blank)
+
+	symbols := stats.public + stats.private
+	percent := 0.0
+	if symbols > 0 {
+		percent = 100 * float64(stats.public) / float64(symbols)
+	}
+	fmt.Fprintf(w, "Symbols: %d (%d public, %d private, %.1f%% public)\n", symbols, stats.public, stats.private, percent)
+	if symbols == 0 {
+		return nil
+	}
+
+	kinds := make([]string, 0, len(stats.kinds))
+	for kind := range stats.kinds {
+		kinds = append(kinds, kind)
+	}
+	// Most frequent kinds first, then by name
+	sort.Slice(kinds, func(i, j int) bool {
+		if stats.kinds[kinds[i]] != stats.kinds[kinds[j]] {
+			return stats.kinds[kinds[i]] > stats.kinds[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, kind := range kinds {
+		fmt.Fprintf(tw, "  %s\t%d\n", kind, stats.kinds[kind])
+	}
+	return tw.Flush()
+}
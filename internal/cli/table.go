@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// tableColumns are the columns of the csv and tsv formats
+var tableColumns = []string{"file", "kind", "name", "signature", "line", "end_line", "public"}
+
+// tableWriter writes one row per symbol, members named after their
+// container as in Shape.area, with a header row first. Rows are written as
+// each file is read, so large scans start printing right away.
+type tableWriter struct {
+	write  func(row []string) error
+	flush  func() error
+	header bool
+}
+
+func newCSVWriter(w io.Writer, dir bool) symbolWriter {
+	c := csv.NewWriter(w)
+	return &tableWriter{
+		write: c.Write,
+		flush: func() error {
+			c.Flush()
+			return c.Error()
+		},
+	}
+}
+
+// newTSVWriter writes tab-separated rows without quoting, which signatures
+// don't need once their whitespace is collapsed
+func newTSVWriter(w io.Writer, dir bool) symbolWriter {
+	return &tableWriter{
+		write: func(row []string) error {
+			_, err := io.WriteString(w, strings.Join(row, "\t")+"\n")
+			return err
+		},
+		flush: func() error { return nil },
+	}
+}
+
+func (t *tableWriter) file(f outlinedFile) error {
+	if err := t.writeHeader(); err != nil {
+		return err
+	}
+	if err := t.writeSymbols(f.Path, "", f.Symbols); err != nil {
+		return err
+	}
+	return t.flush()
+}
+
+func (t *tableWriter) writeSymbols(path, prefix string, symbols []outline.SymbolInfo) error {
+	for _, symbol := range symbols {
+		name := prefix + symbol.Name
+		row := []string{
+			path,
+			symbol.Type,
+			name,
+			strings.Join(strings.Fields(symbol.Signature.String()), " "),
+			strconv.Itoa(symbol.Line),
+			strconv.Itoa(symbol.EndLine),
+			strconv.FormatBool(symbol.IsPublic),
+		}
+		if err := t.write(row); err != nil {
+			return err
+		}
+		if err := t.writeSymbols(path, name+".", symbol.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tableWriter) close() error {
+	if err := t.writeHeader(); err != nil {
+		return err
+	}
+	return t.flush()
+}
+
+// writeHeader writes the header row unless it was written already, so that
+// a scan without symbols still yields a table
+func (t *tableWriter) writeHeader() error {
+	if t.header {
+		return nil
+	}
+	t.header = true
+	return t.write(tableColumns)
+}
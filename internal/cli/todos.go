@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// runTodos lists the TODO-style markers of a file, or of every supported
+// file below a directory, one per line in file:line:column form
+func runTodos(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline todos [--language <lang>] <file|dir>")
+	}
+
+	path := args[0]
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("file not found: %v", err)
+	}
+
+	if !fileInfo.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading file: %v", err)
+		}
+		content, _ = outline.Decode(content)
+		language, err := detectLanguage(path, content, opts)
+		if err != nil {
+			return err
+		}
+		annotations, err := outline.ExtractAnnotations(content, language)
+		if err != nil {
+			return fmt.Errorf("error extracting annotations: %v", err)
+		}
+		return writeAnnotations(os.Stdout, path, annotations)
+	}
+
+	failures := 0
+	err = scanner.Scan(context.Background(), path, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Annotations: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		return writeAnnotations(os.Stdout, result.Path, result.Annotations)
+	})
+	if err != nil {
+		return err
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to analyze %d file(s)", failures)
+	}
+	return nil
+}
+
+// writeAnnotations prints one marker per line. With a path, lines take the
+// file:line:column form editors and CI tools recognize; without one they
+// form the TODOs section of an outline.
+func writeAnnotations(w io.Writer, path string, annotations []outline.Annotation) error {
+	if path == "" {
+		if len(annotations) == 0 {
+			_, err := fmt.Fprintln(w, "TODOs: none found")
+			return err
+		}
+		fmt.Fprintln(w, "TODOs:")
+	}
+
+	for _, annotation := range annotations {
+		location := fmt.Sprintf("  line %d", annotation.Line)
+		if path != "" {
+			location = fmt.Sprintf("%s:%d:%d", path, annotation.Line, annotation.Column)
+		}
+
+		marker := annotation.Kind
+		if annotation.Author != "" {
+			marker += "(" + annotation.Author + ")"
+		}
+
+		in := ""
+		if annotation.Symbol != "" {
+			in = " [in " + annotation.Symbol + "]"
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s: %s%s\n", location, marker, annotation.Text, in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
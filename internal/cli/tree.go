@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// ANSI escape sequences the tree format colors its output with
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiBlue    = "\x1b[34m"
+	ansiCyan    = "\x1b[36m"
+	ansiMagenta = "\x1b[35m"
+	ansiYellow  = "\x1b[33m"
+)
+
+// ColorModes are the values --color accepts
+var ColorModes = []string{"auto", "always", "never"}
+
+// useColor reports whether output written to w is colored: always, never,
+// or with auto only when w is a terminal and NO_COLOR is unset
+func useColor(mode string, w io.Writer) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// treeWriter draws the symbols of each file as a tree, with the kind of
+// every symbol colored, functions followed by their parameters, and the
+// first line of their documentation dimmed below them
+type treeWriter struct {
+	w     io.Writer
+	color bool
+	files int
+}
+
+func newTreeWriter(w io.Writer, dir bool) symbolWriter {
+	return &treeWriter{w: w}
+}
+
+func (t *treeWriter) file(f outlinedFile) error {
+	var b strings.Builder
+	if t.files > 0 {
+		b.WriteString("\n")
+	}
+	t.files++
+
+	fmt.Fprintf(&b, "%s %s\n", t.paint(ansiBold, f.Path), t.paint(ansiDim, f.Language))
+	if len(f.Symbols) == 0 {
+		fmt.Fprintf(&b, "%s\n", t.paint(ansiDim, "(no symbols)"))
+	}
+	t.writeSymbols(&b, "", f.Symbols)
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
+
+func (t *treeWriter) writeSymbols(b *strings.Builder, prefix string, symbols []outline.SymbolInfo) {
+	for i, symbol := range symbols {
+		glyph, indent := "├── ", "│   "
+		if i == len(symbols)-1 {
+			glyph, indent = "└── ", "    "
+		}
+
+		name := symbol.Name
+		if isFunctionMember(symbol) {
+			name += memberParameters(symbol)
+		}
+		fmt.Fprintf(b, "%s%s%s %s %s\n", prefix, glyph, t.paint(kindColor(symbol.Type), symbol.Type),
+			t.paint(ansiBold, name), t.paint(ansiDim, fmt.Sprintf(":%d", symbol.Line)))
+
+		if doc, _, _ := strings.Cut(strings.TrimSpace(symbol.Documentation.String()), "\n"); doc != "" {
+			rail := indent
+			if len(symbol.Children) > 0 {
+				rail += "│ "
+			} else {
+				rail += "  "
+			}
+			fmt.Fprintf(b, "%s%s%s\n", prefix, rail, t.paint(ansiDim, doc))
+		}
+		t.writeSymbols(b, prefix+indent, symbol.Children)
+	}
+}
+
+// paint wraps s in an escape sequence when colors are on
+func (t *treeWriter) paint(code, s string) string {
+	if !t.color {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+func (t *treeWriter) close() error {
+	return nil
+}
+
+// kindColor returns the color of a symbol kind: types, functions, and the
+// fields, constants and variables holding values each have one
+func kindColor(kind string) string {
+	switch kind {
+	case "class", "interface", "struct", "enum", "protocol", "mixin", "record", "trait", "union", "type", "extension":
+		return ansiYellow
+	case "function", "method", "constructor", "initializer", "destructor":
+		return ansiBlue
+	case "field", "property", "enum_member", "variable", "constant":
+		return ansiCyan
+	}
+	return ansiMagenta
+}
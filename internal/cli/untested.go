@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/internal/testlink"
+)
+
+// runUntested links the tests below a directory to the exported functions
+// and methods they mention, and lists the ones no test mentions
+func runUntested(args []string, opts Options) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: outline untested [--language <lang>] <dir>")
+	}
+
+	dir := args[0]
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory not found: %v", err)
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("expected a directory, got a file")
+	}
+
+	index := testlink.NewIndex()
+	failures := 0
+	err = scanner.Scan(context.Background(), dir, scanner.Options{Language: opts.Language, Outline: opts.Outline, NoIgnore: opts.NoIgnore, Exclude: opts.Exclude, Workers: opts.Jobs, MaxFileSize: opts.MaxFileSize, Skipped: noteSkipped, Symbols: true}, func(result scanner.Result) error {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+		index.AddFile(result.Path, result.Language, result.Symbols)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tested, untested := index.Report()
+	total := len(tested) + len(untested)
+	percent := 100.0
+	if total > 0 {
+		percent = 100 * float64(len(tested)) / float64(total)
+	}
+
+	fmt.Printf("Tests: %d\n", len(index.Tests()))
+	fmt.Printf("Linked: %d/%d exported functions and methods (%.1f%%)\n", len(tested), total, percent)
+
+	if len(untested) > 0 {
+		fmt.Println("\nUntested:")
+		for _, symbol := range untested {
+			fmt.Printf("%s:%d: %s %s\n", symbol.Path, symbol.Line, symbol.Type, symbol.Name)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to analyze %d file(s)", failures)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/internal/watch"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// watchReady is the first event, sent once the initial scan is done
+type watchReady struct {
+	Event string `json:"event"`
+	Files int    `json:"files"`
+}
+
+// watchSymbolsChanged reports the symbols a change to a file added, removed
+// or gave a new signature, by qualified name
+type watchSymbolsChanged struct {
+	Event    string   `json:"event"`
+	File     string   `json:"file"`
+	Language string   `json:"language,omitempty"`
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Changed  []string `json:"changed"`
+}
+
+// watchError reports a file that could not be outlined
+type watchError struct {
+	Event string `json:"event"`
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// watchedDocuments is how many files watch mode keeps the trees of, so
+// that the files being edited are re-parsed incrementally
+const watchedDocuments = 64
+
+// watcher prints outlines, or events with --events, as files change
+type watcher struct {
+	opts      Options
+	out       io.Writer
+	events    *json.Encoder
+	documents *outline.DocumentCache
+	// files holds the signatures of the symbols of every file, by qualified
+	// name, to tell what a change did
+	files map[string]map[string]string
+}
+
+// runWatch outlines path, then again every time it changes, until
+// interrupted. A directory is watched as a whole and only the files that
+// changed are outlined again.
+func runWatch(path string, opts Options) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	w := newWatcher(opts, os.Stdout)
+	if err := w.update(ctx, path, true); err != nil {
+		return err
+	}
+	if w.events != nil {
+		if err := w.events.Encode(watchReady{Event: "ready", Files: len(w.files)}); err != nil {
+			return err
+		}
+	}
+
+	return watch.Watch(ctx, path, watch.DefaultDebounce, func(paths []string) error {
+		for _, changed := range paths {
+			if err := w.update(ctx, changed, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// newWatcher returns the watcher writing to out
+func newWatcher(opts Options, out io.Writer) *watcher {
+	w := &watcher{opts: opts, out: out, documents: outline.NewDocumentCache(watchedDocuments), files: make(map[string]map[string]string)}
+	if opts.Events {
+		w.events = json.NewEncoder(out)
+	}
+	return w
+}
+
+// update outlines a changed file or directory, or reports the files below
+// it as removed when it no longer exists
+func (w *watcher) update(ctx context.Context, path string, initial bool) error {
+	if _, err := os.Stat(path); err != nil {
+		return w.removeBelow(path)
+	}
+
+	seen := make(map[string]bool)
+	err := scanner.Scan(ctx, path, scanner.Options{Language: w.opts.Language, Outline: w.opts.Outline, NoIgnore: w.opts.NoIgnore, Exclude: w.opts.Exclude, Workers: w.opts.Jobs, MaxFileSize: w.opts.MaxFileSize, Skipped: noteSkipped, Symbols: true, Documents: w.documents}, func(result scanner.Result) error {
+		seen[result.Path] = true
+		if result.Err != nil {
+			if w.events != nil {
+				return w.events.Encode(watchError{Event: "error", File: result.Path, Error: result.Err.Error()})
+			}
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", result.Path, result.Err)
+			return nil
+		}
+
+		symbols := signatures(result.Symbols)
+		old, known := w.files[result.Path]
+		w.files[result.Path] = symbols
+
+		if w.events == nil {
+			writeHeader(w.out, result.Path, w.opts)
+			_, err := fmt.Fprintf(w.out, "Language: %s\n\n%s\n", result.Language, result.Outline)
+			return err
+		}
+		if initial {
+			return nil
+		}
+		event := diffSymbols(result.Path, old, symbols)
+		event.Language = result.Language
+		if known && len(event.Added) == 0 && len(event.Removed) == 0 && len(event.Changed) == 0 {
+			return nil
+		}
+		return w.events.Encode(event)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Files that were renamed away or are no longer supported
+	if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+		for file := range w.files {
+			if !seen[file] && strings.HasPrefix(file, filepath.Clean(path)+string(filepath.Separator)) {
+				if err := w.remove(file); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// removeBelow reports the file at path, or every file below it, as removed
+func (w *watcher) removeBelow(path string) error {
+	path = filepath.Clean(path)
+	var removed []string
+	for file := range w.files {
+		if file == path || strings.HasPrefix(file, path+string(filepath.Separator)) {
+			removed = append(removed, file)
+		}
+	}
+	sort.Strings(removed)
+	for _, file := range removed {
+		if err := w.remove(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *watcher) remove(file string) error {
+	old := w.files[file]
+	delete(w.files, file)
+	w.documents.Forget(file)
+	if w.events != nil {
+		return w.events.Encode(diffSymbols(file, old, nil))
+	}
+	writeHeader(w.out, file, w.opts)
+	_, err := fmt.Fprintf(w.out, "Removed\n\n")
+	return err
+}
+
+// signatures maps the qualified name of every symbol to its signature.
+// Overloads get a #2, #3... suffix in the order they are declared.
+func signatures(symbols []outline.SymbolInfo) map[string]string {
+	result := make(map[string]string)
+	var add func(symbols []outline.SymbolInfo, scope string)
+	add = func(symbols []outline.SymbolInfo, scope string) {
+		for _, symbol := range symbols {
+			name := scope + symbol.Name
+			if receiver := outline.MethodReceiver(symbol); receiver != "" {
+				name = receiver + "." + name
+			}
+			key := name
+			for i := 2; ; i++ {
+				if _, taken := result[key]; !taken {
+					break
+				}
+				key = fmt.Sprintf("%s#%d", name, i)
+			}
+			result[key] = strings.Join(strings.Fields(symbol.Signature.String()), " ")
+			add(symbol.Children, name+".")
+		}
+	}
+	add(symbols, "")
+	return result
+}
+
+// diffSymbols compares the symbols of a file before and after a change
+func diffSymbols(file string, old, new map[string]string) watchSymbolsChanged {
+	event := watchSymbolsChanged{Event: "symbols_changed", File: file, Added: []string{}, Removed: []string{}, Changed: []string{}}
+	for name, signature := range new {
+		oldSignature, ok := old[name]
+		switch {
+		case !ok:
+			event.Added = append(event.Added, name)
+		case oldSignature != signature:
+			event.Changed = append(event.Changed, name)
+		}
+	}
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			event.Removed = append(event.Removed, name)
+		}
+	}
+	sort.Strings(event.Added)
+	sort.Strings(event.Removed)
+	sort.Strings(event.Changed)
+	return event
+}
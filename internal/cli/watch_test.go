@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func TestWatcherReportsEdits(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Shop.java")
+	if err := os.WriteFile(path, []byte("public class Shop {\n    public void open() {}\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	w := newWatcher(Options{Outline: outline.DefaultOptions(), Events: true}, &out)
+	if err := w.update(context.Background(), dir, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte("public class Shop {\n    public void open() {}\n    public void close() {}\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.update(context.Background(), path, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"added":["Shop.close"]`) {
+		t.Errorf("Expected the edit to add Shop.close, got:\n%s", out.String())
+	}
+
+}
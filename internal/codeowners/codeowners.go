@@ -0,0 +1,140 @@
+// Package codeowners reads GitHub-style CODEOWNERS files and tells who owns
+// the files of a project.
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sourceradar/outline/internal/ignore"
+)
+
+// Rule assigns owners to the paths matching a pattern
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+
+	re *regexp.Regexp
+}
+
+// File is a parsed CODEOWNERS file. Rules are kept in file order; the last
+// rule matching a path wins.
+type File struct {
+	// Path is where the file was read from
+	Path string
+	// Root is the directory patterns are relative to
+	Root  string
+	Rules []Rule
+}
+
+// locations are where GitHub looks for a CODEOWNERS file, in order
+var locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Find looks for a CODEOWNERS file in dir and its parents, up to the root of
+// the git repository containing dir. It returns nil when there is none.
+func Find(dir string) (*File, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		for _, location := range locations {
+			path := filepath.Join(dir, filepath.FromSlash(location))
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return Load(path, dir)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return nil, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads the CODEOWNERS file at path, whose patterns are relative to root
+func Load(path, root string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CODEOWNERS: %v", err)
+	}
+	defer f.Close()
+
+	file, err := Parse(bufio.NewScanner(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	file.Path = path
+	file.Root = root
+	return file, nil
+}
+
+// Parse reads CODEOWNERS rules line by line. Comments, blank lines and
+// section headers are skipped, and a pattern without owners removes the
+// ownership set by earlier rules.
+func Parse(lines *bufio.Scanner) (*File, error) {
+	file := &File{}
+	n := 0
+	for lines.Scan() {
+		n++
+		line := lines.Text()
+		if i := strings.Index(line, "#"); i >= 0 && (i == 0 || line[i-1] != '\\') {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+		re, err := ignore.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		file.Rules = append(file.Rules, Rule{Pattern: pattern, Owners: fields[1:], Line: n, re: re})
+	}
+	return file, lines.Err()
+}
+
+// Owners returns the owners of path, which is either absolute or relative
+// to the working directory, or nil when no rule assigns it any
+func (f *File) Owners(path string) []string {
+	if f == nil {
+		return nil
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		if rel, err := filepath.Rel(f.Root, abs); err == nil {
+			path = rel
+		}
+	}
+	return f.OwnersOf(filepath.ToSlash(path))
+}
+
+// OwnersOf returns the owners of a path relative to the root, using forward
+// slashes
+func (f *File) OwnersOf(rel string) []string {
+	if f == nil {
+		return nil
+	}
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].re.MatchString(rel) {
+			if len(f.Rules[i].Owners) == 0 {
+				return nil
+			}
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
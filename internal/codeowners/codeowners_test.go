@@ -0,0 +1,80 @@
+package codeowners
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const rules = `# Default owners
+*       @org/core
+
+[Docs]
+/docs/  @org/docs
+*.md    @writer
+
+internal/cli/**  @cli-team alice@example.com
+**/testdata      @qa
+/vendor/
+`
+
+func TestOwnersOf(t *testing.T) {
+	file, err := Parse(bufio.NewScanner(strings.NewReader(rules)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		owners []string
+	}{
+		{"main.go", []string{"@org/core"}},
+		{"docs", []string{"@org/docs"}},
+		{"docs/guide/intro.txt", []string{"@org/docs"}},
+		{"docs/guide/intro.md", []string{"@writer"}},
+		{"pkg/docs/notes.txt", []string{"@org/core"}},
+		{"internal/cli/run.go", []string{"@cli-team", "alice@example.com"}},
+		{"internal/cli2/run.go", []string{"@org/core"}},
+		{"pkg/parser/testdata/a.go", []string{"@qa"}},
+		{"vendor/lib/lib.go", nil},
+	}
+	for _, test := range tests {
+		if owners := file.OwnersOf(test.path); !reflect.DeepEqual(owners, test.owners) {
+			t.Errorf("OwnersOf(%q) = %v, want %v", test.path, owners, test.owners)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "src", "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("/src/app/ @app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Found from a subdirectory, with patterns still relative to the root
+	file, err := Find(filepath.Join(root, "src"))
+	if err != nil || file == nil {
+		t.Fatalf("Expected to find the CODEOWNERS file, got %v, %v", file, err)
+	}
+	if owners := file.Owners(filepath.Join(root, "src", "app", "main.go")); !reflect.DeepEqual(owners, []string{"@app"}) {
+		t.Errorf("Expected @app to own src/app/main.go, got %v", owners)
+	}
+
+	// The search stops at the repository root
+	other := t.TempDir()
+	if err := os.Mkdir(filepath.Join(other, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if file, err := Find(other); err != nil || file != nil {
+		t.Errorf("Expected no CODEOWNERS file, got %v, %v", file, err)
+	}
+}
@@ -0,0 +1,186 @@
+// Package config reads .outline.yaml files, which set the defaults of the
+// command-line flags for a user and for a project.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of configuration files, in the home directory of the
+// user and in a project
+const FileName = ".outline.yaml"
+
+// Config holds the settings of a configuration file. Fields left out of the
+// file are zero, or nil for booleans, so that they don't override anything.
+type Config struct {
+	// Format is the default --format
+	Format string `yaml:"format"`
+	// Detail is the default --detail
+	Detail string `yaml:"detail"`
+	// Color is the default --color
+	Color string `yaml:"color"`
+	// Docs tells whether doc comments are included in outlines
+	Docs *bool `yaml:"docs"`
+	// Summarize and Redact are the defaults of --summarize and --redact
+	Summarize *bool `yaml:"summarize"`
+	Redact    *bool `yaml:"redact"`
+	// Exclude lists gitignore-style patterns of files left out of directory
+	// walks, relative to the directory walked
+	Exclude []string `yaml:"exclude"`
+	// Map routes file extensions, such as ".inc", to the language their
+	// files are outlined as, like --map
+	Map map[string]string `yaml:"map"`
+	// Languages holds options for the files of one language, by name
+	Languages map[string]Language `yaml:"languages"`
+}
+
+// Language holds the options that can be set for one language, layered on
+// those of the whole configuration for its files
+type Language struct {
+	Detail    string `yaml:"detail"`
+	Docs      *bool  `yaml:"docs"`
+	Summarize *bool  `yaml:"summarize"`
+	Redact    *bool  `yaml:"redact"`
+}
+
+// Load reads the configuration of the user, in their home directory, and
+// that of the project containing dir, the nearest .outline.yaml in dir or
+// its parents. Settings of the project override those of the user, and
+// excluded patterns add up. Missing files are not an error.
+func Load(dir string) (Config, error) {
+	var cfg Config
+	home, _ := os.UserHomeDir()
+	if home != "" {
+		user, err := loadIfExists(filepath.Join(home, FileName))
+		if err != nil {
+			return Config{}, err
+		}
+		cfg = cfg.Merge(user)
+	}
+
+	project := Find(dir)
+	if project == "" || (home != "" && project == filepath.Join(home, FileName)) {
+		return cfg, nil
+	}
+	projectConfig, err := ReadFile(project)
+	if err != nil {
+		return Config{}, err
+	}
+	return cfg.Merge(projectConfig), nil
+}
+
+// Find returns the path of the nearest .outline.yaml in dir or its parents,
+// or "" when there is none
+func Find(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func loadIfExists(path string) (Config, error) {
+	cfg, err := ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Config{}, nil
+	}
+	return cfg, err
+}
+
+// ReadFile reads a configuration file
+func ReadFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse reads a configuration from YAML. Unknown settings are reported as
+// errors, so that misspelled ones don't go unnoticed.
+func Parse(data []byte) (Config, error) {
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Merge returns c with the settings of other applied over it. Excluded
+// patterns of both are kept, and the extensions mapped and the settings of
+// a language are merged one by one.
+func (c Config) Merge(other Config) Config {
+	c.Format = orString(other.Format, c.Format)
+	c.Detail = orString(other.Detail, c.Detail)
+	c.Color = orString(other.Color, c.Color)
+	c.Docs = orBool(other.Docs, c.Docs)
+	c.Summarize = orBool(other.Summarize, c.Summarize)
+	c.Redact = orBool(other.Redact, c.Redact)
+	c.Exclude = append(c.Exclude[:len(c.Exclude):len(c.Exclude)], other.Exclude...)
+
+	if len(other.Map) > 0 {
+		mapping := make(map[string]string, len(c.Map)+len(other.Map))
+		for ext, language := range c.Map {
+			mapping[ext] = language
+		}
+		for ext, language := range other.Map {
+			mapping[ext] = language
+		}
+		c.Map = mapping
+	}
+
+	if len(other.Languages) > 0 {
+		languages := make(map[string]Language, len(c.Languages)+len(other.Languages))
+		for name, lang := range c.Languages {
+			languages[name] = lang
+		}
+		for name, lang := range other.Languages {
+			base := languages[name]
+			base.Detail = orString(lang.Detail, base.Detail)
+			base.Docs = orBool(lang.Docs, base.Docs)
+			base.Summarize = orBool(lang.Summarize, base.Summarize)
+			base.Redact = orBool(lang.Redact, base.Redact)
+			languages[name] = base
+		}
+		c.Languages = languages
+	}
+	return c
+}
+
+func orString(s, fallback string) string {
+	if s != "" {
+		return s
+	}
+	return fallback
+}
+
+func orBool(b, fallback *bool) *bool {
+	if b != nil {
+		return b
+	}
+	return fallback
+}
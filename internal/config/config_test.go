@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const project = `# Project defaults
+format: markdown
+detail: full   # every symbol
+docs: no
+exclude:
+- vendor/
+- "*.pb.go"
+map:
+  .inc: c
+  .tpl: html
+languages:
+  go:
+    detail: signatures
+  python:
+    docs: true
+    redact: yes
+`
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(project))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format != "markdown" || cfg.Detail != "full" || cfg.Color != "" {
+		t.Errorf("format, detail, color = %q, %q, %q", cfg.Format, cfg.Detail, cfg.Color)
+	}
+	if cfg.Docs == nil || *cfg.Docs || cfg.Summarize != nil {
+		t.Errorf("docs = %v, summarize = %v", cfg.Docs, cfg.Summarize)
+	}
+	if want := []string{"vendor/", "*.pb.go"}; !reflect.DeepEqual(cfg.Exclude, want) {
+		t.Errorf("exclude = %q, want %q", cfg.Exclude, want)
+	}
+	if want := map[string]string{".inc": "c", ".tpl": "html"}; !reflect.DeepEqual(cfg.Map, want) {
+		t.Errorf("map = %q, want %q", cfg.Map, want)
+	}
+	if lang := cfg.Languages["go"]; lang.Detail != "signatures" || lang.Docs != nil {
+		t.Errorf("go = %+v", lang)
+	}
+	if lang := cfg.Languages["python"]; lang.Docs == nil || !*lang.Docs || lang.Redact == nil || !*lang.Redact {
+		t.Errorf("python = %+v", lang)
+	}
+}
+
+func TestParseFlowSequence(t *testing.T) {
+	cfg, err := Parse([]byte("exclude: [dist, 'build/#out', \"a,b\"]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dist", "build/#out", "a,b"}; !reflect.DeepEqual(cfg.Exclude, want) {
+		t.Errorf("exclude = %q, want %q", cfg.Exclude, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		yaml string
+		err  string
+	}{
+		{"fromat: json\n", "line 1: field fromat not found"},
+		{"docs: maybe\n", "line 1: cannot unmarshal !!str `maybe` into bool"},
+		{"format: json\nformat: text\n", `line 2: mapping key "format" already defined`},
+		{"format: json\n  detail: full\n", "line 2: mapping values are not allowed"},
+		{"languages:\n  go:\n    colour: never\n", "line 3: field colour not found"},
+		{"format: [json]\n", "line 1: cannot unmarshal !!seq into string"},
+		{"map:\n  .inc: [c]\n", "line 2: cannot unmarshal !!seq into string"},
+		{"- json\n", "line 1: cannot unmarshal !!seq into config.Config"},
+	}
+	for _, test := range tests {
+		_, err := Parse([]byte(test.yaml))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Parse(%q) error = %v, want %q", test.yaml, err, test.err)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	yes, no := true, false
+	user := Config{Format: "tree", Docs: &no, Exclude: []string{"*.min.js"}, Map: map[string]string{".inc": "c", ".tpl": "html"}, Languages: map[string]Language{"go": {Detail: "full", Docs: &yes}}}
+	project := Config{Detail: "signatures", Docs: &yes, Exclude: []string{"vendor/"}, Map: map[string]string{".inc": "cpp"}, Languages: map[string]Language{"go": {Detail: "signatures"}}}
+
+	cfg := user.Merge(project)
+	if cfg.Format != "tree" || cfg.Detail != "signatures" || !*cfg.Docs {
+		t.Errorf("merged = %+v", cfg)
+	}
+	if want := []string{"*.min.js", "vendor/"}; !reflect.DeepEqual(cfg.Exclude, want) {
+		t.Errorf("exclude = %q, want %q", cfg.Exclude, want)
+	}
+	if want := map[string]string{".inc": "cpp", ".tpl": "html"}; !reflect.DeepEqual(cfg.Map, want) {
+		t.Errorf("map = %q, want %q", cfg.Map, want)
+	}
+	if lang := cfg.Languages["go"]; lang.Detail != "signatures" || lang.Docs == nil || !*lang.Docs {
+		t.Errorf("go = %+v", lang)
+	}
+	if user.Languages["go"].Detail != "full" {
+		t.Error("Merge modified the configuration merged into")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	root := filepath.Join(t.TempDir(), "project")
+	sub := filepath.Join(root, "src", "app")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, FileName), []byte("format: tree\ncolor: never\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte("format: json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format != "json" || cfg.Color != "never" {
+		t.Errorf("format, color = %q, %q, want json, never", cfg.Format, cfg.Color)
+	}
+}
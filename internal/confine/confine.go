@@ -0,0 +1,68 @@
+// Package confine limits what the servers expose: the paths they are asked
+// about stay inside the directory they serve, so that clients can't read the
+// rest of the machine, and they listen on localhost unless told otherwise.
+package confine
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrOutside is the error of paths outside the root
+var ErrOutside = errors.New("outside the served directory")
+
+// Root is a directory that paths are confined to. It is absolute, with its
+// symbolic links resolved. The zero Root confines nothing, taking relative
+// paths from the current directory.
+type Root struct {
+	dir string
+}
+
+// NewRoot returns the root for dir, which may be relative to the current
+// directory
+func NewRoot(dir string) (Root, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Root{}, err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return Root{}, err
+	}
+	return Root{dir: resolved}, nil
+}
+
+// Dir returns the directory of the root, "" for the zero Root
+func (r Root) Dir() string {
+	return r.dir
+}
+
+// Resolve returns path, relative paths being taken from the root, once
+// cleaned and with its symbolic links resolved. Paths that then lie outside
+// the root are refused with ErrOutside, and paths that don't exist with the
+// error of the file system.
+func (r Root) Resolve(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.dir, path)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil || r.dir == "" {
+		return resolved, err
+	}
+	rel, err := filepath.Rel(r.dir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is %w", path, ErrOutside)
+	}
+	return resolved, nil
+}
+
+// LocalAddr returns addr, on localhost when it names no host as in :9090.
+// Serving every interface takes an explicit host such as 0.0.0.0.
+func LocalAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
@@ -0,0 +1,68 @@
+package confine
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	outside := t.TempDir()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(dir, "src", "a.go"), filepath.Join(outside, "secret.go")} {
+		if err := os.WriteFile(path, []byte("package a\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := NewRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(root.Dir(), "src", "a.go")
+	for _, path := range []string{"src/a.go", filepath.Join(dir, "src", "a.go"), "src/../src/a.go"} {
+		if resolved, err := root.Resolve(path); err != nil || resolved != want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", path, resolved, err, want)
+		}
+	}
+
+	for _, path := range []string{"../" + filepath.Base(outside) + "/secret.go", filepath.Join(outside, "secret.go"), "escape/secret.go", "/"} {
+		if _, err := root.Resolve(path); !errors.Is(err, ErrOutside) {
+			t.Errorf("Expected %q to be outside the root, got %v", path, err)
+		}
+	}
+
+	if _, err := root.Resolve("missing.go"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a missing file to not exist, got %v", err)
+	}
+}
+
+func TestResolveUnconfined(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(path, []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved, err := (Root{}).Resolve(path); err != nil || resolved != want {
+		t.Errorf("Resolve(%q) = %q, %v, want %q", path, resolved, err, want)
+	}
+}
+
+func TestLocalAddr(t *testing.T) {
+	for addr, want := range map[string]string{":9090": "localhost:9090", "0.0.0.0:9090": "0.0.0.0:9090", "127.0.0.1:80": "127.0.0.1:80"} {
+		if got := LocalAddr(addr); got != want {
+			t.Errorf("LocalAddr(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package cscope writes symbol definitions as a cscope cross-reference, the
+// uncompressed cscope.out database cscope and the editor plugins built on it
+// read to find definitions. Only definitions are recorded: calls, includes
+// and other references cscope would find by scanning C sources are not.
+package cscope
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// Version is the version of the cross-reference format cscope 15.8 and
+// later read
+const Version = 15
+
+// marks are the characters cscope prefixes a definition with, by symbol kind
+var marks = map[string]byte{
+	"function": '$', "method": '$', "constructor": '$', "destructor": '$',
+	"macro": '#',
+	"class": 'c', "interface": 'c', "protocol": 'c', "trait": 'c', "record": 'c', "mixin": 'c',
+	"struct": 's',
+	"union":  'u',
+	"enum":   'e',
+	"type":   't', "typedef": 't', "type_alias": 't',
+	"field": 'm', "property": 'm', "enum_member": 'm',
+}
+
+// Writer collects the definitions of the files of a project and writes them
+// as one cross-reference
+type Writer struct {
+	root  string
+	body  bytes.Buffer
+	files []string
+}
+
+// NewWriter starts a cross-reference of the project in root, to which the
+// paths of the files added are made relative, as cscope is run from there
+func NewWriter(root string) *Writer {
+	return &Writer{root: root}
+}
+
+// definition is a symbol name at a byte offset of its line
+type definition struct {
+	line  int
+	start int
+	name  string
+	mark  byte
+}
+
+// AddFile records the definitions among the symbols of a file. Function
+// prototypes are skipped, so that a function is found where its body is.
+func (w *Writer) AddFile(path string, content []byte, symbols []outline.SymbolInfo) {
+	if rel, err := filepath.Rel(w.root, path); err == nil {
+		path = rel
+	}
+	path = filepath.ToSlash(path)
+	w.files = append(w.files, path)
+
+	lines := bytes.Split(content, []byte("\n"))
+	starts := make([]int, len(lines))
+	for i := 1; i < len(lines); i++ {
+		starts[i] = starts[i-1] + len(lines[i-1]) + 1
+	}
+
+	var definitions []definition
+	var add func(symbols []outline.SymbolInfo, topLevel bool)
+	add = func(symbols []outline.SymbolInfo, topLevel bool) {
+		for _, symbol := range symbols {
+			mark, ok := marks[symbol.Type]
+			if !ok && topLevel && (symbol.Type == "variable" || symbol.Type == "constant") {
+				mark, ok = 'g', true
+			}
+			if mark == '$' && symbol.Complexity == 0 && strings.HasSuffix(symbol.Signature.String(), ";") {
+				ok = false
+			}
+			if ok && symbol.Name != "" {
+				if start, end := outline.NameSpan(content, symbol); string(content[start:end]) == symbol.Name {
+					line := sort.Search(len(starts), func(i int) bool { return starts[i] > start }) - 1
+					definitions = append(definitions, definition{line: line, start: start - starts[line], name: symbol.Name, mark: mark})
+				}
+			}
+			add(symbol.Children, false)
+		}
+	}
+	add(symbols, true)
+	sort.SliceStable(definitions, func(i, j int) bool {
+		if definitions[i].line != definitions[j].line {
+			return definitions[i].line < definitions[j].line
+		}
+		return definitions[i].start < definitions[j].start
+	})
+
+	fmt.Fprintf(&w.body, "\t@%s\n\n", path)
+	for i := 0; i < len(definitions); {
+		j := i
+		for j < len(definitions) && definitions[j].line == definitions[i].line {
+			j++
+		}
+
+		// A source line is its number, then the text between its
+		// definitions with each definition on a line of its own, and ends
+		// with an empty line
+		line := lines[definitions[i].line]
+		fmt.Fprintf(&w.body, "%d ", definitions[i].line+1)
+		at := 0
+		for _, d := range definitions[i:j] {
+			if d.start < at {
+				continue
+			}
+			text := sourceText(line[at:d.start])
+			if at == 0 {
+				text = strings.TrimLeft(text, " ")
+			}
+			fmt.Fprintf(&w.body, "%s\n\t%c%s\n", text, d.mark, d.name)
+			at = d.start + len(d.name)
+		}
+		if rest := sourceText(line[at:]); rest != "" {
+			fmt.Fprintf(&w.body, "%s\n", rest)
+		}
+		w.body.WriteString("\n")
+		i = j
+	}
+}
+
+// sourceText returns the text of a line around a definition with its runs
+// of spaces and tabs collapsed, as cscope stores it, so that no line of the
+// cross-reference starts with the tab marking a symbol
+func sourceText(text []byte) string {
+	collapsed := strings.Join(strings.Fields(string(text)), " ")
+	if collapsed != "" && len(text) > 0 && (text[0] == ' ' || text[0] == '\t') {
+		collapsed = " " + collapsed
+	}
+	if collapsed != "" && (text[len(text)-1] == ' ' || text[len(text)-1] == '\t') {
+		collapsed += " "
+	}
+	return collapsed
+}
+
+// Write writes the cross-reference: a header naming the project directory
+// and the offset of the trailer, the definitions of each file, and the
+// trailer listing the source directories and files
+func (w *Writer) Write(out io.Writer) error {
+	root, err := filepath.Abs(w.root)
+	if err != nil {
+		return err
+	}
+	header := fmt.Sprintf("cscope %d %s -c ", Version, root)
+	// The trailer offset is zero-padded to ten digits, so the header length
+	// is known before the offset is
+	body := w.body.Len() + len("\t@\n")
+	trailerOffset := len(header) + 11 + body
+
+	b := bufio.NewWriter(out)
+	fmt.Fprintf(b, "%s%010d\n", header, trailerOffset)
+	b.Write(w.body.Bytes())
+	b.WriteString("\t@\n")
+
+	size := 0
+	for _, file := range w.files {
+		size += len(file) + 1
+	}
+	fmt.Fprintf(b, "1\n.\n0\n%d\n%d\n", len(w.files), size)
+	for _, file := range w.files {
+		fmt.Fprintf(b, "%s\n", file)
+	}
+	return b.Flush()
+}
@@ -0,0 +1,61 @@
+package cscope
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+const sampleC = `#define MAX 10
+
+int add(int a, int b);
+
+struct point { int x; };
+
+int add(int a, int b) {
+	return a + b;
+}
+`
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	symbols, err := outline.ExtractSymbols([]byte(sampleC), "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWriter(dir)
+	w.AddFile(filepath.Join(dir, "src", "add.c"), []byte(sampleC), symbols)
+	var out bytes.Buffer
+	if err := w.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	result := out.String()
+
+	for _, want := range []string{
+		"\t@src/add.c\n\n",
+		"1 #define \n\t#MAX\n 10\n\n",
+		"5 struct \n\tspoint\n { int \n\tmx\n; };\n\n",
+		"7 int \n\t$add\n(int a, int b) {\n\n",
+		"\t@\n1\n.\n0\n1\n10\nsrc/add.c\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in cross-reference:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "\n3 ") {
+		t.Errorf("Expected the prototype to be skipped:\n%s", result)
+	}
+
+	var offset int
+	if _, err := fmt.Sscanf(result[strings.LastIndex(result[:strings.IndexByte(result, '\n')], " ")+1:], "%d", &offset); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result[offset:], "1\n.\n") {
+		t.Errorf("Expected the header to give the offset of the trailer, got %d", offset)
+	}
+}
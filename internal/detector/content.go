@@ -0,0 +1,198 @@
+package detector
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// contentSampleSize limits how much of a file the content heuristics look at
+const contentSampleSize = 16 * 1024
+
+// minContentScore is the score a language needs before the heuristics trust it
+const minContentScore = 3
+
+// contentRule awards weight to a language when its pattern matches the content
+type contentRule struct {
+	language string
+	pattern  *regexp.Regexp
+	weight   int
+}
+
+// shebangInterpreters maps interpreter names found in a #! line to languages
+var shebangInterpreters = map[string]string{
+	"python":  "python",
+	"python2": "python",
+	"python3": "python",
+	"node":    "javascript",
+	"nodejs":  "javascript",
+	"deno":    "typescript",
+	"bun":     "javascript",
+	"ts-node": "typescript",
+	"tsx":     "typescript",
+	"swift":   "swift",
+	"dart":    "dart",
+	"lua":     "lua",
+	"luajit":  "lua",
+	"bb":      "clojure",
+	"clojure": "clojure",
+	"crystal": "crystal",
+}
+
+var contentRules = []contentRule{
+	// Go
+	{"go", regexp.MustCompile(`(?m)^package [a-z_][a-z0-9_]*\s*$`), 3},
+	{"go", regexp.MustCompile(`(?m)^func (\([^)]*\) )?[A-Za-z_]\w*\(`), 2},
+	{"go", regexp.MustCompile(`(?m)^import \($`), 2},
+	{"go", regexp.MustCompile(`:=`), 1},
+
+	// Python, including Starlark files such as BUILD and WORKSPACE
+	{"python", regexp.MustCompile(`(?m)^\s*def [A-Za-z_]\w*\(.*\)\s*(->.*)?:\s*$`), 2},
+	{"python", regexp.MustCompile(`(?m)^class [A-Za-z_]\w*(\(.*\))?:\s*$`), 2},
+	{"python", regexp.MustCompile(`(?m)^(from [\w.]+ )?import [\w., ]+$`), 1},
+	{"python", regexp.MustCompile(`if __name__ == ['"]__main__['"]:`), 3},
+	{"python", regexp.MustCompile(`(?m)^load\(\s*"`), 3},
+	{"python", regexp.MustCompile(`(?m)^\s+name\s*=\s*"[^"]*",\s*$`), 1},
+
+	// Java
+	{"java", regexp.MustCompile(`(?m)^package [\w.]+;\s*$`), 3},
+	{"java", regexp.MustCompile(`(?m)^import (static )?[\w.]+(\.\*)?;\s*$`), 2},
+	{"java", regexp.MustCompile(`\b(public|private|protected)\s+(static\s+)?(final\s+)?(abstract\s+)?(class|interface|enum|record)\s+\w+`), 2},
+	{"java", regexp.MustCompile(`System\.out\.print`), 2},
+
+	// JavaScript
+	{"javascript", regexp.MustCompile(`\b(const|let|var)\s+\w+\s*=\s*require\(`), 3},
+	{"javascript", regexp.MustCompile(`\bmodule\.exports\b`), 3},
+	{"javascript", regexp.MustCompile(`\bfunction\s*\*?\s*\w*\s*\(`), 1},
+	{"javascript", regexp.MustCompile(`(?m)^export (default )?(async )?(function|class|const)\b`), 1},
+	{"javascript", regexp.MustCompile(`\bconsole\.log\(`), 1},
+
+	// TypeScript
+	{"typescript", regexp.MustCompile(`(?m)^(export )?(declare )?interface \w+`), 2},
+	{"typescript", regexp.MustCompile(`(?m)^(export )?type \w+(<[^>]*>)?\s*=`), 2},
+	{"typescript", regexp.MustCompile(`:\s*(string|number|boolean|void|unknown|any)\b`), 2},
+	{"typescript", regexp.MustCompile(`(?m)^import .* from ['"]`), 1},
+
+	// Swift
+	{"swift", regexp.MustCompile(`(?m)^import (Foundation|UIKit|SwiftUI|AppKit|Combine)\s*$`), 3},
+	{"swift", regexp.MustCompile(`\bfunc \w+(<[^>]*>)?\(.*\)\s*(async\s+)?(throws\s+)?(->|\{)`), 2},
+	{"swift", regexp.MustCompile(`\b(guard let|if let)\b`), 2},
+
+	// Dart
+	{"dart", regexp.MustCompile(`(?m)^import '(package|dart):[^']+';`), 3},
+	{"dart", regexp.MustCompile(`\bWidget build\(BuildContext \w+\)`), 3},
+	{"dart", regexp.MustCompile(`\bFuture<[^>]*>\s+\w+\([^)]*\)\s+async\b`), 2},
+
+	// Lua
+	{"lua", regexp.MustCompile(`(?m)^local function \w+\(`), 3},
+	{"lua", regexp.MustCompile(`(?m)^local \w+ = require\(?["']`), 3},
+	{"lua", regexp.MustCompile(`(?m)^function [\w.:]+\(.*\)\s*$`), 2},
+	{"lua", regexp.MustCompile(`(?m)^\s*end\s*$`), 1},
+
+	// Clojure
+	{"clojure", regexp.MustCompile(`(?m)^\(ns [\w.\-]+`), 3},
+	{"clojure", regexp.MustCompile(`(?m)^\(defn-? [\w\-?!*]+`), 2},
+
+	// Zig
+	{"zig", regexp.MustCompile(`@import\("[^"]+"\)`), 3},
+	{"zig", regexp.MustCompile(`(?m)^(pub )?fn \w+\(.*\) !?\w`), 2},
+
+	// Crystal, whose method signatures carry type restrictions
+	{"crystal", regexp.MustCompile(`(?m)^\s*def [\w.?!]+(\(.*\))? : [A-Z][\w:()]*\s*$`), 3},
+	{"crystal", regexp.MustCompile(`(?m)^\s*(property|getter|setter)[?!]? \w+ : [A-Z]`), 3},
+	{"crystal", regexp.MustCompile(`(?m)^require "[^"]+"\s*$`), 1},
+
+	// MATLAB
+	{"matlab", regexp.MustCompile(`(?m)^\s*classdef\b`), 3},
+	{"matlab", regexp.MustCompile(`(?m)^function (\[[^\]]*\]|\w+)\s*=\s*\w+\(`), 3},
+
+	// CUDA
+	{"cuda", regexp.MustCompile(`(?m)^\s*(template\s*<[^>]*>\s*)?__global__\s+void\b`), 3},
+	{"cuda", regexp.MustCompile(`\w<<<[^;]+>>>\(`), 3},
+	{"cuda", regexp.MustCompile(`#include\s*<cuda_runtime\.h>`), 3},
+
+	// Apex
+	{"apex", regexp.MustCompile(`(?i)\b(with|without) sharing class\b`), 3},
+	{"apex", regexp.MustCompile(`@AuraEnabled\b`), 3},
+	{"apex", regexp.MustCompile(`(?m)^trigger \w+ on \w+\s*\(`), 3},
+
+	// C
+	{"c", regexp.MustCompile(`(?m)^#include\s*[<"]`), 2},
+	{"c", regexp.MustCompile(`\b(printf|malloc|free|memcpy|sizeof)\s*\(`), 1},
+	{"c", regexp.MustCompile(`(?m)^typedef\s+(struct|enum|union)\b`), 1},
+
+	// C++
+	{"cpp", regexp.MustCompile(`(?m)^#include\s*<(iostream|vector|string|memory|map|algorithm|utility)>`), 3},
+	{"cpp", regexp.MustCompile(`\bstd::`), 2},
+	{"cpp", regexp.MustCompile(`\btemplate\s*<`), 3},
+	{"cpp", regexp.MustCompile(`(?m)^\s*namespace \w+\s*\{`), 2},
+}
+
+// DetectLanguageFromContent guesses the language of a file from its content.
+// It is used when the file name is missing an extension or the extension is
+// unknown, and looks at the shebang line before falling back to keyword scoring.
+func DetectLanguageFromContent(content []byte) (string, bool) {
+	if language, ok := detectShebang(content); ok {
+		return language, true
+	}
+
+	sample := content
+	if len(sample) > contentSampleSize {
+		sample = sample[:contentSampleSize]
+	}
+
+	scores := make(map[string]int)
+	for _, rule := range contentRules {
+		if rule.pattern.Match(sample) {
+			scores[rule.language] += rule.weight
+		}
+	}
+
+	best, bestScore, tied := "", 0, false
+	for language, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = language, score, false
+		case score == bestScore:
+			tied = true
+		}
+	}
+
+	if bestScore < minContentScore || tied {
+		return "", false
+	}
+	return best, true
+}
+
+// detectShebang maps a leading "#!" interpreter line to a language
+func detectShebang(content []byte) (string, bool) {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return "", false
+	}
+
+	line := content[2:]
+	if end := bytes.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	// "#!/usr/bin/env -S node --flags" names the interpreter after env
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				interpreter = field
+				break
+			}
+		}
+	}
+
+	language, ok := shebangInterpreters[interpreter]
+	return language, ok
+}
@@ -0,0 +1,72 @@
+package detector
+
+import "testing"
+
+func TestDetectLanguageFromContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "python shebang",
+			content:  "#!/usr/bin/env python3\nprint('hi')\n",
+			expected: "python",
+		},
+		{
+			name:     "node shebang with env flags",
+			content:  "#!/usr/bin/env -S node --no-warnings\nconsole.log(1)\n",
+			expected: "javascript",
+		},
+		{
+			name:     "go source",
+			content:  "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tx := 1\n\tfmt.Println(x)\n}\n",
+			expected: "go",
+		},
+		{
+			name:     "bazel build file",
+			content:  "load(\"@rules_cc//cc:defs.bzl\", \"cc_library\")\n\ncc_library(\n    name = \"lib\",\n    srcs = [\"lib.cc\"],\n)\n",
+			expected: "python",
+		},
+		{
+			name:     "java source",
+			content:  "package com.example;\n\nimport java.util.List;\n\npublic class App {\n}\n",
+			expected: "java",
+		},
+		{
+			name:     "cpp source",
+			content:  "#include <vector>\n\nnamespace app {\ntemplate <typename T>\nstd::vector<T> make();\n}\n",
+			expected: "cpp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, ok := DetectLanguageFromContent([]byte(tt.content))
+			if !ok {
+				t.Fatalf("Expected %s to be detected", tt.expected)
+			}
+			if language != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, language)
+			}
+		})
+	}
+}
+
+func TestDetectLanguageFromContentUnknown(t *testing.T) {
+	if language, ok := DetectLanguageFromContent([]byte("hello world\nthis is plain text\n")); ok {
+		t.Errorf("Expected plain text not to be detected, got %s", language)
+	}
+}
+
+func TestDetectPrefersExtension(t *testing.T) {
+	language, ok := Detect("script.py", []byte("package main\n\nfunc main() {}\n"))
+	if !ok || language != "python" {
+		t.Errorf("Expected extension to win, got %s", language)
+	}
+
+	language, ok = Detect("BUILD", []byte("load(\"//tools:defs.bzl\", \"lib\")\n"))
+	if !ok || language != "python" {
+		t.Errorf("Expected content fallback to detect python, got %s", language)
+	}
+}
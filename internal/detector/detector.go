@@ -5,8 +5,17 @@ import (
 	"strings"
 )
 
-// DetectLanguage determines the programming language based on file extension
+// DetectLanguage determines the programming language based on the file name
+// and extension
 func DetectLanguage(filePath string) (string, bool) {
+	if language, ok := detectRegisteredName(filePath); ok {
+		return language, true
+	}
+
+	if language, ok := DetectFilename(filePath); ok {
+		return language, true
+	}
+
 	ext := strings.ToLower(filepath.Ext(filePath))
 
 	languages := SupportedLanguages()
@@ -21,7 +30,55 @@ func DetectLanguage(filePath string) (string, bool) {
 	return "", false
 }
 
+// Detect determines the programming language of a file. Repository
+// linguist-language overrides take precedence, followed by editor modelines in
+// the content, then registered rules and the file extension (with .h headers
+// and .m files disambiguated by their content), and finally content heuristics when the
+// extension is missing or unknown.
+func Detect(filePath string, content []byte) (string, bool) {
+	if language, ok := DetectGitAttributes(filePath); ok {
+		return language, true
+	}
+	return DetectName(filePath, content)
+}
+
+// DetectName is Detect for content that isn't a file of the local disk, such
+// as a downloaded one, whose name only serves for its extension: the
+// .gitattributes files of the repository around it are not read.
+func DetectName(name string, content []byte) (string, bool) {
+	if language, ok := DetectModeline(content); ok {
+		return language, true
+	}
+
+	if language, ok := detectRegisteredName(name); ok {
+		return language, true
+	}
+
+	if IsHeader(name) {
+		return DetectHeaderLanguage(content), true
+	}
+
+	if isAmbiguousMFile(name) {
+		return DetectMFileLanguage(content), true
+	}
+
+	if language, ok := DetectLanguage(name); ok {
+		return language, true
+	}
+
+	if language, ok := detectRegisteredContent(name, content); ok {
+		return language, true
+	}
+
+	// Known build files such as Makefiles shouldn't be second-guessed
+	if _, known := IdentifyFilename(name); known {
+		return "", false
+	}
+
+	return DetectLanguageFromContent(content)
+}
+
 // SupportedExtensions returns a list of supported file extensions
 func SupportedExtensions() []string {
-	return GetAllExtensions()
+	return append(GetAllExtensions(), registeredExtensions()...)
 }
@@ -0,0 +1,29 @@
+package detector
+
+import "testing"
+
+func TestDetectModuleExtensions(t *testing.T) {
+	tests := map[string]string{
+		"index.mjs":   "javascript",
+		"config.cjs":  "javascript",
+		"server.mts":  "typescript",
+		"legacy.cts":  "typescript",
+		"module.d.ts": "typescript",
+	}
+
+	for path, expected := range tests {
+		language, ok := DetectLanguage(path)
+		if !ok || language != expected {
+			t.Errorf("%s: expected %s, got %q", path, expected, language)
+		}
+	}
+}
+
+func TestDetectName(t *testing.T) {
+	if language, ok := DetectName("/raw/main/src/app.py", []byte("def main():\n    pass\n")); !ok || language != "python" {
+		t.Errorf("Expected python, got %q", language)
+	}
+	if language, ok := DetectName("/raw/main/script", []byte("#!/usr/bin/env python3\nprint(1)\n")); !ok || language != "python" {
+		t.Errorf("Expected python from the shebang, got %q", language)
+	}
+}
@@ -0,0 +1,61 @@
+package detector
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// wellKnownFilenames maps extensionless build and tooling files to their
+// language. These languages have no extractor yet; the table lets callers
+// explain why such a file can't be outlined instead of guessing from content.
+var wellKnownFilenames = map[string]string{
+	"Makefile":       "make",
+	"GNUmakefile":    "make",
+	"makefile":       "make",
+	"Dockerfile":     "dockerfile",
+	"Containerfile":  "dockerfile",
+	"Jenkinsfile":    "groovy",
+	"Rakefile":       "ruby",
+	"Gemfile":        "ruby",
+	"Podfile":        "ruby",
+	"Vagrantfile":    "ruby",
+	"CMakeLists.txt": "cmake",
+}
+
+// DetectFilename determines the language of a well-known file name such as
+// BUILD.bazel, returning only languages that can be outlined
+func DetectFilename(filePath string) (string, bool) {
+	base := filepath.Base(filePath)
+
+	for langName, langInfo := range SupportedLanguages() {
+		for _, filename := range langInfo.Filenames {
+			if base == filename {
+				return langName, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// IdentifyFilename names the language of a well-known file, including build
+// files such as Makefile or Dockerfile that outline does not support
+func IdentifyFilename(filePath string) (string, bool) {
+	if language, ok := DetectFilename(filePath); ok {
+		return language, true
+	}
+
+	base := filepath.Base(filePath)
+	if language, ok := wellKnownFilenames[base]; ok {
+		return language, true
+	}
+
+	// Variants such as Dockerfile.dev or Makefile.am
+	if prefix, _, found := strings.Cut(base, "."); found {
+		if language, ok := wellKnownFilenames[prefix]; ok {
+			return language, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,37 @@
+package detector
+
+import "testing"
+
+func TestDetectFilename(t *testing.T) {
+	for _, path := range []string{"BUILD", "pkg/BUILD.bazel", "/repo/WORKSPACE", "SConstruct"} {
+		language, ok := DetectLanguage(path)
+		if !ok || language != "python" {
+			t.Errorf("%s: expected python, got %q", path, language)
+		}
+	}
+}
+
+func TestIdentifyFilename(t *testing.T) {
+	tests := map[string]string{
+		"Makefile":             "make",
+		"src/Dockerfile":       "dockerfile",
+		"Dockerfile.dev":       "dockerfile",
+		"Jenkinsfile":          "groovy",
+		"Rakefile":             "ruby",
+		"Gemfile":              "ruby",
+		"build/CMakeLists.txt": "cmake",
+		"BUILD.bazel":          "python",
+	}
+
+	for path, expected := range tests {
+		language, ok := IdentifyFilename(path)
+		if !ok || language != expected {
+			t.Errorf("%s: expected %s, got %q", path, expected, language)
+		}
+	}
+
+	// Unsupported well-known files are not reported as detectable
+	if language, ok := DetectLanguage("Makefile"); ok {
+		t.Errorf("Expected Makefile not to be outlined, got %s", language)
+	}
+}
@@ -0,0 +1,169 @@
+package detector
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// attributeRule is a single "pattern linguist-language=X" line of a .gitattributes file
+type attributeRule struct {
+	dir      string // directory containing the .gitattributes file
+	pattern  *regexp.Regexp
+	basename bool // pattern without a slash, matched against the file name only
+	language string
+}
+
+var (
+	// gitAttributesCache maps a .gitattributes path to its parsed rules
+	gitAttributesCache sync.Map
+	// repoRootCache maps a directory to the root of its enclosing git repository
+	repoRootCache sync.Map
+)
+
+// DetectGitAttributes returns the language a repository assigns to a file
+// through linguist-language entries in its .gitattributes files. Files in
+// deeper directories and later lines take precedence, as they do in git.
+func DetectGitAttributes(filePath string) (string, bool) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", false
+	}
+
+	fileDir := filepath.Dir(absPath)
+	root, ok := findRepoRoot(fileDir)
+	if !ok {
+		return "", false
+	}
+
+	// Collect directories from the repository root down to the file
+	var dirs []string
+	for dir := fileDir; ; dir = filepath.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+		if dir == root || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	language := ""
+	for _, dir := range dirs {
+		for _, rule := range loadGitAttributes(filepath.Join(dir, ".gitattributes")) {
+			if rule.matches(absPath) {
+				language = rule.language
+			}
+		}
+	}
+
+	if language == "" {
+		return "", false
+	}
+	return LookupLanguage(language)
+}
+
+func (r attributeRule) matches(absPath string) bool {
+	if r.basename {
+		return r.pattern.MatchString(filepath.Base(absPath))
+	}
+
+	rel, err := filepath.Rel(r.dir, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	return r.pattern.MatchString(filepath.ToSlash(rel))
+}
+
+// findRepoRoot walks up from dir to the directory containing .git
+func findRepoRoot(dir string) (string, bool) {
+	if cached, ok := repoRootCache.Load(dir); ok {
+		root := cached.(string)
+		return root, root != ""
+	}
+
+	root := ""
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		root = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		root, _ = findRepoRoot(parent)
+	}
+
+	repoRootCache.Store(dir, root)
+	return root, root != ""
+}
+
+// loadGitAttributes parses the linguist-language rules of a .gitattributes file
+func loadGitAttributes(path string) []attributeRule {
+	if cached, ok := gitAttributesCache.Load(path); ok {
+		return cached.([]attributeRule)
+	}
+
+	var rules []attributeRule
+	if file, err := os.Open(path); err == nil {
+		rules = parseGitAttributes(file, filepath.Dir(path))
+		file.Close()
+	}
+
+	gitAttributesCache.Store(path, rules)
+	return rules
+}
+
+func parseGitAttributes(file *os.File, dir string) []attributeRule {
+	var rules []attributeRule
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		language := ""
+		for _, attr := range fields[1:] {
+			if value, ok := strings.CutPrefix(attr, "linguist-language="); ok {
+				language = value
+			}
+		}
+		if language == "" {
+			continue
+		}
+
+		pattern := fields[0]
+		basename := !strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+		rules = append(rules, attributeRule{
+			dir:      dir,
+			pattern:  compileGitPattern(strings.TrimPrefix(pattern, "/")),
+			basename: basename,
+			language: language,
+		})
+	}
+
+	return rules
+}
+
+// compileGitPattern converts a gitattributes glob into an anchored regular expression
+func compileGitPattern(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**"):
+			expr.WriteString("/.*")
+			i += 2
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
@@ -0,0 +1,50 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectGitAttributes(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(root, ".gitattributes"), `# Project overrides
+*.inc linguist-language=C
+scripts/** linguist-language=Python
+*.tmpl linguist-generated
+`)
+	writeFile(t, filepath.Join(root, "vendor", ".gitattributes"), "*.inc linguist-language=C++\n")
+
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{filepath.Join(root, "src", "table.inc"), "c", true},
+		{filepath.Join(root, "scripts", "deploy"), "python", true},
+		{filepath.Join(root, "scripts", "nested", "tool"), "python", true},
+		{filepath.Join(root, "vendor", "lib.inc"), "cpp", true},
+		{filepath.Join(root, "page.tmpl"), "", false},
+	}
+
+	for _, tt := range tests {
+		language, ok := DetectGitAttributes(tt.path)
+		if ok != tt.ok || language != tt.expected {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", tt.path, tt.expected, tt.ok, language, ok)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
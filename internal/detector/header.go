@@ -0,0 +1,61 @@
+package detector
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	objcHeaderPattern = regexp.MustCompile(`(?m)^\s*(@interface|@protocol|@implementation|@class|#import)\b`)
+	cppHeaderPattern  = regexp.MustCompile(`(?m)^\s*(class\s+\w+\s*[:{]|template\s*<|namespace\s*\w*\s*\{|(public|private|protected)\s*:)|\bstd::|\bvirtual\s+\w`)
+
+	objcSourcePattern = regexp.MustCompile(`(?m)^\s*(@interface|@protocol|@implementation|@class|@end|#import|#include)\b`)
+	matlabPattern     = regexp.MustCompile(`(?m)^\s*(function|classdef)\b|^\s*%|^\s*end\s*$`)
+)
+
+// IsHeader reports whether the file is a .h header, which C, C++ and
+// Objective-C all use. The language detected for headers is only the one
+// their content suggests, which outline.HeaderLanguage settles.
+func IsHeader(filePath string) bool {
+	return strings.ToLower(filepath.Ext(filePath)) == ".h"
+}
+
+// DetectHeaderLanguage chooses between C, C++ and Objective-C for a .h header
+// based on constructs that only exist in one of them. Plain C is the default.
+func DetectHeaderLanguage(content []byte) string {
+	sample := content
+	if len(sample) > contentSampleSize {
+		sample = sample[:contentSampleSize]
+	}
+
+	switch {
+	case objcHeaderPattern.Match(sample):
+		return "objc"
+	case cppHeaderPattern.Match(sample):
+		return "cpp"
+	}
+	return "c"
+}
+
+// isAmbiguousMFile reports whether the file is a .m file, which Objective-C
+// and MATLAB both use
+func isAmbiguousMFile(filePath string) bool {
+	return strings.ToLower(filepath.Ext(filePath)) == ".m"
+}
+
+// DetectMFileLanguage chooses between Objective-C and MATLAB for a .m file.
+// Objective-C files start with imports and declare @interface or
+// @implementation blocks, while MATLAB files hold functions, classdef blocks
+// and % comments. Objective-C is the default.
+func DetectMFileLanguage(content []byte) string {
+	sample := content
+	if len(sample) > contentSampleSize {
+		sample = sample[:contentSampleSize]
+	}
+
+	if objcSourcePattern.Match(sample) || !matlabPattern.Match(sample) {
+		return "objc"
+	}
+	return "matlab"
+}
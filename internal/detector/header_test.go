@@ -0,0 +1,85 @@
+package detector
+
+import "testing"
+
+func TestDetectHeaderLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "plain c header",
+			content:  "#ifdef __cplusplus\nextern \"C\" {\n#endif\nint add(int a, int b);\n#ifdef __cplusplus\n}\n#endif\n",
+			expected: "c",
+		},
+		{
+			name:     "class declaration",
+			content:  "#pragma once\nclass Widget {\npublic:\n  void draw();\n};\n",
+			expected: "cpp",
+		},
+		{
+			name:     "template",
+			content:  "template <typename T>\nT max(T a, T b);\n",
+			expected: "cpp",
+		},
+		{
+			name:     "namespace",
+			content:  "namespace gfx {\nvoid init();\n}\n",
+			expected: "cpp",
+		},
+		{
+			name:     "objective-c interface",
+			content:  "#import <Foundation/Foundation.h>\n\n@interface Point : NSObject\n- (void)reset;\n@end\n",
+			expected: "objc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if language := DetectHeaderLanguage([]byte(tt.content)); language != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, language)
+			}
+		})
+	}
+}
+
+func TestDetectMFileLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "objective-c implementation",
+			content:  "#import \"Point.h\"\n\n@implementation Point\n- (void)reset {\n}\n@end\n",
+			expected: "objc",
+		},
+		{
+			name:     "matlab function",
+			content:  "function y = twice(x)\n% TWICE Doubles x.\ny = 2 * x;\nend\n",
+			expected: "matlab",
+		},
+		{
+			name:     "matlab classdef",
+			content:  "classdef Point < handle\n    properties\n        X\n    end\nend\n",
+			expected: "matlab",
+		},
+		{
+			name:     "no telltale constructs",
+			content:  "int add(int a, int b) { return a + b; }\n",
+			expected: "objc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if language := DetectMFileLanguage([]byte(tt.content)); language != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, language)
+			}
+			if language, ok := Detect("file.m", []byte(tt.content)); !ok || language != tt.expected {
+				t.Errorf("Expected Detect to return %s, got %s", tt.expected, language)
+			}
+		})
+	}
+}
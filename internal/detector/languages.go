@@ -1,9 +1,13 @@
 package detector
 
+import "strings"
+
 // LanguageInfo contains metadata about a supported language
 type LanguageInfo struct {
 	Name        string
 	Extensions  []string
+	Filenames   []string
+	Aliases     []string
 	Description string
 }
 
@@ -14,6 +18,7 @@ func SupportedLanguages() map[string]LanguageInfo {
 		"go": {
 			Name:        "go",
 			Extensions:  []string{".go"},
+			Aliases:     []string{"golang"},
 			Description: "Go programming language",
 		},
 		"java": {
@@ -23,22 +28,27 @@ func SupportedLanguages() map[string]LanguageInfo {
 		},
 		"javascript": {
 			Name:        "javascript",
-			Extensions:  []string{".js", ".jsx"},
+			Extensions:  []string{".js", ".jsx", ".mjs", ".cjs"},
+			Aliases:     []string{"js", "node", "javascriptreact"},
 			Description: "JavaScript programming language",
 		},
 		"typescript": {
 			Name:        "typescript",
-			Extensions:  []string{".ts"},
+			Extensions:  []string{".ts", ".mts", ".cts"},
+			Aliases:     []string{"ts"},
 			Description: "TypeScript programming language",
 		},
 		"tsx": {
 			Name:        "tsx",
 			Extensions:  []string{".tsx"},
+			Aliases:     []string{"typescriptreact"},
 			Description: "TypeScript JSX",
 		},
 		"python": {
 			Name:        "python",
 			Extensions:  []string{".py"},
+			Filenames:   []string{"BUILD", "BUILD.bazel", "WORKSPACE", "WORKSPACE.bazel", "SConstruct", "SConscript", "Snakefile", "Tiltfile"},
+			Aliases:     []string{"py", "python3"},
 			Description: "Python programming language",
 		},
 		"swift": {
@@ -46,19 +56,98 @@ func SupportedLanguages() map[string]LanguageInfo {
 			Extensions:  []string{".swift"},
 			Description: "Swift programming language",
 		},
+		"dart": {
+			Name:        "dart",
+			Extensions:  []string{".dart"},
+			Aliases:     []string{"flutter"},
+			Description: "Dart programming language, including Flutter",
+		},
+		"lua": {
+			Name:        "lua",
+			Extensions:  []string{".lua"},
+			Aliases:     []string{"luajit"},
+			Description: "Lua scripting language",
+		},
+		"clojure": {
+			Name:        "clojure",
+			Extensions:  []string{".clj", ".cljs", ".cljc"},
+			Aliases:     []string{"clj", "cljs", "clojurescript"},
+			Description: "Clojure and ClojureScript",
+		},
+		"zig": {
+			Name:        "zig",
+			Extensions:  []string{".zig"},
+			Description: "Zig programming language",
+		},
+		"crystal": {
+			Name:        "crystal",
+			Extensions:  []string{".cr"},
+			Description: "Crystal programming language",
+		},
+		"html": {
+			Name:        "html",
+			Extensions:  []string{".html", ".htm"},
+			Description: "HTML with embedded scripts",
+		},
 		"c": {
 			Name:        "c",
 			Extensions:  []string{".c", ".h"},
 			Description: "C programming language",
 		},
+		"objc": {
+			Name:        "objc",
+			Extensions:  []string{".m", ".mm"},
+			Aliases:     []string{"objective-c", "objectivec", "obj-c"},
+			Description: "Objective-C programming language",
+		},
+		"matlab": {
+			Name:        "matlab",
+			Aliases:     []string{"octave"},
+			Description: "MATLAB and Octave, whose .m files are told apart from Objective-C by their content",
+		},
+		"apex": {
+			Name:        "apex",
+			Extensions:  []string{".cls", ".trigger"},
+			Aliases:     []string{"salesforce"},
+			Description: "Salesforce Apex",
+		},
 		"cpp": {
 			Name:        "cpp",
 			Extensions:  []string{".cpp", ".cxx", ".cc", ".hpp", ".hxx", ".hh"},
+			Aliases:     []string{"c++", "cxx"},
 			Description: "C++ programming language",
 		},
+		"cuda": {
+			Name:        "cuda",
+			Extensions:  []string{".cu", ".cuh"},
+			Aliases:     []string{"cu"},
+			Description: "CUDA C++",
+		},
 	}
 }
 
+// LookupLanguage resolves a language name or alias (case-insensitive), such as
+// "C++" or "golang", to the name of a supported language
+func LookupLanguage(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", false
+	}
+
+	for langName, langInfo := range SupportedLanguages() {
+		if langName == name {
+			return langName, true
+		}
+		for _, alias := range langInfo.Aliases {
+			if alias == name {
+				return langName, true
+			}
+		}
+	}
+
+	return "", false
+}
+
 // GetLanguageNames returns a slice of supported language names
 func GetLanguageNames() []string {
 	languages := SupportedLanguages()
@@ -69,6 +158,16 @@ func GetLanguageNames() []string {
 	return names
 }
 
+// GetAllFilenames returns all supported well-known file names
+func GetAllFilenames() []string {
+	languages := SupportedLanguages()
+	var filenames []string
+	for _, lang := range languages {
+		filenames = append(filenames, lang.Filenames...)
+	}
+	return filenames
+}
+
 // GetAllExtensions returns all supported file extensions
 func GetAllExtensions() []string {
 	languages := SupportedLanguages()
@@ -0,0 +1,78 @@
+package detector
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// modelineScanLines is how many lines at the start and end of a file are
+// searched for modelines, matching Vim's default 'modelines' setting
+const modelineScanLines = 5
+
+var (
+	// vim: ft=python / vim: set filetype=cpp : / vi: syntax=go
+	vimModeline = regexp.MustCompile(`(?:^|\s)(?:vi|vim|ex):\s*(?:set?\s+)?(?:.*[\s:])?(?:ft|filetype|syn|syntax)=([\w+.-]+)`)
+
+	// -*- mode: c++ -*- / -*- Mode: Python; indent-tabs-mode: nil -*-
+	emacsModeVariable = regexp.MustCompile(`(?i)-\*-(?:\s*|.*?[\s;])mode:\s*([\w+.-]+).*-\*-`)
+
+	// -*- c++ -*-
+	emacsModeShort = regexp.MustCompile(`-\*-\s*([\w+.-]+)\s*-\*-`)
+)
+
+// DetectModeline looks for a Vim or Emacs modeline naming the file's language
+func DetectModeline(content []byte) (string, bool) {
+	lines := bytes.Split(content, []byte("\n"))
+
+	// Emacs only honors the first line, or the second one after a shebang
+	emacsLines := lines
+	if len(emacsLines) > 2 {
+		emacsLines = emacsLines[:2]
+	}
+	for i, line := range emacsLines {
+		if i == 1 && !bytes.HasPrefix(lines[0], []byte("#!")) {
+			break
+		}
+		if language, ok := matchEmacsModeline(string(line)); ok {
+			return language, true
+		}
+	}
+
+	// Vim checks the first and last few lines
+	candidates := lines
+	if len(lines) > 2*modelineScanLines {
+		candidates = append(append([][]byte{}, lines[:modelineScanLines]...), lines[len(lines)-modelineScanLines:]...)
+	}
+	for _, line := range candidates {
+		if match := vimModeline.FindSubmatch(line); match != nil {
+			if language, ok := modelineLanguage(string(match[1])); ok {
+				return language, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func matchEmacsModeline(line string) (string, bool) {
+	if match := emacsModeVariable.FindStringSubmatch(line); match != nil {
+		return modelineLanguage(match[1])
+	}
+	if match := emacsModeShort.FindStringSubmatch(line); match != nil {
+		return modelineLanguage(match[1])
+	}
+	return "", false
+}
+
+// modelineLanguage maps editor mode names (e.g. "c++", "python-mode") to languages
+func modelineLanguage(mode string) (string, bool) {
+	mode = strings.TrimSuffix(strings.ToLower(mode), "-mode")
+	switch mode {
+	case "js2", "rjsx":
+		mode = "javascript"
+	case "typescript-tsx":
+		mode = "tsx"
+	}
+	return LookupLanguage(mode)
+}
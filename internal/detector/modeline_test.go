@@ -0,0 +1,48 @@
+package detector
+
+import "testing"
+
+func TestDetectModeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"vim ft", "x = 1\n# vim: ft=python\n", "python"},
+		{"vim set filetype", "// vim: set ts=4 filetype=cpp :\nint x;\n", "cpp"},
+		{"emacs mode variable", "/* -*- mode: c++; indent-tabs-mode: nil -*- */\n", "cpp"},
+		{"emacs short form", "# -*- python -*-\n", "python"},
+		{"emacs after shebang", "#!/bin/sh\n// -*- mode: js2 -*-\n", "javascript"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, ok := DetectModeline([]byte(tt.content))
+			if !ok {
+				t.Fatalf("Expected modeline to be detected")
+			}
+			if language != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, language)
+			}
+		})
+	}
+}
+
+func TestDetectModelineOverridesExtension(t *testing.T) {
+	language, ok := Detect("header.h", []byte("// -*- mode: c++ -*-\nclass Foo {};\n"))
+	if !ok || language != "cpp" {
+		t.Errorf("Expected modeline to override extension, got %s", language)
+	}
+}
+
+func TestDetectModelineIgnoresDistantLines(t *testing.T) {
+	content := "line\n"
+	for i := 0; i < 20; i++ {
+		content += "line\n"
+	}
+	content = "a\nb\nc\nd\ne\nf\n# vim: ft=python\n" + content
+
+	if language, ok := DetectModeline([]byte(content)); ok {
+		t.Errorf("Expected modeline outside scan window to be ignored, got %s", language)
+	}
+}
@@ -0,0 +1,105 @@
+package detector
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DetectionRule teaches the detector to recognize additional files at runtime
+type DetectionRule struct {
+	// Language is the language name files matching the rule resolve to
+	Language string
+	// Extensions are file extensions including the dot, e.g. ".inc"
+	Extensions []string
+	// Filenames are exact base names, e.g. "Justfile"
+	Filenames []string
+	// Match optionally inspects a file that no extension or filename rule
+	// claimed and reports whether it belongs to Language
+	Match func(filePath string, content []byte) bool
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   []DetectionRule
+)
+
+// Register adds a detection rule. Registered rules take precedence over the
+// built-in tables, and later registrations take precedence over earlier ones.
+func Register(rule DetectionRule) error {
+	if rule.Language == "" {
+		return fmt.Errorf("detection rule has no language")
+	}
+	if len(rule.Extensions) == 0 && len(rule.Filenames) == 0 && rule.Match == nil {
+		return fmt.Errorf("detection rule for %s matches nothing", rule.Language)
+	}
+
+	normalized := rule
+	normalized.Extensions = make([]string, len(rule.Extensions))
+	for i, ext := range rule.Extensions {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		normalized.Extensions[i] = ext
+	}
+
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules = append(rules, normalized)
+	return nil
+}
+
+// registeredRules returns a snapshot of the rules, most recent first
+func registeredRules() []DetectionRule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	snapshot := make([]DetectionRule, len(rules))
+	for i, rule := range rules {
+		snapshot[len(rules)-1-i] = rule
+	}
+	return snapshot
+}
+
+// detectRegisteredName matches a path against registered filename and extension rules
+func detectRegisteredName(filePath string) (string, bool) {
+	base := filepath.Base(filePath)
+	ext := strings.ToLower(filepath.Ext(filePath))
+
+	for _, rule := range registeredRules() {
+		for _, filename := range rule.Filenames {
+			if base == filename {
+				return rule.Language, true
+			}
+		}
+		for _, ruleExt := range rule.Extensions {
+			if ext == ruleExt {
+				return rule.Language, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// detectRegisteredContent runs the Match functions of registered rules
+func detectRegisteredContent(filePath string, content []byte) (string, bool) {
+	for _, rule := range registeredRules() {
+		if rule.Match != nil && rule.Match(filePath, content) {
+			return rule.Language, true
+		}
+	}
+
+	return "", false
+}
+
+// registeredExtensions lists the extensions added through Register
+func registeredExtensions() []string {
+	var extensions []string
+	for _, rule := range registeredRules() {
+		extensions = append(extensions, rule.Extensions...)
+	}
+	return extensions
+}
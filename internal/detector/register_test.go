@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"bytes"
+	"testing"
+)
+
+// resetRules restores the registry after a test
+func resetRules(t *testing.T) {
+	t.Cleanup(func() {
+		rulesMu.Lock()
+		rules = nil
+		rulesMu.Unlock()
+	})
+}
+
+func TestRegisterExtensionsAndFilenames(t *testing.T) {
+	resetRules(t)
+
+	if err := Register(DetectionRule{Language: "c", Extensions: []string{"inc", ".H"}, Filenames: []string{"Kconfig.h"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"table.inc": "c",
+		"legacy.h":  "c",
+		"Kconfig.h": "c",
+	}
+	for path, expected := range tests {
+		language, ok := Detect(path, []byte("class Foo {};\n"))
+		if !ok || language != expected {
+			t.Errorf("%s: expected %s, got %q", path, expected, language)
+		}
+	}
+
+	found := false
+	for _, ext := range SupportedExtensions() {
+		if ext == ".inc" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected registered extension to be listed as supported")
+	}
+}
+
+func TestRegisterMatchAndPrecedence(t *testing.T) {
+	resetRules(t)
+
+	if err := Register(DetectionRule{
+		Language: "python",
+		Match: func(filePath string, content []byte) bool {
+			return bytes.HasPrefix(content, []byte("# starlark"))
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(DetectionRule{Language: "javascript", Extensions: []string{".conf"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(DetectionRule{Language: "typescript", Extensions: []string{".conf"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if language, ok := Detect("rules", []byte("# starlark\n")); !ok || language != "python" {
+		t.Errorf("Expected Match rule to detect python, got %q", language)
+	}
+	if language, ok := Detect("app.conf", nil); !ok || language != "typescript" {
+		t.Errorf("Expected the most recent rule to win, got %q", language)
+	}
+}
+
+func TestRegisterRejectsIncompleteRules(t *testing.T) {
+	resetRules(t)
+
+	if err := Register(DetectionRule{Extensions: []string{".x"}}); err == nil {
+		t.Error("Expected error for rule without language")
+	}
+	if err := Register(DetectionRule{Language: "go"}); err == nil {
+		t.Error("Expected error for rule that matches nothing")
+	}
+}
@@ -0,0 +1,151 @@
+// Package docgen generates linked documentation pages for a project from the
+// symbols and doc comments the extractors find, in Markdown and HTML. Each
+// directory is a package with a page of its own, listing the exported
+// symbols of its files.
+package docgen
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// Site is the documentation of a project, built up file by file
+type Site struct {
+	// Title heads the index page
+	Title    string
+	packages map[string]*Package
+}
+
+// Package is the documented files of one directory
+type Package struct {
+	// Path is the slash-separated directory relative to the project root,
+	// "." for the root itself
+	Path  string
+	Files []File
+
+	parent      *Package
+	subpackages []*Package
+}
+
+// File is the exported symbols of one source file
+type File struct {
+	Path     string
+	Language string
+	Symbols  []outline.SymbolInfo
+}
+
+// NewSite returns an empty site with the given title
+func NewSite(title string) *Site {
+	return &Site{Title: title, packages: make(map[string]*Package)}
+}
+
+// Add documents the exported symbols of a file, whose path is relative to
+// the project root. Files without exported symbols are left out.
+func (s *Site) Add(file string, language string, symbols []outline.SymbolInfo) {
+	exported := exportedSymbols(symbols)
+	if len(exported) == 0 {
+		return
+	}
+
+	file = filepath.ToSlash(file)
+	dir := path.Dir(file)
+	pkg, ok := s.packages[dir]
+	if !ok {
+		pkg = &Package{Path: dir}
+		s.packages[dir] = pkg
+	}
+	pkg.Files = append(pkg.Files, File{Path: file, Language: language, Symbols: exported})
+}
+
+// exportedSymbols drops private symbols and members, recursively
+func exportedSymbols(symbols []outline.SymbolInfo) []outline.SymbolInfo {
+	var exported []outline.SymbolInfo
+	for _, symbol := range symbols {
+		if !symbol.IsPublic {
+			continue
+		}
+		symbol.Children = exportedSymbols(symbol.Children)
+		exported = append(exported, symbol)
+	}
+	return exported
+}
+
+// Packages returns the documented packages sorted by path, with the
+// hierarchy between them resolved
+func (s *Site) Packages() []*Package {
+	packages := make([]*Package, 0, len(s.packages))
+	for _, pkg := range s.packages {
+		pkg.parent, pkg.subpackages = nil, nil
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+
+	// The parent of a package is its nearest documented ancestor, so
+	// directories without exported symbols do not break the chain
+	for _, pkg := range packages {
+		for dir := pkg.Path; dir != "." && dir != "/"; {
+			dir = path.Dir(dir)
+			if parent, ok := s.packages[dir]; ok {
+				pkg.parent = parent
+				parent.subpackages = append(parent.subpackages, pkg)
+				break
+			}
+		}
+	}
+	return packages
+}
+
+// Write generates the site into dir: an index page listing the packages,
+// which also documents the root package, and a page per other package. Every
+// page is written as both Markdown and HTML.
+func (s *Site) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating %s: %v", dir, err)
+	}
+
+	packages := s.Packages()
+	pages := []*page{s.indexPage(packages)}
+	for _, pkg := range packages {
+		if pkg.Path != "." {
+			pages = append(pages, s.packagePage(pkg))
+		}
+	}
+
+	for _, p := range pages {
+		for _, format := range []struct {
+			ext    string
+			render func(p *page) ([]byte, error)
+		}{{".md", renderMarkdown}, {".html", renderHTML}} {
+			content, err := format.render(p)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, p.name+format.ext), content, 0o644); err != nil {
+				return fmt.Errorf("error writing %s: %v", p.name+format.ext, err)
+			}
+		}
+	}
+	return nil
+}
+
+// pageName returns the file name, without extension, of the page of a
+// package
+func pageName(pkg *Package) string {
+	if pkg == nil || pkg.Path == "." {
+		return "index"
+	}
+	name := strings.ReplaceAll(pkg.Path, "/", ".")
+	if name == "index" {
+		// Keep a top-level directory called index from replacing the index
+		return "index_"
+	}
+	return name
+}
@@ -0,0 +1,77 @@
+package docgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func addFile(t *testing.T, site *Site, path, language, code string) {
+	t.Helper()
+	symbols, err := outline.ExtractSymbols([]byte(code), language)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	site.Add(path, language, symbols)
+}
+
+func readPage(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestWriteSite(t *testing.T) {
+	site := NewSite("demo")
+	addFile(t, site, "main.go", "go", "package main\n\n// Version of the demo\nconst Version = \"1\"\n")
+	addFile(t, site, "server/run.go", "go", "package server\n\n// Run starts serving\nfunc (s *Server) Run() {}\n\nfunc helper() {}\n")
+	addFile(t, site, "server/server.go", "go", "package server\n\n// Server serves <requests>\ntype Server struct {\n\tAddr string\n\tconn int\n}\n")
+	addFile(t, site, "server/internal/util.go", "go", "package internal\n\nfunc private() {}\n")
+	addFile(t, site, "server/http/http.py", "python", "def handle(request):\n    \"\"\"Handles a request\"\"\"\n")
+
+	dir := t.TempDir()
+	if err := site.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	index := readPage(t, dir, "index.md")
+	for _, want := range []string{"# demo", "[server](server.md)", "[server/http](server.http.md) | python | 1 |", "### Version", "Version of the demo"} {
+		if !strings.Contains(index, want) {
+			t.Errorf("Expected the index to contain %q, got:\n%s", want, index)
+		}
+	}
+	if strings.Contains(index, "server/internal") {
+		t.Errorf("Expected packages without exported symbols to be left out, got:\n%s", index)
+	}
+
+	server := readPage(t, dir, "server.md")
+	for _, want := range []string{
+		"[demo](index.md) › server",
+		"- [server/http](server.http.md)",
+		"- <a id=\"Server.Addr\"></a>`Addr string`",
+		"- <a id=\"Server.Run\"></a>`func (s *Server) Run()`\n  Run starts serving",
+	} {
+		if !strings.Contains(server, want) {
+			t.Errorf("Expected the server page to contain %q, got:\n%s", want, server)
+		}
+	}
+	if strings.Contains(server, "helper") || strings.Contains(server, "conn") || strings.Contains(server, "### Run") {
+		t.Errorf("Expected only exported symbols, with methods under their type, got:\n%s", server)
+	}
+
+	http := readPage(t, dir, "server.http.html")
+	for _, want := range []string{`<a href="index.html">demo</a> › <a href="server.html">server</a> › server/http`, `<h3 id="handle">handle</h3>`, "Handles a request"} {
+		if !strings.Contains(http, want) {
+			t.Errorf("Expected the HTML page to contain %q, got:\n%s", want, http)
+		}
+	}
+	if html := readPage(t, dir, "server.html"); !strings.Contains(html, "Server serves &lt;requests&gt;") {
+		t.Errorf("Expected documentation to be escaped in HTML, got:\n%s", html)
+	}
+}
@@ -0,0 +1,342 @@
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// page is the content of one generated page, rendered as Markdown and HTML
+type page struct {
+	name  string
+	title string
+	// breadcrumbs lead from the index to the parent of the page
+	breadcrumbs []link
+	// packages is only set on the index
+	packages    []packageRow
+	subpackages []link
+	files       []fileEntry
+}
+
+// link is a page, referenced by name without extension
+type link struct {
+	Text string
+	Page string
+}
+
+type packageRow struct {
+	link
+	Languages string
+	Symbols   int
+}
+
+type fileEntry struct {
+	Path     string
+	Language string
+	Entries  []entry
+}
+
+// entry is a symbol with its anchor on the page
+type entry struct {
+	Signature     string
+	Documentation string
+	Name          string
+	Anchor        string
+	Children      []entry
+}
+
+func (s *Site) indexPage(packages []*Package) *page {
+	p := &page{name: "index", title: s.Title}
+	for _, pkg := range packages {
+		if pkg.Path == "." {
+			p.files = entries(pkg.Files)
+			continue
+		}
+		p.packages = append(p.packages, packageRow{
+			link:      link{Text: pkg.Path, Page: pageName(pkg)},
+			Languages: strings.Join(languages(pkg), ", "),
+			Symbols:   countSymbols(pkg),
+		})
+	}
+	return p
+}
+
+func (s *Site) packagePage(pkg *Package) *page {
+	p := &page{name: pageName(pkg), title: pkg.Path, files: entries(pkg.Files)}
+	for parent := pkg.parent; parent != nil; parent = parent.parent {
+		if parent.Path != "." {
+			p.breadcrumbs = append([]link{{Text: parent.Path, Page: pageName(parent)}}, p.breadcrumbs...)
+		}
+	}
+	p.breadcrumbs = append([]link{{Text: s.Title, Page: "index"}}, p.breadcrumbs...)
+	for _, sub := range pkg.subpackages {
+		p.subpackages = append(p.subpackages, link{Text: sub.Path, Page: pageName(sub)})
+	}
+	return p
+}
+
+func languages(pkg *Package) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, file := range pkg.Files {
+		if !seen[file.Language] {
+			seen[file.Language] = true
+			result = append(result, file.Language)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func countSymbols(pkg *Package) int {
+	var count func(symbols []outline.SymbolInfo) int
+	count = func(symbols []outline.SymbolInfo) int {
+		n := len(symbols)
+		for _, symbol := range symbols {
+			n += count(symbol.Children)
+		}
+		return n
+	}
+
+	total := 0
+	for _, file := range pkg.Files {
+		total += count(file.Symbols)
+	}
+	return total
+}
+
+// entries assigns every symbol of the files an anchor unique on their page
+func entries(files []File) []fileEntry {
+	used := make(map[string]bool)
+	var build func(symbols []outline.SymbolInfo, scope string) []entry
+	build = func(symbols []outline.SymbolInfo, scope string) []entry {
+		var result []entry
+		for _, symbol := range symbols {
+			name := scope + symbol.Name
+			anchor := anchorFor(name)
+			for i := 2; used[anchor]; i++ {
+				anchor = fmt.Sprintf("%s-%d", anchorFor(name), i)
+			}
+			used[anchor] = true
+
+			signature := symbol.Signature.String()
+			if signature == "" {
+				signature = symbol.Type + " " + symbol.Name
+			}
+			result = append(result, entry{
+				Signature:     signature,
+				Documentation: symbol.Documentation.String(),
+				Name:          name,
+				Anchor:        anchor,
+				Children:      build(symbol.Children, name+"."),
+			})
+		}
+		return result
+	}
+
+	// Go methods are documented with their type, whichever file of the
+	// package declares them
+	types := make(map[string]bool)
+	for _, file := range files {
+		for _, symbol := range file.Symbols {
+			switch symbol.Type {
+			case "type", "struct", "interface":
+				types[symbol.Name] = true
+			}
+		}
+	}
+
+	result := make([]fileEntry, len(files))
+	methods := make(map[string][]entry)
+	for i, file := range files {
+		var symbols []outline.SymbolInfo
+		for _, symbol := range file.Symbols {
+			if receiver := outline.MethodReceiver(symbol); types[receiver] {
+				methods[receiver] = append(methods[receiver], build([]outline.SymbolInfo{symbol}, receiver+".")...)
+				continue
+			}
+			symbols = append(symbols, symbol)
+		}
+		result[i] = fileEntry{Path: file.Path, Language: file.Language, Entries: build(symbols, "")}
+	}
+
+	for i := range result {
+		for j := range result[i].Entries {
+			e := &result[i].Entries[j]
+			e.Children = append(e.Children, methods[e.Name]...)
+			delete(methods, e.Name)
+		}
+	}
+	return result
+}
+
+// anchorFor keeps the characters of name that are safe in a URL fragment
+func anchorFor(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// renderMarkdown renders a page as Markdown, linking to the Markdown pages
+func renderMarkdown(p *page) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", p.title)
+
+	if len(p.breadcrumbs) > 0 {
+		for _, crumb := range p.breadcrumbs {
+			fmt.Fprintf(&b, "[%s](%s.md) › ", crumb.Text, crumb.Page)
+		}
+		fmt.Fprintf(&b, "%s\n\n", p.title)
+	}
+
+	if len(p.packages) > 0 {
+		b.WriteString("## Packages\n\n| Package | Languages | Symbols |\n|---------|-----------|---------|\n")
+		for _, row := range p.packages {
+			fmt.Fprintf(&b, "| [%s](%s.md) | %s | %d |\n", row.Text, row.Page, row.Languages, row.Symbols)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(p.subpackages) > 0 {
+		b.WriteString("## Subpackages\n\n")
+		for _, sub := range p.subpackages {
+			fmt.Fprintf(&b, "- [%s](%s.md)\n", sub.Text, sub.Page)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(p.files) == 0 {
+		return b.Bytes(), nil
+	}
+
+	b.WriteString("## Contents\n\n")
+	for _, file := range p.files {
+		for _, e := range file.Entries {
+			fmt.Fprintf(&b, "- [%s](#%s)\n", e.Name, e.Anchor)
+		}
+	}
+	b.WriteString("\n")
+
+	for _, file := range p.files {
+		fmt.Fprintf(&b, "## %s\n\n", file.Path)
+		for _, e := range file.Entries {
+			fmt.Fprintf(&b, "<a id=\"%s\"></a>\n\n### %s\n\n```%s\n%s\n```\n\n", e.Anchor, e.Name, file.Language, e.Signature)
+			if e.Documentation != "" {
+				fmt.Fprintf(&b, "%s\n\n", e.Documentation)
+			}
+			if len(e.Children) > 0 {
+				writeMarkdownMembers(&b, e.Children, "")
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// writeMarkdownMembers lists the members of a type, nesting their own
+// members below them
+func writeMarkdownMembers(b *bytes.Buffer, members []entry, indent string) {
+	for _, member := range members {
+		fmt.Fprintf(b, "%s- <a id=\"%s\"></a>`%s`\n", indent, member.Anchor, strings.Join(strings.Fields(member.Signature), " "))
+		if member.Documentation != "" {
+			for _, line := range strings.Split(member.Documentation, "\n") {
+				fmt.Fprintf(b, "%s\n", strings.TrimRight(indent+"  "+line, " "))
+			}
+		}
+		writeMarkdownMembers(b, member.Children, indent+"  ")
+	}
+}
+
+var htmlTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 56rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+pre, code { font-family: ui-monospace, monospace; }
+pre { background: #f6f8fa; padding: 0.75rem; overflow-x: auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 0.25rem 0.75rem; text-align: left; }
+nav { color: #57606a; }
+.doc { white-space: pre-line; }
+</style>
+</head>
+<body>
+{{- if .Breadcrumbs}}
+<nav>{{range .Breadcrumbs}}<a href="{{.Page}}.html">{{.Text}}</a> › {{end}}{{.Title}}</nav>
+{{- end}}
+<h1>{{.Title}}</h1>
+{{- if .Packages}}
+<h2>Packages</h2>
+<table>
+<tr><th>Package</th><th>Languages</th><th>Symbols</th></tr>
+{{- range .Packages}}
+<tr><td><a href="{{.Page}}.html">{{.Text}}</a></td><td>{{.Languages}}</td><td>{{.Symbols}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Subpackages}}
+<h2>Subpackages</h2>
+<ul>
+{{- range .Subpackages}}
+<li><a href="{{.Page}}.html">{{.Text}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Files}}
+<h2>Contents</h2>
+<ul>
+{{- range .Files}}{{range .Entries}}
+<li><a href="#{{.Anchor}}">{{.Name}}</a></li>
+{{- end}}{{end}}
+</ul>
+{{- range .Files}}
+<h2>{{.Path}}</h2>
+{{- range .Entries}}
+<h3 id="{{.Anchor}}">{{.Name}}</h3>
+<pre><code>{{.Signature}}</code></pre>
+{{- if .Documentation}}
+<p class="doc">{{.Documentation}}</p>
+{{- end}}
+{{- if .Children}}{{template "members" .Children}}{{end}}
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+{{define "members"}}
+<ul>
+{{- range .}}
+<li id="{{.Anchor}}"><code>{{.Signature}}</code>
+{{- if .Documentation}}<p class="doc">{{.Documentation}}</p>{{end}}
+{{- if .Children}}{{template "members" .Children}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+`))
+
+// renderHTML renders a page as a standalone HTML document, linking to the
+// HTML pages
+func renderHTML(p *page) ([]byte, error) {
+	var b bytes.Buffer
+	err := htmlTemplate.Execute(&b, struct {
+		Title       string
+		Breadcrumbs []link
+		Packages    []packageRow
+		Subpackages []link
+		Files       []fileEntry
+	}{p.title, p.breadcrumbs, p.packages, p.subpackages, p.files})
+	if err != nil {
+		return nil, fmt.Errorf("error rendering %s.html: %v", p.name, err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,17 @@
+// Package clojure provides the tree-sitter grammar for Clojure, which has no
+// Go bindings upstream. parser.c and tree_sitter/parser.h are generated
+// sources copied unchanged from github.com/sogaiu/tree-sitter-clojure v0.0.13
+// (CC0, see COPYING.txt).
+package clojure
+
+// #cgo CFLAGS: -std=c11 -fPIC
+// typedef struct TSLanguage TSLanguage;
+// const TSLanguage *tree_sitter_clojure(void);
+import "C"
+
+import "unsafe"
+
+// Language returns the tree-sitter language for Clojure
+func Language() unsafe.Pointer {
+	return unsafe.Pointer(C.tree_sitter_clojure())
+}
@@ -0,0 +1,106 @@
+// Package gtags writes the definitions and references of a file as the tag
+// records GNU Global reads from a ctags-compatible parser, so that gtags can
+// build its GTAGS and GRTAGS databases for languages it has no parser for.
+package gtags
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// Terminator is the line that ends the records of each file in filter mode,
+// the one Global's universal-ctags parser waits for
+const Terminator = "###terminator###"
+
+// Tag is a definition or a reference of a name at a line of a file
+type Tag struct {
+	Definition bool
+	Name       string
+	Line       int
+	Path       string
+	// Image is the source line, without indentation
+	Image string
+}
+
+// FromFile returns a definition for every symbol of a file and a reference
+// for every other identifier, in the order of their lines. Function
+// prototypes are references, so that a function is defined where its body
+// is.
+func FromFile(path string, content []byte, language string) ([]Tag, error) {
+	symbols, err := outline.ExtractSymbols(content, language)
+	if err != nil {
+		return nil, err
+	}
+	identifiers, err := outline.FindIdentifiers(content, language)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	defined := map[int]bool{}
+	var add func(symbols []outline.SymbolInfo)
+	add = func(symbols []outline.SymbolInfo) {
+		for _, symbol := range symbols {
+			prototype := symbol.Complexity == 0 && strings.HasSuffix(symbol.Signature.String(), ";") &&
+				(symbol.Type == "function" || symbol.Type == "method")
+			if start, end := outline.NameSpan(content, symbol); !prototype && symbol.Name != "" && string(content[start:end]) == symbol.Name {
+				defined[start] = true
+				tags = append(tags, Tag{Definition: true, Name: symbol.Name, Line: lineAt(content, start), Path: path, Image: lineText(content, start)})
+			}
+			add(symbol.Children)
+		}
+	}
+	add(symbols)
+
+	lineStart := 0
+	line := 1
+	for _, identifier := range identifiers {
+		for ; line < identifier.Line; line++ {
+			lineStart += bytes.IndexByte(content[lineStart:], '\n') + 1
+		}
+		if !defined[lineStart+identifier.Column-1] {
+			tags = append(tags, Tag{Name: identifier.Name, Line: identifier.Line, Path: path, Image: identifier.Text})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].Line < tags[j].Line })
+	return tags, nil
+}
+
+// lineAt returns the 1-based line of a byte offset
+func lineAt(content []byte, offset int) int {
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// lineText returns the line holding a byte offset, without indentation
+func lineText(content []byte, offset int) string {
+	start := bytes.LastIndexByte(content[:offset], '\n') + 1
+	end := bytes.IndexByte(content[offset:], '\n')
+	if end < 0 {
+		end = len(content)
+	} else {
+		end += offset
+	}
+	return strings.TrimSpace(string(content[start:end]))
+}
+
+// Write writes tags in the cross-reference format of universal-ctags'
+// --_xformat="%R %-16N %4n %-16F %C": D or R, the name, the line, the path
+// and the source line
+func Write(w io.Writer, tags []Tag) error {
+	b := bufio.NewWriter(w)
+	for _, tag := range tags {
+		role := "R"
+		if tag.Definition {
+			role = "D"
+		}
+		fmt.Fprintf(b, "%s %-16s %4d %-16s %s\n", role, tag.Name, tag.Line, tag.Path, tag.Image)
+	}
+	return b.Flush()
+}
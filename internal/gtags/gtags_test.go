@@ -0,0 +1,70 @@
+package gtags
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const sampleC = `int add(int a, int b);
+
+int add(int a, int b) {
+	return a + b;
+}
+
+int twice(int n) {
+	return add(n, n);
+}
+`
+
+func TestFromFile(t *testing.T) {
+	tags, err := FromFile("src/add.c", []byte(sampleC), "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var definitions, references []string
+	for _, tag := range tags {
+		if tag.Name != "add" {
+			continue
+		}
+		if tag.Definition {
+			definitions = append(definitions, tag.Image)
+		} else {
+			references = append(references, tag.Image)
+		}
+	}
+	// The prototype and the call refer to the function defined with a body
+	if len(definitions) != 1 || definitions[0] != "int add(int a, int b) {" {
+		t.Errorf("Expected add to be defined once, with its body, got %q", definitions)
+	}
+	if len(references) != 2 || references[1] != "return add(n, n);" {
+		t.Errorf("Expected the prototype and the call as references, got %q", references)
+	}
+
+	for i := 1; i < len(tags); i++ {
+		if tags[i].Line < tags[i-1].Line {
+			t.Fatalf("Expected tags in line order, got %+v", tags)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var out bytes.Buffer
+	err := Write(&out, []Tag{
+		{Definition: true, Name: "twice", Line: 7, Path: "src/add.c", Image: "int twice(int n) {"},
+		{Name: "add", Line: 8, Path: "src/add.c", Image: "return add(n, n);"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "D twice               7 src/add.c        int twice(int n) {\n" +
+		"R add                 8 src/add.c        return add(n, n);\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	if strings.Contains(out.String(), Terminator) {
+		t.Error("Expected the terminator to be left to filter mode")
+	}
+}
@@ -0,0 +1,191 @@
+// Package ignore reads the .gitignore and .ignore files of a project and
+// tells which of the paths found walking a directory they exclude.
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// files are the ignore files read in every directory, in order: rules of
+// .ignore files, which only tools other than git read, override those of
+// .gitignore files
+var files = []string{".gitignore", ".ignore"}
+
+// rule is a pattern of an ignore file
+type rule struct {
+	re *regexp.Regexp
+	// negate re-includes the paths matched, as a pattern starting with !
+	negate bool
+	// dirOnly restricts the pattern to directories, as a trailing slash
+	dirOnly bool
+}
+
+// Matcher tells which paths below a directory are ignored. The ignore files
+// of the directories between the repository root and the directory walked
+// apply too, as git applies them. Files are read once, as the directories
+// are first looked at.
+type Matcher struct {
+	top   string
+	rules map[string][]rule
+}
+
+// New returns a matcher for the paths below root
+func New(root string) *Matcher {
+	m := &Matcher{rules: make(map[string][]rule)}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return m
+	}
+	m.top = abs
+	for dir := abs; ; {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			m.top = dir
+			m.rules[dir] = append(readRules(filepath.Join(dir, ".git", "info", "exclude")), m.load(dir)...)
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return m
+}
+
+// Ignored reports whether path, a directory when dir is set, is excluded.
+// The last pattern matching it wins, patterns of deeper directories coming
+// after those of their parents. A nil matcher ignores nothing.
+func (m *Matcher) Ignored(path string, dir bool) bool {
+	if m == nil || m.top == "" {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(m.top, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+
+	ignored := false
+	base := m.top
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i := range parts {
+		if i > 0 {
+			base = filepath.Join(base, parts[i-1])
+		}
+		below := strings.Join(parts[i:], "/")
+		for _, r := range m.rulesOf(base) {
+			if (!r.dirOnly || dir) && r.re.MatchString(below) {
+				ignored = !r.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// rulesOf returns the rules of the ignore files of a directory
+func (m *Matcher) rulesOf(dir string) []rule {
+	rules, ok := m.rules[dir]
+	if !ok {
+		rules = m.load(dir)
+		m.rules[dir] = rules
+	}
+	return rules
+}
+
+func (m *Matcher) load(dir string) []rule {
+	var rules []rule
+	for _, name := range files {
+		rules = append(rules, readRules(filepath.Join(dir, name))...)
+	}
+	return rules
+}
+
+// readRules reads the patterns of an ignore file, skipping those that are
+// invalid as git does. A missing file has none.
+func readRules(path string) []rule {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules []rule
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		line := strings.TrimRight(lines.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var r rule
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		r.dirOnly = strings.HasSuffix(line, "/")
+		re, err := Compile(line)
+		if err != nil {
+			continue
+		}
+		r.re = re
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// Compile turns a gitignore-style pattern into a regular expression matching
+// the paths it covers, relative to the directory of the file it is in and
+// with forward slashes, including every file below a matching directory
+func Compile(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	// A trailing slash restricts a pattern to directories; paths looked up
+	// are either files below them or the directories themselves
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	// A slash anywhere but at the end anchors the pattern to the root
+	if strings.Contains(pattern, "/") {
+		anchored = true
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("(?:/.*)?$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,62 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, ".git", "info", "exclude"), "*.local.go\n")
+	writeFile(t, filepath.Join(root, ".gitignore"), "# Build output\n/build\ngen/\n*.pb.go\n!keep.pb.go\n")
+	writeFile(t, filepath.Join(root, "src", ".gitignore"), "fixtures/**/*.js\n")
+	writeFile(t, filepath.Join(root, "src", ".ignore"), "!fixtures/big.js\n")
+
+	tests := []struct {
+		path    string
+		dir     bool
+		ignored bool
+	}{
+		{"build", true, true},
+		{"src/build", true, false},
+		{"gen", true, true},
+		{"src/gen", true, true},
+		{"gen", false, false},
+		{"api/service.pb.go", false, true},
+		{"api/keep.pb.go", false, false},
+		{"main.local.go", false, true},
+		{"src/fixtures/a/b.js", false, true},
+		{"src/fixtures/big.js", false, false},
+		{"fixtures/a.js", false, false},
+		{"src/main.go", false, false},
+	}
+
+	// The patterns of the repository apply to a walk of a subdirectory too
+	for _, walked := range []string{root, filepath.Join(root, "src")} {
+		m := New(walked)
+		for _, test := range tests {
+			if got := m.Ignored(filepath.Join(root, filepath.FromSlash(test.path)), test.dir); got != test.ignored {
+				t.Errorf("Ignored(%q, %v) walking %s = %v, want %v", test.path, test.dir, walked, got, test.ignored)
+			}
+		}
+	}
+
+	var none *Matcher
+	if none.Ignored(filepath.Join(root, "build"), true) {
+		t.Error("Expected a nil matcher to ignore nothing")
+	}
+}
@@ -0,0 +1,281 @@
+// Package index stores the symbols of a project in a SQLite database with a
+// full-text index, so repeated lookups do not re-parse the project. Files
+// are re-indexed only when their size or modification time changes.
+package index
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// schema creates the tables of an index. symbols_fts indexes the searchable
+// columns of symbols, and triggers keep it in sync.
+const schema = `
+CREATE TABLE IF NOT EXISTS files (
+	id INTEGER PRIMARY KEY,
+	path TEXT NOT NULL UNIQUE,
+	language TEXT NOT NULL,
+	size INTEGER NOT NULL,
+	mtime INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS symbols (
+	id INTEGER PRIMARY KEY,
+	file_id INTEGER NOT NULL REFERENCES files(id),
+	parent_id INTEGER REFERENCES symbols(id),
+	name TEXT NOT NULL,
+	fqn TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	signature TEXT NOT NULL,
+	documentation TEXT NOT NULL,
+	start_line INTEGER NOT NULL,
+	start_column INTEGER NOT NULL,
+	end_line INTEGER NOT NULL,
+	end_column INTEGER NOT NULL,
+	public INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS symbols_file ON symbols(file_id);
+CREATE INDEX IF NOT EXISTS symbols_name ON symbols(name);
+CREATE VIRTUAL TABLE IF NOT EXISTS symbols_fts USING fts4(
+	content="symbols", name, fqn, signature, documentation
+);
+CREATE TRIGGER IF NOT EXISTS symbols_fts_insert AFTER INSERT ON symbols BEGIN
+	INSERT INTO symbols_fts(docid, name, fqn, signature, documentation)
+	VALUES (new.id, new.name, new.fqn, new.signature, new.documentation);
+END;
+CREATE TRIGGER IF NOT EXISTS symbols_fts_delete BEFORE DELETE ON symbols BEGIN
+	DELETE FROM symbols_fts WHERE docid = old.id;
+END;
+`
+
+// DB is an open symbol index
+type DB struct {
+	db *sql.DB
+}
+
+// Open opens the index at path, creating it when it does not exist
+func Open(path string) (*DB, error) {
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("error opening index: %v", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating index %s: %v", path, err)
+	}
+	return &DB{db: db}, nil
+}
+
+// Close closes the index
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Stats summarizes an update
+type Stats struct {
+	Indexed   int
+	Unchanged int
+	Removed   int
+	Failed    int
+}
+
+// fileState is what an index remembers of a file to tell whether it changed
+type fileState struct {
+	size  int64
+	mtime int64
+}
+
+// Update indexes every supported file below root, which may also be a
+// single file. Files whose size and modification time are unchanged since
+// the last update are not parsed again, and files that no longer exist are
+// removed from the index, all of them when root itself was removed. onError
+// is called for each file that could not be indexed.
+func (d *DB) Update(ctx context.Context, root string, opts scanner.Options, onError func(path string, err error)) (Stats, error) {
+	var stats Stats
+	known, err := d.files(root)
+	if err != nil {
+		return stats, err
+	}
+
+	seen := make(map[string]bool)
+	opts.Symbols = true
+	skip := opts.Skip
+	opts.Skip = func(path string, entry fs.DirEntry) bool {
+		if skip != nil && skip(path, entry) {
+			return true
+		}
+		if entry.IsDir() {
+			return false
+		}
+		seen[path] = true
+		state, ok := known[path]
+		if !ok {
+			return false
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() != state.size || info.ModTime().UnixNano() != state.mtime {
+			return false
+		}
+		stats.Unchanged++
+		return true
+	}
+
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return stats, d.removeUnseen(known, seen, &stats)
+	}
+
+	err = scanner.Scan(ctx, root, opts, func(result scanner.Result) error {
+		if result.Err != nil {
+			stats.Failed++
+			if onError != nil {
+				onError(result.Path, result.Err)
+			}
+			return nil
+		}
+		info, err := os.Stat(result.Path)
+		if err != nil {
+			stats.Failed++
+			if onError != nil {
+				onError(result.Path, err)
+			}
+			return nil
+		}
+		if err := d.ReplaceFile(result.Path, result.Language, info.Size(), info.ModTime(), result.Symbols); err != nil {
+			return err
+		}
+		stats.Indexed++
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+	return stats, d.removeUnseen(known, seen, &stats)
+}
+
+// removeUnseen drops the known files a scan did not come across
+func (d *DB) removeUnseen(known map[string]fileState, seen map[string]bool, stats *Stats) error {
+	for path := range known {
+		if !seen[path] {
+			if err := d.RemoveFile(path); err != nil {
+				return err
+			}
+			stats.Removed++
+		}
+	}
+	return nil
+}
+
+// files returns the indexed files below root
+func (d *DB) files(root string) (map[string]fileState, error) {
+	root = filepath.Clean(root)
+	rows, err := d.db.Query("SELECT path, size, mtime FROM files")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[string]fileState)
+	for rows.Next() {
+		var path string
+		var state fileState
+		if err := rows.Scan(&path, &state.size, &state.mtime); err != nil {
+			return nil, err
+		}
+		if root == "." && !filepath.IsAbs(path) || path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			files[path] = state
+		}
+	}
+	return files, rows.Err()
+}
+
+// ReplaceFile stores the symbols of a file in place of those indexed before
+func (d *DB) ReplaceFile(path, language string, size int64, mtime time.Time, symbols []outline.SymbolInfo) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := removeFile(tx, path); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("INSERT INTO files (path, language, size, mtime) VALUES (?, ?, ?, ?)", path, language, size, mtime.UnixNano())
+	if err != nil {
+		return fmt.Errorf("error indexing %s: %v", path, err)
+	}
+	fileID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	insert, err := tx.Prepare(`INSERT INTO symbols (file_id, parent_id, name, fqn, kind, signature, documentation,
+		start_line, start_column, end_line, end_column, public) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	var add func(symbols []outline.SymbolInfo, parentID sql.NullInt64, scope string) error
+	add = func(symbols []outline.SymbolInfo, parentID sql.NullInt64, scope string) error {
+		for _, symbol := range symbols {
+			fqn := scope + symbol.Name
+			if receiver := outline.MethodReceiver(symbol); receiver != "" {
+				fqn = receiver + "." + fqn
+			}
+			result, err := insert.Exec(fileID, parentID, symbol.Name, fqn, symbol.Type,
+				symbol.Signature.String(), symbol.Documentation.String(),
+				symbol.Line, symbol.Column, symbol.EndLine, symbol.EndColumn, symbol.IsPublic)
+			if err != nil {
+				return fmt.Errorf("error indexing %s in %s: %v", fqn, path, err)
+			}
+			id, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			if err := add(symbol.Children, sql.NullInt64{Int64: id, Valid: true}, fqn+"."); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := add(symbols, sql.NullInt64{}, ""); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveFile drops a file and its symbols from the index
+func (d *DB) RemoveFile(path string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := removeFile(tx, path); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func removeFile(tx *sql.Tx, path string) error {
+	if _, err := tx.Exec("DELETE FROM symbols WHERE file_id IN (SELECT id FROM files WHERE path = ?)", path); err != nil {
+		return fmt.Errorf("error removing %s from the index: %v", path, err)
+	}
+	if _, err := tx.Exec("DELETE FROM files WHERE path = ?", path); err != nil {
+		return fmt.Errorf("error removing %s from the index: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func update(t *testing.T, db *DB, root string) Stats {
+	t.Helper()
+	stats, err := db.Update(context.Background(), root, scanner.Options{}, func(path string, err error) {
+		t.Errorf("%s: %v", path, err)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stats
+}
+
+func TestUpdateAndSearch(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "src")
+	server := filepath.Join(root, "server.go")
+	writeFile(t, server, "package src\n\n// Server handles connections\ntype Server struct {\n\tAddr string\n}\n\nfunc (s *Server) Run() {}\n")
+	writeFile(t, filepath.Join(root, "lib", "parse.py"), "def parse_config(path):\n    \"\"\"Reads the server configuration\"\"\"\n")
+
+	db, err := Open(filepath.Join(dir, "symbols.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if stats := update(t, db, root); stats.Indexed != 2 {
+		t.Fatalf("Expected 2 files to be indexed, got %+v", stats)
+	}
+
+	matches, err := db.Search("Run", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].FQN != "Server.Run" || matches[0].Kind != "method" || matches[0].Path != server || matches[0].Line != 8 {
+		t.Errorf("Expected the Server.Run method, got %+v", matches)
+	}
+
+	// Documentation is searchable too, and exact names rank first
+	matches, err = db.Search("server", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) < 3 || matches[0].FQN != "Server" || matches[len(matches)-1].Name != "parse_config" {
+		t.Errorf("Expected Server first and parse_config through its docstring, got %+v", matches)
+	}
+
+	if matches, err := db.Search("pars*", 0); err != nil || len(matches) != 1 {
+		t.Errorf("Expected a prefix query to find parse_config, got %+v, %v", matches, err)
+	}
+	if _, err := db.Search(`"unbalanced`, 0); err == nil {
+		t.Error("Expected an error for a malformed query")
+	}
+
+	if stats := update(t, db, root); stats.Indexed != 0 || stats.Unchanged != 2 {
+		t.Errorf("Expected unchanged files to be skipped, got %+v", stats)
+	}
+
+	writeFile(t, server, "package src\n\nfunc Serve() {}\n")
+	os.Chtimes(server, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	os.RemoveAll(filepath.Join(root, "lib"))
+	if stats := update(t, db, root); stats.Indexed != 1 || stats.Removed != 1 {
+		t.Errorf("Expected one file re-indexed and one removed, got %+v", stats)
+	}
+
+	if matches, _ := db.Search("Run OR parse_config", 0); len(matches) != 0 {
+		t.Errorf("Expected stale symbols to be gone, got %+v", matches)
+	}
+	if matches, _ := db.Search("Serve", 0); len(matches) != 1 {
+		t.Errorf("Expected the new symbol to be indexed, got %+v", matches)
+	}
+}
+
+func TestUpdatePath(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "src")
+	main := filepath.Join(root, "main.go")
+	writeFile(t, main, "package src\n\nfunc Main() {}\n")
+	writeFile(t, filepath.Join(root, "lib", "a.py"), "def a():\n    pass\n")
+	writeFile(t, filepath.Join(root, "lib", "b.py"), "def b():\n    pass\n")
+
+	db, err := Open(filepath.Join(t.TempDir(), "symbols.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	update(t, db, root)
+
+	// A single file is updated on its own
+	writeFile(t, main, "package src\n\nfunc Start() {}\n")
+	os.Chtimes(main, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	if stats := update(t, db, main); stats.Indexed != 1 || stats.Unchanged != 0 || stats.Removed != 0 {
+		t.Errorf("Expected only the file to be re-indexed, got %+v", stats)
+	}
+
+	// A removed directory takes its files with it
+	os.RemoveAll(filepath.Join(root, "lib"))
+	if stats := update(t, db, filepath.Join(root, "lib")); stats.Removed != 2 {
+		t.Errorf("Expected the files of the removed directory to be dropped, got %+v", stats)
+	}
+
+	if matches, _ := db.Search("Start OR Main OR a OR b", 0); len(matches) != 1 || matches[0].Name != "Start" {
+		t.Errorf("Expected only Start to be left, got %+v", matches)
+	}
+}
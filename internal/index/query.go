@@ -0,0 +1,67 @@
+package index
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Match is a symbol found by a search
+type Match struct {
+	Path          string
+	Language      string
+	Name          string
+	FQN           string
+	Kind          string
+	Signature     string
+	Documentation string
+	Line          int
+	Column        int
+	EndLine       int
+	EndColumn     int
+	IsPublic      bool
+}
+
+// DefaultLimit bounds the matches a search returns when no limit is given
+const DefaultLimit = 50
+
+// Search finds the symbols whose name, qualified name, signature or
+// documentation match query, in SQLite full-text syntax: words must all
+// match, "word*" matches a prefix, and OR and quoted phrases are supported.
+// Symbols named exactly like the query come first, then shorter qualified
+// names.
+func (d *DB) Search(query string, limit int) ([]Match, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("empty query")
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	rows, err := d.db.Query(`
+		SELECT f.path, f.language, s.name, s.fqn, s.kind, s.signature, s.documentation,
+			s.start_line, s.start_column, s.end_line, s.end_column, s.public
+		FROM symbols_fts
+		JOIN symbols s ON s.id = symbols_fts.docid
+		JOIN files f ON f.id = s.file_id
+		WHERE symbols_fts MATCH ?
+		ORDER BY s.name = ? COLLATE NOCASE DESC, s.fqn = ? COLLATE NOCASE DESC, length(s.fqn), f.path, s.start_line
+		LIMIT ?`, query, query, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %q: %v", query, err)
+	}
+	defer rows.Close()
+
+	var matches []Match
+	for rows.Next() {
+		var m Match
+		if err := rows.Scan(&m.Path, &m.Language, &m.Name, &m.FQN, &m.Kind, &m.Signature, &m.Documentation,
+			&m.Line, &m.Column, &m.EndLine, &m.EndColumn, &m.IsPublic); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("invalid query %q: %v", query, err)
+	}
+	return matches, nil
+}
@@ -0,0 +1,194 @@
+// Package lsif writes the symbols of a project as an LSIF dump, the graph
+// of documents, ranges and results code intelligence tools such as
+// Sourcegraph load to answer hover, go-to-definition and document symbol
+// requests. Only definitions are emitted: every symbol becomes a range
+// over its name, with the symbol's signature and documentation as hover
+// text and the range itself as its definition.
+package lsif
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// Version is the version of the LSIF specification the dumps follow
+const Version = "0.4.3"
+
+// Writer emits the vertices and edges of a dump as JSON lines, one
+// document at a time
+type Writer struct {
+	encoder *json.Encoder
+	nextID  int
+	err     error
+
+	// projects holds the project vertex of each language, and documents
+	// the documents each contains, for the edges written by Close
+	projects  map[string]int
+	documents map[int][]int
+}
+
+// element holds the fields of a vertex or edge, and of the values nested
+// in them
+type element map[string]any
+
+// NewWriter starts a dump of the project in root, writing its metadata
+func NewWriter(w io.Writer, root string) (*Writer, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	lw := &Writer{encoder: encoder, projects: map[string]int{}, documents: map[int][]int{}}
+	lw.vertex("metaData", element{
+		"version":          Version,
+		"projectRoot":      fileURI(abs),
+		"positionEncoding": "utf-16",
+		"toolInfo":         element{"name": "outline"},
+	})
+	return lw, lw.err
+}
+
+// AddDocument writes a document with a range, result set, hover and
+// definition for each of its symbols, and its symbol tree as the result of
+// document symbol requests
+func (w *Writer) AddDocument(path, language string, content []byte, symbols []outline.SymbolInfo) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	project, ok := w.projects[language]
+	if !ok {
+		project = w.vertex("project", element{"kind": language})
+		w.projects[language] = project
+	}
+	document := w.vertex("document", element{"uri": fileURI(abs), "languageId": language})
+	w.documents[project] = append(w.documents[project], document)
+
+	d := &documentWriter{Writer: w, document: document, language: language, content: content, lines: lineStarts(content)}
+	tree := d.addSymbols(symbols)
+	if len(d.ranges) > 0 {
+		w.edge("contains", element{"outV": document, "inVs": d.ranges})
+	}
+	if len(tree) > 0 {
+		result := w.vertex("documentSymbolResult", element{"result": tree})
+		w.edge("textDocument/documentSymbol", element{"outV": document, "inV": result})
+	}
+	return w.err
+}
+
+// Close writes the edges from each project to its documents
+func (w *Writer) Close() error {
+	projects := make([]int, 0, len(w.projects))
+	for _, project := range w.projects {
+		projects = append(projects, project)
+	}
+	sort.Ints(projects)
+	for _, project := range projects {
+		w.edge("contains", element{"outV": project, "inVs": w.documents[project]})
+	}
+	return w.err
+}
+
+func (w *Writer) vertex(label string, fields element) int {
+	return w.emit("vertex", label, fields)
+}
+
+func (w *Writer) edge(label string, fields element) int {
+	return w.emit("edge", label, fields)
+}
+
+// emit writes one element and returns its id. The first error is kept and
+// returned by the exported methods.
+func (w *Writer) emit(kind, label string, fields element) int {
+	w.nextID++
+	if w.err != nil {
+		return w.nextID
+	}
+	fields["id"] = w.nextID
+	fields["type"] = kind
+	fields["label"] = label
+	w.err = w.encoder.Encode(fields)
+	return w.nextID
+}
+
+// documentWriter writes the symbols of one document
+type documentWriter struct {
+	*Writer
+	document int
+	language string
+	content  []byte
+	lines    []int
+	ranges   []int
+}
+
+// symbolNode is an entry of a document symbol result, which refers to the
+// range of a symbol by its id
+type symbolNode struct {
+	ID       int          `json:"id"`
+	Children []symbolNode `json:"children,omitempty"`
+}
+
+func (d *documentWriter) addSymbols(symbols []outline.SymbolInfo) []symbolNode {
+	var nodes []symbolNode
+	for _, symbol := range symbols {
+		start, end := outline.NameSpan(d.content, symbol)
+		rangeID := d.vertex("range", element{"start": d.position(start), "end": d.position(end)})
+		d.ranges = append(d.ranges, rangeID)
+
+		resultSet := d.vertex("resultSet", element{})
+		d.edge("next", element{"outV": rangeID, "inV": resultSet})
+
+		contents := []any{element{"language": d.language, "value": symbol.Signature.String()}}
+		if doc := symbol.Documentation.String(); doc != "" {
+			contents = append(contents, doc)
+		}
+		hover := d.vertex("hoverResult", element{"result": element{"contents": contents}})
+		d.edge("textDocument/hover", element{"outV": resultSet, "inV": hover})
+
+		definition := d.vertex("definitionResult", element{})
+		d.edge("textDocument/definition", element{"outV": resultSet, "inV": definition})
+		d.edge("item", element{"outV": definition, "inVs": []int{rangeID}, "document": d.document})
+
+		nodes = append(nodes, symbolNode{ID: rangeID, Children: d.addSymbols(symbol.Children)})
+	}
+	return nodes
+}
+
+// position turns a byte offset into the 0-based line and UTF-16 character
+// of an LSIF position
+func (d *documentWriter) position(offset int) element {
+	line := sort.Search(len(d.lines), func(i int) bool { return d.lines[i] > offset }) - 1
+	character := 0
+	for _, r := range string(d.content[d.lines[line]:offset]) {
+		if r >= 0x10000 {
+			character += 2
+		} else {
+			character++
+		}
+	}
+	return element{"line": line, "character": character}
+}
+
+// lineStarts returns the byte offset at which each line of content starts
+func lineStarts(content []byte) []int {
+	starts := []int{0}
+	for i, c := range content {
+		if c == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// fileURI returns the file URI of an absolute path
+func fileURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
@@ -0,0 +1,98 @@
+package lsif
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+const sample = `package sample
+
+// Greeter says hello
+type Greeter struct {
+	Name string
+}
+
+// Greet returns a greeting ✓
+func (g *Greeter) Greet() string {
+	return "hello " + g.Name
+}
+`
+
+func TestWriter(t *testing.T) {
+	symbols, err := outline.ExtractSymbols([]byte(sample), "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	w, err := NewWriter(&out, "project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddDocument(filepath.Join("project", "greeter.go"), "go", []byte(sample), symbols); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	elements := map[int]map[string]any{}
+	labels := map[string][]map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var e map[string]any
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("Invalid line %q: %v", line, err)
+		}
+		elements[int(e["id"].(float64))] = e
+		labels[e["label"].(string)] = append(labels[e["label"].(string)], e)
+	}
+
+	if meta := labels["metaData"]; len(meta) != 1 || meta[0]["version"] != Version || !strings.HasPrefix(meta[0]["projectRoot"].(string), "file:///") {
+		t.Fatalf("Expected the metadata first, got %v", meta)
+	}
+	if docs := labels["document"]; len(docs) != 1 || !strings.HasSuffix(docs[0]["uri"].(string), "/project/greeter.go") || docs[0]["languageId"] != "go" {
+		t.Fatalf("Expected the greeter.go document, got %v", docs)
+	}
+
+	// Greeter, its Name field and Greet, each over its name
+	ranges := labels["range"]
+	if len(ranges) != 3 {
+		t.Fatalf("Expected 3 ranges, got %d", len(ranges))
+	}
+	for i, want := range []string{`{"character":5,"line":3}`, `{"character":1,"line":4}`, `{"character":18,"line":8}`} {
+		start, _ := json.Marshal(ranges[i]["start"])
+		if string(start) != want {
+			t.Errorf("Expected range %d to start at %s, got %s", i, want, start)
+		}
+	}
+
+	var hovers []string
+	for _, hover := range labels["hoverResult"] {
+		contents, _ := json.Marshal(hover["result"])
+		hovers = append(hovers, string(contents))
+	}
+	if !strings.Contains(hovers[2], `"value":"func (g *Greeter) Greet() string"`) || !strings.Contains(hovers[2], `"Greet returns a greeting ✓"`) {
+		t.Errorf("Expected the signature and doc of Greet as hover, got %s", hovers[2])
+	}
+
+	// Every definition result points back at its range in the document
+	for _, edge := range labels["item"] {
+		inVs := edge["inVs"].([]any)
+		if elements[int(inVs[0].(float64))]["label"] != "range" || edge["document"] != labels["document"][0]["id"] {
+			t.Errorf("Expected an item edge to a range of the document, got %v", edge)
+		}
+	}
+
+	symbolsResult, _ := json.Marshal(labels["documentSymbolResult"][0]["result"])
+	if strings.Count(string(symbolsResult), `"id"`) != 3 || !strings.Contains(string(symbolsResult), `"children"`) {
+		t.Errorf("Expected the symbol tree as document symbols, got %s", symbolsResult)
+	}
+	if contains := labels["contains"]; len(contains) != 2 {
+		t.Errorf("Expected the document and project contains edges, got %v", contains)
+	}
+}
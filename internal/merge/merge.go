@@ -0,0 +1,288 @@
+// Package merge consolidates declarations of one logical symbol that are
+// spread across the files of a project, such as C prototypes in headers and
+// their definitions, C++ members defined outside their class, the methods of
+// a Go type declared in several files of its package, or Swift extensions.
+package merge
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// Location is one place a logical symbol is declared
+type Location struct {
+	Path string
+	Line int
+	// Definition is false for declarations without a body, such as C
+	// prototypes and extern variables
+	Definition bool
+	// Extension is set for a Swift extension adding to a type
+	Extension bool
+}
+
+// Entry is a logical symbol with every place it is declared
+type Entry struct {
+	Name          string
+	Type          string
+	Signature     string
+	Documentation string
+	Locations     []Location
+	Children      []*Entry
+
+	// hasDefinition records whether Signature comes from a definition
+	hasDefinition bool
+	children      map[string]*Entry
+}
+
+// Group is the entries of one package, file or shared namespace, in the
+// order they were first seen
+type Group struct {
+	// Name is the package directory for Go, the module directory for Swift,
+	// "C/C++" for the global namespace shared by C and C++ files, and the
+	// file path otherwise
+	Name     string
+	Language string
+	Entries  []*Entry
+
+	entries map[string]*Entry
+}
+
+// Merger collects the symbols of a project file by file
+type Merger struct {
+	groups []*Group
+	byName map[string]*Group
+
+	// cClasses are the C++ classes, structs and unions seen so far, by
+	// qualified name
+	cClasses map[string]*Entry
+	// outOfLine are the C++ member functions defined outside their class,
+	// which are only attached once every file was added, since a source file
+	// is often seen before the header declaring its class
+	outOfLine []outOfLineMember
+}
+
+type outOfLineMember struct {
+	path, language string
+	class, name    string
+	symbol         outline.SymbolInfo
+}
+
+// NewMerger returns an empty merger
+func NewMerger() *Merger {
+	return &Merger{byName: make(map[string]*Group), cClasses: make(map[string]*Entry)}
+}
+
+// Groups returns the merged entries, grouped in the order files were added
+func (m *Merger) Groups() []*Group {
+	for _, member := range m.outOfLine {
+		if class, ok := m.cClasses[member.class]; ok {
+			class.addChild(member.name, member.symbol, member.path)
+		} else {
+			m.group(cGroup, member.language).add(member.class+"::"+member.name, member.symbol, member.path)
+		}
+	}
+	m.outOfLine = nil
+	return m.groups
+}
+
+// cGroup holds the functions and variables C and C++ files share through
+// the linker
+const cGroup = "C/C++"
+
+// Add merges the symbols of one file
+func (m *Merger) Add(path, language string, symbols []outline.SymbolInfo) {
+	switch language {
+	case "go":
+		m.addGo(path, symbols)
+	case "c", "cpp", "cuda":
+		m.addC(path, language, symbols, "")
+	case "swift":
+		m.addSwift(path, symbols)
+	default:
+		group := m.group(path, language)
+		for _, symbol := range symbols {
+			group.add(symbol.Name, symbol, path)
+		}
+	}
+}
+
+func (m *Merger) group(name, language string) *Group {
+	if group, ok := m.byName[name]; ok {
+		return group
+	}
+	group := &Group{Name: name, Language: language, entries: make(map[string]*Entry)}
+	m.groups = append(m.groups, group)
+	m.byName[name] = group
+	return group
+}
+
+// addGo merges the declarations of a package across its files and nests
+// methods under their receiver type, wherever the type is declared
+func (m *Merger) addGo(path string, symbols []outline.SymbolInfo) {
+	group := m.group(filepath.Dir(path), "go")
+
+	for _, symbol := range symbols {
+		name := outline.MethodReceiver(symbol)
+		if name == "" {
+			group.add(symbol.Name, symbol, path)
+			continue
+		}
+
+		// The type may be declared in a file that has not been seen yet
+		receiver, ok := group.entries[name]
+		if !ok {
+			receiver = &Entry{Name: name, Type: "type", children: make(map[string]*Entry)}
+			group.entries[name] = receiver
+			group.Entries = append(group.Entries, receiver)
+		}
+		receiver.addChild(symbol.Name, symbol, path)
+	}
+}
+
+// addC merges functions and variables with external linkage across every C
+// and C++ file, so a prototype in a header and its definition become one
+// entry. Static declarations and types stay with their file.
+func (m *Merger) addC(path, language string, symbols []outline.SymbolInfo, scope string) {
+	for _, symbol := range symbols {
+		name := scope + symbol.Name
+		switch {
+		case symbol.Type == "namespace":
+			m.addC(path, language, symbol.Children, name+"::")
+		case symbol.Type == "function" && strings.Contains(symbol.Name, "::"):
+			i := strings.LastIndex(name, "::")
+			m.outOfLine = append(m.outOfLine, outOfLineMember{path: path, language: language, class: name[:i], name: name[i+2:], symbol: symbol})
+		case symbol.IsPublic && (symbol.Type == "function" || symbol.Type == "variable"):
+			m.group(cGroup, language).add(name, symbol, path)
+		default:
+			group := m.group(path, language)
+			group.add(name, symbol, path)
+			switch symbol.Type {
+			case "class", "struct", "union":
+				if _, ok := m.cClasses[name]; !ok {
+					m.cClasses[name] = group.entries[name]
+				}
+			}
+		}
+	}
+}
+
+// addSwift merges the files of a module, taken to be a directory, and nests
+// the members of extensions under the type they extend, wherever the type
+// is declared
+func (m *Merger) addSwift(path string, symbols []outline.SymbolInfo) {
+	group := m.group(filepath.Dir(path), "swift")
+
+	for _, symbol := range symbols {
+		if symbol.Type != "extension" {
+			group.add(symbol.Name, symbol, path)
+			continue
+		}
+
+		// Extensions of nested types name them as Outer.Inner
+		if extended := group.lookup(symbol.Name); extended != nil {
+			extended.merge(symbol, path)
+			continue
+		}
+		// The type may be declared in a file that has not been seen yet, or
+		// outside the module
+		group.add(symbol.Name, symbol, path)
+	}
+}
+
+// lookup returns the entry of a dotted name, descending into members
+func (g *Group) lookup(name string) *Entry {
+	parts := strings.Split(name, ".")
+	entry := g.entries[parts[0]]
+	for _, part := range parts[1:] {
+		if entry == nil {
+			return nil
+		}
+		entry = entry.children[part]
+	}
+	return entry
+}
+
+// add merges symbol into the entry with the given key
+func (g *Group) add(key string, symbol outline.SymbolInfo, path string) {
+	if entry, ok := g.entries[key]; ok {
+		entry.merge(symbol, path)
+		return
+	}
+	entry := newEntry(symbol, path)
+	g.entries[key] = entry
+	g.Entries = append(g.Entries, entry)
+}
+
+func (e *Entry) addChild(key string, symbol outline.SymbolInfo, path string) {
+	if child, ok := e.children[key]; ok {
+		child.merge(symbol, path)
+		return
+	}
+	child := newEntry(symbol, path)
+	e.children[key] = child
+	e.Children = append(e.Children, child)
+}
+
+func newEntry(symbol outline.SymbolInfo, path string) *Entry {
+	entry := &Entry{
+		Name:          symbol.Name,
+		Type:          symbol.Type,
+		Signature:     symbol.Signature.String(),
+		Documentation: symbol.Documentation.String(),
+		Locations:     []Location{location(symbol, path)},
+		hasDefinition: isDefinition(symbol),
+		children:      make(map[string]*Entry),
+	}
+	for _, child := range symbol.Children {
+		entry.addChild(child.Name, child, path)
+	}
+	return entry
+}
+
+// merge adds another declaration of the same logical symbol. The signature
+// of the definition wins over those of prototypes and extensions, and the
+// first doc comment found is kept, except that the doc comment of a type
+// wins over that of an extension seen first.
+func (e *Entry) merge(symbol outline.SymbolInfo, path string) {
+	loc := location(symbol, path)
+	e.Locations = append(e.Locations, loc)
+
+	// A placeholder made for the receiver of a method takes over the
+	// declaration of its type once seen
+	if e.Signature == "" || (loc.Definition && !e.hasDefinition) {
+		if doc := symbol.Documentation.String(); e.Type == "extension" && doc != "" {
+			e.Documentation = doc
+		}
+		e.Type = symbol.Type
+		e.Signature = symbol.Signature.String()
+		e.hasDefinition = loc.Definition
+	}
+	if e.Documentation == "" {
+		e.Documentation = symbol.Documentation.String()
+	}
+
+	for _, child := range symbol.Children {
+		e.addChild(child.Name, child, path)
+	}
+}
+
+func location(symbol outline.SymbolInfo, path string) Location {
+	return Location{Path: path, Line: symbol.Line, Definition: isDefinition(symbol), Extension: symbol.Type == "extension"}
+}
+
+// isDefinition reports whether a declaration provides its symbol rather
+// than announcing it. Functions only count when they have a body, which is
+// when metrics are computed for them.
+func isDefinition(symbol outline.SymbolInfo) bool {
+	switch symbol.Type {
+	case "function", "method", "constructor":
+		return symbol.Complexity > 0
+	case "variable":
+		return !strings.HasPrefix(symbol.Signature.String(), "extern ")
+	case "extension":
+		return false
+	}
+	return true
+}
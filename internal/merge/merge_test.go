@@ -0,0 +1,121 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func addFile(t *testing.T, m *Merger, path, language, code string) {
+	t.Helper()
+	symbols, err := outline.ExtractSymbols([]byte(code), language)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	m.Add(path, language, symbols)
+}
+
+func TestMergeGoMethodsAcrossFiles(t *testing.T) {
+	m := NewMerger()
+	// The method is seen before the file declaring its type
+	addFile(t, m, "pkg/run.go", "go", "package pkg\n\nfunc (s *Server) Run() {}\n")
+	addFile(t, m, "pkg/server.go", "go", "package pkg\n\n// Server serves\ntype Server struct{}\n\nfunc (s Server) Name() string { return \"\" }\n")
+	addFile(t, m, "other/server.go", "go", "package other\n\ntype Server struct{}\n")
+
+	groups := m.Groups()
+	if len(groups) != 2 || groups[0].Name != "pkg" || len(groups[0].Entries) != 1 {
+		t.Fatalf("Expected one entry in package pkg and another package, got %+v", groups)
+	}
+
+	server := groups[0].Entries[0]
+	if server.Signature != "type Server struct" || server.Documentation != "Server serves" {
+		t.Errorf("Expected the type declaration to replace the placeholder, got %+v", server)
+	}
+	if len(server.Locations) != 1 || server.Locations[0].Path != "pkg/server.go" {
+		t.Errorf("Expected Server to be declared in pkg/server.go only, got %+v", server.Locations)
+	}
+	if len(server.Children) != 2 || server.Children[0].Name != "Run" || server.Children[1].Name != "Name" {
+		t.Fatalf("Expected Run and Name as methods of Server, got %+v", server.Children)
+	}
+	if loc := server.Children[0].Locations[0]; loc.Path != "pkg/run.go" || loc.Line != 3 {
+		t.Errorf("Expected Run at pkg/run.go:3, got %+v", loc)
+	}
+}
+
+func TestMergeCPrototypesWithDefinitions(t *testing.T) {
+	m := NewMerger()
+	addFile(t, m, "util.h", "c", "/* Parses s */\nint parse(const char *s);\n")
+	addFile(t, m, "util.c", "c", "int parse(const char *s) { return s[0]; }\nstatic int helper(void) { return 1; }\n")
+	addFile(t, m, "main.c", "c", "static int helper(void) { return 2; }\n")
+
+	var shared *Group
+	for _, group := range m.Groups() {
+		if group.Name == cGroup {
+			shared = group
+		}
+		if group.Name == "main.c" && len(group.Entries) != 1 {
+			t.Errorf("Expected static helper to stay in main.c, got %+v", group.Entries)
+		}
+	}
+	if shared == nil || len(shared.Entries) != 1 {
+		t.Fatalf("Expected parse to be merged into one entry, got %+v", shared)
+	}
+
+	parse := shared.Entries[0]
+	if parse.Signature != "int parse(const char *s)" || parse.Documentation != "Parses s" {
+		t.Errorf("Expected the definition's signature and the header's docs, got %+v", parse)
+	}
+	expected := []Location{{Path: "util.h", Line: 2}, {Path: "util.c", Line: 1, Definition: true}}
+	if len(parse.Locations) != 2 || parse.Locations[0] != expected[0] || parse.Locations[1] != expected[1] {
+		t.Errorf("Expected locations %+v, got %+v", expected, parse.Locations)
+	}
+}
+
+func TestMergeSwiftExtensions(t *testing.T) {
+	m := NewMerger()
+	// The extension is seen before the file declaring its type
+	addFile(t, m, "Sources/Foo+Codable.swift", "swift", "extension Foo: Codable {\n    public func encode() -> Int { return 1 }\n}\n")
+	addFile(t, m, "Sources/Foo.swift", "swift", "/// A foo\npublic struct Foo {\n    public var name: String\n}\n")
+
+	groups := m.Groups()
+	if len(groups) != 1 || groups[0].Name != "Sources" || len(groups[0].Entries) != 1 {
+		t.Fatalf("Expected one entry in module Sources, got %+v", groups)
+	}
+
+	foo := groups[0].Entries[0]
+	if foo.Type != "struct" || foo.Documentation != "A foo" {
+		t.Errorf("Expected the struct declaration to replace the extension, got %+v", foo)
+	}
+	if len(foo.Locations) != 2 || !foo.Locations[0].Extension || foo.Locations[1].Extension {
+		t.Errorf("Expected an extension and a declaration location, got %+v", foo.Locations)
+	}
+	if len(foo.Children) != 2 || foo.Children[0].Name != "encode" || foo.Children[1].Name != "name" {
+		t.Fatalf("Expected encode and name as members of Foo, got %+v", foo.Children)
+	}
+	if loc := foo.Children[0].Locations[0]; loc.Path != "Sources/Foo+Codable.swift" || loc.Line != 2 {
+		t.Errorf("Expected encode at Sources/Foo+Codable.swift:2, got %+v", loc)
+	}
+}
+
+func TestMergeCppOutOfLineMembers(t *testing.T) {
+	m := NewMerger()
+	// The source file is seen before the header declaring the class
+	addFile(t, m, "server.cpp", "cpp", "namespace app {\nvoid Server::run() { start(); }\n}\nvoid helper::go() {}\n")
+	addFile(t, m, "server.h", "cpp", "namespace app {\nclass Server {\npublic:\n    void run();\n};\n}\n")
+
+	var server *Entry
+	for _, group := range m.Groups() {
+		for _, entry := range group.Entries {
+			if entry.Name == "Server" {
+				server = entry
+			}
+		}
+	}
+	if server == nil || len(server.Children) != 1 {
+		t.Fatalf("Expected run as the only member of Server, got %+v", server)
+	}
+	run := server.Children[0]
+	if len(run.Locations) != 2 || run.Locations[0].Definition || !run.Locations[1].Definition {
+		t.Errorf("Expected the prototype and the out-of-line definition of run, got %+v", run.Locations)
+	}
+}
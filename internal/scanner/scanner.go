@@ -0,0 +1,426 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/internal/ignore"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// binarySniffLength is how much of a file is searched for the NUL bytes that
+// mark binary content, as git does
+const binarySniffLength = 8000
+
+// minifiedLineLength is the length of line over which a file is taken for
+// minified or bundled code, whose outline would be a single unreadable line
+// that takes long to parse
+const minifiedLineLength = 64 << 10
+
+// DefaultQueueSize bounds the number of files waiting to be parsed or
+// waiting to be consumed, which caps the memory held by a scan
+const DefaultQueueSize = 256
+
+// skippedDirs are never descended into
+var skippedDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	"node_modules": true,
+}
+
+// Options configures a directory scan
+type Options struct {
+	// Workers is the number of files parsed concurrently, GOMAXPROCS by default
+	Workers int
+	// QueueSize bounds pending work, DefaultQueueSize by default
+	QueueSize int
+	// Language forces a language for every file instead of detecting it
+	Language string
+	// Outline is how files are outlined. The zero Options outlines them in
+	// full, without any limits.
+	Outline outline.Options
+	// Documents optionally keeps the trees of the files scanned, so that
+	// scanning them again after an edit only re-parses what changed
+	Documents *outline.DocumentCache
+	// Skip optionally excludes files and directories from the walk
+	Skip func(path string, entry fs.DirEntry) bool
+	// NoIgnore walks the files .gitignore and .ignore files exclude too
+	NoIgnore bool
+	// Exclude lists gitignore-style patterns, relative to the root, of files
+	// and directories left out of the walk, with NoIgnore too
+	Exclude []string
+	// Symbols also extracts the symbol tree of every file
+	Symbols bool
+	// Coverage also reports the documentation coverage of every file
+	Coverage bool
+	// Annotations also lists the TODO-style markers of every file
+	Annotations bool
+	// SyntaxErrors also lists the parts of every file that failed to parse
+	SyntaxErrors bool
+	// References also lists the identifiers of every file spelled like it
+	References string
+	// Content also returns the content of every file
+	Content bool
+	// MaxFileSize is the size in bytes of the largest file outlined. Larger
+	// files, such as enormous generated ones, are skipped without being
+	// read. Zero means no limit.
+	MaxFileSize int64
+	// Skipped is told, in walk order, about the files left out because their
+	// content is not worth outlining, such as binary or minified files, and
+	// why
+	Skipped func(path, reason string)
+}
+
+// Result is the outline of a single file, or the error that prevented it
+type Result struct {
+	Path     string
+	Language string
+	Outline  string
+	// Symbols is only set when Options.Symbols is
+	Symbols []outline.SymbolInfo
+	// Coverage is only set when Options.Coverage is
+	Coverage *outline.Coverage
+	// Annotations is only set when Options.Annotations is
+	Annotations []outline.Annotation
+	// SyntaxErrors is only set when Options.SyntaxErrors is
+	SyntaxErrors []outline.SyntaxError
+	// References is only set when Options.References is
+	References []outline.Reference
+	// Encoding is the encoding the file was transcoded to UTF-8 from
+	Encoding outline.Encoding
+	// Content is only set when Options.Content is, and is always UTF-8
+	Content []byte
+	Err     error
+}
+
+// job is a file handed to the worker pool; its result is delivered on its
+// own channel so results can be consumed in walk order
+type job struct {
+	path   string
+	result chan Result
+}
+
+// errSkipped marks files that turned out not to be source code
+var errSkipped = errors.New("skipped")
+
+// skippedFile marks files left out for a reason worth reporting
+type skippedFile struct {
+	reason string
+}
+
+func (s *skippedFile) Error() string {
+	return "skipped: " + s.reason
+}
+
+// Scan walks root and outlines every file with a detectable language using a
+// bounded worker pool. Results are passed to fn one at a time in walk order.
+// Once the queues fill up, the walk pauses until fn catches up, so memory use
+// stays flat regardless of the size of the tree. Returning an error from fn
+// stops the scan.
+func Scan(ctx context.Context, root string, opts Options, fn func(Result) error) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan job, queueSize)
+	pending := make(chan chan Result, queueSize)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.result <- processFile(j.path, opts)
+			}
+		}()
+	}
+
+	walkErr := make(chan error, 1)
+	go func() {
+		defer close(pending)
+		defer close(jobs)
+		walkErr <- walk(ctx, root, opts, jobs, pending)
+	}()
+
+	var consumeErr error
+	for resultChan := range pending {
+		result := <-resultChan
+		if consumeErr != nil || errors.Is(result.Err, errSkipped) {
+			continue
+		}
+		var skipped *skippedFile
+		if errors.As(result.Err, &skipped) {
+			if opts.Skipped != nil {
+				opts.Skipped(result.Path, skipped.reason)
+			}
+			continue
+		}
+		if err := fn(result); err != nil {
+			consumeErr = err
+			cancel()
+		}
+	}
+
+	wg.Wait()
+	if consumeErr != nil {
+		return consumeErr
+	}
+	if err := <-walkErr; err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Files lists the files below root that Scan would outline with opts, in
+// walk order, for callers that need to know how many there are before
+// outlining them. Entries that can't be read are left out.
+func Files(ctx context.Context, root string, opts Options) ([]string, error) {
+	var files []string
+	err := walkFiles(ctx, root, opts, func(path string, err error) error {
+		if err == nil {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func walk(ctx context.Context, root string, opts Options, jobs chan<- job, pending chan<- chan Result) error {
+	return walkFiles(ctx, root, opts, func(path string, err error) error {
+		result := make(chan Result, 1)
+		if err != nil {
+			result <- Result{Path: path, Err: err}
+		}
+		select {
+		case pending <- result:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if err != nil {
+			return nil
+		}
+		select {
+		case jobs <- job{path: path, result: result}:
+		case <-ctx.Done():
+			result <- Result{Path: path, Err: ctx.Err()}
+			return ctx.Err()
+		}
+		return nil
+	})
+}
+
+// walkFiles calls visit with every file below root that is outlined with
+// opts, in walk order, and with the entries that can't be read along with
+// their error
+func walkFiles(ctx context.Context, root string, opts Options, visit func(path string, err error) error) error {
+	var ignored *ignore.Matcher
+	if !opts.NoIgnore {
+		ignored = ignore.New(root)
+	}
+	excluded, err := compileExcludes(opts.Exclude)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			// Report unreadable entries and keep walking the rest of the tree
+			if err := visit(path, err); err != nil {
+				return err
+			}
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if entry.IsDir() {
+			if path != root && IsSkippedDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			if opts.Skip != nil && path != root && opts.Skip(path, entry) {
+				return filepath.SkipDir
+			}
+			if path != root && (ignored.Ignored(path, true) || matchesAny(excluded, root, path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() || (opts.Skip != nil && opts.Skip(path, entry)) {
+			return nil
+		}
+		if opts.Language == "" && !IsCandidate(path) {
+			return nil
+		}
+		if ignored.Ignored(path, false) || matchesAny(excluded, root, path) {
+			return nil
+		}
+		return visit(path, nil)
+	})
+}
+
+// compileExcludes compiles the patterns of Options.Exclude
+func compileExcludes(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := ignore.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %v", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchesAny reports whether path, relative to root, matches one of the
+// excluded patterns
+func matchesAny(patterns []*regexp.Regexp, root, path string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, re := range patterns {
+		if re.MatchString(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSkippedDir reports whether directories with the given name, such as
+// version control metadata and installed dependencies, are left out of scans
+func IsSkippedDir(name string) bool {
+	return skippedDirs[name]
+}
+
+// IsCandidate reports whether a file may contain outlinable source code
+// without reading it: its name or extension is recognized, the repository
+// assigns it a language, or it has no extension and must be sniffed
+func IsCandidate(path string) bool {
+	if _, ok := detector.DetectLanguage(path); ok {
+		return true
+	}
+	if _, ok := detector.DetectGitAttributes(path); ok {
+		return true
+	}
+	if _, known := detector.IdentifyFilename(path); known {
+		return false
+	}
+	return filepath.Ext(path) == "" && !strings.HasPrefix(filepath.Base(path), ".")
+}
+
+// processFile reads, detects and outlines a single file
+func processFile(path string, opts Options) Result {
+	if opts.MaxFileSize > 0 {
+		if info, err := os.Stat(path); err == nil && info.Size() > opts.MaxFileSize {
+			return Result{Path: path, Err: &skippedFile{reason: fmt.Sprintf("larger than %s", formatSize(opts.MaxFileSize))}}
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Result{Path: path, Err: err}
+	}
+	content, encoding := outline.Decode(content)
+	if reason := skipReason(content); reason != "" {
+		return Result{Path: path, Err: &skippedFile{reason: reason}}
+	}
+
+	language := opts.Language
+	if language == "" {
+		var ok bool
+		language, ok = detector.Detect(path, content)
+		if !ok {
+			return Result{Path: path, Err: errSkipped}
+		}
+		if detector.IsHeader(path) {
+			language = outline.HeaderLanguage(language, opts.Outline)
+		}
+	}
+
+	req := outline.AnalysisRequest{
+		Symbols:      opts.Symbols,
+		Coverage:     opts.Coverage,
+		Annotations:  opts.Annotations,
+		SyntaxErrors: opts.SyntaxErrors,
+		References:   opts.References,
+	}
+	var analysis *outline.Analysis
+	if opts.Documents != nil {
+		analysis, err = opts.Documents.Analyze(path, content, language, opts.Outline, req)
+	} else {
+		analysis, err = outline.Analyze(content, language, opts.Outline, req)
+	}
+	if err != nil {
+		return Result{Path: path, Language: language, Err: err}
+	}
+
+	// Languages without a symbol tree of their own, such as HTML, still
+	// report their outline
+	file := Result{
+		Path:         path,
+		Language:     language,
+		Encoding:     encoding,
+		Outline:      analysis.Outline,
+		Symbols:      analysis.Symbols,
+		Coverage:     analysis.Coverage,
+		Annotations:  analysis.Annotations,
+		SyntaxErrors: analysis.SyntaxErrors,
+		References:   analysis.References,
+	}
+	if opts.Content {
+		file.Content = content
+	}
+	return file
+}
+
+// skipReason returns why content, transcoded to UTF-8, is not worth
+// outlining, or "" when it is
+func skipReason(content []byte) string {
+	if bytes.IndexByte(content[:min(len(content), binarySniffLength)], 0) >= 0 {
+		return "binary file"
+	}
+	for line := range bytes.Lines(content) {
+		if len(line) > minifiedLineLength {
+			return "minified file"
+		}
+	}
+	return ""
+}
+
+// formatSize writes a size in bytes the way --max-file-size is given
+func formatSize(n int64) string {
+	if n >= 1<<20 && n%(1<<20) == 0 {
+		return fmt.Sprintf("%d MB", n>>20)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
@@ -0,0 +1,214 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanOutlinesFilesInWalkOrder(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n\nfunc A() {}\n")
+	writeFile(t, filepath.Join(root, "b", "b.py"), "def b():\n    pass\n")
+	writeFile(t, filepath.Join(root, "b", "notes.txt"), "not code\n")
+	writeFile(t, filepath.Join(root, "c.js"), "function c() {}\n")
+	writeFile(t, filepath.Join(root, "node_modules", "dep.js"), "function dep() {}\n")
+	writeFile(t, filepath.Join(root, "tool"), "#!/usr/bin/env python3\ndef main():\n    pass\n")
+
+	var paths []string
+	err := Scan(context.Background(), root, Options{Workers: 3, QueueSize: 2}, func(result Result) error {
+		if result.Err != nil {
+			t.Errorf("%s: unexpected error: %v", result.Path, result.Err)
+		}
+		rel, _ := filepath.Rel(root, result.Path)
+		paths = append(paths, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a.go", filepath.Join("b", "b.py"), "c.js", "tool"}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, paths)
+			break
+		}
+	}
+}
+
+func TestScanStopsOnCallbackError(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go", "d.go", "e.go"} {
+		writeFile(t, filepath.Join(root, name), "package x\n")
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := Scan(context.Background(), root, Options{Workers: 2, QueueSize: 1}, func(result Result) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single callback, got %d", calls)
+	}
+}
+
+func TestScanExtractsSymbolsOnRequest(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n\nfunc A(x int) int {\n\tif x > 0 {\n\t\treturn x\n\t}\n\treturn 0\n}\n")
+	writeFile(t, filepath.Join(root, "index.html"), "<html><body></body></html>\n")
+
+	results := map[string]Result{}
+	err := Scan(context.Background(), root, Options{Symbols: true}, func(result Result) error {
+		if result.Err != nil {
+			t.Errorf("%s: unexpected error: %v", result.Path, result.Err)
+		}
+		results[filepath.Base(result.Path)] = result
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	symbols := results["a.go"].Symbols
+	if len(symbols) != 1 || symbols[0].Name != "A" || symbols[0].Complexity != 2 {
+		t.Errorf("Expected function A with complexity 2, got %+v", symbols)
+	}
+	if html, ok := results["index.html"]; !ok || html.Symbols != nil {
+		t.Errorf("Expected the HTML file to be outlined without symbols, got %+v", html)
+	}
+}
+
+func TestScanHonorsIgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".gitignore"), "generated/\n*_gen.go\n")
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n")
+	writeFile(t, filepath.Join(root, "a_gen.go"), "package a\n")
+	writeFile(t, filepath.Join(root, "generated", "api.go"), "package generated\n")
+
+	scan := func(opts Options) int {
+		count := 0
+		err := Scan(context.Background(), root, opts, func(result Result) error {
+			count++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+	if count := scan(Options{}); count != 1 {
+		t.Errorf("Expected the ignored files to be skipped, scanned %d", count)
+	}
+	if count := scan(Options{NoIgnore: true}); count != 3 {
+		t.Errorf("Expected every file with NoIgnore, scanned %d", count)
+	}
+}
+
+func TestFilesListsWhatScanOutlines(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".gitignore"), "generated/\n")
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n")
+	writeFile(t, filepath.Join(root, "b", "b.py"), "def b():\n    pass\n")
+	writeFile(t, filepath.Join(root, "b", "notes.txt"), "not code\n")
+	writeFile(t, filepath.Join(root, "generated", "api.go"), "package generated\n")
+
+	files, err := Files(context.Background(), root, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(root, "a.go"), filepath.Join(root, "b", "b.py")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, files)
+	}
+}
+
+func TestScanSkipsExcludedPatterns(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n")
+	writeFile(t, filepath.Join(root, "a.pb.go"), "package a\n")
+	writeFile(t, filepath.Join(root, "vendor", "lib", "lib.go"), "package lib\n")
+
+	var paths []string
+	err := Scan(context.Background(), root, Options{NoIgnore: true, Exclude: []string{"*.pb.go", "vendor/"}}, func(result Result) error {
+		paths = append(paths, filepath.Base(result.Path))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "a.go" {
+		t.Errorf("Expected only a.go to be scanned, got %v", paths)
+	}
+}
+
+func TestScanSkipsLargeFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n")
+	writeFile(t, filepath.Join(root, "generated.go"), "package a\n\n"+strings.Repeat("var x = 1\n", 200))
+
+	var paths []string
+	skipped := map[string]string{}
+	opts := Options{MaxFileSize: 1000, Skipped: func(path, reason string) {
+		skipped[filepath.Base(path)] = reason
+	}}
+	err := Scan(context.Background(), root, opts, func(result Result) error {
+		paths = append(paths, filepath.Base(result.Path))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "a.go" {
+		t.Errorf("Expected only a.go to be outlined, got %v", paths)
+	}
+	if skipped["generated.go"] != "larger than 1000 bytes" {
+		t.Errorf("Expected the large file to be reported, got %v", skipped)
+	}
+}
+
+func TestScanSkipsBinaryAndMinifiedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n")
+	writeFile(t, filepath.Join(root, "blob.go"), "package a\x00\x01\x02\n")
+	writeFile(t, filepath.Join(root, "bundle.min.js"), "var a=1;"+strings.Repeat("function f(){return 1}", 4000)+"\n")
+
+	var paths []string
+	skipped := map[string]string{}
+	opts := Options{Skipped: func(path, reason string) {
+		skipped[filepath.Base(path)] = reason
+	}}
+	err := Scan(context.Background(), root, opts, func(result Result) error {
+		paths = append(paths, filepath.Base(result.Path))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "a.go" {
+		t.Errorf("Expected only a.go to be outlined, got %v", paths)
+	}
+	if skipped["blob.go"] != "binary file" || skipped["bundle.min.js"] != "minified file" {
+		t.Errorf("Expected the binary and minified files to be reported, got %v", skipped)
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// responses keeps the responses for recently outlined files, so that agents
+// asking again about a file that hasn't changed get their answer without it
+// being read or parsed
+var responses = newResponseCache(256)
+
+// responseKey identifies a response: the file, as it was when outlined, and
+// the request
+type responseKey struct {
+	path    string
+	mtime   int64
+	size    int64
+	request string
+}
+
+type cachedResponse struct {
+	key  responseKey
+	text string
+}
+
+// responseCache holds at most capacity responses, dropping the least
+// recently used. A file that changes gets a new key, so its old responses
+// are never returned and age out of the cache.
+type responseCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[responseKey]*list.Element
+}
+
+func newResponseCache(capacity int) *responseCache {
+	return &responseCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[responseKey]*list.Element),
+	}
+}
+
+// newResponseKey returns the key of the response to a request for the file
+// at path, with info its current state
+func newResponseKey(path string, info os.FileInfo, request fileRequest) responseKey {
+	opts, _ := json.Marshal(request.opts)
+	return responseKey{
+		path:    path,
+		mtime:   info.ModTime().UnixNano(),
+		size:    info.Size(),
+		request: fmt.Sprintf("%s %s %t %s", request.format, request.language, request.named, opts),
+	}
+}
+
+func (c *responseCache) get(key responseKey) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cachedResponse).text, true
+}
+
+func (c *responseCache) put(key responseKey, text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cachedResponse).text = text
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cachedResponse{key: key, text: text})
+	for c.order.Len() > c.capacity {
+		entry := c.order.Remove(c.order.Back()).(*cachedResponse)
+		delete(c.entries, entry.key)
+	}
+}
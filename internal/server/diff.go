@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sourceradar/outline/internal/apidiff"
+	"github.com/sourceradar/outline/internal/confine"
+	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// OutlineDiffToolParams defines the parameters for the outline_diff tool
+type OutlineDiffToolParams struct {
+	File string `json:"file" jsonschema:"description=Path to the file to compare"`
+	From string `json:"from" jsonschema:"description=Git revision to compare from"`
+	To   string `json:"to,omitempty" jsonschema:"description=Git revision to compare to, the working tree by default"`
+}
+
+// OutlineDiffToolHandler returns the handler listing the public symbols of a
+// file within root added, removed or changed between two git revisions, so
+// that review agents can reason about API changes without diffing raw text
+func OutlineDiffToolHandler(root confine.Root) mcp.ToolHandlerFor[OutlineDiffToolParams, any] {
+	return func(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[OutlineDiffToolParams]) (*mcp.CallToolResultFor[any], error) {
+		args := params.Arguments
+		if args.File == "" || args.From == "" {
+			return errorResult("Error: expected file and from arguments"), nil
+		}
+
+		path, err := resolveRevisionPath(root, args.File)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+		text, err := diffFile(path, args.From, args.To)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: text,
+				},
+			},
+		}, nil
+	}
+}
+
+// resolveRevisionPath resolves path within root like Root.Resolve, except
+// that the file itself may be missing, since it may only exist in a
+// revision. Its directory has to exist within root.
+func resolveRevisionPath(root confine.Root, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root.Dir(), path)
+	}
+	path = filepath.Clean(path)
+	dir, err := root.Resolve(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := root.Resolve(path); err == nil || !os.IsNotExist(err) {
+		return resolved, err
+	}
+	return filepath.Join(dir, filepath.Base(path)), nil
+}
+
+// diffFile describes the public API changes of the file at path from the
+// git revision from to the revision to, or to the working tree when to is
+// empty
+func diffFile(path, from, to string) (string, error) {
+	dir := filepath.Dir(path)
+	for _, ref := range []string{from, to} {
+		if ref == "" {
+			continue
+		}
+		if err := apidiff.VerifyRevision(dir, ref); err != nil {
+			return "", err
+		}
+	}
+
+	old, existed, err := apidiff.ReadRevision(from, path)
+	if err != nil {
+		return "", err
+	}
+	var new []byte
+	var exists bool
+	target := to
+	if to == "" {
+		target = "the working tree"
+		if new, err = os.ReadFile(path); err == nil {
+			exists = true
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("reading file: %v", err)
+		}
+	} else if new, exists, err = apidiff.ReadRevision(to, path); err != nil {
+		return "", err
+	}
+	if !existed && !exists {
+		return "", fmt.Errorf("%s exists neither in %s nor in %s", path, from, target)
+	}
+	old, _ = outline.Decode(old)
+	new, _ = outline.Decode(new)
+
+	// The language is detected from the newest content there is
+	content := new
+	if !exists {
+		content = old
+	}
+	language, ok := detector.Detect(path, content)
+	if !ok {
+		return "", fmt.Errorf("could not detect language for %s", path)
+	}
+	if detector.IsHeader(path) {
+		language = outline.HeaderLanguage(language, outline.Options{})
+	}
+
+	// A version that doesn't parse as its language has no API to speak of
+	var before, after []outline.SymbolInfo
+	if existed {
+		before, _ = outline.ExtractSymbols(old, language)
+	}
+	if exists {
+		after, _ = outline.ExtractSymbols(new, language)
+	}
+	changes := apidiff.Compare(language, before, after)
+	if len(changes) == 0 {
+		return fmt.Sprintf("No public API changes to %s between %s and %s\n", path, from, target), nil
+	}
+
+	breaking := 0
+	var lines strings.Builder
+	for _, change := range changes {
+		severity := "additive"
+		if change.Breaking {
+			severity = "BREAKING"
+			breaking++
+		}
+		description := change.New
+		switch change.Kind {
+		case apidiff.Removed:
+			description = change.Old
+		case apidiff.Changed:
+			description = fmt.Sprintf("%s (was: %s)", change.New, change.Old)
+		}
+		fmt.Fprintf(&lines, "%s %s: %s // line %d\n", severity, change.Kind, description, change.Line)
+	}
+	return fmt.Sprintf("Public API changes to %s (%s) between %s and %s: %d breaking, %d additive\n\n%s", path, language, from, target, breaking, len(changes)-breaking, lines.String()), nil
+}
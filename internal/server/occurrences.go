@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sourceradar/outline/internal/confine"
+	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/internal/scanner"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// maxOccurrences bounds the occurrences listed of each kind, so that the
+// response for a common name stays usable
+const maxOccurrences = 200
+
+// FindOccurrencesToolParams defines the parameters for the
+// find_symbol_occurrences tool
+type FindOccurrencesToolParams struct {
+	Name string `json:"name" jsonschema:"description=Identifier to look for"`
+	Root string `json:"root" jsonschema:"description=File or directory to search"`
+}
+
+// occurrence is an identifier spelled like the name looked for
+type occurrence struct {
+	path      string
+	reference outline.Reference
+}
+
+// FindOccurrencesToolHandler returns the handler listing where an
+// identifier is defined and where it is used below a path within root, to
+// help agents trace usage. Matching is by identifier in the syntax tree
+// rather than by name resolution, so it is best effort: identifiers of
+// unrelated symbols spelled the same match too.
+func FindOccurrencesToolHandler(root confine.Root) mcp.ToolHandlerFor[FindOccurrencesToolParams, any] {
+	return func(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[FindOccurrencesToolParams]) (*mcp.CallToolResultFor[any], error) {
+		return findOccurrencesResult(ctx, root, params.Arguments)
+	}
+}
+
+// findOccurrencesResult answers a find_symbol_occurrences call
+func findOccurrencesResult(ctx context.Context, root confine.Root, args FindOccurrencesToolParams) (*mcp.CallToolResultFor[any], error) {
+	if args.Name == "" || args.Root == "" {
+		return errorResult("Error: expected name and root arguments"), nil
+	}
+
+	path, err := root.Resolve(args.Root)
+	if errors.Is(err, confine.ErrOutside) {
+		return errorResult(fmt.Sprintf("Error: %v", err)), nil
+	}
+	if err != nil {
+		return errorResult(fmt.Sprintf("Error: file not found: %v", err)), nil
+	}
+	definitions, references, err := findOccurrences(ctx, args.Name, path)
+	if err != nil {
+		return errorResult(fmt.Sprintf("Error: %v", err)), nil
+	}
+
+	var text strings.Builder
+	if len(definitions) == 0 && len(references) == 0 {
+		fmt.Fprintf(&text, "No occurrences of %s found in %s\n", args.Name, args.Root)
+	} else {
+		writeOccurrences(&text, "Definitions", definitions)
+		text.WriteString("\n")
+		writeOccurrences(&text, "References", references)
+	}
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text.String(),
+			},
+		},
+	}, nil
+}
+
+// findOccurrences returns the identifiers spelled name in the file at root,
+// or in the supported files below it, split into the declarations of name
+// and its other uses
+func findOccurrences(ctx context.Context, name, root string) (definitions, references []occurrence, err error) {
+	add := func(path string, found []outline.Reference) {
+		for _, reference := range found {
+			if reference.Declaration {
+				definitions = append(definitions, occurrence{path: path, reference: reference})
+			} else {
+				references = append(references, occurrence{path: path, reference: reference})
+			}
+		}
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("file not found: %v", err)
+	}
+	if !info.IsDir() {
+		content, err := os.ReadFile(root)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading file: %v", err)
+		}
+		content, _ = outline.Decode(content)
+		language, ok := detector.Detect(root, content)
+		if !ok {
+			return nil, nil, fmt.Errorf("could not detect language for %s", root)
+		}
+		if detector.IsHeader(root) {
+			language = outline.HeaderLanguage(language, outline.Options{})
+		}
+		found, err := outline.FindReferences(content, language, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("finding references: %v", err)
+		}
+		add(root, found)
+		return definitions, references, nil
+	}
+
+	// Files that fail to parse are left out, as this is best effort
+	err = scanner.Scan(ctx, root, scanner.Options{References: name}, func(result scanner.Result) error {
+		if result.Err == nil {
+			add(result.Path, result.References)
+		}
+		return nil
+	})
+	return definitions, references, err
+}
+
+// writeOccurrences lists occurrences under a heading, as path:line followed
+// by the source line and the symbol they are in
+func writeOccurrences(text *strings.Builder, heading string, occurrences []occurrence) {
+	fmt.Fprintf(text, "%s (%d):\n", heading, len(occurrences))
+	for i, occurrence := range occurrences {
+		if i == maxOccurrences {
+			fmt.Fprintf(text, "... %d more\n", len(occurrences)-maxOccurrences)
+			break
+		}
+		reference := occurrence.reference
+		fmt.Fprintf(text, "%s:%d: %s", occurrence.path, reference.Line, reference.Text)
+		if reference.Symbol != "" {
+			fmt.Fprintf(text, " (in %s)", reference.Symbol)
+		}
+		text.WriteString("\n")
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sourceradar/outline/internal/confine"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// outlinePrompt is a prompt template that asks about the outlines of the
+// files below a path, which are outlined when the prompt is requested
+type outlinePrompt struct {
+	name        string
+	title       string
+	description string
+	// instructions is the request made of the model, with %s standing for
+	// the path
+	instructions string
+	// options, when set, narrows the outlines to what the request needs
+	options func(opts *outline.Options)
+}
+
+var outlinePrompts = []outlinePrompt{
+	{
+		name:         "summarize_architecture",
+		title:        "Summarize architecture",
+		description:  "Summarize the architecture of a package or directory from the outlines of its files",
+		instructions: "Summarize the architecture of %s: what it is responsible for, its main types and functions and how they fit together, and its entry points. Base the summary on the outlines of its files below, which show declarations without their bodies.",
+	},
+	{
+		name:         "list_public_api",
+		title:        "List public API",
+		description:  "List the public API of a package or directory from the exported symbols of its files",
+		instructions: "List the public API of %s, grouped by file, with a one-line description of each exported type and function. The outlines of its files below show the exported symbols alone.",
+		options: func(opts *outline.Options) {
+			opts.Detail = outline.DetailSignatures
+			opts.PublicOnly = true
+		},
+	},
+}
+
+// addPrompts registers the prompt templates with server, outlining files
+// within root with opts
+func addPrompts(server *mcp.Server, root confine.Root, opts outline.Options) {
+	for _, prompt := range outlinePrompts {
+		server.AddPrompt(&mcp.Prompt{
+			Name:        prompt.name,
+			Title:       prompt.title,
+			Description: prompt.description,
+			Arguments: []*mcp.PromptArgument{{
+				Name:        "path",
+				Description: "Path to the directory or file to describe",
+				Required:    true,
+			}},
+		}, prompt.handler(root, opts))
+	}
+}
+
+// handler returns the handler composing the prompt for the path it is
+// given, within root, with the outlines of the files below it starting from
+// base
+func (p outlinePrompt) handler(root confine.Root, base outline.Options) mcp.PromptHandler {
+	return func(ctx context.Context, cc *mcp.ServerSession, params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
+		return p.handle(ctx, params, root, base)
+	}
+}
+
+// handle composes the prompt for the path params give
+func (p outlinePrompt) handle(ctx context.Context, params *mcp.GetPromptParams, root confine.Root, base outline.Options) (*mcp.GetPromptResult, error) {
+	path := params.Arguments["path"]
+	if path == "" {
+		return nil, fmt.Errorf("expected a path argument")
+	}
+	if _, err := root.Resolve(path); err != nil {
+		return nil, err
+	}
+	files, err := expandDirectories(ctx, root, []string{path}, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no supported files found in %s", path)
+	}
+
+	opts := base
+	if p.options != nil {
+		p.options(&opts)
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, p.instructions, path)
+	for _, file := range files {
+		outlined, err := outlineFile(file, fileRequest{root: root, opts: opts, format: "text", named: true})
+		if err != nil {
+			outlined = fmt.Sprintf("File: %s\nError: %v", file, err)
+		}
+		text.WriteString("\n\n" + outlined)
+	}
+
+	return &mcp.GetPromptResult{
+		Description: p.description,
+		Messages: []*mcp.PromptMessage{{
+			Role:    "user",
+			Content: &mcp.TextContent{Text: text.String()},
+		}},
+	}, nil
+}
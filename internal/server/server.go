@@ -2,17 +2,103 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sourceradar/outline/internal/confine"
 	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/pkg/outline"
 )
 
-// Run starts the MCP server
-func Run() error {
+// Run starts the MCP server, over stdio, or over HTTP on addr when it is
+// set until interrupted. Files are outlined with opts unless a request asks
+// otherwise, and the paths of requests must lie within root, unless it is
+// the zero Root. Over HTTP, clients must send token as a bearer token when it
+// is set.
+func Run(addr string, root confine.Root, token string, opts outline.Options) error {
+	server := newServer(root, opts)
+
+	if addr != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Fprintf(os.Stderr, "Serving MCP for %s on %s (Streamable HTTP at /mcp, SSE at /sse)\n", root.Dir(), confine.LocalAddr(addr))
+		return ListenAndServe(ctx, addr, server, token)
+	}
+
+	// Run server using stdio transport
+	if err := server.Run(context.Background(), mcp.NewStdioTransport()); err != nil {
+		log.Fatal(err)
+	}
+
+	return nil
+}
+
+// Handler returns the HTTP handler serving server to remote clients, over
+// Streamable HTTP at /mcp and over the older SSE transport at /sse. When
+// token is set, requests without it as their bearer token are refused.
+func Handler(server *mcp.Server, token string) http.Handler {
+	getServer := func(*http.Request) *mcp.Server { return server }
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(getServer, nil))
+	mux.Handle("/sse", mcp.NewSSEHandler(getServer))
+	if token == "" {
+		return mux
+	}
+	return requireToken(token, mux)
+}
+
+// requireToken refuses the requests to next that don't carry token in
+// their Authorization header
+func requireToken(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ListenAndServe serves server over HTTP on addr until ctx is done, with
+// token as for Handler. Addresses without a host, such as :8080, listen on
+// localhost alone.
+func ListenAndServe(ctx context.Context, addr string, server *mcp.Server, token string) error {
+	httpServer := &http.Server{
+		Addr:              confine.LocalAddr(addr),
+		Handler:           Handler(server, token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdown)
+	}
+}
+
+// newServer returns the MCP server with its tools, outlining files within
+// root with opts
+func newServer(root confine.Root, opts outline.Options) *mcp.Server {
 	// Create server with implementation details
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "outline",
@@ -28,19 +114,111 @@ func Run() error {
 			Properties: map[string]*jsonschema.Schema{
 				"file": {
 					Type:        "string",
-					Description: "Path to the source code file to analyze",
+					Description: "Path to the source code file to analyze, or to a directory to outline every supported file below it, leaving out those ignore files exclude",
+				},
+				"files": {
+					Type:        "array",
+					Description: "Paths of several source code files or directories to outline in one call, instead of or along with file. Each file gets its own content item starting with a File: line, and files that can't be outlined get their error instead.",
+					Items:       &jsonschema.Schema{Type: "string"},
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language to outline the files as, such as go, python or typescript, instead of detecting it from their name and content. For files with nonstandard extensions or embedded snippets. Accepts the language names and aliases the CLI's --language does.",
+				},
+				"detail": {
+					Type:        "string",
+					Description: "Level of detail: signatures lists one line per exported symbol, compact (the default) shows pseudo-source with bodies elided, and full lists every symbol including private members and fields with their documentation",
+					Enum:        []any{"signatures", "compact", "full"},
+				},
+				"format": {
+					Type:        "string",
+					Description: "Output format: text (the default) is the outline, and json is the symbol tree with the kind, signature, documentation, start and end positions, visibility and children of every symbol",
+					Enum:        []any{"text", "json"},
+				},
+				"public_only": {
+					Type:        "boolean",
+					Description: "Only show exported symbols, and their exported members",
+				},
+				"kinds": {
+					Type:        "array",
+					Description: "Only show symbols of these kinds, such as function, method, class or type, along with the symbols enclosing them",
+					Items:       &jsonschema.Schema{Type: "string"},
+				},
+				"include_docs": {
+					Type:        "boolean",
+					Description: "Whether to show the doc comments and docstrings of symbols, which are shown unless the server runs with --no-docs",
+				},
+				"max_depth": {
+					Type:        "integer",
+					Description: "How many levels of nested symbols to show, 1 being the top-level declarations alone",
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"max_tokens": {
+					Type:        "integer",
+					Description: "Estimated number of tokens the outline must fit in. Documentation, then private symbols and fields, then nested members are left out until it does, and the response then says truncated: true",
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"start_line": {
+					Type:        "integer",
+					Description: "Only show the symbols whose declaration spans lines from this one on, with the symbols enclosing them, to outline one region of a large file",
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"end_line": {
+					Type:        "integer",
+					Description: "Only show the symbols whose declaration starts at or before this line, with the symbols enclosing them",
+					Minimum:     jsonschema.Ptr(1.0),
 				},
 			},
-			Required: []string{"file"},
 		},
-	}, OutlineToolHandler)
+	}, OutlineToolHandler(root, opts))
 
-	// Run server using stdio transport
-	if err := server.Run(context.Background(), mcp.NewStdioTransport()); err != nil {
-		log.Fatal(err)
-	}
+	// Register the outline_diff tool
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "outline_diff",
+		Description: "List the public symbols of a source file added, removed or changed between two git revisions, with the old and new signature of each and whether the change is breaking. Lets review agents reason about API changes without diffing raw text.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"file": {
+					Type:        "string",
+					Description: "Path to the source code file to compare, in a git repository",
+				},
+				"from": {
+					Type:        "string",
+					Description: "Git revision to compare from, such as main, HEAD~3 or a commit hash",
+				},
+				"to": {
+					Type:        "string",
+					Description: "Git revision to compare to. Defaults to the file in the working tree.",
+				},
+			},
+			Required: []string{"file", "from"},
+		},
+	}, OutlineDiffToolHandler(root))
 
-	return nil
+	// Register the find_symbol_occurrences tool
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "find_symbol_occurrences",
+		Description: "Find where an identifier is defined and where it is referenced in a file or in every supported source file below a directory, with the line and the enclosing symbol of each occurrence. Matches identifiers in the syntax tree, so comments, strings and longer names containing it are left out, but unrelated symbols spelled the same are not told apart: results are best effort.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"name": {
+					Type:        "string",
+					Description: "Identifier to look for, such as a function, type or variable name",
+				},
+				"root": {
+					Type:        "string",
+					Description: "File or directory to search. Directories are searched recursively, leaving out the files ignore files exclude.",
+				},
+			},
+			Required: []string{"name", "root"},
+		},
+	}, FindOccurrencesToolHandler(root))
+
+	addPrompts(server, root, opts)
+
+	return server
 }
 
 // getToolDescription generates the tool description with supported languages
@@ -2,91 +2,362 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sourceradar/outline/internal/confine"
 	"github.com/sourceradar/outline/internal/detector"
+	"github.com/sourceradar/outline/internal/scanner"
 	"github.com/sourceradar/outline/pkg/outline"
 )
 
+// documents keeps the trees of recently outlined files so that repeated
+// requests for a file being edited only re-parse what changed
+var documents = outline.NewDocumentCache(64)
+
 // OutlineToolParams defines the parameters for the outline tool
 type OutlineToolParams struct {
-	File string `json:"file" jsonschema:"description=Path to the file to analyze"`
+	File   string   `json:"file,omitempty" jsonschema:"description=Path to the file to analyze"`
+	Files  []string `json:"files,omitempty" jsonschema:"description=Paths of several files to analyze in one call"`
+	Detail string   `json:"detail,omitempty" jsonschema:"description=Level of detail of the outline"`
+	Format string   `json:"format,omitempty" jsonschema:"description=text for the outline or json for the symbol tree"`
+	// Language overrides the language detected for the files
+	Language string `json:"language,omitempty" jsonschema:"description=Language of the files, instead of detecting it"`
+	// PublicOnly, Kinds, IncludeDocs and MaxDepth narrow the outline
+	PublicOnly  bool     `json:"public_only,omitempty" jsonschema:"description=Only show exported symbols"`
+	Kinds       []string `json:"kinds,omitempty" jsonschema:"description=Only show symbols of these kinds"`
+	IncludeDocs *bool    `json:"include_docs,omitempty" jsonschema:"description=Whether to show documentation"`
+	MaxDepth    int      `json:"max_depth,omitempty" jsonschema:"description=How many levels of nested symbols to show"`
+	// StartLine and EndLine restrict the outline to one region of the file
+	StartLine int `json:"start_line,omitempty" jsonschema:"description=First line of the region to outline"`
+	EndLine   int `json:"end_line,omitempty" jsonschema:"description=Last line of the region to outline"`
+	// MaxTokens is the estimated size the response must fit in
+	MaxTokens int `json:"max_tokens,omitempty" jsonschema:"description=Estimated number of tokens the outline must fit in"`
 }
 
-// OutlineToolHandler handles outline tool requests
-func OutlineToolHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[OutlineToolParams]) (*mcp.CallToolResultFor[any], error) {
-	filePath := params.Arguments.File
+// symbolTree is the response of the tool in the json format. File is only
+// set in the responses to several files.
+type symbolTree struct {
+	File     string               `json:"file,omitempty"`
+	Language string               `json:"language"`
+	Symbols  []outline.SymbolInfo `json:"symbols"`
+	// Truncated is set when symbols were left out to fit max_tokens
+	Truncated bool `json:"truncated,omitempty"`
+}
 
-	// Check if file exists
-	fileInfo, err := os.Stat(filePath)
-	if err != nil {
+// fileRequest is how each file of a call is outlined
+type fileRequest struct {
+	// root is the directory the files must lie in
+	root   confine.Root
+	opts   outline.Options
+	format string
+	// language, when set, is used instead of the detected language
+	language string
+	// named responses say which file they are for, as the responses to
+	// several files do
+	named bool
+}
+
+// OutlineToolHandler returns the handler of outline tool requests, which
+// outline files within root with base unless the request asks otherwise
+func OutlineToolHandler(root confine.Root, base outline.Options) mcp.ToolHandlerFor[OutlineToolParams, any] {
+	return func(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[OutlineToolParams]) (*mcp.CallToolResultFor[any], error) {
+		args := params.Arguments
+
+		opts, err := toolOptions(base, args)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+		switch args.Format {
+		case "", "text", "json":
+		default:
+			return errorResult(fmt.Sprintf("Error: unknown format: %s", args.Format)), nil
+		}
+		request := fileRequest{root: root, opts: opts, format: args.Format}
+		if args.Language != "" {
+			language, ok := detector.LookupLanguage(args.Language)
+			if !ok {
+				return errorResult(fmt.Sprintf("Error: unsupported language: %s", args.Language)), nil
+			}
+			request.language = language
+		}
+
+		if args.File == "" && len(args.Files) == 0 {
+			return errorResult("Error: expected a file or files argument"), nil
+		}
+		if len(args.Files) > 0 || isDir(root, args.File) {
+			paths := args.Files
+			if args.File != "" {
+				paths = append([]string{args.File}, paths...)
+			}
+			files, err := expandDirectories(ctx, root, paths, request.language)
+			if err != nil {
+				return errorResult(fmt.Sprintf("Error: %v", err)), nil
+			}
+			if len(files) == 0 {
+				return errorResult("Error: no supported files found"), nil
+			}
+			request.named = true
+			return batchResult(ctx, cc, params.GetProgressToken(), files, request), nil
+		}
+
+		text, err := outlineFile(args.File, request)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
 		return &mcp.CallToolResultFor[any]{
 			Content: []mcp.Content{
 				&mcp.TextContent{
-					Text: fmt.Sprintf("Error: file not found: %v", err),
+					Text: text,
 				},
 			},
-			IsError: true,
 		}, nil
 	}
+}
+
+// toolOptions returns the outline options args ask for, on top of opts, those
+// the server was started with
+func toolOptions(opts outline.Options, args OutlineToolParams) (outline.Options, error) {
+	if args.Detail != "" {
+		detail, err := outline.ParseDetail(args.Detail)
+		if err != nil {
+			return opts, err
+		}
+		opts.Detail = detail
+	}
+	if len(args.Kinds) > 0 {
+		kinds, err := outline.ParseKinds(strings.Join(args.Kinds, ","))
+		if err != nil {
+			return opts, err
+		}
+		opts.Kinds = kinds
+	}
+	if args.MaxDepth < 0 {
+		return opts, fmt.Errorf("max_depth must be non-negative, got %d", args.MaxDepth)
+	}
+	if args.MaxDepth > 0 {
+		opts.MaxDepth = args.MaxDepth
+	}
+	if args.StartLine < 0 || args.EndLine < 0 {
+		return opts, fmt.Errorf("start_line and end_line must be positive")
+	}
+	if args.EndLine > 0 && args.EndLine < args.StartLine {
+		return opts, fmt.Errorf("end_line %d is before start_line %d", args.EndLine, args.StartLine)
+	}
+	opts.StartLine, opts.EndLine = args.StartLine, args.EndLine
+	if args.MaxTokens < 0 {
+		return opts, fmt.Errorf("max_tokens must be positive, got %d", args.MaxTokens)
+	}
+	if args.MaxTokens > 0 {
+		opts.MaxTokens = args.MaxTokens
+	}
+	if args.IncludeDocs != nil {
+		opts.NoDocs = !*args.IncludeDocs
+	}
+	opts.PublicOnly = opts.PublicOnly || args.PublicOnly
+	return opts, nil
+}
+
+// isDir reports whether path is a directory within root
+func isDir(root confine.Root, path string) bool {
+	resolved, err := root.Resolve(path)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(resolved)
+	return err == nil && info.IsDir()
+}
+
+// expandDirectories replaces the directories within root among paths with
+// the supported files below them, leaving out those ignore files exclude as
+// the CLI does. With a language, every file is taken to be in it, as with
+// --language.
+func expandDirectories(ctx context.Context, root confine.Root, paths []string, language string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		if !isDir(root, path) {
+			// Files that don't exist or lie outside root are reported with
+			// the others
+			files = append(files, path)
+			continue
+		}
+		dir, err := root.Resolve(path)
+		if err != nil {
+			return nil, err
+		}
+		found, err := scanner.Files(ctx, dir, scanner.Options{Language: language})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// batchResult answers with one content item per file, so that an agent can
+// outline many files in one call. A file that can't be outlined gets its
+// error in place of its outline, and the call only fails when every file
+// does. When the client asked for progress with a progress token, it is
+// told after each file how many of them are done, so that it can show
+// progress and doesn't time out on large trees.
+func batchResult(ctx context.Context, cc *mcp.ServerSession, progressToken any, paths []string, request fileRequest) *mcp.CallToolResultFor[any] {
+	result := &mcp.CallToolResultFor[any]{IsError: true}
+	for i, path := range paths {
+		// The client has given up on the call
+		if ctx.Err() != nil {
+			break
+		}
+		notifyProgress(ctx, cc, progressToken, i, len(paths), path)
+
+		text, err := outlineFile(path, request)
+		if err != nil {
+			text = fmt.Sprintf("File: %s\nError: %v", path, err)
+		} else {
+			result.IsError = false
+		}
+		result.Content = append(result.Content, &mcp.TextContent{Text: text})
+	}
+	notifyProgress(ctx, cc, progressToken, len(result.Content), len(paths), "")
+	return result
+}
+
+// notifyProgress tells the client that done of total files are outlined,
+// with the file being outlined as the message, when it asked for progress
+func notifyProgress(ctx context.Context, cc *mcp.ServerSession, progressToken any, done, total int, message string) {
+	if progressToken == nil || cc == nil {
+		return
+	}
+	// Progress is best effort, and never fails the call
+	cc.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: progressToken,
+		Progress:      float64(done),
+		Total:         float64(total),
+		Message:       message,
+	})
+}
+
+// outlineFile returns the response for one file, which must lie within the
+// root of request. Responses are cached until the file changes.
+func outlineFile(path string, request fileRequest) (string, error) {
+	path, err := request.root.Resolve(path)
+	if errors.Is(err, confine.ErrOutside) {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("file not found: %v", err)
+	}
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("file not found: %v", err)
+	}
 	if fileInfo.IsDir() {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Error: expected a file, got directory",
-				},
-			},
-			IsError: true,
-		}, nil
+		return "", fmt.Errorf("expected a file, got directory")
 	}
 
+	key := newResponseKey(path, fileInfo, request)
+	if text, ok := responses.get(key); ok {
+		return text, nil
+	}
+	text, err := outlineContent(path, request)
+	if err != nil {
+		return "", err
+	}
+	responses.put(key, text)
+	return text, nil
+}
+
+// outlineContent reads the file at path and returns its response
+func outlineContent(path string, request fileRequest) (string, error) {
+	opts := request.opts
 	// Read file content
-	content, err := os.ReadFile(filePath)
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error reading file: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return "", fmt.Errorf("reading file: %v", err)
 	}
 
-	// Detect language based on file extension
-	language, ok := detector.DetectLanguage(filePath)
-	if !ok {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error: unsupported file extension"),
-				},
-			},
-			IsError: true,
-		}, nil
+	content, _ = outline.Decode(content)
+
+	// Detect language based on file extension, falling back to the content
+	language := request.language
+	if language == "" {
+		var ok bool
+		if language, ok = detector.Detect(path, content); !ok {
+			return "", fmt.Errorf("could not detect language for %s (set language)", path)
+		}
+		if detector.IsHeader(path) {
+			language = outline.HeaderLanguage(language, request.opts)
+		}
+	}
+
+	if request.format == "json" {
+		tree := symbolTree{Language: language}
+		if request.named {
+			tree.File = path
+		}
+		return symbolTreeJSON(content, tree, opts)
 	}
 
-	// Extract symbols based on language
-	result, err := outline.ExtractOutline(content, language)
+	// Extract symbols based on language. The cached documents outline whole
+	// files, so narrowed outlines are extracted afresh.
+	var result string
+	var truncated bool
+	if narrowed(opts) {
+		result, truncated, err = outline.FitOutline(content, language, opts)
+	} else {
+		result, err = documents.OutlineWithOptions(path, content, language, opts)
+	}
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error extracting outline: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return "", fmt.Errorf("extracting outline: %v", err)
+	}
+
+	text := fmt.Sprintf("Language: %s\n\n%s", language, result)
+	if truncated {
+		// Agents are told the outline leaves things out to fit max_tokens
+		text = fmt.Sprintf("Language: %s\nTruncated: true\n\n%s", language, result)
+	}
+	if request.named {
+		text = fmt.Sprintf("File: %s\n%s", path, text)
+	}
+	return text, nil
+}
+
+// narrowed reports whether opts leave symbols or documentation out of
+// outlines
+func narrowed(opts outline.Options) bool {
+	return opts.PublicOnly || len(opts.Kinds) > 0 || opts.StartLine > 0 || opts.EndLine > 0 || opts.MaxDepth > 0 || opts.NoDocs || opts.MaxTokens > 0
+}
+
+// symbolTreeJSON returns tree with the symbols of content as JSON, for
+// clients that need the kinds and positions of symbols
+func symbolTreeJSON(content []byte, tree symbolTree, opts outline.Options) (string, error) {
+	symbols, truncated, err := outline.FitSymbols(content, tree.Language, opts)
+	if err != nil {
+		return "", fmt.Errorf("extracting symbols: %v", err)
+	}
+	if symbols == nil {
+		symbols = []outline.SymbolInfo{}
 	}
+	tree.Symbols, tree.Truncated = symbols, truncated
 
-	formattedResult := fmt.Sprintf("Language: %s\n\n%s", language, result)
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// errorResult is a failed tool call reporting text
+func errorResult(text string) *mcp.CallToolResultFor[any] {
 	return &mcp.CallToolResultFor[any]{
 		Content: []mcp.Content{
 			&mcp.TextContent{
-				Text: formattedResult,
+				Text: text,
 			},
 		},
-	}, nil
+		IsError: true,
+	}
 }
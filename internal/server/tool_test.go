@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sourceradar/outline/internal/confine"
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+const shopSource = `public class Shop {
+    private int count;
+    public void open() {
+        count++;
+    }
+    public void close() {}
+}
+`
+
+// writeShop writes a Java file to a new directory and returns its path
+func writeShop(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Shop.java")
+	if err := os.WriteFile(path, []byte(shopSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// dirRoot returns the root of the directory holding path
+func dirRoot(t *testing.T, path string) confine.Root {
+	t.Helper()
+	root, err := confine.NewRoot(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// callOutline calls the outline tool and returns the text of its response
+func callOutline(t *testing.T, handler mcp.ToolHandlerFor[OutlineToolParams, any], args OutlineToolParams) string {
+	t.Helper()
+	result, err := handler(context.Background(), nil, &mcp.CallToolParamsFor[OutlineToolParams]{Arguments: args})
+	if err != nil {
+		t.Fatalf("outline tool failed: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError {
+		t.Fatalf("outline tool returned an error: %s", text)
+	}
+	return text
+}
+
+func TestOutlineToolPositions(t *testing.T) {
+	path := writeShop(t)
+
+	base := outline.DefaultOptions()
+	base.Positions = outline.PositionsRange
+	text := callOutline(t, OutlineToolHandler(dirRoot(t, path), base), OutlineToolParams{File: path})
+	if !strings.Contains(text, "// lines 1-7") || !strings.Contains(text, "// lines 3-5") {
+		t.Errorf("Expected the line ranges the server was started with, got:\n%s", text)
+	}
+
+	base.Positions = outline.PositionsNone
+	text = callOutline(t, OutlineToolHandler(dirRoot(t, path), base), OutlineToolParams{File: path})
+	if strings.Contains(text, "// line") || !strings.Contains(text, "open()") {
+		t.Errorf("Expected no line annotations, got:\n%s", text)
+	}
+}
+
+func TestOutlineToolSort(t *testing.T) {
+	path := writeShop(t)
+
+	base := outline.DefaultOptions()
+	base.Sort = outline.SortName
+	text := callOutline(t, OutlineToolHandler(dirRoot(t, path), base), OutlineToolParams{File: path})
+	closeAt, openAt := strings.Index(text, "close()"), strings.Index(text, "open()")
+	if closeAt < 0 || openAt < 0 || closeAt > openAt {
+		t.Errorf("Expected the methods in the order the server was started with, got:\n%s", text)
+	}
+}
+
+func TestOutlineToolConfined(t *testing.T) {
+	path := writeShop(t)
+	outside := writeShop(t)
+	handler := OutlineToolHandler(dirRoot(t, path), outline.DefaultOptions())
+
+	if text := callOutline(t, handler, OutlineToolParams{File: "Shop.java"}); !strings.Contains(text, "open()") {
+		t.Errorf("Expected a path relative to the root to be outlined, got:\n%s", text)
+	}
+
+	for _, file := range []string{outside, filepath.Join("..", filepath.Base(filepath.Dir(outside)), "Shop.java")} {
+		result, err := handler(context.Background(), nil, &mcp.CallToolParamsFor[OutlineToolParams]{Arguments: OutlineToolParams{File: file}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := result.Content[0].(*mcp.TextContent).Text
+		if !result.IsError || !strings.Contains(text, "outside the served directory") || strings.Contains(text, "open()") {
+			t.Errorf("Expected %s to be refused, got:\n%s", file, text)
+		}
+	}
+}
+
+func TestOutlineToolUnconfined(t *testing.T) {
+	path := writeShop(t)
+	handler := OutlineToolHandler(confine.Root{}, outline.DefaultOptions())
+
+	if text := callOutline(t, handler, OutlineToolParams{File: path}); !strings.Contains(text, "open()") {
+		t.Errorf("Expected any path to be outlined without a root, got:\n%s", text)
+	}
+}
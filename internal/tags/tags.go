@@ -0,0 +1,169 @@
+// Package tags writes symbols as a tags file in the extended ctags format
+// read by Vim, Emacs and most other editors.
+package tags
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// Tag is a symbol at a line of a file
+type Tag struct {
+	Name string
+	Path string
+	Line int
+	Kind string
+	// ScopeKind and Scope name the symbol the tag is a member of, as in
+	// class:Server
+	ScopeKind string
+	Scope     string
+}
+
+// FromSymbols returns a tag for every symbol of a file and its members. Go
+// methods are scoped to their receiver type.
+func FromSymbols(path string, symbols []outline.SymbolInfo) []Tag {
+	var result []Tag
+	var add func(symbols []outline.SymbolInfo, scopeKind, scope string)
+	add = func(symbols []outline.SymbolInfo, scopeKind, scope string) {
+		for _, symbol := range symbols {
+			tag := Tag{Name: symbol.Name, Path: path, Line: symbol.Line, Kind: symbol.Type, ScopeKind: scopeKind, Scope: scope}
+			if receiver := outline.MethodReceiver(symbol); receiver != "" {
+				tag.ScopeKind, tag.Scope = "type", receiver
+			}
+			result = append(result, tag)
+
+			qualified := symbol.Name
+			if tag.Scope != "" {
+				qualified = tag.Scope + "." + symbol.Name
+			}
+			add(symbol.Children, symbol.Type, qualified)
+		}
+	}
+	add(symbols, "", "")
+	return result
+}
+
+// Set holds the tags of a project by file, so that a changed file can be
+// re-tagged without touching the others
+type Set struct {
+	files map[string][]Tag
+}
+
+// NewSet returns an empty set
+func NewSet() *Set {
+	return &Set{files: make(map[string][]Tag)}
+}
+
+// Replace sets the tags of the file at path
+func (s *Set) Replace(path string, symbols []outline.SymbolInfo) {
+	s.files[filepath.Clean(path)] = FromSymbols(filepath.Clean(path), symbols)
+}
+
+// Remove drops the tags of the file at path, or of every file below it when
+// it is a directory, and returns how many files were dropped
+func (s *Set) Remove(path string) int {
+	path = filepath.Clean(path)
+	removed := 0
+	for file := range s.files {
+		if file == path || strings.HasPrefix(file, path+string(filepath.Separator)) {
+			delete(s.files, file)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of files in the set
+func (s *Set) Len() int {
+	return len(s.files)
+}
+
+// Write writes the tags of the set sorted by name, as ctags does, so that
+// editors can binary search them. Paths are written relative to base when
+// it is not empty.
+func (s *Set) Write(w io.Writer, base string) error {
+	var all []Tag
+	for _, tags := range s.files {
+		for _, tag := range tags {
+			if base != "" {
+				if rel, err := relativePath(base, tag.Path); err == nil {
+					tag.Path = rel
+				}
+			}
+			all = append(all, tag)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Name != all[j].Name {
+			return all[i].Name < all[j].Name
+		}
+		if all[i].Path != all[j].Path {
+			return all[i].Path < all[j].Path
+		}
+		return all[i].Line < all[j].Line
+	})
+
+	b := bufio.NewWriter(w)
+	b.WriteString("!_TAG_FILE_FORMAT\t2\t/extended format/\n")
+	b.WriteString("!_TAG_FILE_SORTED\t1\t/0=unsorted, 1=sorted, 2=foldcase/\n")
+	b.WriteString("!_TAG_PROGRAM_NAME\toutline\t//\n")
+	for _, tag := range all {
+		fmt.Fprintf(b, "%s\t%s\t%d;\"\tkind:%s", tag.Name, tag.Path, tag.Line, tag.Kind)
+		if tag.Scope != "" {
+			fmt.Fprintf(b, "\t%s:%s", tag.ScopeKind, tag.Scope)
+		}
+		b.WriteString("\n")
+	}
+	return b.Flush()
+}
+
+// Save writes the set to a tags file at path, with paths relative to its
+// directory. The file is replaced at once, so editors never read it half
+// written.
+func (s *Set) Save(path string) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".tags-*")
+	if err != nil {
+		return fmt.Errorf("error writing tags: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing tags: %v", err)
+	}
+	if err := s.Write(tmp, dir); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing tags: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing tags: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing tags: %v", err)
+	}
+	return nil
+}
+
+func relativePath(base, path string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,53 @@
+package tags
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func symbols(t *testing.T, code, language string) []outline.SymbolInfo {
+	t.Helper()
+	result, err := outline.ExtractSymbols([]byte(code), language)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	set := NewSet()
+	set.Replace(filepath.Join(dir, "src", "server.go"), symbols(t, "package src\n\ntype Server struct{}\n\nfunc (s *Server) Run() {}\n", "go"))
+	set.Replace(filepath.Join(dir, "src", "app.py"), symbols(t, "class App:\n    def run(self):\n        pass\n", "python"))
+	set.Replace(filepath.Join(dir, "old", "gone.py"), symbols(t, "def gone():\n    pass\n", "python"))
+
+	if removed := set.Remove(filepath.Join(dir, "old")); removed != 1 || set.Len() != 2 {
+		t.Errorf("Expected the directory's file to be removed, removed %d, %d left", removed, set.Len())
+	}
+
+	path := filepath.Join(dir, "tags")
+	if err := set.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"!_TAG_FILE_FORMAT\t2\t/extended format/",
+		"!_TAG_FILE_SORTED\t1\t/0=unsorted, 1=sorted, 2=foldcase/",
+		"!_TAG_PROGRAM_NAME\toutline\t//",
+		"App\tsrc/app.py\t1;\"\tkind:class",
+		"Run\tsrc/server.go\t5;\"\tkind:method\ttype:Server",
+		"Server\tsrc/server.go\t3;\"\tkind:struct",
+		"run\tsrc/app.py\t2;\"\tkind:method\tclass:App",
+	}, "\n") + "\n"
+	if string(content) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, content)
+	}
+}
@@ -0,0 +1,221 @@
+// Package testlink finds the test functions of a project and heuristically
+// links them to the production symbols they exercise, so that symbols no
+// test mentions can be reported as untested.
+package testlink
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+// Test is a test function
+type Test struct {
+	Name string
+	Path string
+	Line int
+}
+
+// Symbol is an exported production function or method and the tests that
+// appear to exercise it
+type Symbol struct {
+	// Name is qualified with the enclosing type for methods
+	Name  string
+	Type  string
+	Path  string
+	Line  int
+	Tests []Test
+}
+
+// Index collects the tests and production symbols of a project one file at
+// a time. Links are only resolved by Report, once every file has been seen.
+type Index struct {
+	tests   []Test
+	symbols []Symbol
+
+	// references maps identifiers mentioned by tests to the tests
+	// mentioning them
+	references map[string][]int
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{references: make(map[string][]int)}
+}
+
+// identifierPattern matches the identifiers of every supported language
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// junitPattern matches the annotations that mark JUnit test methods
+var junitPattern = regexp.MustCompile(`@(?:org\.junit\.(?:jupiter\.api\.)?)?(?:Test|ParameterizedTest|RepeatedTest|TestFactory)\b`)
+
+// apexTestPattern matches the annotation and modifier that mark Apex test
+// methods, which Apex accepts in any case
+var apexTestPattern = regexp.MustCompile(`(?i)@istest\b|\btestmethod\b`)
+
+// AddFile records the tests of a file, or its exported functions and
+// methods when it contains no tests
+func (idx *Index) AddFile(path, language string, symbols []outline.SymbolInfo) {
+	var tests []outline.SymbolInfo
+	collectTests(path, language, symbols, &tests)
+
+	if len(tests) == 0 && !IsTestFile(path, language) {
+		collectProduction(path, symbols, "", &idx.symbols)
+		return
+	}
+
+	for _, test := range tests {
+		id := len(idx.tests)
+		idx.tests = append(idx.tests, Test{Name: test.Name, Path: path, Line: test.Line})
+
+		seen := make(map[string]bool)
+		for _, name := range referencedNames(test) {
+			if !seen[name] {
+				seen[name] = true
+				idx.references[name] = append(idx.references[name], id)
+			}
+		}
+	}
+}
+
+// Report links every production symbol to the tests mentioning it and
+// returns the symbols with and without tests, in the order their files
+// were added
+func (idx *Index) Report() (tested, untested []Symbol) {
+	for _, symbol := range idx.symbols {
+		name := symbol.Name[strings.LastIndexByte(symbol.Name, '.')+1:]
+		for _, id := range idx.references[name] {
+			symbol.Tests = append(symbol.Tests, idx.tests[id])
+		}
+
+		if len(symbol.Tests) > 0 {
+			tested = append(tested, symbol)
+		} else {
+			untested = append(untested, symbol)
+		}
+	}
+	return tested, untested
+}
+
+// Tests returns every test found so far
+func (idx *Index) Tests() []Test {
+	return idx.tests
+}
+
+// IsTestFile reports whether a file holds tests by naming convention, so
+// that its helpers are not reported as untested production code
+func IsTestFile(path, language string) bool {
+	base := filepath.Base(path)
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+
+	switch language {
+	case "go":
+		return strings.HasSuffix(base, "_test.go")
+	case "python":
+		return strings.HasPrefix(stem, "test_") || strings.HasSuffix(stem, "_test") || stem == "conftest"
+	case "java", "swift", "objc":
+		return strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "Tests")
+	case "apex":
+		return strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "_Test") || strings.HasSuffix(stem, "Tests")
+	case "dart":
+		return strings.HasSuffix(stem, "_test")
+	case "clojure":
+		return strings.HasSuffix(stem, "_test")
+	case "lua":
+		return strings.HasSuffix(stem, "_spec") || strings.HasSuffix(stem, "_test") || strings.HasPrefix(stem, "test_")
+	}
+	return false
+}
+
+// collectTests finds the test functions among symbols. Go tests live in
+// _test.go files and start with Test, pytest tests start with test, JUnit
+// tests are annotated with @Test, Apex tests with @IsTest or testMethod, XCTest methods in Swift and Objective-C
+// start with test, Zig tests are test blocks, Clojure tests are deftest
+// forms, and LuaUnit tests start with test in a test file.
+func collectTests(path, language string, symbols []outline.SymbolInfo, tests *[]outline.SymbolInfo) {
+	for _, symbol := range symbols {
+		if isTest(path, language, symbol) {
+			*tests = append(*tests, symbol)
+			continue
+		}
+		collectTests(path, language, symbol.Children, tests)
+	}
+}
+
+func isTest(path, language string, symbol outline.SymbolInfo) bool {
+	if symbol.Type != "function" && symbol.Type != "method" {
+		return false
+	}
+
+	switch language {
+	case "go":
+		return strings.HasSuffix(path, "_test.go") && hasTestPrefix(symbol.Name, "Test")
+	case "python":
+		return strings.HasPrefix(symbol.Name, "test")
+	case "java":
+		return junitPattern.MatchString(symbol.Signature.String())
+	case "apex":
+		return apexTestPattern.MatchString(symbol.Signature.String())
+	case "swift", "objc":
+		return symbol.Type == "method" && hasTestPrefix(symbol.Name, "test")
+	case "zig":
+		return strings.HasPrefix(symbol.Signature.String(), "test ")
+	case "clojure":
+		return strings.HasPrefix(symbol.Signature.String(), "(deftest ")
+	case "lua":
+		return IsTestFile(path, language) && (hasTestPrefix(symbol.Name, "test") || hasTestPrefix(symbol.Name, "Test"))
+	}
+	return false
+}
+
+// hasTestPrefix reports whether name starts with prefix followed by the
+// start of a new word, so that TestMain matches but Testify does not
+func hasTestPrefix(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	rest := name[len(prefix):]
+	return rest == "" || rest[0] == '_' || (rest[0] >= 'A' && rest[0] <= 'Z')
+}
+
+// referencedNames returns the identifiers used in the body of a test, along
+// with the words of its name, so that TestServer_Run links to Server.Run
+// even when the test only calls it indirectly
+func referencedNames(test outline.SymbolInfo) []string {
+	names := identifierPattern.FindAllString(string(test.Source.Bytes()), -1)
+
+	name := strings.TrimLeft(test.Name, "_")
+	for _, prefix := range []string{"Test", "test_", "test"} {
+		if strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+			break
+		}
+	}
+	for _, word := range strings.Split(name, "_") {
+		if word != "" {
+			names = append(names, word, strings.ToLower(word[:1])+word[1:])
+		}
+	}
+
+	return names
+}
+
+// collectProduction flattens the exported functions and methods of a file.
+// Python's special methods are left out since tests exercise them
+// implicitly.
+func collectProduction(path string, symbols []outline.SymbolInfo, prefix string, result *[]Symbol) {
+	for _, symbol := range symbols {
+		if !symbol.IsPublic || strings.HasPrefix(symbol.Name, "__") {
+			continue
+		}
+
+		name := prefix + symbol.Name
+		switch symbol.Type {
+		case "function", "method":
+			*result = append(*result, Symbol{Name: name, Type: symbol.Type, Path: path, Line: symbol.Line})
+		}
+		collectProduction(path, symbol.Children, name+".", result)
+	}
+}
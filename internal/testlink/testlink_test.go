@@ -0,0 +1,102 @@
+package testlink
+
+import (
+	"testing"
+
+	"github.com/sourceradar/outline/pkg/outline"
+)
+
+func addFile(t *testing.T, idx *Index, path, language, code string) {
+	t.Helper()
+	symbols, err := outline.ExtractSymbols([]byte(code), language)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	idx.AddFile(path, language, symbols)
+}
+
+func names(symbols []Symbol) []string {
+	var result []string
+	for _, symbol := range symbols {
+		result = append(result, symbol.Name)
+	}
+	return result
+}
+
+func TestReportLinksGoTests(t *testing.T) {
+	idx := NewIndex()
+	addFile(t, idx, "server.go", "go", `package server
+
+func Parse(s string) int { return 0 }
+
+func Render() string { return "" }
+
+func (s *Server) Run() {}
+
+func helper() {}
+`)
+	addFile(t, idx, "server_test.go", "go", `package server
+
+func TestParse(t *testing.T) {
+	if Parse("1") != 1 {
+		t.Fail()
+	}
+}
+
+// Mentions Run only through its name
+func TestServer_Run(t *testing.T) {}
+
+func setup() {}
+`)
+
+	tested, untested := idx.Report()
+	if got := names(tested); len(got) != 2 || got[0] != "Parse" || got[1] != "Run" {
+		t.Errorf("Expected Parse and Run to be tested, got %v", got)
+	}
+	if got := names(untested); len(got) != 1 || got[0] != "Render" {
+		t.Errorf("Expected only Render to be untested, got %v", got)
+	}
+	if len(idx.Tests()) != 2 {
+		t.Errorf("Expected 2 tests, got %+v", idx.Tests())
+	}
+	if tests := tested[0].Tests; len(tests) != 1 || tests[0].Name != "TestParse" || tests[0].Line != 3 {
+		t.Errorf("Expected Parse to be linked to TestParse, got %+v", tests)
+	}
+}
+
+func TestReportDetectsPytestAndJUnit(t *testing.T) {
+	idx := NewIndex()
+	addFile(t, idx, "shapes.py", "python", `class Circle:
+    def __init__(self, r):
+        self.r = r
+
+    def area(self):
+        return 3.14 * self.r ** 2
+
+    def perimeter(self):
+        return 6.28 * self.r
+`)
+	addFile(t, idx, "test_shapes.py", "python", `def test_area():
+    assert Circle(1).area() > 3
+`)
+	addFile(t, idx, "Stack.java", "java", `public class Stack {
+    public void push(int x) {}
+    public int pop() { return 0; }
+}
+`)
+	addFile(t, idx, "StackTest.java", "java", `class StackTest {
+    @Test
+    void pushes() { new Stack().push(1); }
+
+    void notATest() { new Stack().pop(); }
+}
+`)
+
+	_, untested := idx.Report()
+	if got := names(untested); len(got) != 2 || got[0] != "Circle.perimeter" || got[1] != "Stack.pop" {
+		t.Errorf("Expected Circle.perimeter and Stack.pop to be untested, got %v", got)
+	}
+	if len(idx.Tests()) != 2 {
+		t.Errorf("Expected test_area and pushes to be found, got %+v", idx.Tests())
+	}
+}
@@ -0,0 +1,128 @@
+// Package watch reports the files that change below a directory, in batches
+// so that a burst of writes, such as a branch checkout, is handled once.
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/sourceradar/outline/internal/scanner"
+)
+
+// DefaultDebounce is how long a directory must stay quiet before the changes
+// made to it are reported
+const DefaultDebounce = 200 * time.Millisecond
+
+// Watch calls fn with the paths created, written, removed or renamed below
+// root, sorted, once no change has been seen for debounce. Paths may be
+// files or directories, and may no longer exist. New directories are
+// watched as they appear, and directories the scanner skips are never
+// watched. When root is a file, its directory is watched for changes to
+// that file alone, which also catches editors that save by replacing the
+// file. Watch blocks until ctx is done or fn returns an error.
+func Watch(ctx context.Context, root string, debounce time.Duration, fn func(paths []string) error) error {
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	file := ""
+	if info.IsDir() {
+		err = addTree(watcher, root)
+	} else {
+		file = filepath.Clean(root)
+		err = watcher.Add(filepath.Dir(file))
+	}
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if file != "" && event.Name != file {
+				continue
+			}
+			if event.Has(fsnotify.Create) && file == "" {
+				// Files created in a new directory before it was watched are
+				// found by scanning the directory itself
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if scanner.IsSkippedDir(info.Name()) {
+						continue
+					}
+					if err := addTree(watcher, event.Name); err != nil {
+						return err
+					}
+				}
+			}
+			changed[event.Name] = true
+			timer.Reset(debounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+
+		case <-timer.C:
+			paths := make([]string, 0, len(changed))
+			for path := range changed {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			clear(changed)
+			if err := fn(paths); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// addTree watches dir and every directory below it
+func addTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// The directory may be gone again before it is watched
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != dir && scanner.IsSkippedDir(entry.Name()) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
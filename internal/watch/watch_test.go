@@ -0,0 +1,103 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "node_modules"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	batches := make(chan []string)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, root, 50*time.Millisecond, func(paths []string) error {
+			batches <- paths
+			return nil
+		})
+	}()
+	// Give the watcher time to register the tree
+	time.Sleep(100 * time.Millisecond)
+
+	sub := filepath.Join(root, "sub")
+	os.Mkdir(sub, 0o755)
+	os.WriteFile(filepath.Join(root, "node_modules", "skipped.js"), []byte("x"), 0o644)
+	os.WriteFile(filepath.Join(root, "a.go"), []byte("package a\n"), 0o644)
+
+	select {
+	case paths := <-batches:
+		expected := []string{filepath.Join(root, "a.go"), sub}
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("Expected %v, got %v", expected, paths)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for changes")
+	}
+
+	// New directories are watched too
+	os.WriteFile(filepath.Join(sub, "b.go"), []byte("package b\n"), 0o644)
+	select {
+	case paths := <-batches:
+		expected := []string{filepath.Join(sub, "b.go")}
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("Expected %v, got %v", expected, paths)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for changes in a new directory")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "main.go")
+	os.WriteFile(file, []byte("package main\n"), 0o644)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	batches := make(chan []string)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, file, 50*time.Millisecond, func(paths []string) error {
+			batches <- paths
+			return nil
+		})
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// Saved by replacing the file, as many editors do, next to an unrelated
+	// file
+	os.WriteFile(filepath.Join(root, "other.go"), []byte("package main\n"), 0o644)
+	os.WriteFile(file+".tmp", []byte("package main\n\nfunc main() {}\n"), 0o644)
+	os.Rename(file+".tmp", file)
+
+	select {
+	case paths := <-batches:
+		expected := []string{file}
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("Expected %v, got %v", expected, paths)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for changes")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,124 @@
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// Analysis is the outline of a file along with whatever else was asked of
+// it, all derived from the same syntax tree
+type Analysis struct {
+	Outline string
+	// Symbols is only set when AnalysisRequest.Symbols is
+	Symbols []SymbolInfo
+	// Coverage is only set when AnalysisRequest.Coverage is
+	Coverage *Coverage
+	// Annotations is only set when AnalysisRequest.Annotations is
+	Annotations []Annotation
+	// SyntaxErrors is only set when AnalysisRequest.SyntaxErrors is
+	SyntaxErrors []SyntaxError
+	// References is only set when AnalysisRequest.References is
+	References []Reference
+}
+
+// AnalysisRequest is what Analyze reports besides the outline
+type AnalysisRequest struct {
+	Symbols      bool
+	Coverage     bool
+	Annotations  bool
+	SyntaxErrors bool
+	// References is the name whose references are listed
+	References string
+}
+
+// Analyze outlines content like ExtractOutlineWithOptions and reports what
+// req asks for from the same parse, instead of parsing content once for each.
+// Languages without a grammar only have an outline and symbols, and HTML
+// only an outline. Outlines fitted to MaxTokens or stripped of their
+// documentation take parses of their own.
+func Analyze(content []byte, language string, opts Options, req AnalysisRequest) (*Analysis, error) {
+	opts = opts.forLanguage(language)
+	analysis := &Analysis{}
+
+	support, ok := lookupLanguage(language)
+	if !ok || opts.MaxTokens > 0 || opts.NoDocs {
+		var err error
+		if opts.MaxTokens > 0 {
+			analysis.Outline, _, err = fitTokens(content, language, opts)
+		} else {
+			analysis.Outline, err = outlineWithOptions(content, language, opts)
+		}
+		if err != nil {
+			return analysis, err
+		}
+		if text, isText := lookupTextLanguage(language); isText {
+			if req.Symbols {
+				content, _ = opts.truncate(content)
+				analysis.reportSymbols(text.symbols(content), opts)
+			}
+			return analysis, nil
+		}
+		if !ok {
+			return analysis, nil
+		}
+		err = withSymbols(content, language, opts, func(root *sitter.Node, parsed []byte, symbols []SymbolInfo) {
+			analysis.derive(root, parsed, symbols, opts, req)
+		})
+		return analysis, err
+	}
+
+	total := len(content)
+	content, truncated := opts.truncate(content)
+	tree, parsed, reason, err := parseContent(content, language, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Close()
+
+	root := tree.RootNode()
+	symbols := support.symbols(root, parsed)
+	if analysis.Outline, err = renderOutline(root, parsed, language, symbols, opts); err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		truncated = &truncation{parsed: len(parsed), total: total, reason: reason}
+	}
+	if truncated != nil {
+		analysis.Outline += truncated.String()
+	}
+
+	analysis.derive(root, parsed, symbols, opts, req)
+	return analysis, nil
+}
+
+// derive fills in what req asks for from a parsed tree and its symbols. The
+// symbols reported come last, since NoDocs clears the documentation the
+// others rely on.
+func (a *Analysis) derive(root *sitter.Node, parsed []byte, symbols []SymbolInfo, opts Options, req AnalysisRequest) {
+	if req.Coverage {
+		a.Coverage = &Coverage{Symbols: exportedCoverage(symbols, "", nil)}
+		a.Coverage.CodeLines, a.Coverage.CommentLines = languages.CountLines(root, parsed, symbols)
+	}
+	if req.Annotations {
+		a.Annotations = languages.ExtractAnnotations(root, parsed, symbols)
+	}
+	if req.SyntaxErrors {
+		collectSyntaxErrors(root, &a.SyntaxErrors)
+	}
+	if req.References != "" {
+		a.References = languages.FindReferences(root, parsed, symbols, req.References)
+	}
+	if req.Symbols {
+		a.reportSymbols(symbols, opts)
+	}
+}
+
+// reportSymbols sets the symbols reported, left out, reordered and stripped
+// of their documentation as opts asks for
+func (a *Analysis) reportSymbols(symbols []SymbolInfo, opts Options) {
+	a.Symbols = filterSymbols(symbols, opts)
+	if opts.NoDocs {
+		clearDocumentation(a.Symbols)
+	}
+}
@@ -0,0 +1,55 @@
+package outline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeMatchesSeparateExtraction(t *testing.T) {
+	content := []byte(`public class Shop {
+    // TODO(ann): restock
+    public void sell(int count) {
+        count = count - 1;
+    }
+
+    /** Closes the shop */
+    public void close() {
+    }
+}
+`)
+
+	analysis, err := Analyze(content, "java", Options{}, AnalysisRequest{Symbols: true, Coverage: true, Annotations: true, SyntaxErrors: true, References: "count"})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	outline, _ := ExtractOutlineWithOptions(content, "java", Options{})
+	if analysis.Outline != outline {
+		t.Errorf("Expected outline:\n%s\nGot:\n%s", outline, analysis.Outline)
+	}
+	symbols, _ := ExtractSymbolsWithOptions(content, "java", Options{})
+	if !reflect.DeepEqual(analysis.Symbols, symbols) {
+		t.Errorf("Expected symbols %+v, got %+v", symbols, analysis.Symbols)
+	}
+	coverage, _ := ExtractCoverage(content, "java")
+	if !reflect.DeepEqual(analysis.Coverage, coverage) {
+		t.Errorf("Expected coverage %+v, got %+v", coverage, analysis.Coverage)
+	}
+	annotations, _ := ExtractAnnotations(content, "java")
+	if len(annotations) != 1 || !reflect.DeepEqual(analysis.Annotations, annotations) {
+		t.Errorf("Expected annotations %+v, got %+v", annotations, analysis.Annotations)
+	}
+	if len(analysis.SyntaxErrors) != 0 {
+		t.Errorf("Expected no syntax errors, got %+v", analysis.SyntaxErrors)
+	}
+	references, _ := FindReferences(content, "java", "count")
+	if len(references) == 0 || !reflect.DeepEqual(analysis.References, references) {
+		t.Errorf("Expected references %+v, got %+v", references, analysis.References)
+	}
+
+	// Only the outline is reported unless asked for
+	analysis, err = Analyze(content, "java", Options{}, AnalysisRequest{})
+	if err != nil || analysis.Symbols != nil || analysis.Coverage != nil {
+		t.Errorf("Expected only an outline, got %+v, %v", analysis, err)
+	}
+}
@@ -0,0 +1,20 @@
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// Annotation is a TODO, FIXME, HACK, XXX or BUG marker found in a comment,
+// along with its author and the symbol it is in
+type Annotation = languages.Annotation
+
+// ExtractAnnotations lists the TODO-style markers in the comments of content
+func ExtractAnnotations(content []byte, language string) ([]Annotation, error) {
+	var annotations []Annotation
+	err := withSymbols(content, language, DefaultOptions(), func(root *sitter.Node, parsed []byte, symbols []SymbolInfo) {
+		annotations = languages.ExtractAnnotations(root, parsed, symbols)
+	})
+	return annotations, err
+}
@@ -0,0 +1,41 @@
+package outline
+
+import "testing"
+
+func TestExtractAnnotations(t *testing.T) {
+	requireLanguages(t, "go")
+
+	code := `// TODO(alice): split this file
+package main
+
+type Server struct{}
+
+func (s *Server) Run() {
+	// FIXME handle shutdown (bob)
+	/* HACK: retry
+	   XXX - flaky */
+	todo := "TODO: not a comment"
+	_ = todo
+}
+`
+
+	annotations, err := ExtractAnnotations([]byte(code), "go")
+	if err != nil {
+		t.Fatalf("ExtractAnnotations failed: %v", err)
+	}
+
+	expected := []Annotation{
+		{Kind: "TODO", Author: "alice", Text: "split this file", Line: 1, Column: 4},
+		{Kind: "FIXME", Author: "bob", Text: "handle shutdown", Line: 7, Column: 5, Symbol: "Run"},
+		{Kind: "HACK", Text: "retry", Line: 8, Column: 5, Symbol: "Run"},
+		{Kind: "XXX", Text: "flaky", Line: 9, Column: 5, Symbol: "Run"},
+	}
+	if len(annotations) != len(expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, annotations)
+	}
+	for i, want := range expected {
+		if annotations[i] != want {
+			t.Errorf("Expected %+v, got %+v", want, annotations[i])
+		}
+	}
+}
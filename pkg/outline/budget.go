@@ -0,0 +1,101 @@
+package outline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// ErrBudgetExceeded is returned when not even a partial outline could be
+// produced within the configured timeout and memory limits
+var ErrBudgetExceeded = errors.New("extraction budget exceeded")
+
+// memoryCheckInterval throttles how often the parser's progress callback
+// samples the resident set size
+const memoryCheckInterval = 5 * time.Millisecond
+
+// parseWithBudget parses content while enforcing the timeout and memory
+// limits in opts. When the budget runs out, the part of the file the parser
+// had reached is parsed again on its own so the caller still gets a partial
+// tree. The returned slice is the content the tree was built from, and the
+// reason is non-empty when that is less than the whole file.
+func parseWithBudget(parser *sitter.Parser, content []byte, oldTree *sitter.Tree, opts Options) (*sitter.Tree, []byte, string, error) {
+	if opts.Timeout <= 0 && opts.MaxMemory == 0 {
+		return parser.Parse(content, oldTree), content, "", nil
+	}
+
+	tree, reached, reason := parseUntil(parser, content, oldTree, opts)
+	if tree != nil {
+		return tree, content, "", nil
+	}
+
+	// Retry on half of what the first attempt got through, so that the
+	// prefix comfortably fits in a fresh budget of the same size
+	parser.Reset()
+	prefix := cutAtLineBreak(content, reached/2)
+	if len(prefix) > 0 {
+		if tree, _, _ = parseUntil(parser, prefix, nil, opts); tree != nil {
+			return tree, prefix, reason, nil
+		}
+		parser.Reset()
+	}
+
+	return nil, nil, "", fmt.Errorf("%w: %s", ErrBudgetExceeded, reason)
+}
+
+// parseUntil runs a single parse that is cancelled as soon as the budget is
+// spent. It returns a nil tree along with how far the parser got and why it
+// stopped when that happens.
+func parseUntil(parser *sitter.Parser, content []byte, oldTree *sitter.Tree, opts Options) (*sitter.Tree, int, string) {
+	start := time.Now()
+	baseline := residentMemory()
+	lastCheck := start
+
+	var reached int
+	var reason string
+
+	progress := func(state sitter.ParseState) bool {
+		reached = int(state.CurrentByteOffset)
+
+		now := time.Now()
+		if opts.Timeout > 0 && now.Sub(start) > opts.Timeout {
+			reason = fmt.Sprintf("timeout of %s exceeded", opts.Timeout)
+			return true
+		}
+
+		if opts.MaxMemory > 0 && baseline > 0 && now.Sub(lastCheck) >= memoryCheckInterval {
+			lastCheck = now
+			if rss := residentMemory(); rss > baseline && rss-baseline > opts.MaxMemory {
+				reason = fmt.Sprintf("memory limit of %s exceeded", formatBytes(int(opts.MaxMemory)))
+				return true
+			}
+		}
+
+		return false
+	}
+
+	length := len(content)
+	tree := parser.ParseWithOptions(func(i int, _ sitter.Point) []byte {
+		if i < length {
+			return content[i:]
+		}
+		return []byte{}
+	}, oldTree, &sitter.ParseOptions{ProgressCallback: progress})
+
+	return tree, reached, reason
+}
+
+// cutAtLineBreak shortens content to at most limit bytes, ending after the
+// last complete line when there is one
+func cutAtLineBreak(content []byte, limit int) []byte {
+	if limit >= len(content) {
+		return content
+	}
+	if i := bytes.LastIndexByte(content[:limit], '\n'); i > 0 {
+		return content[:i+1]
+	}
+	return content[:limit]
+}
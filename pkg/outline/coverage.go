@@ -0,0 +1,81 @@
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// Coverage reports how well a file is documented
+type Coverage struct {
+	// Symbols lists every exported symbol, in source order with members
+	// after their type
+	Symbols []SymbolCoverage `json:"symbols"`
+
+	CodeLines    int `json:"codeLines"`
+	CommentLines int `json:"commentLines"`
+}
+
+// SymbolCoverage records whether an exported symbol has a doc comment
+type SymbolCoverage struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Line       int    `json:"line"`
+	Documented bool   `json:"documented"`
+}
+
+// Documented returns the number of exported symbols with a doc comment
+func (c *Coverage) Documented() int {
+	documented := 0
+	for _, symbol := range c.Symbols {
+		if symbol.Documented {
+			documented++
+		}
+	}
+	return documented
+}
+
+// Add merges the counts of another file into c, for totals over a project
+func (c *Coverage) Add(other *Coverage) {
+	c.Symbols = append(c.Symbols, other.Symbols...)
+	c.CodeLines += other.CodeLines
+	c.CommentLines += other.CommentLines
+}
+
+// ExtractCoverage reports which exported symbols of content are documented
+// and how many of its lines are comments
+func ExtractCoverage(content []byte, language string) (*Coverage, error) {
+	coverage := &Coverage{}
+	err := withSymbols(content, language, DefaultOptions(), func(root *sitter.Node, parsed []byte, symbols []SymbolInfo) {
+		coverage.CodeLines, coverage.CommentLines = languages.CountLines(root, parsed, symbols)
+		coverage.Symbols = exportedCoverage(symbols, "", nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return coverage, nil
+}
+
+// exportedCoverage flattens the exported symbols of a tree. Members are only
+// exported when their type is, and fields and enum members are left out
+// since they are usually described by the documentation of their type.
+func exportedCoverage(symbols []SymbolInfo, prefix string, result []SymbolCoverage) []SymbolCoverage {
+	for _, symbol := range symbols {
+		if !symbol.IsPublic {
+			continue
+		}
+		if symbol.Type == "field" || symbol.Type == "enum_member" {
+			continue
+		}
+
+		name := prefix + symbol.Name
+		result = append(result, SymbolCoverage{
+			Name:       name,
+			Type:       symbol.Type,
+			Line:       symbol.Line,
+			Documented: !symbol.Documentation.IsZero(),
+		})
+		result = exportedCoverage(symbol.Children, name+".", result)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package outline
+
+import "testing"
+
+func TestExtractCoverage(t *testing.T) {
+	requireLanguages(t, "python")
+
+	code := `# Utilities for greeting people
+
+class Greeter:
+    """Says hello."""
+
+    def greet(self, name):
+        return "hi " + name  # friendly
+
+    def _helper(self):
+        pass
+
+
+def farewell(name):
+    """Say goodbye.
+
+    Politely.
+    """
+    return "bye " + name
+`
+
+	coverage, err := ExtractCoverage([]byte(code), "python")
+	if err != nil {
+		t.Fatalf("ExtractCoverage failed: %v", err)
+	}
+
+	expected := []SymbolCoverage{
+		{Name: "Greeter", Type: "class", Line: 3, Documented: true},
+		{Name: "Greeter.greet", Type: "method", Line: 6, Documented: false},
+		{Name: "farewell", Type: "function", Line: 13, Documented: true},
+	}
+	if len(coverage.Symbols) != len(expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, coverage.Symbols)
+	}
+	for i, want := range expected {
+		if coverage.Symbols[i] != want {
+			t.Errorf("Expected %+v, got %+v", want, coverage.Symbols[i])
+		}
+	}
+	if coverage.Documented() != 2 {
+		t.Errorf("Expected 2 documented symbols, got %d", coverage.Documented())
+	}
+
+	// The header comment and both docstrings are comments; the trailing
+	// comment shares its line with code
+	if coverage.CommentLines != 5 || coverage.CodeLines != 7 {
+		t.Errorf("Expected 5 comment and 7 code lines, got %d and %d", coverage.CommentLines, coverage.CodeLines)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !outline_nolang_apex && !outline_nolang_c && !outline_nolang_clojure && !outline_nolang_cpp && !outline_nolang_crystal && !outline_nolang_cuda && !outline_nolang_dart && !outline_nolang_go && !outline_nolang_java && !outline_nolang_javascript && !outline_nolang_lua && !outline_nolang_matlab && !outline_nolang_objc && !outline_nolang_python && !outline_nolang_swift && !outline_nolang_typescript && !outline_nolang_zig
+
+package outline
+
+import "testing"
+
+func TestDefaultBuildLanguages(t *testing.T) {
+	for _, want := range []string{"apex", "c", "clojure", "cpp", "crystal", "cuda", "dart", "go", "html", "java", "javascript", "lua", "matlab", "objc", "python", "swift", "tsx", "typescript", "zig"} {
+		if !IsSupported(want) {
+			t.Errorf("Expected %s to be supported in the default build", want)
+		}
+	}
+}
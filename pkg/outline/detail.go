@@ -0,0 +1,260 @@
+package outline
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Detail selects how much of each symbol an outline shows
+type Detail int
+
+const (
+	// DetailCompact is the pseudo-source outline of each language, with
+	// bodies elided
+	DetailCompact Detail = iota
+	// DetailSignatures lists the signature of every exported symbol on a
+	// line of its own, without fields or documentation
+	DetailSignatures
+	// DetailFull lists every symbol, private members and fields included,
+	// with its documentation
+	DetailFull
+)
+
+var detailNames = map[Detail]string{
+	DetailCompact:    "compact",
+	DetailSignatures: "signatures",
+	DetailFull:       "full",
+}
+
+// DetailNames lists the names ParseDetail accepts, from least to most
+// detailed
+func DetailNames() []string {
+	return []string{"signatures", "compact", "full"}
+}
+
+// ParseDetail returns the detail level with the given name
+func ParseDetail(name string) (Detail, error) {
+	for detail, detailName := range detailNames {
+		if strings.EqualFold(name, detailName) {
+			return detail, nil
+		}
+	}
+	return DetailCompact, fmt.Errorf("unknown detail level %q (expected %s)", name, strings.Join(DetailNames(), ", "))
+}
+
+func (d Detail) String() string {
+	if name, ok := detailNames[d]; ok {
+		return name
+	}
+	return "Detail(" + strconv.Itoa(int(d)) + ")"
+}
+
+// renderTree outlines a parsed tree at the level of detail of opts. The
+// compact outline comes from each language's own extractor, while the other
+// levels are rendered the same way for every language from its symbols.
+func renderTree(root *sitter.Node, content []byte, language string, opts Options) (string, error) {
+	if !opts.Redact {
+		return renderSource(root, content, language, opts)
+	}
+	result, err := renderSource(root, redactLiterals(root, content), language, opts)
+	return restoreRedacted(result), err
+}
+
+// stripDocumentation blanks the doc comments and docstrings of the symbols
+// of content, keeping its line breaks so that line numbers don't move
+func stripDocumentation(content []byte, language string, opts Options) []byte {
+	var symbols []SymbolInfo
+	err := withSymbols(content, language, opts, func(_ *sitter.Node, _ []byte, extracted []SymbolInfo) {
+		symbols = extracted
+	})
+	if err != nil {
+		return content
+	}
+	return blankDocumentation(content, language, symbols)
+}
+
+// blankDocumentation is stripDocumentation for symbols already extracted
+// from content
+func blankDocumentation(content []byte, language string, symbols []SymbolInfo) []byte {
+	var stripped []byte
+	var strip func(symbols []SymbolInfo)
+	strip = func(symbols []SymbolInfo) {
+		for _, symbol := range symbols {
+			doc := symbol.Documentation
+			// Documentation that isn't a range of content, such as one
+			// assembled from several places, is left alone
+			if start, end := doc.Start(), doc.End(); !doc.IsZero() && end <= len(content) && bytes.Equal(content[start:end], doc.Bytes()) {
+				if stripped == nil {
+					stripped = bytes.Clone(content)
+				}
+				for i := start; i < end; i++ {
+					if stripped[i] != '\n' {
+						stripped[i] = ' '
+					}
+				}
+				// Python docstrings become empty strings, so that bodies
+				// holding nothing else stay valid
+				if language == "python" && end-start >= 2 {
+					copy(stripped[start:], `""`)
+				}
+			}
+			strip(symbol.Children)
+		}
+	}
+	strip(symbols)
+	if stripped == nil {
+		return content
+	}
+	return stripped
+}
+
+// clearDocumentation drops the documentation of symbols and their members
+func clearDocumentation(symbols []SymbolInfo) {
+	for i := range symbols {
+		symbols[i].Documentation = Text{}
+		clearDocumentation(symbols[i].Children)
+	}
+}
+
+func renderSource(root *sitter.Node, content []byte, language string, opts Options) (string, error) {
+	if opts.Detail == DetailCompact && !opts.Summarize && !opts.fromSymbolTree() {
+		return outlineTree(root, content, language)
+	}
+
+	support, ok := lookupLanguage(language)
+	if !ok {
+		return "", fmt.Errorf("unsupported language: %s", language)
+	}
+	symbols := filterSymbols(support.symbols(root, content), opts)
+
+	if opts.Detail == DetailCompact && !opts.fromSymbolTree() {
+		return summarizeOutline(support.outline(root, content), symbols, commentPrefix(language)), nil
+	}
+
+	var result strings.Builder
+	writeSymbols(&result, symbols, commentPrefix(language), opts.Detail, opts.Summarize, 0)
+	return result.String(), nil
+}
+
+// renderText is renderTree for the languages outlined by scanning their
+// source. Their symbols stand in for the syntax tree, except for redaction,
+// which needs one.
+func renderText(content []byte, language string, text textLanguage, opts Options) string {
+	// Blanking MATLAB help text can leave a comment above the function to
+	// be read as its help instead, so this goes on until none is left
+	for opts.NoDocs {
+		stripped := blankDocumentation(content, language, text.symbols(content))
+		if bytes.Equal(stripped, content) {
+			break
+		}
+		content = stripped
+	}
+	compact := opts.Detail == DetailCompact && !opts.fromSymbolTree()
+	var symbols []SymbolInfo
+	if !compact || opts.Summarize || opts.Positions == PositionsRange {
+		symbols = text.symbols(content)
+	}
+
+	var result string
+	switch {
+	case compact && !opts.Summarize:
+		result = text.outline(content)
+	case compact:
+		result = summarizeOutline(text.outline(content), filterSymbols(symbols, opts), commentPrefix(language))
+	default:
+		var b strings.Builder
+		writeSymbols(&b, filterSymbols(symbols, opts), commentPrefix(language), opts.Detail, opts.Summarize, 0)
+		result = b.String()
+	}
+	var ends map[int]int
+	if opts.Positions == PositionsRange {
+		ends = symbolEnds(symbols, make(map[int]int))
+	}
+	return applyPositions(result, opts.Positions, ends)
+}
+
+// commentPrefix returns the line comment marker of a language
+func commentPrefix(language string) string {
+	switch language {
+	case "python", "crystal":
+		return "#"
+	case "matlab":
+		return "%"
+	}
+	return "//"
+}
+
+// writeSymbols renders symbols one per line, indenting members under their
+// type. With summarize, long runs of similar members are replaced by a
+// comment.
+func writeSymbols(result *strings.Builder, symbols []SymbolInfo, comment string, detail Detail, summarize bool, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	if detail == DetailSignatures {
+		var shown []SymbolInfo
+		for _, symbol := range symbols {
+			if symbol.IsPublic && !isDataMember(symbol.Type) {
+				shown = append(shown, symbol)
+			}
+		}
+		symbols = shown
+	}
+
+	elided := make(map[int]bool)
+	summaries := make(map[int]elision)
+	if summarize {
+		for _, e := range findElisions(symbols) {
+			summaries[e.members[0]] = e
+			for _, member := range e.members {
+				elided[member] = true
+			}
+		}
+	}
+
+	for i, symbol := range symbols {
+		if e, ok := summaries[i]; ok {
+			if detail == DetailFull && depth == 0 && i > 0 {
+				result.WriteString("\n")
+			}
+			result.WriteString(indent + e.summary(symbols, comment) + "\n")
+			continue
+		}
+		if elided[i] {
+			continue
+		}
+
+		if detail == DetailFull {
+			// Documented declarations read better apart from each other
+			if depth == 0 && i > 0 {
+				result.WriteString("\n")
+			}
+			if doc := symbol.Documentation.String(); doc != "" {
+				for _, line := range strings.Split(doc, "\n") {
+					result.WriteString(strings.TrimRight(indent+comment+" "+line, " ") + "\n")
+				}
+			}
+		}
+
+		signature := symbol.Signature.String()
+		if signature == "" {
+			signature = symbol.Type + " " + symbol.Name
+		}
+		fmt.Fprintf(result, "%s%s %s line %d\n", indent, signature, comment, symbol.Line)
+
+		writeSymbols(result, symbol.Children, comment, detail, summarize, depth+1)
+	}
+}
+
+// isDataMember reports whether symbols of the kind are the fields or values
+// of a type rather than API of their own
+func isDataMember(kind string) bool {
+	switch kind {
+	case "field", "enum_member":
+		return true
+	}
+	return false
+}
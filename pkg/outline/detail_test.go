@@ -0,0 +1,193 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+)
+
+const detailSample = `package sample
+
+// Greeter says hello
+type Greeter struct {
+	Name  string
+	count int
+}
+
+// Greet returns a greeting
+func (g *Greeter) Greet() string {
+	return "hello " + g.Name
+}
+
+func helper() {}
+`
+
+func TestParseDetail(t *testing.T) {
+	for _, name := range DetailNames() {
+		detail, err := ParseDetail(name)
+		if err != nil {
+			t.Fatalf("ParseDetail(%q) failed: %v", name, err)
+		}
+		if detail.String() != name {
+			t.Errorf("Expected %q to round-trip, got %q", name, detail.String())
+		}
+	}
+	if _, err := ParseDetail("verbose"); err == nil {
+		t.Error("Expected an error for an unknown detail level")
+	}
+}
+
+func TestExtractOutlineSignatures(t *testing.T) {
+	requireLanguages(t, "go")
+
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailSignatures})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+
+	expected := "type Greeter struct // line 4\nfunc (g *Greeter) Greet() string // line 10\n"
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
+
+func TestExtractOutlineFull(t *testing.T) {
+	requireLanguages(t, "go")
+
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailFull})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"// Greeter says hello\ntype Greeter struct // line 4\n",
+		"  Name string // line 5\n",
+		"  count int // line 6\n",
+		"\n// Greet returns a greeting\nfunc (g *Greeter) Greet() string // line 10\n",
+		"\nfunc helper() // line 14\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestDocumentCacheOutlineWithDetail(t *testing.T) {
+	requireLanguages(t, "go")
+
+	cache := NewDocumentCache(4)
+	content := []byte(detailSample)
+
+	compact, err := cache.Outline("sample.go", content, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signatures, err := cache.OutlineWithDetail("sample.go", content, "go", DetailSignatures)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if compact == signatures || strings.Contains(signatures, "helper") {
+		t.Errorf("Expected a signatures outline without private functions, got:\n%s", signatures)
+	}
+}
+
+func TestExtractOutlineNoDocs(t *testing.T) {
+	requireLanguages(t, "go", "python")
+
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailFull, NoDocs: true})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if strings.Contains(result, "Greeter says hello") || strings.Contains(result, "returns a greeting") {
+		t.Errorf("Expected no doc comments, got:\n%s", result)
+	}
+	if !strings.Contains(result, "// line 10") {
+		t.Errorf("Expected line numbers to be kept, got:\n%s", result)
+	}
+
+	python := "def greet(name):\n    \"\"\"Say hello\n    to name\"\"\"\n\ndef part():\n    pass\n"
+	result, err = ExtractOutlineWithOptions([]byte(python), "python", Options{NoDocs: true})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if strings.Contains(result, "Say hello") || !strings.Contains(result, "def part") {
+		t.Errorf("Expected the docstring to be dropped and both functions kept, got:\n%s", result)
+	}
+}
+
+func TestExtractOutlineLanguageOptions(t *testing.T) {
+	requireLanguages(t, "go")
+
+	signatures := DetailSignatures
+	opts := Options{Languages: map[string]LanguageOptions{"go": {Detail: &signatures}}}
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", opts)
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if strings.Contains(result, "helper") || strings.Contains(result, "{") {
+		t.Errorf("Expected the signatures of the Go options, got:\n%s", result)
+	}
+}
+
+func TestLanguageOptionsKeepCallOptions(t *testing.T) {
+	requireLanguages(t, "go", "java")
+
+	signatures := DetailSignatures
+	languages := map[string]LanguageOptions{"go": {Detail: &signatures}, "java": {Detail: &signatures}}
+
+	// The kinds keep both functions, and the signatures of the Go options
+	// then leave the private one out
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Kinds: []string{"function", "method"}, Languages: languages})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if result != "func (g *Greeter) Greet() string // line 10\n" {
+		t.Errorf("Expected the kinds to apply along with the Go options, got:\n%s", result)
+	}
+	result, err = ExtractOutlineWithOptions([]byte(detailSample), "go", Options{MaxTokens: 10, Languages: languages})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if EstimateTokens([]byte(result)) > 10 {
+		t.Errorf("Expected at most 10 tokens along with the Go options, got %d:\n%s", EstimateTokens([]byte(result)), result)
+	}
+
+	java := `public class Shop {
+    private int count;
+    public Shop() {}
+    public void open() {}
+}
+`
+	symbols, err := ExtractSymbolsWithOptions([]byte(java), "java", Options{Kinds: []string{"method"}, Languages: languages})
+	if err != nil {
+		t.Fatalf("ExtractSymbolsWithOptions failed: %v", err)
+	}
+	if len(symbols) != 1 || len(symbols[0].Children) != 1 || symbols[0].Children[0].Name != "open" {
+		t.Errorf("Expected the kinds to apply along with the Java options, got %+v", symbols)
+	}
+	result, err = ExtractOutlineWithOptions([]byte(java), "java", Options{MaxTokens: 10, Languages: languages})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if EstimateTokens([]byte(result)) > 10 {
+		t.Errorf("Expected at most 10 tokens along with the Java options, got %d:\n%s", EstimateTokens([]byte(result)), result)
+	}
+}
+
+func TestExtractOutlineSignaturesExternC(t *testing.T) {
+	requireLanguages(t, "cpp")
+
+	code := "extern \"C\" int x;\nextern \"C\" int table[] = {1, 2};\nextern \"C\" int lib_version(void);\n"
+	for _, opts := range []Options{{Detail: DetailSignatures}, {Summarize: true}} {
+		result, err := ExtractOutlineWithOptions([]byte(code), "cpp", opts)
+		if err != nil {
+			t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+		}
+		if strings.Contains(result, "extern \"C\" extern") {
+			t.Errorf("Expected no dangling linkage, got:\n%s", result)
+		}
+		if !strings.Contains(result, "lib_version(void)") {
+			t.Errorf("Expected lib_version in outline, got:\n%s", result)
+		}
+	}
+}
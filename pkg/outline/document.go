@@ -0,0 +1,341 @@
+package outline
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"sync"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Document is a source file whose syntax tree is kept between outlines, so
+// that an edited version can be re-parsed incrementally instead of from
+// scratch. A Document is safe for concurrent use.
+type Document struct {
+	mu       sync.Mutex
+	language string
+	closed   bool
+
+	// source is the content of the last update, and content the part of it
+	// that tree was parsed from. They only differ when the parse ran out of
+	// budget, in which case reason says why.
+	source  []byte
+	content []byte
+	tree    *sitter.Tree
+	reason  string
+}
+
+// NewDocument returns an empty document for the language
+func NewDocument(language string) *Document {
+	return &Document{language: language}
+}
+
+// Language returns the language the document is parsed as
+func (d *Document) Language() string {
+	return d.language
+}
+
+// Update replaces the document content and returns its outline. The change
+// from the previous content is applied to the cached tree as a single edit,
+// letting tree-sitter reuse every subtree outside the edited range.
+func (d *Document) Update(content []byte) (string, error) {
+	return d.UpdateWithOptions(content, DefaultOptions())
+}
+
+// UpdateWithDetail is Update with an explicit level of detail
+func (d *Document) UpdateWithDetail(content []byte, detail Detail) (string, error) {
+	opts := DefaultOptions()
+	opts.Detail = detail
+	return d.UpdateWithOptions(content, opts)
+}
+
+// UpdateWithOptions is Update with the limits, level of detail, summaries,
+// redaction, order and positions of opts
+func (d *Document) UpdateWithOptions(content []byte, opts Options) (string, error) {
+	opts = opts.forLanguage(d.language)
+	total := len(content)
+	content, truncated := opts.truncate(content)
+	// The cached tree is outlined whole, so only the rendering is taken
+	// from opts. Sorting moves symbols without leaving any out.
+	render := Options{Detail: opts.Detail, Summarize: opts.Summarize, Redact: opts.Redact, Sort: opts.Sort, Positions: opts.Positions}
+
+	// HTML documents are outlined through the code they embed
+	if d.language == "html" {
+		result, err := extractHTMLOutline(content, render)
+		if err != nil {
+			return "", err
+		}
+		result = applyPositions(result, render.Positions, nil)
+		if truncated == nil {
+			return result, nil
+		}
+		return result + truncated.String(), nil
+	}
+
+	// Languages without a grammar are outlined from their source
+	if text, ok := lookupTextLanguage(d.language); ok {
+		result := renderText(content, d.language, text, render)
+		if truncated != nil {
+			result += truncated.String()
+		}
+		return result, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.tree == nil || !bytes.Equal(d.source, content) {
+		if err := d.reparse(content, opts); err != nil {
+			return "", err
+		}
+	}
+
+	root := d.tree.RootNode()
+	var symbols []SymbolInfo
+	if render.Positions == PositionsRange {
+		support, _ := lookupLanguage(d.language)
+		symbols = support.symbols(root, d.content)
+	}
+	result, err := renderOutline(root, d.content, d.language, symbols, render)
+	if err != nil {
+		return "", err
+	}
+
+	if d.reason != "" {
+		truncated = &truncation{parsed: len(d.content), total: total, reason: d.reason}
+	}
+	if truncated != nil {
+		result += truncated.String()
+	}
+
+	// A closed document outlines but keeps nothing for next time
+	if d.closed {
+		d.release()
+	}
+	return result, nil
+}
+
+// Analyze is UpdateWithOptions reporting what req asks for besides the
+// outline, like the package's Analyze, from the cached tree. Every option
+// applies. Languages without a symbol tree, and outlines fitted to MaxTokens
+// or stripped of their documentation, are analyzed from scratch as Analyze
+// does.
+func (d *Document) Analyze(content []byte, opts Options, req AnalysisRequest) (*Analysis, error) {
+	opts = opts.forLanguage(d.language)
+	support, ok := lookupLanguage(d.language)
+	if !ok || opts.MaxTokens > 0 || opts.NoDocs {
+		return Analyze(content, d.language, opts, req)
+	}
+
+	total := len(content)
+	content, truncated := opts.truncate(content)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.tree == nil || !bytes.Equal(d.source, content) {
+		if err := d.reparse(content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	root := d.tree.RootNode()
+	symbols := support.symbols(root, d.content)
+	analysis := &Analysis{}
+	var err error
+	if analysis.Outline, err = renderOutline(root, d.content, d.language, symbols, opts); err != nil {
+		return nil, err
+	}
+	if d.reason != "" {
+		truncated = &truncation{parsed: len(d.content), total: total, reason: d.reason}
+	}
+	if truncated != nil {
+		analysis.Outline += truncated.String()
+	}
+	analysis.derive(root, d.content, symbols, opts, req)
+
+	if d.closed {
+		d.release()
+	}
+	return analysis, nil
+}
+
+// reparse brings the cached tree up to date with content within the limits
+// of opts, which must be called with the lock held
+func (d *Document) reparse(content []byte, opts Options) error {
+	parser, err := acquireParser(d.language)
+	if err != nil {
+		return fmt.Errorf("error creating parser: %v", err)
+	}
+	defer releaseParser(d.language, parser)
+
+	// The edit is between the texts the grammar read, which are the
+	// preprocessed copies for the languages that have one
+	support, _ := lookupLanguage(d.language)
+	var oldTree *sitter.Tree
+	if d.tree != nil {
+		old, new := d.content, content
+		if support.preprocess != nil {
+			old, new = support.preprocess(d.source)[:len(d.content)], support.preprocess(content)
+		}
+		edit := diffEdit(old, new)
+		d.tree.Edit(&edit)
+		oldTree = d.tree
+	}
+
+	tree, parsed, reason, err := parseSource(parser, support, content, oldTree, opts)
+	d.release()
+	if err != nil {
+		return err
+	}
+
+	d.tree = tree
+	d.source = bytes.Clone(content)
+	d.content = d.source[:len(parsed)]
+	d.reason = reason
+	return nil
+}
+
+// release drops the cached tree and content
+func (d *Document) release() {
+	if d.tree != nil {
+		d.tree.Close()
+		d.tree = nil
+	}
+	d.source = nil
+	d.content = nil
+	d.reason = ""
+}
+
+// Close releases the cached syntax tree
+func (d *Document) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.release()
+	d.closed = true
+}
+
+// diffEdit describes the change from old to new as one edit covering
+// everything between their common prefix and common suffix
+func diffEdit(old, new []byte) sitter.InputEdit {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && old[prefix] == new[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix &&
+		old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+
+	oldEnd := len(old) - suffix
+	newEnd := len(new) - suffix
+
+	return sitter.InputEdit{
+		StartByte:      uint(prefix),
+		OldEndByte:     uint(oldEnd),
+		NewEndByte:     uint(newEnd),
+		StartPosition:  pointAt(old, prefix),
+		OldEndPosition: pointAt(old, oldEnd),
+		NewEndPosition: pointAt(new, newEnd),
+	}
+}
+
+// pointAt converts a byte offset into a tree-sitter row and byte column
+func pointAt(content []byte, offset int) sitter.Point {
+	before := content[:offset]
+	row := bytes.Count(before, []byte{'\n'})
+	column := offset - (bytes.LastIndexByte(before, '\n') + 1)
+	return sitter.Point{Row: uint(row), Column: uint(column)}
+}
+
+// DocumentCache keeps the most recently outlined documents by path so that
+// repeated requests for a changing file are parsed incrementally
+type DocumentCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type cachedDocument struct {
+	path string
+	doc  *Document
+}
+
+// NewDocumentCache returns a cache holding at most capacity documents
+func NewDocumentCache(capacity int) *DocumentCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &DocumentCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Outline returns the outline of the file at path with the given content,
+// reusing the tree from the previous call for the same path and language
+func (c *DocumentCache) Outline(path string, content []byte, language string) (string, error) {
+	return c.document(path, language).Update(content)
+}
+
+// OutlineWithDetail is Outline with an explicit level of detail
+func (c *DocumentCache) OutlineWithDetail(path string, content []byte, language string, detail Detail) (string, error) {
+	return c.document(path, language).UpdateWithDetail(content, detail)
+}
+
+// OutlineWithOptions is Outline with the limits, level of detail, summaries,
+// redaction, order and positions of opts
+func (c *DocumentCache) OutlineWithOptions(path string, content []byte, language string, opts Options) (string, error) {
+	return c.document(path, language).UpdateWithOptions(content, opts)
+}
+
+// Analyze is the package's Analyze for the file at path with the given
+// content, reusing the tree from the previous call for the same path and
+// language
+func (c *DocumentCache) Analyze(path string, content []byte, language string, opts Options, req AnalysisRequest) (*Analysis, error) {
+	return c.document(path, language).Analyze(content, opts, req)
+}
+
+// Forget drops the cached document for path
+func (c *DocumentCache) Forget(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[path]; ok {
+		c.remove(elem)
+	}
+}
+
+func (c *DocumentCache) document(path, language string) *Document {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[path]; ok {
+		entry := elem.Value.(*cachedDocument)
+		if entry.doc.Language() == language {
+			c.order.MoveToFront(elem)
+			return entry.doc
+		}
+		c.remove(elem)
+	}
+
+	doc := NewDocument(language)
+	c.entries[path] = c.order.PushFront(&cachedDocument{path: path, doc: doc})
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return doc
+}
+
+func (c *DocumentCache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*cachedDocument)
+	delete(c.entries, entry.path)
+	entry.doc.Close()
+}
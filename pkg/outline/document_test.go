@@ -0,0 +1,184 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+func TestDiffEdit(t *testing.T) {
+	old := []byte("package main\n\nfunc a() {}\n")
+	new := []byte("package main\n\nfunc abc() {}\n")
+
+	edit := diffEdit(old, new)
+
+	want := sitter.InputEdit{
+		StartByte:      20,
+		OldEndByte:     20,
+		NewEndByte:     22,
+		StartPosition:  sitter.Point{Row: 2, Column: 6},
+		OldEndPosition: sitter.Point{Row: 2, Column: 6},
+		NewEndPosition: sitter.Point{Row: 2, Column: 8},
+	}
+	if edit != want {
+		t.Errorf("diffEdit() = %+v, want %+v", edit, want)
+	}
+}
+
+func TestDiffEditDeletion(t *testing.T) {
+	old := []byte("a\nbb\nccc\n")
+	new := []byte("a\nccc\n")
+
+	edit := diffEdit(old, new)
+
+	if edit.StartByte != 2 || edit.OldEndByte != 5 || edit.NewEndByte != 2 {
+		t.Errorf("unexpected byte range: %+v", edit)
+	}
+	if edit.OldEndPosition != (sitter.Point{Row: 2, Column: 0}) {
+		t.Errorf("unexpected old end position: %+v", edit.OldEndPosition)
+	}
+}
+
+func TestDocumentUpdateMatchesFullParse(t *testing.T) {
+	requireLanguages(t, "go")
+
+	versions := []string{
+		"package main\n\nfunc First() {}\n",
+		"package main\n\n// First does things\nfunc First(x int) error { return nil }\n",
+		"package main\n\ntype T struct {\n\tName string\n}\n\nfunc First(x int) error { return nil }\n",
+		"package main\n\ntype T struct {\n\tName string\n}\n",
+	}
+
+	doc := NewDocument("go")
+	defer doc.Close()
+
+	for i, version := range versions {
+		got, err := doc.Update([]byte(version))
+		if err != nil {
+			t.Fatalf("version %d: Update() error: %v", i, err)
+		}
+		want, err := ExtractOutline([]byte(version), "go")
+		if err != nil {
+			t.Fatalf("version %d: ExtractOutline() error: %v", i, err)
+		}
+		if got != want {
+			t.Errorf("version %d: incremental outline differs\ngot:\n%s\nwant:\n%s", i, got, want)
+		}
+	}
+}
+
+func TestDocumentCacheEvictsAndSwitchesLanguage(t *testing.T) {
+	requireLanguages(t, "go", "python", "javascript")
+
+	cache := NewDocumentCache(1)
+
+	if _, err := cache.Outline("a.go", []byte("package a\n"), "go"); err != nil {
+		t.Fatalf("Outline() error: %v", err)
+	}
+	if _, err := cache.Outline("b.py", []byte("def f():\n    pass\n"), "python"); err != nil {
+		t.Fatalf("Outline() error: %v", err)
+	}
+	if len(cache.entries) != 1 {
+		t.Errorf("expected 1 cached document, got %d", len(cache.entries))
+	}
+
+	got, err := cache.Outline("b.py", []byte("function f() {}\n"), "javascript")
+	if err != nil {
+		t.Fatalf("Outline() error: %v", err)
+	}
+	want, _ := ExtractOutline([]byte("function f() {}\n"), "javascript")
+	if got != want {
+		t.Errorf("expected outline after language switch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	cache.Forget("b.py")
+	if len(cache.entries) != 0 {
+		t.Errorf("expected empty cache after Forget, got %d", len(cache.entries))
+	}
+}
+
+func TestDocumentUpdateWithOptions(t *testing.T) {
+	content := []byte("public class Config {\n    public static final String TOKEN = \"sk-abc123\";\n}\n")
+
+	doc := NewDocument("java")
+	defer doc.Close()
+
+	opts := DefaultOptions()
+	opts.Redact = true
+	result, err := doc.UpdateWithOptions(content, opts)
+	if err != nil {
+		t.Fatalf("UpdateWithOptions failed: %v", err)
+	}
+	if strings.Contains(result, "sk-abc123") {
+		t.Errorf("Expected the literal to be redacted, got:\n%s", result)
+	}
+
+	// Options belong to each update, not to the document
+	result, err = doc.Update(content)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if !strings.Contains(result, "sk-abc123") {
+		t.Errorf("Expected the literal with the default options, got:\n%s", result)
+	}
+}
+
+func TestDocumentUpdatePreprocessed(t *testing.T) {
+	versions := []string{
+		"public class Account {\n    public String name { get; set; }\n}\n",
+		"public virtual class Account {\n    public String name { get; private set; }\n    public Integer count;\n}\n",
+		"public virtual class Account {\n    public Integer count { get; }\n    public override String describe() { return 'account'; }\n}\n",
+	}
+
+	doc := NewDocument("apex")
+	defer doc.Close()
+
+	for i, version := range versions {
+		got, err := doc.Update([]byte(version))
+		if err != nil {
+			t.Fatalf("version %d: Update() error: %v", i, err)
+		}
+		want, err := ExtractOutline([]byte(version), "apex")
+		if err != nil {
+			t.Fatalf("version %d: ExtractOutline() error: %v", i, err)
+		}
+		if got != want {
+			t.Errorf("version %d: incremental outline differs\ngot:\n%s\nwant:\n%s", i, got, want)
+		}
+	}
+}
+
+func TestDocumentCacheAnalyzeReusesTree(t *testing.T) {
+	versions := []string{
+		"public class Shop {\n    public void open() {}\n}\n",
+		"public class Shop {\n    public void open() {}\n    public void close() {}\n}\n",
+	}
+	opts := DefaultOptions()
+	opts.Kinds = []string{"method"}
+	req := AnalysisRequest{Symbols: true}
+
+	cache := NewDocumentCache(4)
+	if _, err := cache.Analyze("Shop.java", []byte(versions[0]), "java", opts, req); err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	doc := cache.entries["Shop.java"].Value.(*cachedDocument).doc
+	if doc.tree == nil {
+		t.Fatal("Expected the tree to be kept for the next version")
+	}
+
+	got, err := cache.Analyze("Shop.java", []byte(versions[1]), "java", opts, req)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if cache.entries["Shop.java"].Value.(*cachedDocument).doc != doc || string(doc.source) != versions[1] {
+		t.Error("Expected the edit to update the cached document")
+	}
+	want, err := Analyze([]byte(versions[1]), "java", opts, req)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if got.Outline != want.Outline || len(got.Symbols) != len(want.Symbols) {
+		t.Errorf("incremental analysis differs\ngot:\n%s\nwant:\n%s", got.Outline, want.Outline)
+	}
+}
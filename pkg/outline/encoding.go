@@ -0,0 +1,121 @@
+package outline
+
+import (
+	"bytes"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is the character encoding a source file was written in
+type Encoding string
+
+const (
+	EncodingUTF8    Encoding = "UTF-8"
+	EncodingUTF8BOM Encoding = "UTF-8 with BOM"
+	EncodingUTF16LE Encoding = "UTF-16LE"
+	EncodingUTF16BE Encoding = "UTF-16BE"
+	// EncodingLatin1 covers ISO 8859-1 and its Windows-1252 superset, which
+	// is what editors on Windows write when they say Latin-1
+	EncodingLatin1 Encoding = "Latin-1"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Decode detects the encoding of a source file and returns its content as
+// UTF-8 without a byte order mark, which is what the parsers expect. UTF-16
+// is recognized by its byte order mark, or without one by the zero bytes of
+// ASCII characters, and content that is not valid UTF-8 is taken to be
+// Latin-1. Valid UTF-8 is returned as is.
+func Decode(content []byte) ([]byte, Encoding) {
+	switch {
+	case bytes.HasPrefix(content, utf8BOM):
+		return content[len(utf8BOM):], EncodingUTF8BOM
+	case bytes.HasPrefix(content, []byte{0xFF, 0xFE}):
+		return decodeUTF16(content[2:], false), EncodingUTF16LE
+	case bytes.HasPrefix(content, []byte{0xFE, 0xFF}):
+		return decodeUTF16(content[2:], true), EncodingUTF16BE
+	}
+
+	if bigEndian, ok := looksLikeUTF16(content); ok {
+		if bigEndian {
+			return decodeUTF16(content, true), EncodingUTF16BE
+		}
+		return decodeUTF16(content, false), EncodingUTF16LE
+	}
+	if utf8.Valid(content) {
+		return content, EncodingUTF8
+	}
+	return decodeLatin1(content), EncodingLatin1
+}
+
+// looksLikeUTF16 reports whether content without a byte order mark is
+// UTF-16, which for source code means most of its first characters are ASCII
+// and so have a zero byte on the same side
+func looksLikeUTF16(content []byte) (bigEndian, ok bool) {
+	sample := content
+	if len(sample) > 1024 {
+		sample = sample[:1024]
+	}
+	sample = sample[:len(sample)&^1]
+	if len(sample) < 4 {
+		return false, false
+	}
+
+	even, odd := 0, 0
+	for i := 0; i < len(sample); i += 2 {
+		if sample[i] == 0 {
+			even++
+		}
+		if sample[i+1] == 0 {
+			odd++
+		}
+	}
+	pairs := len(sample) / 2
+	switch {
+	case odd*10 >= pairs*7 && even == 0:
+		return false, true
+	case even*10 >= pairs*7 && odd == 0:
+		return true, true
+	}
+	return false, false
+}
+
+func decodeUTF16(content []byte, bigEndian bool) []byte {
+	units := make([]uint16, len(content)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(content[2*i])<<8 | uint16(content[2*i+1])
+		} else {
+			units[i] = uint16(content[2*i+1])<<8 | uint16(content[2*i])
+		}
+	}
+
+	result := make([]byte, 0, len(units))
+	for _, r := range utf16.Decode(units) {
+		result = utf8.AppendRune(result, r)
+	}
+	return result
+}
+
+// windows1252 maps the bytes 0x80 to 0x9F, control characters in
+// ISO 8859-1, to the punctuation Windows-1252 puts there. Unassigned bytes
+// keep their ISO 8859-1 meaning.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+func decodeLatin1(content []byte) []byte {
+	result := make([]byte, 0, len(content)+len(content)/8)
+	for _, b := range content {
+		switch {
+		case b < 0x80:
+			result = append(result, b)
+		case b < 0xA0:
+			result = utf8.AppendRune(result, windows1252[b-0x80])
+		default:
+			result = utf8.AppendRune(result, rune(b))
+		}
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func encodeUTF16(s string, bigEndian, bom bool) []byte {
+	var result []byte
+	if bom {
+		if bigEndian {
+			result = append(result, 0xFE, 0xFF)
+		} else {
+			result = append(result, 0xFF, 0xFE)
+		}
+	}
+	for _, unit := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			result = append(result, byte(unit>>8), byte(unit))
+		} else {
+			result = append(result, byte(unit), byte(unit>>8))
+		}
+	}
+	return result
+}
+
+func TestDecode(t *testing.T) {
+	source := "public class Café {\n    // “quoted”\n}\n"
+
+	tests := []struct {
+		name     string
+		content  []byte
+		want     string
+		encoding Encoding
+	}{
+		{"utf-8", []byte(source), source, EncodingUTF8},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, source...), source, EncodingUTF8BOM},
+		{"utf-16le bom", encodeUTF16(source, false, true), source, EncodingUTF16LE},
+		{"utf-16be bom", encodeUTF16(source, true, true), source, EncodingUTF16BE},
+		{"utf-16le", encodeUTF16(source, false, false), source, EncodingUTF16LE},
+		{"utf-16be", encodeUTF16(source, true, false), source, EncodingUTF16BE},
+		{"latin-1", []byte("class Caf\xe9 { // \x93quoted\x94\n}\n"), "class Café { // “quoted”\n}\n", EncodingLatin1},
+	}
+	for _, test := range tests {
+		decoded, encoding := Decode(test.content)
+		if string(decoded) != test.want || encoding != test.encoding {
+			t.Errorf("%s: got %q as %s, want %q as %s", test.name, decoded, encoding, test.want, test.encoding)
+		}
+	}
+}
+
+func TestDecodeUTF16Outline(t *testing.T) {
+	content, _ := Decode(encodeUTF16("public class Greeter {\n    public void greet() { }\n}\n", false, true))
+	result, err := ExtractOutline(content, "java")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "public void greet()") {
+		t.Errorf("Expected the method in the outline, got:\n%s", result)
+	}
+}
@@ -0,0 +1,282 @@
+package outline
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	htmlScriptPattern    = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`)
+	htmlTagPattern       = regexp.MustCompile(`(?is)<([a-z][\w-]*)(\s[^<>]*)?>`)
+	htmlAttributePattern = regexp.MustCompile(`(?is)([\w:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	htmlElementPattern   = regexp.MustCompile(`(?is)<(/?)([a-z][\w-]*)(\s[^<>]*?)?(/?)>`)
+	htmlStylePattern     = regexp.MustCompile(`(?is)<style\b([^>]*)>(.*?)</style\s*>`)
+	htmlCommentPattern   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlMarkupPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// htmlAttributes parses the attributes of a tag into a lowercase-keyed map
+func htmlAttributes(attrText []byte) map[string]string {
+	attrs := make(map[string]string)
+	for _, match := range htmlAttributePattern.FindAllSubmatch(attrText, -1) {
+		value := match[2]
+		if value == nil {
+			value = match[3]
+		}
+		if value == nil {
+			value = match[4]
+		}
+		attrs[strings.ToLower(string(match[1]))] = string(value)
+	}
+	return attrs
+}
+
+// scriptLanguage maps a <script> tag's type/lang attributes to a language,
+// returning false for non-code payloads such as JSON or templates
+func scriptLanguage(attrs map[string]string) (string, bool) {
+	switch strings.ToLower(attrs["lang"]) {
+	case "ts", "typescript":
+		return "typescript", true
+	}
+
+	switch strings.ToLower(attrs["type"]) {
+	case "", "module", "text/javascript", "application/javascript", "text/babel", "text/jsx":
+		return "javascript", true
+	case "text/typescript", "application/typescript":
+		return "typescript", true
+	}
+	return "", false
+}
+
+// lineAt returns the 1-indexed line number of a byte offset
+func lineAt(content []byte, offset int) int {
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// htmlSections are the semantic elements that structure a document
+var htmlSections = map[string]bool{
+	"header": true, "nav": true, "main": true, "section": true, "article": true, "aside": true,
+	"footer": true, "form": true, "dialog": true, "details": true, "template": true,
+}
+
+// htmlVoidElements never have a closing tag
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlOpenElement is an element whose closing tag hasn't been seen yet.
+// shown is set for elements listed in the outline, which indent the
+// elements inside them.
+type htmlOpenElement struct {
+	name  string
+	shown bool
+}
+
+// isHeading reports whether an element is one of h1 to h6
+func isHeading(name string) bool {
+	return len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6'
+}
+
+// extractHTMLOutline outlines the structure of an HTML document and the
+// code it embeds. Semantic sections, headings and elements with an id are
+// listed as a tree, <style> blocks by location, and each <script> block is
+// outlined in place with the JavaScript or TypeScript extractor. Elements
+// with inline event handlers are listed after the tree.
+func extractHTMLOutline(content []byte, opts Options) (string, error) {
+	var result strings.Builder
+
+	scripts := htmlScriptPattern.FindAllSubmatchIndex(content, -1)
+	scriptAt := make(map[int][]int, len(scripts))
+	for _, match := range scripts {
+		scriptAt[match[0]] = match
+	}
+
+	// Tags are searched outside of comments and of script and style bodies
+	masked := append([]byte(nil), content...)
+	blank := func(start, end int) {
+		for i := start; i < end; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+	for _, match := range scripts {
+		blank(match[4], match[5])
+	}
+	for _, match := range htmlCommentPattern.FindAllIndex(content, -1) {
+		blank(match[0], match[1])
+	}
+	for _, match := range htmlStylePattern.FindAllSubmatchIndex(masked, -1) {
+		blank(match[4], match[5])
+	}
+
+	var open []htmlOpenElement
+	depth := 0
+	for _, match := range htmlElementPattern.FindAllSubmatchIndex(masked, -1) {
+		name := strings.ToLower(string(masked[match[4]:match[5]]))
+		indent := strings.Repeat("  ", depth)
+
+		if match[3] > match[2] {
+			// A closing tag closes the innermost element of its name, and
+			// any left open inside it
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i].name != name {
+					continue
+				}
+				for _, element := range open[i:] {
+					if element.shown {
+						depth--
+					}
+				}
+				open = open[:i]
+				break
+			}
+			continue
+		}
+
+		if script, ok := scriptAt[match[0]]; ok {
+			if err := writeHTMLScript(&result, content, script, indent, opts); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		var attrText []byte
+		if match[6] >= 0 {
+			attrText = masked[match[6]:match[7]]
+		}
+		attrs := htmlAttributes(attrText)
+		line := lineAt(content, match[0])
+
+		if name == "style" {
+			fmt.Fprintf(&result, "%s<style%s> // line %d\n", indent, strings.TrimRight(collapseHTMLWhitespace(attrText), " /"), line)
+			continue
+		}
+
+		_, hasID := attrs["id"]
+		shown := htmlSections[name] || isHeading(name) || hasID
+		if shown {
+			element := name
+			if hasID {
+				element += "#" + attrs["id"]
+			}
+			fmt.Fprintf(&result, "%s<%s>", indent, element)
+			if isHeading(name) {
+				if text := htmlHeadingText(content, match[1], name); text != "" {
+					result.WriteString(" " + text)
+				}
+			}
+			fmt.Fprintf(&result, " // line %d\n", line)
+		}
+
+		if htmlVoidElements[name] || match[9] > match[8] {
+			continue
+		}
+		open = append(open, htmlOpenElement{name: name, shown: shown})
+		if shown {
+			depth++
+		}
+	}
+	if result.Len() > 0 && !strings.HasSuffix(result.String(), "\n\n") {
+		result.WriteString("\n")
+	}
+
+	var handlers []string
+	for _, match := range htmlTagPattern.FindAllSubmatchIndex(masked, -1) {
+		if match[4] < 0 {
+			continue
+		}
+		attrs := htmlAttributes(masked[match[4]:match[5]])
+
+		var events []string
+		for name, value := range attrs {
+			if strings.HasPrefix(name, "on") && len(name) > 2 {
+				events = append(events, fmt.Sprintf("%s=\"%s\"", name, strings.Join(strings.Fields(value), " ")))
+			}
+		}
+		if len(events) == 0 {
+			continue
+		}
+		sort.Strings(events)
+
+		element := strings.ToLower(string(masked[match[2]:match[3]]))
+		if id, ok := attrs["id"]; ok {
+			element += "#" + id
+		}
+		handlers = append(handlers, fmt.Sprintf("  <%s> %s // line %d\n", element, strings.Join(events, " "), lineAt(content, match[0])))
+	}
+
+	if len(handlers) > 0 {
+		result.WriteString("// event handlers\n")
+		for _, handler := range handlers {
+			result.WriteString(handler)
+		}
+	}
+
+	return result.String(), nil
+}
+
+// writeHTMLScript outlines one <script> block at indent, or shows where an
+// external script is loaded
+func writeHTMLScript(result *strings.Builder, content []byte, match []int, indent string, opts Options) error {
+	attrText := content[match[2]:match[3]]
+	body := content[match[4]:match[5]]
+	attrs := htmlAttributes(attrText)
+	lineNum := lineAt(content, match[0])
+
+	openTag := "<script" + strings.TrimRight(string(attrText), " ") + ">"
+	if src, ok := attrs["src"]; ok && len(bytes.TrimSpace(body)) == 0 {
+		fmt.Fprintf(result, "%s<script src=\"%s\"> // line %d\n", indent, src, lineNum)
+		return nil
+	}
+
+	language, ok := scriptLanguage(attrs)
+	if !ok {
+		return nil
+	}
+
+	// Keep the document's line numbering by blanking everything before
+	// the script body except newlines
+	script := make([]byte, 0, match[5])
+	script = append(script, bytes.Repeat([]byte("\n"), lineAt(content, match[4])-1)...)
+	script = append(script, body...)
+
+	scriptOutline, err := extractOutline(script, language, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(result, "%s%s // line %d\n", indent, openTag, lineNum)
+	for _, line := range strings.Split(strings.Trim(scriptOutline, "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			result.WriteString("\n")
+			continue
+		}
+		result.WriteString(indent + "  " + line + "\n")
+	}
+	result.WriteString(indent + "</script>\n")
+	return nil
+}
+
+// htmlHeadingText returns the text of the heading whose opening tag ends at
+// start, without its markup
+func htmlHeadingText(content []byte, start int, name string) string {
+	end := bytes.Index(bytes.ToLower(content[start:]), []byte("</"+name))
+	if end < 0 {
+		return ""
+	}
+	text := htmlMarkupPattern.ReplaceAll(content[start:start+end], []byte(" "))
+	return strings.Join(strings.Fields(string(text)), " ")
+}
+
+// collapseHTMLWhitespace joins the attributes of a tag onto one line
+func collapseHTMLWhitespace(attrText []byte) string {
+	if fields := strings.Fields(string(attrText)); len(fields) > 0 {
+		return " " + strings.Join(fields, " ")
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLOutlineWithEmbeddedScripts(t *testing.T) {
+	requireLanguages(t, "html")
+
+	htmlCode := `<!DOCTYPE html>
+<html>
+<head>
+  <script src="vendor.js"></script>
+  <script type="application/json" id="config">{"debug": true}</script>
+  <script>
+    /** Wire up the page */
+    function init() {
+      document.title = "ready";
+    }
+  </script>
+</head>
+<body onload="init()">
+  <button id="save" onclick="save(event)">Save</button>
+  <script lang="ts">
+    export function save(e: Event): void {
+      console.log(e);
+    }
+  </script>
+</body>
+</html>
+`
+
+	result, err := ExtractOutline([]byte(htmlCode), "html")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		`<script src="vendor.js"> // line 4`,
+		"<script> // line 6",
+		"function init() { // line 8",
+		`<script lang="ts"> // line 15`,
+		"save",
+		`<body> onload="init()" // line 13`,
+		`<button#save> onclick="save(event)" // line 14`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+
+	// Non-code script payloads are skipped
+	if strings.Contains(result, "debug") {
+		t.Error("Expected JSON script block to be skipped")
+	}
+}
+
+func TestHTMLStructuralOutline(t *testing.T) {
+	requireLanguages(t, "html")
+
+	htmlCode := `<!DOCTYPE html>
+<html>
+<head>
+  <style media="screen">
+    .hero { color: red; }
+  </style>
+</head>
+<body>
+  <header>
+    <nav id="menu"><a href="/">Home</a></nav>
+  </header>
+  <!-- <section id="old"></section> -->
+  <main>
+    <h1>Welcome <em>home</em></h1>
+    <section id="features">
+      <h2>Features</h2>
+      <p>Fast<br>and small</p>
+      <script>
+        function toggle() {}
+      </script>
+    </section>
+  </main>
+  <footer id="bottom"></footer>
+</body>
+</html>
+`
+
+	result, err := ExtractOutline([]byte(htmlCode), "html")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"<style media=\"screen\"> // line 4\n",
+		"<header> // line 9\n  <nav#menu> // line 10\n",
+		"<main> // line 13\n  <h1> Welcome home // line 14\n  <section#features> // line 15\n    <h2> Features // line 16\n",
+		"    <script> // line 18\n      function toggle() { // line 19\n",
+		"    </script>\n",
+		"<footer#bottom> // line 23\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+
+	// Commented out markup is not part of the document
+	if strings.Contains(result, "old") {
+		t.Errorf("Expected commented out section to be skipped, got:\n%s", result)
+	}
+}
@@ -0,0 +1,123 @@
+package outline
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// clausePattern matches the extends, implements and with clauses of
+	// Java-like class and interface signatures once type arguments are gone
+	clausePattern = regexp.MustCompile(`\b(extends|implements|with)\s+([\w.$]+(?:\s*,\s*[\w.$]+)*)`)
+	pythonBases   = regexp.MustCompile(`^class\s+\w+\s*\((.*)\)`)
+	swiftBases    = regexp.MustCompile(`\b(?:class|struct|enum|protocol|actor|extension)\s+[\w.]+\s*:\s*(.+)$`)
+	cppBases      = regexp.MustCompile(`\b(?:class|struct)\s+[\w:]+(?:\s+final)?\s*:\s*(.+)$`)
+	objcBases     = regexp.MustCompile(`@(?:interface|protocol)\s+\w+\s*(?:\([^)]*\)\s*)?(?::\s*(\w+)\s*)?(?:<([^>]*)>)?`)
+	goEmbedding   = regexp.MustCompile(`^\*?[\w.]+$`)
+	cppAccess     = regexp.MustCompile(`\b(?:public|private|protected|virtual)\s+`)
+)
+
+// Supertypes returns the types a class, interface, struct or protocol
+// symbol extends and the interfaces it implements, as its signature names
+// them: package qualifiers are kept and type arguments dropped. Go structs
+// extend the types they embed, and Swift classes are taken to extend the
+// first type they list and to conform to the others, since a signature
+// alone doesn't tell a superclass from a protocol.
+func Supertypes(symbol SymbolInfo, language string) (extends, implements []string) {
+	switch symbol.Type {
+	case "class", "interface", "struct", "enum", "protocol", "mixin", "record":
+	default:
+		return nil, nil
+	}
+	signature := symbol.Signature.String()
+
+	switch language {
+	case "java", "apex", "javascript", "typescript", "tsx", "dart":
+		for _, clause := range clausePattern.FindAllStringSubmatch(stripTypeArguments(signature, '<', '>'), -1) {
+			names := splitTypeList(clause[2])
+			switch {
+			case clause[1] == "implements":
+				implements = append(implements, names...)
+			case clause[1] == "extends" || language == "dart":
+				extends = append(extends, names...)
+			}
+		}
+	case "python":
+		if match := pythonBases.FindStringSubmatch(stripTypeArguments(signature, '[', ']')); match != nil {
+			for _, base := range splitTypeList(match[1]) {
+				if !strings.Contains(base, "=") && base != "object" {
+					extends = append(extends, base)
+				}
+			}
+		}
+	case "swift":
+		if match := swiftBases.FindStringSubmatch(stripTypeArguments(signature, '<', '>')); match != nil {
+			list, _, _ := strings.Cut(match[1], " where ")
+			names := splitTypeList(list)
+			switch symbol.Type {
+			case "protocol":
+				extends = names
+			case "class":
+				extends, implements = names[:1], names[1:]
+			default:
+				implements = names
+			}
+		}
+	case "c", "cpp", "cuda":
+		if match := cppBases.FindStringSubmatch(stripTypeArguments(signature, '<', '>')); match != nil {
+			extends = splitTypeList(cppAccess.ReplaceAllString(match[1], ""))
+		}
+	case "objc":
+		if match := objcBases.FindStringSubmatch(signature); match != nil {
+			if match[1] != "" {
+				extends = append(extends, match[1])
+			}
+			if symbol.Type == "protocol" {
+				extends = append(extends, splitTypeList(match[2])...)
+			} else {
+				implements = splitTypeList(match[2])
+			}
+		}
+	case "go":
+		for _, field := range symbol.Children {
+			if field.Type == "field" && goEmbedding.MatchString(field.Signature.String()) {
+				extends = append(extends, strings.TrimPrefix(field.Signature.String(), "*"))
+			}
+		}
+	}
+
+	if len(implements) == 0 {
+		implements = nil
+	}
+	return extends, implements
+}
+
+// stripTypeArguments removes every bracketed group opened by open, such as
+// the type arguments in Comparable<Dog>
+func stripTypeArguments(s string, open, close byte) string {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == open:
+			depth++
+		case s[i] == close && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitTypeList splits a comma-separated list of type names, dropping the
+// spaces around them and anything after a type's name, such as a body
+func splitTypeList(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(name), "{")); len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
@@ -0,0 +1,40 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSupertypes(t *testing.T) {
+	tests := []struct {
+		language, code      string
+		extends, implements string
+	}{
+		{"java", "public class Dog extends Animal<Dog> implements Comparable<Dog>, java.io.Serializable {}", "Animal", "Comparable, java.io.Serializable"},
+		{"java", "interface Walker extends Mover, Closeable {}", "Mover, Closeable", ""},
+		{"typescript", "export class Box<T extends Shape> extends Base<T> implements Pet {}", "Base", "Pet"},
+		{"dart", "class Dog extends Animal with Loud implements Pet {}", "Animal, Loud", "Pet"},
+		{"python", "class Cat(base.Animal, Generic[T], metaclass=ABCMeta):\n    pass\n", "base.Animal, Generic", ""},
+		{"swift", "class Dog: Animal, Pet {}", "Animal", "Pet"},
+		{"swift", "struct Point: Equatable, Hashable {}", "", "Equatable, Hashable"},
+		{"cpp", "class Dog : public Animal, private virtual Pet<int> {};", "Animal, Pet", ""},
+		{"go", "package a\n\ntype Dog struct {\n\tAnimal\n\t*pets.Pet\n\tName string\n}\n", "Animal, pets.Pet", ""},
+	}
+
+	for _, tt := range tests {
+		if !IsSupported(tt.language) {
+			continue
+		}
+		symbols, err := ExtractSymbols([]byte(tt.code), tt.language)
+		if err != nil || len(symbols) == 0 {
+			t.Fatalf("%s: expected a symbol, got %v (%v)", tt.language, symbols, err)
+		}
+		extends, implements := Supertypes(symbols[0], tt.language)
+		if got := strings.Join(extends, ", "); got != tt.extends {
+			t.Errorf("%s: expected %s to extend %q, got %q", tt.language, symbols[0].Name, tt.extends, got)
+		}
+		if got := strings.Join(implements, ", "); got != tt.implements {
+			t.Errorf("%s: expected %s to implement %q, got %q", tt.language, symbols[0].Name, tt.implements, got)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package outline
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// symbolKinds are the kinds of symbol the extractors report, across
+// languages
+var symbolKinds = []string{
+	"actor", "class", "comptime", "constant", "constructor", "enum",
+	"enum_member", "extension", "field", "function", "interface", "macro",
+	"method", "mixin", "module", "namespace", "property", "protocol",
+	"record", "struct", "test", "trigger", "type", "union", "variable",
+}
+
+// kindAliases are the short names ParseKinds accepts for some kinds
+var kindAliases = map[string]string{
+	"const": "constant",
+	"ctor":  "constructor",
+	"fn":    "function",
+	"func":  "function",
+	"var":   "variable",
+}
+
+// SymbolKinds lists the kinds of symbol ParseKinds accepts
+func SymbolKinds() []string {
+	return slices.Clone(symbolKinds)
+}
+
+// ParseKinds parses a comma-separated list of symbol kinds, such as
+// "func,type,class", into the kinds of Options.Kinds
+func ParseKinds(list string) ([]string, error) {
+	var kinds []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if kind, ok := kindAliases[name]; ok {
+			name = kind
+		}
+		if !slices.Contains(symbolKinds, name) {
+			return nil, fmt.Errorf("unknown symbol kind %q (expected %s)", name, strings.Join(symbolKinds, ", "))
+		}
+		if !slices.Contains(kinds, name) {
+			kinds = append(kinds, name)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds, nil
+}
+
+// filterSymbols leaves out and reorders symbols as opts asks for
+func filterSymbols(symbols []SymbolInfo, opts Options) []SymbolInfo {
+	if opts.PublicOnly {
+		symbols = filterPublic(symbols)
+	}
+	if len(opts.Kinds) > 0 {
+		symbols = filterKinds(symbols, opts.Kinds)
+	}
+	if opts.StartLine > 0 || opts.EndLine > 0 {
+		symbols = filterLines(symbols, opts.StartLine, opts.EndLine)
+	}
+	if opts.MaxDepth > 0 {
+		symbols = limitDepth(symbols, opts.MaxDepth)
+	}
+	return sortSymbols(symbols, opts.Sort)
+}
+
+// filterPublic keeps the exported symbols, with their exported members
+func filterPublic(symbols []SymbolInfo) []SymbolInfo {
+	var kept []SymbolInfo
+	for _, symbol := range symbols {
+		if !symbol.IsPublic {
+			continue
+		}
+		symbol.Children = filterPublic(symbol.Children)
+		kept = append(kept, symbol)
+	}
+	return kept
+}
+
+// filterLines keeps the symbols whose declaration spans a line between start
+// and end, zero leaving that end open, with the members that do
+func filterLines(symbols []SymbolInfo, start, end int) []SymbolInfo {
+	var kept []SymbolInfo
+	for _, symbol := range symbols {
+		last := max(symbol.EndLine, symbol.Line)
+		if (start > 0 && last < start) || (end > 0 && symbol.Line > end) {
+			continue
+		}
+		symbol.Children = filterLines(symbol.Children, start, end)
+		kept = append(kept, symbol)
+	}
+	return kept
+}
+
+// filterKinds keeps the symbols of the given kinds. The symbols enclosing
+// one that is kept are kept too, so that members stay under their type,
+// but with only the members that are kept.
+func filterKinds(symbols []SymbolInfo, kinds []string) []SymbolInfo {
+	var kept []SymbolInfo
+	for _, symbol := range symbols {
+		children := filterKinds(symbol.Children, kinds)
+		if len(children) == 0 && !slices.Contains(kinds, symbol.Type) {
+			continue
+		}
+		symbol.Children = children
+		kept = append(kept, symbol)
+	}
+	return kept
+}
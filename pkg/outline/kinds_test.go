@@ -0,0 +1,86 @@
+package outline
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKinds(t *testing.T) {
+	kinds, err := ParseKinds("func, Type,class,function")
+	if err != nil {
+		t.Fatalf("ParseKinds failed: %v", err)
+	}
+	if want := []string{"class", "function", "type"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Expected %v, got %v", want, kinds)
+	}
+	if _, err := ParseKinds("func,widget"); err == nil {
+		t.Error("Expected an error for an unknown kind")
+	}
+}
+
+func TestExtractOutlineKinds(t *testing.T) {
+	requireLanguages(t, "go")
+
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Kinds: []string{"function"}})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if result != "func helper() // line 14\n" {
+		t.Errorf("Expected only the function, got:\n%s", result)
+	}
+
+	java := `public class Shop {
+    private int count;
+    public Shop() {}
+    public void open() {}
+}
+`
+	symbols, err := ExtractSymbolsWithOptions([]byte(java), "java", Options{Kinds: []string{"method"}})
+	if err != nil {
+		t.Fatalf("ExtractSymbolsWithOptions failed: %v", err)
+	}
+	if len(symbols) != 1 || len(symbols[0].Children) != 1 || symbols[0].Children[0].Name != "open" {
+		t.Errorf("Expected the class to hold only its method, got %+v", symbols)
+	}
+
+	result, err = ExtractOutlineWithOptions([]byte(java), "java", Options{Kinds: []string{"class"}})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if !strings.Contains(result, "Shop") || strings.Contains(result, "open") {
+		t.Errorf("Expected only the class, got:\n%s", result)
+	}
+}
+
+func TestExtractOutlinePublicOnlyAndMaxDepth(t *testing.T) {
+	requireLanguages(t, "go")
+
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailFull, PublicOnly: true})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if !strings.Contains(result, "Name string") || strings.Contains(result, "count") || strings.Contains(result, "helper") {
+		t.Errorf("Expected only the exported symbols and fields, got:\n%s", result)
+	}
+
+	result, err = ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailFull, MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if !strings.Contains(result, "Greeter") || strings.Contains(result, "Name string") {
+		t.Errorf("Expected the types without their fields, got:\n%s", result)
+	}
+}
+
+func TestExtractOutlineLineRange(t *testing.T) {
+	requireLanguages(t, "go")
+
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailFull, StartLine: 6, EndLine: 11})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if !strings.Contains(result, "count int") || !strings.Contains(result, "Greet()") || strings.Contains(result, "Name string") || strings.Contains(result, "helper") {
+		t.Errorf("Expected the symbols spanning lines 6 to 11, got:\n%s", result)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !outline_nolang_apex
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	java "github.com/tree-sitter/tree-sitter-java/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// Apex has no grammar of its own: its classes are parsed with the Java
+// grammar once the Apex-only syntax is masked, and its triggers are scanned
+// from the source.
+func init() {
+	registerLanguage("apex", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(java.Language())
+		},
+		outline:    languages.ExtractApexOutline,
+		symbols:    languages.ExtractApexSymbols,
+		features:   Features{Docs: true, Nesting: true, Visibility: true},
+		preprocess: languages.MaskApex,
+	})
+}
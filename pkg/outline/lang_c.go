@@ -0,0 +1,21 @@
+//go:build !outline_nolang_c
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("c", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(c.Language())
+		},
+		outline:  languages.ExtractCOutline,
+		symbols:  languages.ExtractCSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
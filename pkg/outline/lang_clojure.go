@@ -0,0 +1,21 @@
+//go:build !outline_nolang_clojure
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/internal/grammars/clojure"
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("clojure", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(clojure.Language())
+		},
+		outline:  languages.ExtractClojureOutline,
+		symbols:  languages.ExtractClojureSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
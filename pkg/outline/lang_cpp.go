@@ -0,0 +1,21 @@
+//go:build !outline_nolang_cpp
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("cpp", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(cpp.Language())
+		},
+		outline:  languages.ExtractCppOutline,
+		symbols:  languages.ExtractCSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
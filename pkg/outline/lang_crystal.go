@@ -0,0 +1,15 @@
+//go:build !outline_nolang_crystal
+
+package outline
+
+import "github.com/sourceradar/outline/pkg/outline/languages"
+
+// Crystal has no tree-sitter grammar, so it is outlined and its symbols are
+// extracted by scanning its source.
+func init() {
+	registerTextLanguage("crystal", textLanguage{
+		outline:  languages.ExtractCrystalOutline,
+		symbols:  languages.ExtractCrystalSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
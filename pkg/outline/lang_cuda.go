@@ -0,0 +1,24 @@
+//go:build !outline_nolang_cuda
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// CUDA is parsed with the C++ grammar once its execution space qualifiers
+// and kernel launches are masked.
+func init() {
+	registerLanguage("cuda", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(cpp.Language())
+		},
+		outline:    languages.ExtractCppOutline,
+		symbols:    languages.ExtractCSymbols,
+		features:   Features{Docs: true, Nesting: true, Visibility: true},
+		preprocess: languages.MaskCUDA,
+	})
+}
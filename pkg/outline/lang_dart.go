@@ -0,0 +1,21 @@
+//go:build !outline_nolang_dart
+
+package outline
+
+import (
+	dart "github.com/UserNobody14/tree-sitter-dart/bindings/go"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("dart", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(dart.Language())
+		},
+		outline:  languages.ExtractDartOutline,
+		symbols:  languages.ExtractDartSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
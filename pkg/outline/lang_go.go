@@ -0,0 +1,21 @@
+//go:build !outline_nolang_go
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	golang "github.com/tree-sitter/tree-sitter-go/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("go", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(golang.Language())
+		},
+		outline:  languages.ExtractGoOutline,
+		symbols:  languages.ExtractGoSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
@@ -0,0 +1,21 @@
+//go:build !outline_nolang_java
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	java "github.com/tree-sitter/tree-sitter-java/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("java", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(java.Language())
+		},
+		outline:  languages.ExtractJavaOutline,
+		symbols:  languages.ExtractJavaSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
@@ -0,0 +1,21 @@
+//go:build !outline_nolang_javascript
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	javascript "github.com/tree-sitter/tree-sitter-javascript/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("javascript", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(javascript.Language())
+		},
+		outline:  languages.ExtractJSOutline,
+		symbols:  languages.ExtractJSSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
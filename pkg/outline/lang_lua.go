@@ -0,0 +1,24 @@
+//go:build !outline_nolang_lua
+
+package outline
+
+import (
+	lua "github.com/tree-sitter-grammars/tree-sitter-lua/bindings/go"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// Lua has no visibility modifiers. Locals are taken to be private, except
+// for the table a file returns as its module, which only convention makes
+// the public one.
+func init() {
+	registerLanguage("lua", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(lua.Language())
+		},
+		outline:  languages.ExtractLuaOutline,
+		symbols:  languages.ExtractLuaSymbols,
+		features: Features{Docs: true, Nesting: true},
+	})
+}
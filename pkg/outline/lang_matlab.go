@@ -0,0 +1,15 @@
+//go:build !outline_nolang_matlab
+
+package outline
+
+import "github.com/sourceradar/outline/pkg/outline/languages"
+
+// MATLAB has no tree-sitter grammar, so it is outlined and its symbols are
+// extracted by scanning its source.
+func init() {
+	registerTextLanguage("matlab", textLanguage{
+		outline:  languages.ExtractMATLABOutline,
+		symbols:  languages.ExtractMATLABSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
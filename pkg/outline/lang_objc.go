@@ -0,0 +1,25 @@
+//go:build !outline_nolang_objc
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// Objective-C has no grammar of its own: its C parts are parsed with the C
+// grammar once its blocks are masked, and its classes, categories and
+// protocols are scanned from the source.
+func init() {
+	registerLanguage("objc", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(c.Language())
+		},
+		outline:    languages.ExtractObjCOutline,
+		symbols:    languages.ExtractObjCSymbols,
+		features:   Features{Docs: true, Nesting: true, Visibility: true},
+		preprocess: languages.MaskObjC,
+	})
+}
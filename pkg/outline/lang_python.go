@@ -0,0 +1,23 @@
+//go:build !outline_nolang_python
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// Python has no visibility of its own: names are private by the convention
+// of a leading underscore, which PublicOnly goes by all the same.
+func init() {
+	registerLanguage("python", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(python.Language())
+		},
+		outline:  languages.ExtractPythonOutline,
+		symbols:  languages.ExtractPythonSymbols,
+		features: Features{Docs: true, Nesting: true},
+	})
+}
@@ -0,0 +1,21 @@
+//go:build !outline_nolang_swift
+
+package outline
+
+import (
+	swift "github.com/alex-pinkus/tree-sitter-swift/bindings/go"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("swift", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(swift.Language())
+		},
+		outline:  languages.ExtractSwiftOutline,
+		symbols:  languages.ExtractSwiftSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
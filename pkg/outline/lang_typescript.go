@@ -0,0 +1,29 @@
+//go:build !outline_nolang_typescript
+
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	typescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("typescript", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(typescript.LanguageTypescript())
+		},
+		outline:  languages.ExtractTSOutline,
+		symbols:  languages.ExtractTSSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+	registerLanguage("tsx", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(typescript.LanguageTSX())
+		},
+		outline:  languages.ExtractTSOutline,
+		symbols:  languages.ExtractTSSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
@@ -0,0 +1,21 @@
+//go:build !outline_nolang_zig
+
+package outline
+
+import (
+	zig "github.com/tree-sitter-grammars/tree-sitter-zig/bindings/go"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+func init() {
+	registerLanguage("zig", languageSupport{
+		grammar: func() *sitter.Language {
+			return sitter.NewLanguage(zig.Language())
+		},
+		outline:  languages.ExtractZigOutline,
+		symbols:  languages.ExtractZigSymbols,
+		features: Features{Docs: true, Nesting: true, Visibility: true},
+	})
+}
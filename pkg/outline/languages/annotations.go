@@ -0,0 +1,92 @@
+package languages
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Annotation is a TODO-style marker found in a comment
+type Annotation struct {
+	// Kind is the marker, such as TODO or FIXME
+	Kind string `json:"kind"`
+	// Author is the name given in TODO(name) or at the end of the comment
+	// as (name), if any
+	Author string `json:"author,omitempty"`
+	Text   string `json:"text"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	// Symbol is the qualified name of the innermost symbol the comment is
+	// in, empty at the top level of the file
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// annotationPattern matches a marker, an optional (author) right after it,
+// and the text that follows
+var annotationPattern = regexp.MustCompile(`\b(TODO|FIXME|HACK|XXX|BUG)\b(?:\(([^)]*)\))?[:\s-]*(.*)`)
+
+// trailingAuthorPattern matches the "fix this (name)" convention
+var trailingAuthorPattern = regexp.MustCompile(`\s*\(([\w.@-]+)\)$`)
+
+// ExtractAnnotations lists the TODO-style markers in the comments of a file.
+// The symbols of the file are used to find the declaration each marker is
+// in, and may be nil.
+func ExtractAnnotations(root *sitter.Node, content []byte, symbols []Symbol) []Annotation {
+	var annotations []Annotation
+
+	for _, comment := range commentRanges(root, nil) {
+		text := content[comment.start:comment.end]
+		offset := comment.start
+
+		for len(text) > 0 {
+			line, rest, _ := bytes.Cut(text, []byte("\n"))
+			if match := annotationPattern.FindSubmatchIndex(line); match != nil {
+				annotations = append(annotations, newAnnotation(line, match, offset, content, symbols))
+			}
+			offset += len(line) + 1
+			text = rest
+		}
+	}
+
+	return annotations
+}
+
+func newAnnotation(line []byte, match []int, offset int, content []byte, symbols []Symbol) Annotation {
+	markerStart := offset + match[0]
+
+	annotation := Annotation{
+		Kind: string(line[match[2]:match[3]]),
+		Text: strings.TrimSpace(cleanComment(string(line[match[6]:match[7]]))),
+	}
+	if match[4] >= 0 {
+		annotation.Author = strings.TrimSpace(string(line[match[4]:match[5]]))
+	} else if author := trailingAuthorPattern.FindStringSubmatchIndex(annotation.Text); author != nil {
+		annotation.Author = annotation.Text[author[2]:author[3]]
+		annotation.Text = annotation.Text[:author[0]]
+	}
+
+	lineStart := bytes.LastIndexByte(content[:markerStart], '\n') + 1
+	annotation.Line = bytes.Count(content[:markerStart], []byte("\n")) + 1
+	annotation.Column = markerStart - lineStart + 1
+	annotation.Symbol = enclosingSymbol(symbols, markerStart, "")
+
+	return annotation
+}
+
+// enclosingSymbol returns the qualified name of the innermost symbol whose
+// source contains offset
+func enclosingSymbol(symbols []Symbol, offset int, prefix string) string {
+	for _, symbol := range symbols {
+		if offset < symbol.Source.Start() || offset >= symbol.Source.End() {
+			continue
+		}
+		name := prefix + symbol.Name
+		if inner := enclosingSymbol(symbol.Children, offset, name+"."); inner != "" {
+			return inner
+		}
+		return name
+	}
+	return ""
+}
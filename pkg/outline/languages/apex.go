@@ -0,0 +1,307 @@
+package languages
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Apex is close enough to Java for the Java grammar to read its classes once
+// the constructs Java lacks are masked out of a copy of the file: sharing
+// modes, property accessors, SOQL queries, collection initializers and
+// single-quoted strings. Masking keeps every offset, so the tree of the copy
+// is read against the original content and signatures keep the Apex text.
+// Triggers aren't classes at all and are found by scanning.
+
+var (
+	apexWordPattern        = regexp.MustCompile(`[A-Za-z_]\w*`)
+	apexPropertyPattern    = regexp.MustCompile(`(?i)\w\s*(\{)\s*(?:(?:public|private|protected|global)\s+)?(?:get|set)\s*[;{]`)
+	apexAccessorPattern    = regexp.MustCompile(`(?i)\b((?:(?:public|private|protected|global)\s+)?(?:get|set))\s*[;{]`)
+	apexInitializerPattern = regexp.MustCompile(`(?i)\bnew\s+[\w.]+\s*(?:<[^;{}()]*>)?\s*(\{)`)
+	apexQueryPattern       = regexp.MustCompile(`(?i)\[\s*(?:SELECT|FIND)\b`)
+	apexTriggerPattern     = regexp.MustCompile(`(?im)^[ \t]*trigger\s+(\w+)\s+on\s+(\w+)\s*\([^)]*\)\s*(\{)`)
+)
+
+// apexKeywords are the Java keywords Apex also has, which Apex accepts in
+// any case
+var apexKeywords = map[string]bool{
+	"public": true, "private": true, "protected": true, "static": true, "final": true, "abstract": true,
+	"transient": true, "class": true, "interface": true, "enum": true, "extends": true, "implements": true,
+	"void": true, "return": true, "new": true, "if": true, "else": true, "for": true, "while": true, "do": true,
+	"try": true, "catch": true, "finally": true, "throw": true, "this": true, "super": true, "null": true,
+	"true": true, "false": true, "instanceof": true, "break": true, "continue": true,
+}
+
+// apexModifiers are the modifiers Java doesn't have, which are masked out
+var apexModifiers = map[string]bool{
+	"virtual": true, "override": true, "testmethod": true, "webservice": true,
+}
+
+// apexMasked is an Apex file prepared for the Java grammar. properties maps
+// the offset of the semicolon that replaced the accessors of a property to
+// the accessors, as in { get; private set; }.
+type apexMasked struct {
+	content    []byte
+	properties map[int]string
+	triggers   []Symbol
+}
+
+// maskApex builds the copy of content the Java grammar reads
+func maskApex(content []byte) *apexMasked {
+	m := &apexMasked{content: bytes.Clone(content), properties: map[int]string{}}
+
+	// code is content with its comments and string contents blanked, which
+	// the masks are looked for in
+	code := bytes.Clone(content)
+	for i := 0; i < len(code); i++ {
+		switch {
+		case bytes.HasPrefix(code[i:], []byte("//")):
+			for ; i < len(code) && code[i] != '\n'; i++ {
+				code[i] = ' '
+			}
+		case bytes.HasPrefix(code[i:], []byte("/*")):
+			end := len(code)
+			if e := bytes.Index(code[i+2:], []byte("*/")); e >= 0 {
+				end = i + 2 + e + 2
+			}
+			for ; i < end; i++ {
+				if code[i] != '\n' {
+					code[i] = ' '
+				}
+			}
+			i--
+		case code[i] == '\'':
+			// Apex strings are single-quoted, which Java reads as characters
+			m.content[i] = '"'
+			for i++; i < len(code) && code[i] != '\'' && code[i] != '\n'; i++ {
+				if code[i] == '\\' && i+1 < len(code) {
+					code[i] = ' '
+					i++
+				} else if code[i] == '"' {
+					m.content[i] = ' '
+				}
+				code[i] = ' '
+			}
+			if i < len(code) && code[i] == '\'' {
+				m.content[i] = '"'
+			}
+		}
+	}
+
+	blank := func(start, end int, replacement string) {
+		copy(m.content[start:], replacement)
+		for i := start + len(replacement); i < end; i++ {
+			if m.content[i] != '\n' {
+				m.content[i] = ' '
+			}
+		}
+	}
+
+	// Keywords are fixed up first, since the blocks masked next may
+	// contain some
+	for _, word := range apexWordPattern.FindAllIndex(code, -1) {
+		text := strings.ToLower(string(code[word[0]:word[1]]))
+		switch {
+		case text == "global":
+			copy(m.content[word[0]:], "public")
+		case apexModifiers[text]:
+			blank(word[0], word[1], "")
+		case text == "with" || text == "without" || text == "inherited":
+			if next := apexWordPattern.FindIndex(code[word[1]:]); next != nil &&
+				strings.TrimSpace(string(code[word[1]:word[1]+next[0]])) == "" &&
+				strings.EqualFold(string(code[word[1]+next[0]:word[1]+next[1]]), "sharing") {
+				blank(word[0], word[1]+next[1], "")
+			}
+		case apexKeywords[text]:
+			copy(m.content[word[0]:], text)
+		}
+	}
+
+	for _, match := range apexTriggerPattern.FindAllSubmatchIndex(code, -1) {
+		end := matchBrace(code, match[6])
+		m.triggers = append(m.triggers, apexTrigger(content, match, end))
+		blank(match[0], end, "")
+	}
+	for _, match := range apexPropertyPattern.FindAllSubmatchIndex(code, -1) {
+		open := match[2]
+		end := matchBrace(code, open)
+		var accessors []string
+		for _, accessor := range apexAccessorPattern.FindAllSubmatch(code[open:end], -1) {
+			accessors = append(accessors, collapseWhitespace(string(accessor[1]))+";")
+		}
+		m.properties[open] = "{ " + strings.Join(accessors, " ") + " }"
+		blank(open, end, ";")
+	}
+	for _, match := range apexInitializerPattern.FindAllSubmatchIndex(code, -1) {
+		blank(match[0], matchBrace(code, match[2]), "null")
+	}
+	for _, match := range apexQueryPattern.FindAllIndex(code, -1) {
+		end := bytes.IndexByte(code[match[0]:], ']')
+		if end < 0 {
+			continue
+		}
+		blank(match[0], match[0]+end+1, "null")
+	}
+	return m
+}
+
+// matchBrace returns the offset just past the brace that closes the one at
+// open, or the end of code
+func matchBrace(code []byte, open int) int {
+	depth := 0
+	for i := open; i < len(code); i++ {
+		switch code[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(code)
+}
+
+// apexTrigger builds the symbol of a trigger, whose signature names the
+// object and the events it fires on
+func apexTrigger(content []byte, match []int, end int) Symbol {
+	start := match[0] + len(content[match[0]:match[1]]) - len(bytes.TrimLeft(content[match[0]:match[1]], " \t"))
+	startLine, startColumn := offsetPosition(content, start)
+	endLine, endColumn := offsetPosition(content, end)
+	return Symbol{
+		Type:          "trigger",
+		Name:          string(content[match[2]:match[3]]),
+		Signature:     newText(content, uint(start), uint(match[6]), signatureText),
+		Documentation: apexDocComment(content, start),
+		Line:          startLine,
+		Column:        startColumn,
+		EndLine:       endLine,
+		EndColumn:     endColumn,
+		Source:        newText(content, uint(start), uint(end), rawText),
+		IsPublic:      true,
+	}
+}
+
+// apexDocComment returns the /** */ comment right above offset, if any
+func apexDocComment(content []byte, offset int) Text {
+	before := bytes.TrimRight(content[:offset], " \t\r\n")
+	if !bytes.HasSuffix(before, []byte("*/")) {
+		return Text{}
+	}
+	start := bytes.LastIndex(before, []byte("/**"))
+	if start < 0 {
+		return Text{}
+	}
+	return newText(content, uint(start), uint(len(before)), commentText)
+}
+
+// offsetPosition returns the 1-based line and column of offset
+func offsetPosition(content []byte, offset int) (int, int) {
+	line := bytes.Count(content[:offset], []byte("\n")) + 1
+	return line, offset - (bytes.LastIndexByte(content[:offset], '\n') + 1) + 1
+}
+
+var apexSymbols = &symbolSpec{
+	rules:      javaSymbols.rules,
+	containers: javaSymbols.containers,
+	public:     isApexPublic,
+	complexity: javaComplexity,
+}
+
+// isApexPublic treats global, public and protected members as part of the
+// API, as well as everything declared in an interface
+func isApexPublic(node *sitter.Node, name string, content []byte) bool {
+	if node.Kind() == "enum_constant" {
+		return true
+	}
+	if parent := node.Parent(); parent != nil && parent.Kind() == "interface_body" {
+		return true
+	}
+	for _, modifier := range getJavaModifiers(node, content) {
+		switch strings.ToLower(modifier) {
+		case "global", "public", "protected":
+			return true
+		}
+	}
+	return false
+}
+
+// MaskApex returns the copy of content the Java grammar reads
+func MaskApex(content []byte) []byte {
+	return maskApex(content).content
+}
+
+// ExtractApexSymbols extracts the classes, interfaces, enums and triggers
+// of an Apex file with their members. Fields with accessors are properties.
+// root is the Java tree of the content masked by MaskApex.
+func ExtractApexSymbols(root *sitter.Node, content []byte) []Symbol {
+	m := maskApex(content)
+
+	symbols := extractSymbols(root, content, apexSymbols)
+	m.markProperties(symbols)
+
+	symbols = append(symbols, m.triggers...)
+	sort.SliceStable(symbols, func(i, j int) bool { return symbols[i].Line < symbols[j].Line })
+	return symbols
+}
+
+// markProperties turns the fields whose accessors were masked into
+// properties, with the accessors in their signature
+func (m *apexMasked) markProperties(symbols []Symbol) {
+	for i := range symbols {
+		symbol := &symbols[i]
+		if symbol.Type == "field" {
+			if accessors, ok := m.properties[symbol.Source.End()-1]; ok {
+				symbol.Type = "property"
+				symbol.Signature = NewText(symbol.Signature.String() + " " + accessors)
+			}
+		}
+		m.markProperties(symbol.Children)
+	}
+}
+
+// ExtractApexOutline renders the classes, interfaces, enums and triggers of
+// an Apex file as pseudo-source, with method bodies elided. root is the Java
+// tree of the content masked by MaskApex.
+func ExtractApexOutline(root *sitter.Node, content []byte) string {
+	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
+	writeApexSymbols(&result, ExtractApexSymbols(root, content), 0)
+	return result.String()
+}
+
+func writeApexSymbols(result *strings.Builder, symbols []Symbol, depth int) {
+	indent := tabIndent(depth)
+	for _, symbol := range symbols {
+		writeDocComment(result, indent, symbol.Documentation.String())
+		writeStrings(result, indent, symbol.Signature.String())
+
+		switch symbol.Type {
+		case "class", "interface", "enum":
+			result.WriteString(" { // line ")
+			writeUint(result, uint(symbol.Line))
+			result.WriteString("\n")
+			writeApexSymbols(result, symbol.Children, depth+1)
+			writeStrings(result, indent, "}\n\n")
+			continue
+		case "method", "constructor", "trigger":
+			// Interface and abstract methods have no body
+			if strings.HasSuffix(symbol.Source.String(), "}") {
+				result.WriteString(" { ... }")
+			} else if !strings.HasSuffix(symbol.Signature.String(), ";") {
+				result.WriteString(";")
+			}
+		case "field":
+			result.WriteString(";")
+		case "enum_member":
+			result.WriteString(",")
+		}
+		result.WriteString(" // line ")
+		writeUint(result, uint(symbol.Line))
+		result.WriteString("\n")
+	}
+}
@@ -0,0 +1,116 @@
+//go:build !outline_nolang_apex
+
+package languages
+
+import (
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	java "github.com/tree-sitter/tree-sitter-java/bindings/go"
+)
+
+const apexCode = `/**
+ * Queries and updates accounts.
+ */
+public With Sharing class AccountService extends BaseService implements Queueable {
+    private static final Set<String> TYPES = new Set<String>{'Customer', 'Partner'};
+    public String name { get; private set; }
+    public Integer count {
+        get { return items.size(); }
+    }
+
+    public AccountService(String name) {
+        this.name = name;
+    }
+
+    /** Returns the accounts of a type */
+    @AuraEnabled(cacheable=true)
+    global static List<Account> getAccounts(String type) {
+        if (String.isBlank(type) && TYPES.contains(type)) {
+            return new List<Account>();
+        }
+        return [SELECT Id, Name FROM Account WHERE Type = :type];
+    }
+
+    public override void execute(QueueableContext context) {
+        insert new Account(Name = 'It''s new');
+    }
+
+    private virtual void helper() {}
+
+    public interface Visitor {
+        void visit(Account account);
+    }
+
+    public enum Status { ACTIVE, CLOSED }
+}
+`
+
+const apexTriggerCode = `/** Keeps accounts in sync */
+trigger AccountTrigger on Account (before insert, after update) {
+    for (Account a : Trigger.new) {
+        a.Name = 'x';
+    }
+}
+`
+
+func TestApexOutline(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(java.Language()), MaskApex([]byte(apexCode)))
+	result := ExtractApexOutline(tree.RootNode(), []byte(apexCode))
+
+	for _, want := range []string{
+		"// Queries and updates accounts.\npublic With Sharing class AccountService extends BaseService implements Queueable { // line 4\n",
+		"\tprivate static final Set<String> TYPES; // line 5\n",
+		"\tpublic String name { get; private set; } // line 6\n",
+		"\tpublic Integer count { get; } // line 7\n",
+		"\tpublic AccountService(String name) { ... } // line 11\n",
+		"\t// Returns the accounts of a type\n\t@AuraEnabled(cacheable=true) global static List<Account> getAccounts(String type) { ... } // line 16\n",
+		"\tpublic override void execute(QueueableContext context) { ... } // line 24\n",
+		"\tpublic interface Visitor { // line 30\n\t\tvoid visit(Account account); // line 31\n",
+		"\t\tACTIVE, // line 34\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+
+	tree = parseSource(t, sitter.NewLanguage(java.Language()), MaskApex([]byte(apexTriggerCode)))
+	result = ExtractApexOutline(tree.RootNode(), []byte(apexTriggerCode))
+	want := "// Keeps accounts in sync\ntrigger AccountTrigger on Account (before insert, after update) { ... } // line 2\n"
+	if result != want {
+		t.Errorf("Expected %q, got %q", want, result)
+	}
+}
+
+func TestApexSymbols(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(java.Language()), MaskApex([]byte(apexCode)))
+	symbols := ExtractApexSymbols(tree.RootNode(), []byte(apexCode))
+	if len(symbols) != 1 || symbols[0].Name != "AccountService" {
+		t.Fatalf("Expected the AccountService class, got %+v", symbols)
+	}
+
+	service := symbols[0]
+	var members []string
+	for _, member := range service.Children {
+		members = append(members, member.Type+" "+member.Name)
+	}
+	want := "field TYPES, property name, property count, constructor AccountService, method getAccounts, method execute, method helper, interface Visitor, enum Status"
+	if got := strings.Join(members, ", "); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	getAccounts := service.Children[4]
+	if !getAccounts.IsPublic || service.Children[6].IsPublic {
+		t.Error("Expected global methods to be public and private ones not")
+	}
+	if getAccounts.Complexity != 3 {
+		t.Errorf("Expected getAccounts to have complexity 3, got %d", getAccounts.Complexity)
+	}
+
+	tree = parseSource(t, sitter.NewLanguage(java.Language()), MaskApex([]byte(apexTriggerCode)))
+	symbols = ExtractApexSymbols(tree.RootNode(), []byte(apexTriggerCode))
+	if len(symbols) != 1 || symbols[0].Type != "trigger" || symbols[0].Name != "AccountTrigger" || symbols[0].EndLine != 6 {
+		t.Errorf("Expected the AccountTrigger trigger ending on line 6, got %+v", symbols)
+	}
+}
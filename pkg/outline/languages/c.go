@@ -46,6 +46,12 @@ func processCNode(node *tree_sitter.Node, indentLevel int, content []byte, resul
 	case "template_declaration":
 		processCTemplateDeclaration(node, indentLevel, content, result)
 
+	case "linkage_specification":
+		processCLinkageSpecification(node, indentLevel, content, result)
+
+	case "preproc_if", "preproc_ifdef":
+		processCPreprocConditional(node, indentLevel, content, result)
+
 	default:
 		// Handle other node types by checking children
 		var i uint
@@ -57,7 +63,8 @@ func processCNode(node *tree_sitter.Node, indentLevel int, content []byte, resul
 }
 
 func processCDefine(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
-	defineText := getNodeText(node, content)
+	// The node includes the line break that terminates the directive
+	defineText := strings.TrimSpace(getNodeText(node, content))
 	lineNum := getNodeLineNumber(node)
 	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, defineText, lineNum))
 }
@@ -140,17 +147,28 @@ func processCStructUnionEnum(node *tree_sitter.Node, content []byte, result *str
 		}
 	}
 
-	lineNum := getNodeLineNumber(node)
+	// C++ scoped enums carry a class/struct keyword and an optional underlying type
+	if node.Kind() == "enum_specifier" {
+		if keyword := node.Child(1); keyword != nil && (keyword.Kind() == "class" || keyword.Kind() == "struct") {
+			structType += " " + keyword.Kind()
+		}
+	}
+
+	header := structType
 	if name != "" {
-		result.WriteString(fmt.Sprintf("%s%s %s { // line %d\n", indent, structType, name, lineNum))
-	} else {
-		result.WriteString(fmt.Sprintf("%s%s { // line %d\n", indent, structType, lineNum))
+		header += " " + name
+	}
+	if baseNode := node.ChildByFieldName("base"); baseNode != nil {
+		header += " : " + getNodeText(baseNode, content)
 	}
 
+	lineNum := getNodeLineNumber(node)
+	result.WriteString(fmt.Sprintf("%s%s { // line %d\n", indent, header, lineNum))
+
 	// Process fields/members
 	bodyNode := node.ChildByFieldName("body")
 	if bodyNode != nil {
-		processCStructBody(bodyNode, 1, content, result)
+		processCStructBody(bodyNode, len(indent)+1, content, result)
 	}
 
 	result.WriteString(fmt.Sprintf("%s}\n\n", indent))
@@ -162,19 +180,213 @@ func processCStructBody(bodyNode *tree_sitter.Node, indentLevel int, content []b
 	for i := uint(0); i < bodyNode.NamedChildCount(); i++ {
 		child := bodyNode.NamedChild(i)
 		if child.Kind() == "field_declaration" {
-			fieldText := getNodeText(child, content)
-			result.WriteString(fmt.Sprintf("%s%s\n", indent, strings.TrimSpace(fieldText)))
+			processCField(child, indentLevel, content, result)
 		} else if child.Kind() == "enumerator" {
-			enumText := getNodeText(child, content)
-			result.WriteString(fmt.Sprintf("%s%s\n", indent, strings.TrimSpace(enumText)))
+			processCEnumerator(child, content, result, indent)
 		}
 	}
 }
 
+func processCField(node *tree_sitter.Node, indentLevel int, content []byte, result *strings.Builder) {
+	indent := strings.Repeat("\t", indentLevel)
+
+	// Nested struct/union members are outlined as blocks of their own:
+	// struct { ... } header;
+	typeNode := node.ChildByFieldName("type")
+	if typeNode != nil && isCAggregateSpecifier(typeNode) && typeNode.ChildByFieldName("body") != nil {
+		result.WriteString(fmt.Sprintf("%s%s {\n", indent, cAggregateHeader(typeNode, content)))
+		processCStructBody(typeNode.ChildByFieldName("body"), indentLevel+1, content, result)
+		declarators := collapseWhitespace(string(content[typeNode.EndByte():node.EndByte()]))
+		result.WriteString(fmt.Sprintf("%s}%s\n", indent, cDeclaratorSuffix(declarators)))
+		return
+	}
+
+	// Plain fields, including bitfields such as "unsigned flags : 3;"
+	result.WriteString(fmt.Sprintf("%s%s\n", indent, normalizeCDeclaration(getNodeText(node, content))))
+}
+
+func processCEnumerator(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+
+	enumText := getNodeText(nameNode, content)
+	if valueNode := node.ChildByFieldName("value"); valueNode != nil {
+		enumText += " = " + strings.Join(strings.Fields(getNodeText(valueNode, content)), " ")
+	}
+	result.WriteString(fmt.Sprintf("%s%s\n", indent, enumText))
+}
+
 func processCTypedef(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
-	typedefText := getNodeText(node, content)
 	lineNum := getNodeLineNumber(node)
-	result.WriteString(fmt.Sprintf("%s%s // line %d\n\n", indent, strings.TrimSpace(typedefText), lineNum))
+
+	// typedef struct { ... } Name; keeps the member list as a block
+	typeNode := node.ChildByFieldName("type")
+	if typeNode != nil && isCAggregateSpecifier(typeNode) && typeNode.ChildByFieldName("body") != nil {
+		result.WriteString(fmt.Sprintf("%stypedef %s { // line %d\n", indent, cAggregateHeader(typeNode, content), lineNum))
+		processCStructBody(typeNode.ChildByFieldName("body"), len(indent)+1, content, result)
+		declarators := collapseWhitespace(string(content[typeNode.EndByte():node.EndByte()]))
+		result.WriteString(fmt.Sprintf("%s}%s\n\n", indent, cDeclaratorSuffix(declarators)))
+		return
+	}
+
+	// Everything else, function pointer typedefs included, fits on one line
+	typedefText := normalizeCDeclaration(getNodeText(node, content))
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n\n", indent, typedefText, lineNum))
+}
+
+func isCAggregateSpecifier(node *tree_sitter.Node) bool {
+	switch node.Kind() {
+	case "struct_specifier", "union_specifier", "enum_specifier":
+		return true
+	}
+	return false
+}
+
+// cAggregateHeader renders "struct Name" (or "union"/"enum") without the body
+func cAggregateHeader(node *tree_sitter.Node, content []byte) string {
+	header := strings.TrimSuffix(node.Kind(), "_specifier")
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		header += " " + getNodeText(nameNode, content)
+	}
+	return header
+}
+
+// cDeclaratorSuffix formats the declarators that follow a closing brace, e.g. " Person;"
+func cDeclaratorSuffix(declarators string) string {
+	if declarators == "" || declarators == ";" {
+		return declarators
+	}
+	return " " + declarators
+}
+
+// normalizeCDeclaration collapses a declaration onto a single line and tidies
+// the spacing inside parentheses, e.g. "typedef int ( *cb )( int );" becomes
+// "typedef int (*cb)(int);"
+func normalizeCDeclaration(text string) string {
+	text = collapseWhitespace(text)
+	text = strings.ReplaceAll(text, "( ", "(")
+	text = strings.ReplaceAll(text, " )", ")")
+	text = strings.ReplaceAll(text, " ;", ";")
+	text = strings.ReplaceAll(text, " ,", ",")
+	return text
+}
+
+func processCLinkageSpecification(node *tree_sitter.Node, indentLevel int, content []byte, result *strings.Builder) {
+	indent := strings.Repeat("\t", indentLevel)
+
+	linkage := "extern"
+	if valueNode := node.ChildByFieldName("value"); valueNode != nil {
+		linkage += " " + getNodeText(valueNode, content)
+	}
+
+	bodyNode := node.ChildByFieldName("body")
+	if bodyNode == nil {
+		return
+	}
+
+	// Single declaration form: extern "C" int f(void);. The linkage is only
+	// written when the declaration itself is outlined.
+	if bodyNode.Kind() != "declaration_list" {
+		var declaration strings.Builder
+		processCNode(bodyNode, 0, content, &declaration)
+		if declaration.Len() > 0 {
+			result.WriteString(fmt.Sprintf("%s%s %s", indent, linkage, declaration.String()))
+		}
+		return
+	}
+
+	lineNum := getNodeLineNumber(node)
+	result.WriteString(fmt.Sprintf("%s%s { // line %d\n", indent, linkage, lineNum))
+
+	for i := uint(0); i < bodyNode.NamedChildCount(); i++ {
+		child := bodyNode.NamedChild(i)
+		processCNode(child, indentLevel+1, content, result)
+	}
+
+	result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+}
+
+func processCPreprocConditional(node *tree_sitter.Node, indentLevel int, content []byte, result *strings.Builder) {
+	indent := strings.Repeat("\t", indentLevel)
+
+	// Include guards wrap the whole header and add no information, so their
+	// contents are outlined as if the guard wasn't there
+	if isCIncludeGuard(node, content) {
+		processCPreprocBranch(node, indentLevel, content, result)
+		return
+	}
+
+	lineNum := getNodeLineNumber(node)
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, cPreprocDirective(node, content), lineNum))
+	processCPreprocBranch(node, indentLevel+1, content, result)
+
+	// Walk the #elif/#else chain
+	alternative := node.ChildByFieldName("alternative")
+	for alternative != nil {
+		result.WriteString(fmt.Sprintf("%s%s\n", indent, cPreprocDirective(alternative, content)))
+		processCPreprocBranch(alternative, indentLevel+1, content, result)
+		alternative = alternative.ChildByFieldName("alternative")
+	}
+
+	result.WriteString(fmt.Sprintf("%s#endif\n\n", indent))
+}
+
+// processCPreprocBranch outlines the declarations guarded by a single conditional branch
+func processCPreprocBranch(node *tree_sitter.Node, indentLevel int, content []byte, result *strings.Builder) {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		// Skip the condition, name and alternative fields
+		if node.FieldNameForNamedChild(uint32(i)) != "" {
+			continue
+		}
+		processCNode(node.NamedChild(i), indentLevel, content, result)
+	}
+}
+
+// cPreprocDirective renders the opening line of a conditional branch, e.g. "#ifdef PLATFORM_X"
+func cPreprocDirective(node *tree_sitter.Node, content []byte) string {
+	directive := node.Child(0).Kind()
+
+	if conditionNode := node.ChildByFieldName("condition"); conditionNode != nil {
+		return directive + " " + collapseWhitespace(getNodeText(conditionNode, content))
+	}
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		return directive + " " + getNodeText(nameNode, content)
+	}
+	return directive
+}
+
+// isCIncludeGuard detects the #ifndef X / #define X ... #endif header guard idiom
+func isCIncludeGuard(node *tree_sitter.Node, content []byte) bool {
+	if node.Kind() != "preproc_ifdef" || node.Child(0).Kind() != "#ifndef" {
+		return false
+	}
+	if node.ChildByFieldName("alternative") != nil {
+		return false
+	}
+
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return false
+	}
+
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if node.FieldNameForNamedChild(uint32(i)) != "" {
+			continue
+		}
+		child := node.NamedChild(i)
+		if child.Kind() == "comment" {
+			continue
+		}
+		if child.Kind() != "preproc_def" {
+			return false
+		}
+		defNameNode := child.ChildByFieldName("name")
+		return defNameNode != nil && getNodeText(defNameNode, content) == getNodeText(nameNode, content)
+	}
+
+	return false
 }
 
 // C++ specific functions
@@ -290,6 +502,18 @@ func processCClassBody(bodyNode *tree_sitter.Node, indent string, content []byte
 				result.WriteString(fmt.Sprintf("%s\t%s // line %d\n", indent, strings.TrimSpace(declText), lineNum))
 			}
 
+		case "alias_declaration", "using_declaration", "friend_declaration":
+			// using Alias = ...; / using Base::member; / friend class X;
+			lineNum := getNodeLineNumber(child)
+			result.WriteString(fmt.Sprintf("%s\t%s // line %d\n", indent, collapseWhitespace(getNodeText(child, content)), lineNum))
+
+		case "field_declaration":
+			// Instance fields are omitted, but static members belong to the class interface
+			if hasCStorageClass(child, content, "static") {
+				lineNum := getNodeLineNumber(child)
+				result.WriteString(fmt.Sprintf("%s\t%s // line %d\n", indent, collapseWhitespace(getNodeText(child, content)), lineNum))
+			}
+
 		case "constructor_declaration", "destructor_declaration":
 			signature := extractFunctionSignature(child, content)
 			lineNum := getNodeLineNumber(child)
@@ -298,6 +522,17 @@ func processCClassBody(bodyNode *tree_sitter.Node, indent string, content []byte
 	}
 }
 
+// hasCStorageClass reports whether a declaration carries the given storage class specifier
+func hasCStorageClass(node *tree_sitter.Node, content []byte, storageClass string) bool {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		child := node.NamedChild(i)
+		if child.Kind() == "storage_class_specifier" && getNodeText(child, content) == storageClass {
+			return true
+		}
+	}
+	return false
+}
+
 func extractFunctionName(declaratorNode *tree_sitter.Node, content []byte) string {
 	// Handle different declarator types
 	switch declaratorNode.Kind() {
@@ -321,6 +556,14 @@ func extractFunctionSignature(node *tree_sitter.Node, content []byte) string {
 	// Try to build a clean function signature
 	var parts []string
 
+	// Keep attributes, which carry the CUDA execution space qualifiers of
+	// kernels
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if child := node.NamedChild(i); child.Kind() == "attribute_declaration" {
+			parts = append(parts, getNodeText(child, content))
+		}
+	}
+
 	// Get return type if present
 	typeNode := node.ChildByFieldName("type")
 	if typeNode != nil {
@@ -354,6 +597,7 @@ func extractFunctionSignature(node *tree_sitter.Node, content []byte) string {
 // ExtractCOutline extracts C outline directly from the code
 func ExtractCOutline(root *tree_sitter.Node, content []byte) string {
 	var result = new(strings.Builder)
+	result.Grow(outlineSizeHint(content))
 
 	// Function to process a node and its children
 	processCNode(root, 0, content, result)
@@ -399,9 +643,167 @@ func processCTemplateDeclaration(node *tree_sitter.Node, indentLevel int, conten
 // ExtractCppOutline extracts C++ outline directly from the code
 func ExtractCppOutline(root *tree_sitter.Node, content []byte) string {
 	var result = new(strings.Builder)
+	result.Grow(outlineSizeHint(content))
 
 	// Function to process a node and its children (same as C, but handles C++ constructs)
 	processCNode(root, 0, content, result)
 
 	return result.String()
 }
+
+var cSymbols = &symbolSpec{
+	rules: map[string]symbolRule{
+		"function_definition":  {kind: "function", name: cSymbolName, body: "body"},
+		"declaration":          {kindOf: cDeclarationKind, name: cSymbolName},
+		"field_declaration":    {kindOf: cDeclarationKind, name: cSymbolName},
+		"struct_specifier":     {kind: "struct", name: cAggregateName, body: "body", members: membersIn("body")},
+		"union_specifier":      {kind: "union", name: cAggregateName, body: "body", members: membersIn("body")},
+		"class_specifier":      {kind: "class", name: cAggregateName, body: "body", members: membersIn("body")},
+		"enum_specifier":       {kind: "enum", name: cAggregateName, body: "body", members: membersIn("body")},
+		"enumerator":           {kind: "enum_member"},
+		"type_definition":      {kindOf: cTypedefKind, name: cSymbolName, signatureEnd: cTypedefSignatureEnd, members: cTypedefMembers},
+		"alias_declaration":    {kind: "type"},
+		"namespace_definition": {kind: "namespace", body: "body", members: membersIn("body")},
+		"preproc_def":          {kind: "macro"},
+		"preproc_function_def": {kind: "macro"},
+	},
+	containers: map[string]bool{
+		"declaration":           true,
+		"field_declaration":     true,
+		"namespace_definition":  true,
+		"template_declaration":  true,
+		"linkage_specification": true,
+		"declaration_list":      true,
+		"preproc_if":            true,
+		"preproc_ifdef":         true,
+		"preproc_else":          true,
+		"preproc_elif":          true,
+		"preproc_elifdef":       true,
+	},
+	wrappers: map[string]bool{
+		"template_declaration": true,
+	},
+	public:     isCPublic,
+	complexity: cComplexity,
+}
+
+// cComplexity is shared by C and C++
+var cComplexity = &complexitySpec{
+	branches: kinds("if_statement", "for_statement", "for_range_loop", "while_statement", "do_statement",
+		"case_statement", "conditional_expression", "catch_clause"),
+	operators: kinds("&&", "||"),
+}
+
+// cSymbolName follows the declarator chain of a declaration down to the
+// identifier it declares
+func cSymbolName(node *tree_sitter.Node, content []byte) string {
+	declarator := node.ChildByFieldName("declarator")
+	for declarator != nil {
+		switch declarator.Kind() {
+		case "identifier", "field_identifier", "type_identifier", "qualified_identifier",
+			"destructor_name", "operator_name", "primitive_type":
+			return getNodeText(declarator, content)
+		case "reference_declarator", "parenthesized_declarator":
+			declarator = declarator.NamedChild(0)
+		default:
+			declarator = declarator.ChildByFieldName("declarator")
+		}
+	}
+	return ""
+}
+
+// cDeclarationKind tells function prototypes and methods apart from
+// variables and fields. Function pointers are variables.
+func cDeclarationKind(node *tree_sitter.Node, content []byte) string {
+	declarator := node.ChildByFieldName("declarator")
+	for declarator != nil {
+		switch declarator.Kind() {
+		case "function_declarator":
+			inner := declarator.ChildByFieldName("declarator")
+			if inner == nil || inner.Kind() != "parenthesized_declarator" {
+				return "function"
+			}
+			declarator = nil
+		case "init_declarator", "pointer_declarator", "reference_declarator":
+			next := declarator.ChildByFieldName("declarator")
+			if next == nil {
+				next = declarator.NamedChild(0)
+			}
+			declarator = next
+		default:
+			declarator = nil
+		}
+	}
+
+	if node.Kind() == "field_declaration" {
+		return "field"
+	}
+	return "variable"
+}
+
+// cAggregateName skips forward declarations and uses of a struct type,
+// which have no body of their own
+func cAggregateName(node *tree_sitter.Node, content []byte) string {
+	if node.ChildByFieldName("body") == nil {
+		return ""
+	}
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		return getNodeText(nameNode, content)
+	}
+	return ""
+}
+
+// cTypedefAggregate returns the struct, union or enum defined inline by a
+// typedef
+func cTypedefAggregate(node *tree_sitter.Node) *tree_sitter.Node {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode != nil && isCAggregateSpecifier(typeNode) && typeNode.ChildByFieldName("body") != nil {
+		return typeNode
+	}
+	return nil
+}
+
+func cTypedefKind(node *tree_sitter.Node, content []byte) string {
+	if aggregate := cTypedefAggregate(node); aggregate != nil {
+		return strings.TrimSuffix(aggregate.Kind(), "_specifier")
+	}
+	return "type"
+}
+
+func cTypedefSignatureEnd(node *tree_sitter.Node) uint {
+	if aggregate := cTypedefAggregate(node); aggregate != nil {
+		return aggregate.ChildByFieldName("body").StartByte()
+	}
+	return node.EndByte()
+}
+
+func cTypedefMembers(node *tree_sitter.Node) *tree_sitter.Node {
+	if aggregate := cTypedefAggregate(node); aggregate != nil {
+		return aggregate.ChildByFieldName("body")
+	}
+	return nil
+}
+
+// isCPublic treats static file-level declarations as private, and class
+// members by the access specifier in effect where they are declared
+func isCPublic(node *tree_sitter.Node, name string, content []byte) bool {
+	parent := node.Parent()
+	if parent == nil || parent.Kind() != "field_declaration_list" {
+		return !hasCStorageClass(node, content, "static")
+	}
+
+	for prev := node.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if prev.Kind() == "access_specifier" {
+			return getNodeText(prev, content) == "public"
+		}
+	}
+
+	// Without a specifier, class members are private and struct members public
+	owner := parent.Parent()
+	return owner == nil || owner.Kind() != "class_specifier"
+}
+
+// ExtractCSymbols extracts the declarations of a C or C++ file as symbols
+func ExtractCSymbols(root *tree_sitter.Node, content []byte) []Symbol {
+	return extractSymbols(root, content, cSymbols)
+}
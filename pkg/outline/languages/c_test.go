@@ -1,3 +1,5 @@
+//go:build !outline_nolang_c && !outline_nolang_cpp
+
 package languages
 
 import (
@@ -336,3 +338,246 @@ enum net_error {
 		t.Error("Expected enum net_error to be included")
 	}
 }
+
+func TestCppScopedEnums(t *testing.T) {
+	cppCode := `#include <cstdint>
+
+// Display colors
+enum class Color : uint8_t {
+    Red = 1,
+    Green = 1 << 1,
+    Blue
+};
+
+enum struct Mode { Fast, Safe };
+
+enum Legacy { A, B };
+`
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	if err := parser.SetLanguage(sitter.NewLanguage(cpp.Language())); err != nil {
+		t.Fatalf("Failed to set C++ language: %v", err)
+	}
+
+	tree := parser.Parse([]byte(cppCode), nil)
+	defer tree.Close()
+
+	result := ExtractCppOutline(tree.RootNode(), []byte(cppCode))
+
+	expected := []string{
+		"enum class Color : uint8_t {",
+		"\tRed = 1\n",
+		"\tGreen = 1 << 1\n",
+		"\tBlue\n",
+		"enum struct Mode {",
+		"enum Legacy {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestCppUsingFriendAndStaticMembers(t *testing.T) {
+	cppCode := `class Registry {
+public:
+    using Handle = std::shared_ptr<Entry>;
+    using Base::lookup;
+
+    static constexpr int kMaxEntries = 64;
+    static Registry& instance();
+
+    void add(Handle h);
+
+private:
+    friend class RegistryTest;
+    friend bool operator==(const Registry& a,
+                           const Registry& b);
+
+    int count;
+};
+`
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	if err := parser.SetLanguage(sitter.NewLanguage(cpp.Language())); err != nil {
+		t.Fatalf("Failed to set C++ language: %v", err)
+	}
+
+	tree := parser.Parse([]byte(cppCode), nil)
+	defer tree.Close()
+
+	result := ExtractCppOutline(tree.RootNode(), []byte(cppCode))
+
+	expected := []string{
+		"\tusing Handle = std::shared_ptr<Entry>; // line 3",
+		"\tusing Base::lookup; // line 4",
+		"\tstatic constexpr int kMaxEntries = 64; // line 6",
+		"\tstatic Registry& instance(); // line 7",
+		"\tfriend class RegistryTest; // line 12",
+		"\tfriend bool operator==(const Registry& a, const Registry& b); // line 13",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+
+	// Instance fields stay out of the outline
+	if strings.Contains(result, "int count") {
+		t.Error("Expected non-static field to be omitted")
+	}
+}
+
+func TestCppExternCBlocks(t *testing.T) {
+	cppCode := `extern "C" {
+// Initialize the library
+int lib_init(const char *config);
+void lib_shutdown(void);
+}
+
+extern "C" int lib_version(void);
+
+namespace wrapper {
+extern "C" {
+void wrapper_entry(void);
+}
+}
+`
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	if err := parser.SetLanguage(sitter.NewLanguage(cpp.Language())); err != nil {
+		t.Fatalf("Failed to set C++ language: %v", err)
+	}
+
+	tree := parser.Parse([]byte(cppCode), nil)
+	defer tree.Close()
+
+	result := ExtractCppOutline(tree.RootNode(), []byte(cppCode))
+
+	expected := []string{
+		"extern \"C\" { // line 1\n",
+		"\tint lib_init(const char *config); // line 3\n",
+		"\tvoid lib_shutdown(void); // line 4\n",
+		"extern \"C\" int lib_version(void); // line 7\n",
+		"\textern \"C\" { // line 10\n",
+		"\t\tvoid wrapper_entry(void); // line 11\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+
+	// A declaration that isn't outlined leaves no linkage behind
+	cppCode = "extern \"C\" int table[] = {1, 2};\nextern \"C\" int count;\n"
+	tree2 := parser.Parse([]byte(cppCode), nil)
+	defer tree2.Close()
+	result = ExtractCppOutline(tree2.RootNode(), []byte(cppCode))
+	if result != "extern \"C\" int count; // line 2\n" {
+		t.Errorf("Expected only the outlined declaration, got:\n%q", result)
+	}
+}
+
+func TestCPreprocessorConditionals(t *testing.T) {
+	cCode := `#ifndef PLATFORM_H
+#define PLATFORM_H
+
+#ifdef _WIN32
+int platform_init(HANDLE h);
+#elif defined(__APPLE__)
+int platform_init(CFRunLoopRef loop);
+#else
+int platform_init(int fd);
+#endif
+
+#if PLATFORM_VERSION >= 2
+void platform_extra(void);
+#endif
+
+#endif
+`
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	if err := parser.SetLanguage(sitter.NewLanguage(c.Language())); err != nil {
+		t.Fatalf("Failed to set C language: %v", err)
+	}
+
+	tree := parser.Parse([]byte(cCode), nil)
+	defer tree.Close()
+
+	result := ExtractCOutline(tree.RootNode(), []byte(cCode))
+
+	expected := `#define PLATFORM_H // line 2
+#ifdef _WIN32 // line 4
+	int platform_init(HANDLE h); // line 5
+#elif defined(__APPLE__)
+	int platform_init(CFRunLoopRef loop); // line 7
+#else
+	int platform_init(int fd); // line 9
+#endif
+
+#if PLATFORM_VERSION >= 2 // line 12
+	void platform_extra(void); // line 13
+#endif
+
+`
+
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
+
+func TestCFunctionPointerTypedefsAndBitfields(t *testing.T) {
+	cCode := `typedef void ( *event_cb )(
+    struct event *ev,
+    void *user_data );
+
+struct flags {
+    unsigned enabled : 1;
+    unsigned mode    : 3;
+    union {
+        int   as_int;
+        float as_float;
+    } value;
+};
+
+typedef struct {
+    int x;
+    int y;
+} Point;
+`
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	if err := parser.SetLanguage(sitter.NewLanguage(c.Language())); err != nil {
+		t.Fatalf("Failed to set C language: %v", err)
+	}
+
+	tree := parser.Parse([]byte(cCode), nil)
+	defer tree.Close()
+
+	result := ExtractCOutline(tree.RootNode(), []byte(cCode))
+
+	expected := []string{
+		"typedef void (*event_cb)(struct event *ev, void *user_data); // line 1\n",
+		"\tunsigned enabled : 1;\n",
+		"\tunsigned mode : 3;\n",
+		"\tunion {\n\t\tint as_int;\n\t\tfloat as_float;\n\t} value;\n",
+		"typedef struct { // line 14\n\tint x;\n\tint y;\n} Point;\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Clojure definitions are lists headed by a symbol such as defn, so symbols
+// are read from the forms at the top level of a file rather than through a
+// symbolSpec, which dispatches on node kinds.
+
+// clojureDefinitions maps the forms that define a var or type to symbol
+// kinds
+var clojureDefinitions = map[string]string{
+	"def":          "variable",
+	"defonce":      "variable",
+	"defn":         "function",
+	"defn-":        "function",
+	"defmacro":     "macro",
+	"defmulti":     "function",
+	"defmethod":    "method",
+	"deftest":      "function",
+	"defprotocol":  "protocol",
+	"definterface": "interface",
+	"defrecord":    "record",
+	"deftype":      "class",
+}
+
+// clojureBranches are the forms that add a path through a function. The
+// clauses of cond, condp and case are counted one by one.
+var clojureBranches = map[string]bool{
+	"if": true, "if-not": true, "if-let": true, "if-some": true,
+	"when": true, "when-not": true, "when-let": true, "when-some": true, "when-first": true,
+	"and": true, "or": true, "loop": true, "for": true, "doseq": true, "while": true, "catch": true,
+}
+
+// ExtractClojureSymbols extracts the namespace and the top-level
+// definitions of a Clojure file as symbols
+func ExtractClojureSymbols(root *sitter.Node, content []byte) []Symbol {
+	var symbols []Symbol
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		form := root.NamedChild(i)
+		if form.Kind() != "list_lit" {
+			continue
+		}
+		if symbol, ok := clojureSymbol(form, content); ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// clojureSymbol builds the symbol of a top-level form headed by ns or by
+// one of the def forms
+func clojureSymbol(form *sitter.Node, content []byte) (Symbol, bool) {
+	values := clojureValues(form)
+	if len(values) < 2 || values[0].Kind() != "sym_lit" || values[1].Kind() != "sym_lit" {
+		return Symbol{}, false
+	}
+	head := clojureName(values[0], content)
+	name := clojureName(values[1], content)
+
+	kind := clojureDefinitions[head]
+	if head == "ns" {
+		kind = "namespace"
+	}
+	if kind == "" || name == "" {
+		return Symbol{}, false
+	}
+
+	// A docstring follows the name, except in def where it is only one when
+	// a value follows it
+	rest := values[2:]
+	var doc Text
+	if len(rest) > 0 && rest[0].Kind() == "str_lit" && (head != "def" && head != "defonce" || len(rest) > 1) {
+		doc = NewText(clojureDocstring(getNodeText(rest[0], content)))
+		rest = rest[1:]
+	}
+	if head != "defmethod" && head != "ns" && len(rest) > 0 && rest[0].Kind() == "map_lit" {
+		// An attribute map, as in (defn f {:added "1.0"} [x])
+		rest = rest[1:]
+	}
+	if doc.IsZero() {
+		doc = clojureMetaDoc(values[1], content)
+	}
+	if doc.IsZero() {
+		doc = adjacentComments(form, content)
+		doc.form = semicolonCommentText
+	}
+
+	symbol := clojureFormSymbol(form, content, kind, name, NewText(clojureSignature(head, values[1], rest, content)))
+	symbol.Documentation = doc
+	symbol.IsPublic = head != "defn-" && head != "deftest" && !clojurePrivate(values[1], content)
+
+	switch head {
+	case "defn", "defn-", "defmacro", "defmethod", "deftest":
+		symbol.Complexity = 1 + clojureComplexity(form, content)
+		symbol.Lines = symbol.EndLine - symbol.Line + 1
+	case "defprotocol", "definterface", "defrecord", "deftype":
+		symbol.Children = clojureMethods(rest, content, symbol.IsPublic)
+	}
+	return symbol, true
+}
+
+// clojureMethods builds the method signatures of a protocol, or the method
+// implementations of a record or type
+func clojureMethods(forms []*sitter.Node, content []byte, public bool) []Symbol {
+	var methods []Symbol
+	for _, form := range forms {
+		if form.Kind() != "list_lit" {
+			continue
+		}
+		values := clojureValues(form)
+		if len(values) == 0 || values[0].Kind() != "sym_lit" {
+			continue
+		}
+		name := clojureName(values[0], content)
+
+		var params []string
+		var doc Text
+		for _, value := range values[1:] {
+			switch value.Kind() {
+			case "vec_lit":
+				params = append(params, collapseWhitespace(getNodeText(value, content)))
+			case "str_lit":
+				doc = NewText(clojureDocstring(getNodeText(value, content)))
+			}
+		}
+
+		method := clojureFormSymbol(form, content, "method", name, NewText("("+name+" "+strings.Join(params, " ")+")"))
+		method.Documentation = doc
+		method.IsPublic = public
+		if len(values) > 2 && values[len(values)-1].Kind() != "str_lit" && values[len(values)-1].Kind() != "vec_lit" {
+			// An implementation rather than a protocol signature
+			method.Complexity = 1 + clojureComplexity(form, content)
+			method.Lines = method.EndLine - method.Line + 1
+		}
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// clojureSignature renders a definition without its body: the parameter
+// vectors of a function or each of its arities, the fields of a record, and
+// the value of a var when it is a plain literal
+func clojureSignature(head string, name *sitter.Node, rest []*sitter.Node, content []byte) string {
+	parts := []string{"(" + head, collapseWhitespace(getNodeText(name, content))}
+	switch head {
+	case "ns", "defprotocol", "definterface":
+	case "def", "defonce":
+		if len(rest) > 0 && clojureIsLiteral(rest[0]) {
+			parts = append(parts, collapseWhitespace(getNodeText(rest[0], content)))
+		}
+	case "defmulti", "defmethod":
+		if len(rest) > 0 && rest[0].Kind() != "list_lit" {
+			parts = append(parts, collapseWhitespace(getNodeText(rest[0], content)))
+		}
+		if head == "defmethod" && len(rest) > 1 && rest[1].Kind() == "vec_lit" {
+			parts = append(parts, collapseWhitespace(getNodeText(rest[1], content)))
+		}
+	default:
+		for _, value := range rest {
+			if value.Kind() == "vec_lit" {
+				parts = append(parts, collapseWhitespace(getNodeText(value, content)))
+				break
+			}
+			if value.Kind() == "list_lit" {
+				// One arity of a function with several, as in ([x] body)
+				if arity := clojureValues(value); len(arity) > 0 && arity[0].Kind() == "vec_lit" {
+					parts = append(parts, "("+collapseWhitespace(getNodeText(arity[0], content))+")")
+				}
+			}
+		}
+	}
+	return strings.Join(parts, " ") + ")"
+}
+
+func clojureFormSymbol(form *sitter.Node, content []byte, kind, name string, signature Text) Symbol {
+	start, end := form.StartPosition(), form.EndPosition()
+	return Symbol{
+		Type:      kind,
+		Name:      name,
+		Signature: signature,
+		Line:      int(start.Row) + 1,
+		Column:    int(start.Column) + 1,
+		EndLine:   int(end.Row) + 1,
+		EndColumn: int(end.Column) + 1,
+		Source:    newText(content, form.StartByte(), form.EndByte(), rawText),
+	}
+}
+
+// clojureValues returns the forms of a list, leaving out comments,
+// metadata and forms discarded with #_
+func clojureValues(list *sitter.Node) []*sitter.Node {
+	var values []*sitter.Node
+	for i := uint(0); i < list.ChildCount(); i++ {
+		if list.FieldNameForChild(uint32(i)) == "value" {
+			values = append(values, list.Child(i))
+		}
+	}
+	return values
+}
+
+// clojureName returns the name of a symbol without its metadata
+func clojureName(symbol *sitter.Node, content []byte) string {
+	if name := symbol.ChildByFieldName("name"); name != nil {
+		return getNodeText(name, content)
+	}
+	return ""
+}
+
+// clojurePrivate reports whether a name carries ^:private or
+// ^{:private true} metadata
+func clojurePrivate(symbol *sitter.Node, content []byte) bool {
+	for i := uint(0); i < symbol.NamedChildCount(); i++ {
+		if meta := symbol.NamedChild(i); meta.Kind() == "meta_lit" {
+			text := collapseWhitespace(getNodeText(meta, content))
+			if text == "^:private" || strings.Contains(text, ":private true") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clojureMetaDoc returns the :doc of a name's metadata map, as in
+// (def ^{:doc "..."} x 1)
+func clojureMetaDoc(symbol *sitter.Node, content []byte) Text {
+	for i := uint(0); i < symbol.NamedChildCount(); i++ {
+		meta := symbol.NamedChild(i)
+		if meta.Kind() != "meta_lit" || meta.NamedChild(0) == nil || meta.NamedChild(0).Kind() != "map_lit" {
+			continue
+		}
+		entries := clojureValues(meta.NamedChild(0))
+		for j := 0; j+1 < len(entries); j += 2 {
+			if getNodeText(entries[j], content) == ":doc" && entries[j+1].Kind() == "str_lit" {
+				return NewText(clojureDocstring(getNodeText(entries[j+1], content)))
+			}
+		}
+	}
+	return Text{}
+}
+
+// clojureDocstring unquotes a docstring and removes the indentation of its
+// continuation lines
+func clojureDocstring(literal string) string {
+	text := strings.TrimSuffix(strings.TrimPrefix(literal, `"`), `"`)
+	text = strings.ReplaceAll(text, `\"`, `"`)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// clojureIsLiteral reports whether a value is short enough to show in the
+// signature of a var
+func clojureIsLiteral(value *sitter.Node) bool {
+	switch value.Kind() {
+	case "num_lit", "str_lit", "kwd_lit", "bool_lit", "nil_lit", "char_lit", "sym_lit":
+		return value.StartPosition().Row == value.EndPosition().Row
+	}
+	return false
+}
+
+// clojureComplexity counts the branching forms below a definition
+func clojureComplexity(node *sitter.Node, content []byte) int {
+	count := 0
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		child := node.NamedChild(i)
+		if child.Kind() == "list_lit" {
+			if values := clojureValues(child); len(values) > 0 && values[0].Kind() == "sym_lit" {
+				switch head := clojureName(values[0], content); {
+				case clojureBranches[head]:
+					count++
+				case head == "cond" || head == "case" || head == "condp":
+					// Each clause, as a chain of ifs would count them
+					count += (len(values) - 1) / 2
+				}
+			}
+		}
+		count += clojureComplexity(child, content)
+	}
+	return count
+}
+
+// ExtractClojureOutline renders the namespace declaration and top-level
+// definitions of a Clojure file, with bodies elided
+func ExtractClojureOutline(root *sitter.Node, content []byte) string {
+	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
+
+	for _, symbol := range ExtractClojureSymbols(root, content) {
+		if doc := symbol.Documentation.String(); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				writeStrings(&result, strings.TrimRight(";; "+line, " "), "\n")
+			}
+		}
+
+		signature := symbol.Signature.String()
+		if symbol.Type == "namespace" {
+			writeClojureNamespace(&result, symbol, clojureForm(root, symbol), content)
+			continue
+		}
+		if symbol.Complexity > 0 {
+			signature = strings.TrimSuffix(signature, ")") + " ...)"
+		}
+		if len(symbol.Children) > 0 {
+			signature = strings.TrimSuffix(signature, ")")
+		}
+		writeStrings(&result, signature, " ; line ")
+		writeUint(&result, uint(symbol.Line))
+		result.WriteString("\n")
+
+		for i, method := range symbol.Children {
+			signature := method.Signature.String()
+			if method.Complexity > 0 {
+				signature = strings.TrimSuffix(signature, ")") + " ...)"
+			}
+			if i == len(symbol.Children)-1 {
+				signature += ")"
+			}
+			writeStrings(&result, "  ", signature, " ; line ")
+			writeUint(&result, uint(method.Line))
+			result.WriteString("\n")
+		}
+	}
+	return result.String()
+}
+
+// writeClojureNamespace renders an ns form with its :require, :import and
+// other clauses collapsed onto one line each
+func writeClojureNamespace(result *strings.Builder, namespace Symbol, form *sitter.Node, content []byte) {
+	writeStrings(result, strings.TrimSuffix(namespace.Signature.String(), ")"), " ; line ")
+	writeUint(result, uint(namespace.Line))
+	result.WriteString("\n")
+
+	var clauses []string
+	for _, value := range clojureValues(form)[2:] {
+		if value.Kind() == "list_lit" {
+			clauses = append(clauses, collapseWhitespace(getNodeText(value, content)))
+		}
+	}
+	for i, clause := range clauses {
+		writeStrings(result, "  ", clause)
+		if i == len(clauses)-1 {
+			result.WriteString(")")
+		}
+		result.WriteString("\n")
+	}
+	if len(clauses) == 0 {
+		result.WriteString(")\n")
+	}
+	result.WriteString("\n")
+}
+
+// clojureForm returns the top-level form a symbol was read from
+func clojureForm(root *sitter.Node, symbol Symbol) *sitter.Node {
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		if form := root.NamedChild(i); int(form.StartByte()) == symbol.Source.Start() {
+			return form
+		}
+	}
+	return root
+}
@@ -0,0 +1,99 @@
+//go:build !outline_nolang_clojure
+
+package languages
+
+import (
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/internal/grammars/clojure"
+)
+
+const clojureCode = `(ns my.app.core
+  "Core functions"
+  (:require [clojure.string :as str])
+  (:import (java.util Date)))
+
+(def ^:private secret 42)
+
+(def version "The version" "1.0")
+
+(defn greet
+  "Greets someone
+  by name"
+  [name]
+  (if (str/blank? name)
+    "Hello"
+    (str "Hello, " name)))
+
+(defn- helper [x] (when x (inc x)))
+
+(defn multi
+  ([] (multi 1))
+  ([x] (* x 2)))
+
+;; Runs body unless test holds
+(defmacro unless [test & body]
+  ` + "`" + `(if ~test nil (do ~@body)))
+
+(defprotocol Shape
+  "A shape"
+  (area [this] "The area")
+  (perimeter [this]))
+
+(defrecord Circle [radius]
+  Shape
+  (area [this] (* 3.14 radius radius))
+  (perimeter [this] (* 2 3.14 radius)))
+`
+
+func TestClojureOutline(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(clojure.Language()), []byte(clojureCode))
+	result := ExtractClojureOutline(tree.RootNode(), []byte(clojureCode))
+
+	for _, want := range []string{
+		";; Core functions\n(ns my.app.core ; line 1\n  (:require [clojure.string :as str])\n  (:import (java.util Date)))\n",
+		"(def ^:private secret 42) ; line 6\n",
+		";; The version\n(def version \"1.0\") ; line 8\n",
+		";; Greets someone\n;; by name\n(defn greet [name] ...) ; line 10\n",
+		"(defn multi ([]) ([x]) ...) ; line 20\n",
+		";; Runs body unless test holds\n(defmacro unless [test & body] ...) ; line 25\n",
+		"(defprotocol Shape ; line 28\n  (area [this]) ; line 30\n  (perimeter [this])) ; line 31\n",
+		"(defrecord Circle [radius] ; line 33\n  (area [this] ...) ; line 35\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestClojureSymbols(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(clojure.Language()), []byte(clojureCode))
+	symbols := ExtractClojureSymbols(tree.RootNode(), []byte(clojureCode))
+
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Type+" "+symbol.Name)
+	}
+	want := "namespace my.app.core, variable secret, variable version, function greet, function helper, function multi, macro unless, protocol Shape, record Circle"
+	if got := strings.Join(names, ", "); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	if symbols[1].IsPublic || symbols[4].IsPublic || !symbols[3].IsPublic {
+		t.Error("Expected ^:private vars and defn- functions to be private")
+	}
+	if greet := symbols[3]; greet.Complexity != 2 || greet.Lines != 7 {
+		t.Errorf("Expected greet to have complexity 2 over 7 lines, got %d over %d", greet.Complexity, greet.Lines)
+	}
+
+	shape := symbols[7]
+	if len(shape.Children) != 2 || shape.Children[0].Documentation.String() != "The area" {
+		t.Errorf("Expected the documented methods of Shape, got %+v", shape.Children)
+	}
+	if circle := symbols[8]; len(circle.Children) != 2 || circle.Children[0].Complexity != 1 {
+		t.Errorf("Expected the implemented methods of Circle, got %+v", circle.Children)
+	}
+}
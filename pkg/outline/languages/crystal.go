@@ -0,0 +1,503 @@
+package languages
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Crystal has no tree-sitter grammar, so its declarations are found by
+// scanning the source line by line. The language is made of keyword ... end
+// blocks, and knowing which keywords open a block is enough to track the
+// nesting of the classes, modules and methods of a file.
+
+var (
+	crystalWordPattern     = regexp.MustCompile(`[A-Za-z_]\w*[?!]?`)
+	crystalMacroPattern    = regexp.MustCompile(`\{%.*?%\}|\{\{.*?\}\}`)
+	crystalHeredocPattern  = regexp.MustCompile(`<<-["']?(\w+)`)
+	crystalModifierPattern = regexp.MustCompile(`^(?:(?:private|protected|abstract)\s+)*`)
+	crystalTypePattern     = regexp.MustCompile(`^(class|struct|module|enum|lib|annotation|union)\s+([A-Z][\w:]*)`)
+	crystalDefPattern      = regexp.MustCompile(`^def\s+(?:self\.)?([A-Za-z_]\w*[?!=]?|\[\][?=]?|[-+*/%<>=!~^&|]+)`)
+	crystalMacroDefPattern = regexp.MustCompile(`^macro\s+(\w+[?!=]?)`)
+	crystalFunPattern      = regexp.MustCompile(`^fun\s+(\w+)`)
+	crystalPropertyPattern = regexp.MustCompile(`^(?:class_)?(?:getter|setter|property)[?!]?\s+(.+)$`)
+	crystalConstantPattern = regexp.MustCompile(`^([A-Z][A-Z0-9_]*)\s*=[^=~]`)
+	crystalAliasPattern    = regexp.MustCompile(`^(?:alias|type)\s+([A-Z]\w*)\s*=`)
+	crystalMemberPattern   = regexp.MustCompile(`^([A-Z]\w*)\s*(=.*)?$`)
+	crystalRequirePattern  = regexp.MustCompile(`^require\s+"`)
+)
+
+// crystalTypeKinds maps the keywords that declare a type to symbol kinds
+var crystalTypeKinds = map[string]string{
+	"class":      "class",
+	"struct":     "struct",
+	"module":     "module",
+	"enum":       "enum",
+	"lib":        "namespace",
+	"annotation": "annotation",
+	"union":      "struct",
+}
+
+// crystalContainers are the kinds of the symbols that hold members
+var crystalContainers = map[string]bool{
+	"class": true, "struct": true, "module": true, "enum": true, "namespace": true, "annotation": true,
+}
+
+// crystalBlocks are the keywords that open a block closed by end when they
+// start a statement
+var crystalBlocks = map[string]bool{
+	"class": true, "struct": true, "module": true, "enum": true, "lib": true, "annotation": true, "union": true,
+	"def": true, "macro": true, "fun": true,
+	"if": true, "unless": true, "while": true, "until": true, "case": true, "begin": true, "select": true,
+}
+
+// crystalExpressions are the blocks that can also be used as a value, as in
+// x = if ready then 1 else 2 end
+var crystalExpressions = map[string]bool{
+	"if": true, "unless": true, "while": true, "until": true, "case": true, "begin": true,
+}
+
+// crystalLine is one line of a Crystal file. code is the line with its
+// trailing comment cut off, the contents of its strings blanked and its
+// macro expressions removed, so that only keywords of the program itself
+// remain, at the same offsets as in the source.
+type crystalLine struct {
+	code    string
+	start   int
+	number  int
+	comment bool
+}
+
+// crystalLexer carries the state of a string literal or heredoc from one
+// line to the next
+type crystalLexer struct {
+	quote   byte
+	interp  int
+	heredoc string
+}
+
+// scan returns the code of a line, and whether the line is a comment on its
+// own
+func (l *crystalLexer) scan(line string) (string, bool) {
+	if l.heredoc != "" {
+		if strings.TrimSpace(line) == l.heredoc {
+			l.heredoc = ""
+		}
+		return "", false
+	}
+
+	code := []byte(line)
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if l.quote == 0 {
+			if c == '"' || c == '\'' {
+				l.quote = c
+			} else if c == '#' {
+				code = code[:i]
+				break
+			}
+			continue
+		}
+		switch {
+		case l.interp > 0:
+			if c == '{' {
+				l.interp++
+			} else if c == '}' {
+				l.interp--
+			}
+			code[i] = ' '
+		case c == '\\' && i+1 < len(code):
+			code[i], code[i+1] = ' ', ' '
+			i++
+		case c == '#' && l.quote == '"' && i+1 < len(code) && code[i+1] == '{':
+			code[i], code[i+1] = ' ', ' '
+			l.interp = 1
+			i++
+		case c == l.quote:
+			l.quote = 0
+		default:
+			code[i] = ' '
+		}
+	}
+
+	if match := crystalHeredocPattern.FindStringSubmatch(line[:len(code)]); match != nil {
+		l.heredoc = match[1]
+	}
+	text := crystalMacroPattern.ReplaceAllStringFunc(string(code), func(macro string) string {
+		return strings.Repeat(" ", len(macro))
+	})
+	return text, len(code) < len(line) && strings.TrimSpace(text) == "" && l.quote == 0
+}
+
+// crystalLines splits content into lines and scans each of them
+func crystalLines(content []byte) []crystalLine {
+	var lexer crystalLexer
+	var lines []crystalLine
+	start := 0
+	for number := 1; start <= len(content); number++ {
+		end := start
+		for end < len(content) && content[end] != '\n' {
+			end++
+		}
+		code, comment := lexer.scan(strings.TrimSuffix(string(content[start:end]), "\r"))
+		lines = append(lines, crystalLine{code: code, start: start, number: number, comment: comment})
+		start = end + 1
+	}
+	return lines
+}
+
+// crystalScope is a block opened by a keyword and not yet closed. symbol is
+// the declaration the block belongs to, if it is listed.
+type crystalScope struct {
+	symbol *crystalDecl
+}
+
+// crystalDecl is a declaration being collected, with its members. block is
+// set for declarations with a body closed by end.
+type crystalDecl struct {
+	Symbol
+	members []*crystalDecl
+	block   bool
+}
+
+// ExtractCrystalSymbols extracts the types, methods, macros, properties and
+// constants of a Crystal file as symbols
+func ExtractCrystalSymbols(content []byte) []Symbol {
+	decls, _ := scanCrystal(content)
+	return crystalSymbolTree(decls)
+}
+
+// scanCrystal collects the declarations of a Crystal file, along with its
+// top-level requires
+func scanCrystal(content []byte) ([]*crystalDecl, []string) {
+	lines := crystalLines(content)
+
+	var roots []*crystalDecl
+	var requires []string
+	var scopes []crystalScope
+	docStart, docEnd := -1, -1
+
+	for i, line := range lines {
+		if line.comment {
+			if docStart < 0 {
+				docStart = line.start + strings.Index(string(content[line.start:]), "#")
+			}
+			docEnd = line.start + len(strings.TrimRight(string(content[line.start:line.start+lineLength(content, line.start)]), " \t\r"))
+			continue
+		}
+
+		for _, statement := range crystalStatements(line.code) {
+			text := strings.TrimSpace(line.code[statement[0]:statement[1]])
+			if text == "" {
+				continue
+			}
+			column := statement[0] + len(line.code[statement[0]:statement[1]]) - len(strings.TrimLeft(line.code[statement[0]:statement[1]], " \t"))
+			start := line.start + column
+
+			var parent *crystalDecl
+			listed := len(scopes) == 0
+			if !listed {
+				parent = scopes[len(scopes)-1].symbol
+				listed = parent != nil && crystalContainers[parent.Type]
+			}
+
+			if len(scopes) == 0 && crystalRequirePattern.MatchString(text) {
+				requires = append(requires, collapseWhitespace(string(content[start:line.start+statement[1]])))
+			}
+
+			var decl *crystalDecl
+			opens := false
+			if listed {
+				decl, opens = crystalDeclaration(content, lines[i:], statement, column, parent)
+			}
+			if decl != nil {
+				decl.Line = line.number
+				decl.Column = column + 1
+				if docStart >= 0 && docEnd < start && strings.TrimSpace(string(content[docEnd:start])) == "" {
+					decl.Documentation = newText(content, uint(docStart), uint(docEnd), commentText)
+				}
+				if parent != nil {
+					parent.members = append(parent.members, decl)
+				} else {
+					roots = append(roots, decl)
+				}
+			}
+
+			crystalNesting(content, line, statement, opens, decl, &scopes)
+		}
+		docStart, docEnd = -1, -1
+	}
+
+	// Blocks left open run to the end of the file
+	for _, scope := range scopes {
+		if scope.symbol != nil {
+			scope.symbol.close(content, len(content), lines[len(lines)-1].number)
+		}
+	}
+
+	return roots, requires
+}
+
+// crystalStatements splits the code of a line at its semicolons
+func crystalStatements(code string) [][2]int {
+	var statements [][2]int
+	start := 0
+	for i := 0; i <= len(code); i++ {
+		if i == len(code) || code[i] == ';' {
+			statements = append(statements, [2]int{start, i})
+			start = i + 1
+		}
+	}
+	return statements
+}
+
+// crystalDeclaration recognizes the declaration a statement makes, if any,
+// and whether it opens a block. lines starts with the line of the statement,
+// so that a signature broken over several lines can be read in full.
+func crystalDeclaration(content []byte, lines []crystalLine, statement [2]int, column int, parent *crystalDecl) (*crystalDecl, bool) {
+	line := lines[0]
+	code := strings.TrimSpace(line.code[column:statement[1]])
+	modifiers := crystalModifierPattern.FindString(code)
+	rest := code[len(modifiers):]
+	private := strings.Contains(modifiers, "private") || strings.Contains(modifiers, "protected")
+	abstract := strings.Contains(modifiers, "abstract")
+	inLib := parent != nil && parent.Type == "namespace"
+
+	start := line.start + column
+	end := line.start + statement[1]
+	decl := &crystalDecl{}
+	decl.IsPublic = !private
+	opens := false
+
+	switch {
+	case crystalTypePattern.MatchString(rest):
+		match := crystalTypePattern.FindStringSubmatch(rest)
+		decl.Type, decl.Name = crystalTypeKinds[match[1]], match[2]
+		opens = true
+	case crystalDefPattern.MatchString(rest):
+		decl.Name = crystalDefPattern.FindStringSubmatch(rest)[1]
+		decl.Type = "function"
+		if parent != nil {
+			decl.Type = "method"
+			if decl.Name == "initialize" {
+				decl.Type = "constructor"
+			}
+		}
+		end = crystalSignatureEnd(lines, statement, end)
+		opens = !abstract
+	case crystalMacroDefPattern.MatchString(rest):
+		decl.Type, decl.Name = "macro", crystalMacroDefPattern.FindStringSubmatch(rest)[1]
+		end = crystalSignatureEnd(lines, statement, end)
+		opens = true
+	case crystalFunPattern.MatchString(rest):
+		decl.Type, decl.Name = "function", crystalFunPattern.FindStringSubmatch(rest)[1]
+		end = crystalSignatureEnd(lines, statement, end)
+		opens = !inLib
+	case parent != nil && crystalPropertyPattern.MatchString(rest):
+		decl.Type = "property"
+		decl.Name = strings.TrimLeft(crystalWordPattern.FindString(crystalPropertyPattern.FindStringSubmatch(rest)[1]), "@")
+	case crystalAliasPattern.MatchString(rest):
+		decl.Type, decl.Name = "type_alias", crystalAliasPattern.FindStringSubmatch(rest)[1]
+	case parent != nil && parent.Type == "enum" && crystalMemberPattern.MatchString(rest):
+		decl.Type, decl.Name = "enum_member", crystalMemberPattern.FindStringSubmatch(rest)[1]
+		decl.IsPublic = parent.IsPublic
+	case crystalConstantPattern.MatchString(rest):
+		decl.Type, decl.Name = "constant", crystalConstantPattern.FindStringSubmatch(rest)[1]
+	default:
+		return nil, false
+	}
+
+	decl.Signature = NewText(collapseWhitespace(strings.TrimSpace(crystalSource(content, lines, start, end))))
+	decl.Source = newText(content, uint(start), uint(end), rawText)
+	decl.EndLine = lineOf(lines, end)
+	decl.EndColumn = end - lines[decl.EndLine-line.number].start + 1
+	decl.block = opens
+	return decl, opens
+}
+
+// crystalSignatureEnd returns where the signature of a method starting in
+// statement ends, following its parameters onto the next lines while their
+// parentheses are open
+func crystalSignatureEnd(lines []crystalLine, statement [2]int, end int) int {
+	depth := strings.Count(lines[0].code[statement[0]:statement[1]], "(") - strings.Count(lines[0].code[statement[0]:statement[1]], ")")
+	for _, line := range lines[1:] {
+		if depth <= 0 {
+			break
+		}
+		depth += strings.Count(line.code, "(") - strings.Count(line.code, ")")
+		end = line.start + len(strings.TrimRight(line.code, " \t"))
+	}
+	return end
+}
+
+// crystalSource returns the source between start and end with the comments
+// of the lines it spans left out
+func crystalSource(content []byte, lines []crystalLine, start, end int) string {
+	var source strings.Builder
+	for _, line := range lines {
+		if line.start > end {
+			break
+		}
+		from, to := max(line.start, start), min(line.start+len(line.code), end)
+		if from < to {
+			source.Write(content[from:to])
+			source.WriteString(" ")
+		}
+	}
+	return source.String()
+}
+
+// crystalNesting updates the open blocks with the keywords of a statement.
+// opens tells whether the declaration the statement makes, if any, opens a
+// block of its own.
+func crystalNesting(content []byte, line crystalLine, statement [2]int, opens bool, decl *crystalDecl, scopes *[]crystalScope) {
+	code := line.code[:statement[1]]
+	first := true
+	for _, word := range crystalWordPattern.FindAllStringIndex(code, -1) {
+		if word[0] < statement[0] {
+			continue
+		}
+		keyword := code[word[0]:word[1]]
+		if word[0] > 0 && strings.ContainsRune(".:@$", rune(code[word[0]-1])) ||
+			word[1] < len(code) && code[word[1]] == ':' && (word[1]+1 == len(code) || code[word[1]+1] != ':') {
+			first = false
+			continue
+		}
+
+		switch {
+		case first && (keyword == "private" || keyword == "protected" || keyword == "abstract"):
+			continue
+		case first && crystalBlocks[keyword]:
+			// Abstract methods have no body
+			abstract := keyword == "def" && strings.HasPrefix(strings.TrimSpace(code[statement[0]:]), "abstract")
+			switch {
+			case decl != nil && opens:
+				*scopes = append(*scopes, crystalScope{symbol: decl})
+			case decl == nil && !abstract:
+				*scopes = append(*scopes, crystalScope{})
+			}
+		case keyword == "do" || crystalExpressions[keyword] && crystalValuePosition(code[statement[0]:word[0]]):
+			*scopes = append(*scopes, crystalScope{})
+		case keyword == "end" && len(*scopes) > 0:
+			scope := (*scopes)[len(*scopes)-1]
+			*scopes = (*scopes)[:len(*scopes)-1]
+			if scope.symbol != nil {
+				scope.symbol.close(content, line.start+word[1], line.number)
+			}
+		}
+		first = false
+	}
+}
+
+// crystalValuePosition reports whether a keyword following before is used as
+// a value, as after = or return, rather than as a modifier such as the if of
+// return x if y
+func crystalValuePosition(before string) bool {
+	before = strings.TrimRight(before, " \t")
+	if before == "" {
+		return false
+	}
+	if strings.ContainsRune("=(,[{|&!", rune(before[len(before)-1])) {
+		return true
+	}
+	word := crystalWordPattern.FindAllStringIndex(before, -1)
+	return len(word) > 0 && word[len(word)-1][1] == len(before) && before[word[len(word)-1][0]:] == "return"
+}
+
+// close ends a declaration at the end keyword of its block
+func (d *crystalDecl) close(content []byte, end, line int) {
+	d.Source = newText(content, uint(d.Source.Start()), uint(end), rawText)
+	d.EndLine = line
+	d.EndColumn = end - lineStart(content, end) + 1
+}
+
+// crystalSymbolTree turns collected declarations into symbols
+func crystalSymbolTree(decls []*crystalDecl) []Symbol {
+	symbols := make([]Symbol, 0, len(decls))
+	for _, decl := range decls {
+		symbol := decl.Symbol
+		symbol.Children = crystalSymbolTree(decl.members)
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// lineOf returns the number of the line that offset is on
+func lineOf(lines []crystalLine, offset int) int {
+	number := lines[0].number
+	for _, line := range lines {
+		if line.start > offset {
+			break
+		}
+		number = line.number
+	}
+	return number
+}
+
+// lineStart returns the offset of the start of the line offset is on
+func lineStart(content []byte, offset int) int {
+	return strings.LastIndexByte(string(content[:offset]), '\n') + 1
+}
+
+// lineLength returns the length of the line starting at offset, without its
+// line break
+func lineLength(content []byte, offset int) int {
+	if end := strings.IndexByte(string(content[offset:]), '\n'); end >= 0 {
+		return end
+	}
+	return len(content) - offset
+}
+
+// ExtractCrystalOutline renders the requires and declarations of a Crystal
+// file as pseudo-source, with method bodies elided
+func ExtractCrystalOutline(content []byte) string {
+	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
+
+	decls, requires := scanCrystal(content)
+	for _, require := range requires {
+		writeStrings(&result, require, "\n")
+	}
+	if len(requires) > 0 {
+		result.WriteString("\n")
+	}
+
+	writeCrystalDecls(&result, decls, 0)
+	return result.String()
+}
+
+func writeCrystalDecls(result *strings.Builder, decls []*crystalDecl, depth int) {
+	indent := spaceIndent(depth)
+	for _, decl := range decls {
+		isType := crystalContainers[decl.Type]
+		hasMembers := isType && len(decl.members) > 0
+
+		// Types with members are set apart from the declarations before them
+		if depth == 0 && hasMembers && result.Len() > 0 && !strings.HasSuffix(result.String(), "\n\n") {
+			result.WriteString("\n")
+		}
+		if doc := decl.Documentation.String(); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				writeStrings(result, strings.TrimRight(indent+"# "+line, " "), "\n")
+			}
+		}
+
+		writeStrings(result, indent, decl.Signature.String())
+		switch {
+		case isType && !hasMembers && decl.block:
+			result.WriteString("; end")
+		case !isType && decl.block:
+			result.WriteString(" ... end")
+		}
+		result.WriteString(" # line ")
+		writeUint(result, uint(decl.Line))
+		result.WriteString("\n")
+
+		if hasMembers {
+			writeCrystalDecls(result, decl.members, depth+1)
+			writeStrings(result, indent, "end\n")
+			if depth == 0 {
+				result.WriteString("\n")
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package languages
+
+import (
+	"strings"
+	"testing"
+)
+
+const crystalCode = `require "json"
+require "./helpers"
+
+# The largest count
+MAX_COUNT = 10
+
+# A greeter.
+# Says hello.
+class Greeter < Base
+  include Comparable(Greeter)
+
+  getter name : String
+  property count = 0, total = 0
+
+  def initialize(@name : String)
+  end
+
+  # Greets someone
+  def greet(other : String = "world #{name}") : String
+    if other.empty?
+      return "nothing" if name.empty?
+    end
+    [1, 2].each do |i|
+      puts i
+    end
+    result = case other
+             when "x" then "y"
+             else          "z"
+             end
+    "Hello, #{other}"
+  end
+
+  def self.create(name : String,
+                  count : Int32) : Greeter
+    new(name)
+  end
+
+  private def helper; end
+
+  abstract def run : Nil
+
+  macro define(name)
+    def {{name.id}}
+      {% if flag?(:debug) %}
+        puts "end"
+      {% end %}
+    end
+  end
+end
+
+module Shapes
+  abstract struct Shape
+    abstract def area : Float64
+  end
+
+  struct Point(T) < Shape
+    def area : Float64
+      0.0
+    end
+  end
+
+  enum Color
+    Red
+    Green = 2
+
+    def hot? : Bool
+      self == Red
+    end
+  end
+end
+
+lib LibC
+  fun strlen(s : UInt8*) : Int32
+  alias SizeT = UInt64
+end
+
+class Error < Exception; end
+
+def main(args : Array(String)) : Nil
+  text = <<-EOS
+    def fake
+    end
+    EOS
+  puts text
+end
+`
+
+func TestCrystalOutline(t *testing.T) {
+	result := ExtractCrystalOutline([]byte(crystalCode))
+
+	for _, want := range []string{
+		"require \"json\"\nrequire \"./helpers\"\n\n",
+		"# The largest count\nMAX_COUNT = 10 # line 5\n",
+		"# A greeter.\n# Says hello.\nclass Greeter < Base # line 9\n",
+		"  getter name : String # line 12\n",
+		"  def initialize(@name : String) ... end # line 15\n",
+		"  # Greets someone\n  def greet(other : String = \"world #{name}\") : String ... end # line 19\n",
+		"  def self.create(name : String, count : Int32) : Greeter ... end # line 33\n",
+		"  private def helper ... end # line 38\n",
+		"  abstract def run : Nil # line 40\n",
+		"  macro define(name) ... end # line 42\nend\n",
+		"  struct Point(T) < Shape # line 56\n    def area : Float64 ... end # line 57\n  end\n",
+		"    Red # line 63\n    Green = 2 # line 64\n",
+		"lib LibC # line 72\n  fun strlen(s : UInt8*) : Int32 # line 73\n",
+		"class Error < Exception; end # line 77\n",
+		"def main(args : Array(String)) : Nil ... end # line 79\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+
+	// Definitions inside macros and heredocs are not declarations of the file
+	if strings.Contains(result, "fake") || strings.Contains(result, "name.id") {
+		t.Errorf("Expected no declarations from macro bodies or heredocs, got:\n%s", result)
+	}
+}
+
+func TestCrystalSymbols(t *testing.T) {
+	symbols := ExtractCrystalSymbols([]byte(crystalCode))
+
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Type+" "+symbol.Name)
+	}
+	want := "constant MAX_COUNT, class Greeter, module Shapes, namespace LibC, class Error, function main"
+	if got := strings.Join(names, ", "); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	greeter := symbols[1]
+	if greeter.Documentation.String() != "A greeter.\nSays hello." {
+		t.Errorf("Expected the doc comment of Greeter, got %q", greeter.Documentation.String())
+	}
+	if greeter.EndLine != 49 {
+		t.Errorf("Expected Greeter to end on line 49, got %d", greeter.EndLine)
+	}
+	var members []string
+	for _, member := range greeter.Children {
+		members = append(members, member.Type+" "+member.Name)
+	}
+	want = "property name, property count, constructor initialize, method greet, method create, method helper, method run, macro define"
+	if got := strings.Join(members, ", "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if greet := greeter.Children[3]; greet.Line != 19 || greet.EndLine != 31 {
+		t.Errorf("Expected greet to span lines 19 to 31, got %d to %d", greet.Line, greet.EndLine)
+	}
+	if greeter.Children[5].IsPublic {
+		t.Error("Expected helper to be private")
+	}
+
+	var colors []string
+	for _, member := range symbols[2].Children[2].Children {
+		colors = append(colors, member.Type+" "+member.Name)
+	}
+	want = "enum_member Red, enum_member Green, method hot?"
+	if got := strings.Join(colors, ", "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,37 @@
+package languages
+
+import "regexp"
+
+// CUDA is C++ with execution space qualifiers and kernel launches, neither of
+// which the C++ grammar knows. The qualifiers are rewritten to attributes of
+// the same length, __global__ to [[global]], and launch configurations are
+// blanked, in a copy of the file the grammar reads. Offsets are kept, so the
+// tree of the copy is read against the original content and signatures keep
+// the CUDA qualifiers.
+
+var (
+	cudaQualifierPattern    = regexp.MustCompile(`\b__(global|device|host|forceinline|noinline|constant|shared|managed|grid_constant)__\b`)
+	cudaLaunchBoundsPattern = regexp.MustCompile(`\b__launch_bounds__\s*\([^()]*\)`)
+	cudaLaunchPattern       = regexp.MustCompile(`<<<[^;]*?>>>`)
+)
+
+// MaskCUDA builds the copy of content the C++ grammar reads. The C++
+// extractors read its tree against the original content, so kernels and
+// device functions keep their qualifiers in their signature.
+func MaskCUDA(content []byte) []byte {
+	masked := cudaQualifierPattern.ReplaceAll(content, []byte("[[$1]]"))
+	for _, pattern := range []*regexp.Regexp{cudaLaunchBoundsPattern, cudaLaunchPattern} {
+		masked = pattern.ReplaceAllFunc(masked, func(match []byte) []byte {
+			blank := make([]byte, len(match))
+			for i, c := range match {
+				if c == '\n' {
+					blank[i] = '\n'
+				} else {
+					blank[i] = ' '
+				}
+			}
+			return blank
+		})
+	}
+	return masked
+}
@@ -0,0 +1,75 @@
+//go:build !outline_nolang_cuda
+
+package languages
+
+import (
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+)
+
+const cudaCode = `#include <cuda_runtime.h>
+
+// Adds a to b element-wise
+__global__ void __launch_bounds__(256) add(const float* a, float* b, int n) {
+	int i = blockIdx.x * blockDim.x + threadIdx.x;
+	if (i < n) b[i] += a[i];
+}
+
+__device__ __forceinline__ float square(float x) { return x * x; }
+
+__host__ __device__ static inline int clamp(int v);
+
+template <typename T>
+__global__ void fill(T* out, T v) { out[threadIdx.x] = v; }
+
+__constant__ float weights[16];
+
+void launch(float* a, float* b, int n) {
+	add<<<(n + 255) / 256, 256>>>(a, b, n);
+}
+`
+
+func TestCudaOutline(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(cpp.Language()), MaskCUDA([]byte(cudaCode)))
+	result := ExtractCppOutline(tree.RootNode(), []byte(cudaCode))
+
+	for _, want := range []string{
+		"// Adds a to b element-wise\n__global__ void add(const float* a, float* b, int n) { //... } // line 4\n",
+		"__device__ __forceinline__ float square(float x) { //... } // line 9\n",
+		"__host__ __device__ static inline int clamp(int v); // line 11\n",
+		"template <typename T> // line 13\n__global__ void fill(T* out, T v) { //... } // line 14\n",
+		"__constant__ float weights[16]; // line 16\n",
+		"void launch(float* a, float* b, int n) { //... } // line 18\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestCudaSymbols(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(cpp.Language()), MaskCUDA([]byte(cudaCode)))
+	symbols := ExtractCSymbols(tree.RootNode(), []byte(cudaCode))
+
+	var got []string
+	for _, symbol := range symbols {
+		got = append(got, symbol.Type+" "+symbol.Name)
+	}
+	want := "function add, function square, function clamp, function fill, variable weights, function launch"
+	if strings.Join(got, ", ") != want {
+		t.Fatalf("Expected %s, got %s", want, strings.Join(got, ", "))
+	}
+
+	if sig := symbols[0].Signature.String(); sig != "__global__ void __launch_bounds__(256) add(const float* a, float* b, int n)" {
+		t.Errorf("Expected the kernel signature to keep its qualifiers, got %q", sig)
+	}
+	if symbols[2].IsPublic {
+		t.Error("Expected the static clamp to be private")
+	}
+	if symbols[0].Complexity != 2 {
+		t.Errorf("Expected add to have complexity 2, got %d", symbols[0].Complexity)
+	}
+}
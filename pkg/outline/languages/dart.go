@@ -0,0 +1,349 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// The Dart grammar keeps declarations flat: the modifiers, signature and
+// body of a member are consecutive siblings rather than one node, and
+// top-level variables are a run of keywords and types followed by the list
+// of names they declare. Symbols are therefore built from runs of siblings
+// instead of through a symbolSpec.
+
+var dartComplexity = &complexitySpec{
+	branches: kinds("if_statement", "if_element", "for_statement", "for_element", "while_statement",
+		"do_statement", "switch_statement_case", "switch_expression_case", "catch_clause",
+		"conditional_expression", "logical_and_operator", "logical_or_operator", "if_null_expression"),
+}
+
+// dartTypes maps the kinds of type declarations to symbol kinds
+var dartTypes = map[string]string{
+	"class_definition":      "class",
+	"mixin_declaration":     "mixin",
+	"extension_declaration": "extension",
+	"enum_declaration":      "enum",
+}
+
+// dartDirectives are the library-level directives shown at the top of an
+// outline
+var dartDirectives = map[string]bool{
+	"import_or_export":    true,
+	"part_directive":      true,
+	"part_of_directive":   true,
+	"library_name":        true,
+	"library_declaration": true,
+}
+
+// ExtractDartSymbols extracts the declarations of a Dart file as symbols
+func ExtractDartSymbols(root *sitter.Node, content []byte) []Symbol {
+	return dartMembers(root, content, "")
+}
+
+// dartMembers collects the declarations among the children of a program or
+// of a type body. owner is the kind of the enclosing type, if any.
+func dartMembers(parent *sitter.Node, content []byte, owner string) []Symbol {
+	var symbols []Symbol
+
+	// start is the first node of the declaration being read, including its
+	// annotations, and modifiers the first one after them
+	var start, modifiers *sitter.Node
+	reset := func() { start, modifiers = nil, nil }
+
+	count := parent.ChildCount()
+	for i := uint(0); i < count; i++ {
+		child := parent.Child(i)
+		kind := child.Kind()
+
+		switch {
+		case strings.Contains(kind, "comment"):
+			continue
+		case kind == ";" || kind == "," || kind == "{" || kind == "}" || dartDirectives[kind]:
+			reset()
+			continue
+		case kind == "annotation":
+			if start == nil {
+				start = child
+			}
+			continue
+		}
+		if start == nil {
+			start = child
+		}
+		if modifiers == nil {
+			modifiers = child
+		}
+
+		var body *sitter.Node
+		if next := child.NextNamedSibling(); next != nil && next.Kind() == "function_body" {
+			body = next
+		}
+
+		switch {
+		case dartTypes[kind] != "":
+			symbols = append(symbols, dartType(child, content, start))
+		case kind == "type_alias":
+			// The signature leaves out the closing semicolon
+			signature := child
+			if n := child.ChildCount(); n > 1 && child.Child(n-1).Kind() == ";" {
+				signature = child.Child(n - 2)
+			}
+			symbols = append(symbols, dartSymbol(child, content, "type", dartFirstIdentifier(child, content, "type_identifier"), start, modifiers, signature, nil))
+		case kind == "enum_constant":
+			symbols = append(symbols, dartSymbol(child, content, "enum_member", dartFieldName(child, content), start, modifiers, child, nil))
+		case kind == "method_signature" || kind == "declaration":
+			symbols = append(symbols, dartDeclaration(child, content, owner, start, body)...)
+		case dartIsSignature(kind):
+			symbols = append(symbols, dartFunction(child, content, owner, start, modifiers, child, body)...)
+		case kind == "static_final_declaration_list" || kind == "initialized_identifier_list":
+			symbols = append(symbols, dartVariables(child, content, owner, start, modifiers)...)
+		case kind == "function_body":
+			// Consumed with the signature before it
+		default:
+			// Keywords and types of a declaration still being read
+			continue
+		}
+		reset()
+	}
+	return symbols
+}
+
+// dartIsSignature reports whether a node kind is the signature of a
+// function, accessor, operator or constructor
+func dartIsSignature(kind string) bool {
+	switch kind {
+	case "function_signature", "getter_signature", "setter_signature", "operator_signature",
+		"constructor_signature", "constant_constructor_signature", "factory_constructor_signature",
+		"redirecting_factory_constructor_signature":
+		return true
+	}
+	return false
+}
+
+// dartDeclaration reads a member of a type body, which wraps a signature or
+// a field declaration together with its modifiers
+func dartDeclaration(node *sitter.Node, content []byte, owner string, start, body *sitter.Node) []Symbol {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		child := node.NamedChild(i)
+		switch kind := child.Kind(); {
+		case dartIsSignature(kind):
+			// Modifiers such as static belong to the declaration
+			return dartFunction(node, content, owner, start, node, child, body)
+		case kind == "static_final_declaration_list" || kind == "initialized_identifier_list":
+			return dartVariables(child, content, owner, start, node)
+		}
+	}
+	return nil
+}
+
+// dartFunction builds the symbol of a function, method, accessor or
+// constructor. The signature spans from modifiers to the end of signature,
+// leaving out the initializer list of constructors, and body, when present,
+// is the sibling holding its implementation.
+func dartFunction(node *sitter.Node, content []byte, owner string, start, modifiers, signature, body *sitter.Node) []Symbol {
+	kind := "function"
+	if owner != "" {
+		kind = "method"
+	}
+	name := dartFieldName(signature, content)
+	switch signature.Kind() {
+	case "constructor_signature", "constant_constructor_signature", "factory_constructor_signature",
+		"redirecting_factory_constructor_signature":
+		kind = "constructor"
+		name = dartConstructorName(signature, content)
+	case "operator_signature":
+		name = "operator " + dartFirstIdentifier(signature, content, "binary_operator")
+	}
+
+	symbol := dartSymbol(node, content, kind, name, start, modifiers, signature, body)
+	if body != nil {
+		addMetrics(&symbol, body, content, dartComplexity)
+	}
+	return []Symbol{symbol}
+}
+
+// dartVariables builds one symbol per name of a variable or field
+// declaration, each with the shared modifiers and type as its signature
+func dartVariables(list *sitter.Node, content []byte, owner string, start, modifiers *sitter.Node) []Symbol {
+	prefix := collapseWhitespace(string(content[modifiers.StartByte():list.StartByte()]))
+	kind := "variable"
+	if owner != "" {
+		kind = "field"
+	}
+	if strings.HasPrefix(prefix, "const ") || strings.Contains(prefix, " const ") {
+		kind = "constant"
+	}
+
+	var symbols []Symbol
+	for i := uint(0); i < list.NamedChildCount(); i++ {
+		declaration := list.NamedChild(i)
+		name := dartFirstIdentifier(declaration, content, "identifier")
+		if name == "" {
+			continue
+		}
+		symbol := dartSymbol(declaration, content, kind, name, start, declaration, declaration, nil)
+		symbol.Signature.prefix = prefix + " "
+		if list.NamedChildCount() == 1 {
+			// The only name owns the whole declaration
+			symbol.Signature = newText(content, modifiers.StartByte(), list.EndByte(), signatureText)
+			symbol.Line, symbol.Column = int(modifiers.StartPosition().Row)+1, int(modifiers.StartPosition().Column)+1
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// dartType builds the symbol of a class, mixin, extension or enum and its
+// members
+func dartType(node *sitter.Node, content []byte, start *sitter.Node) Symbol {
+	kind := dartTypes[node.Kind()]
+
+	var body *sitter.Node
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		child := node.NamedChild(i)
+		if strings.HasSuffix(child.Kind(), "_body") {
+			body = child
+		}
+	}
+
+	name := dartFieldName(node, content)
+	if name == "" {
+		name = dartFirstIdentifier(node, content, "identifier")
+	}
+	if name == "" && kind == "extension" {
+		// Unnamed extensions are known by the type they extend
+		if extended := node.ChildByFieldName("class"); extended != nil {
+			name = getNodeText(extended, content)
+		}
+	}
+
+	symbol := dartSymbol(node, content, kind, name, start, node, node, nil)
+	if body != nil {
+		symbol.Signature = newText(content, node.StartByte(), body.StartByte(), signatureText)
+		symbol.Children = dartMembers(body, content, kind)
+	}
+	return symbol
+}
+
+// dartSymbol assembles a symbol whose signature runs from modifiers to the
+// end of signature and whose source ends with body, or with node without
+// one. Doc comments are looked for above start, before any annotation.
+func dartSymbol(node *sitter.Node, content []byte, kind, name string, start, modifiers, signature, body *sitter.Node) Symbol {
+	end := node
+	if body != nil {
+		end = body
+	}
+	startPos, endPos := modifiers.StartPosition(), end.EndPosition()
+
+	return Symbol{
+		Type:          kind,
+		Name:          name,
+		Signature:     newText(content, modifiers.StartByte(), signature.EndByte(), signatureText),
+		Documentation: adjacentComments(start, content),
+		Line:          int(startPos.Row) + 1,
+		Column:        int(startPos.Column) + 1,
+		EndLine:       int(endPos.Row) + 1,
+		EndColumn:     int(endPos.Column) + 1,
+		IsPublic:      isDartPublic(name),
+		Source:        newText(content, start.StartByte(), end.EndByte(), rawText),
+	}
+}
+
+// isDartPublic treats names with a leading underscore, or constructors
+// whose own name has one, as private to their library
+func isDartPublic(name string) bool {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return !strings.HasPrefix(name, "_")
+}
+
+func dartFieldName(node *sitter.Node, content []byte) string {
+	if name := node.ChildByFieldName("name"); name != nil {
+		return getNodeText(name, content)
+	}
+	return ""
+}
+
+// dartFirstIdentifier returns the text of the first named child of the kind
+func dartFirstIdentifier(node *sitter.Node, content []byte, kind string) string {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if child := node.NamedChild(i); child.Kind() == kind {
+			return getNodeText(child, content)
+		}
+	}
+	return ""
+}
+
+// dartConstructorName joins the class and constructor names of a
+// constructor, such as Point.origin
+func dartConstructorName(node *sitter.Node, content []byte) string {
+	var parts []string
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if child := node.NamedChild(i); child.Kind() == "identifier" {
+			parts = append(parts, getNodeText(child, content))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// ExtractDartOutline renders the directives and declarations of a Dart file
+// as pseudo-source, with bodies elided
+func ExtractDartOutline(root *sitter.Node, content []byte) string {
+	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
+
+	directives := false
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		if child := root.NamedChild(i); dartDirectives[child.Kind()] {
+			writeStrings(&result, collapseWhitespace(getNodeText(child, content)), "\n")
+			directives = true
+		}
+	}
+	if directives {
+		result.WriteString("\n")
+	}
+
+	writeDartSymbols(&result, ExtractDartSymbols(root, content), 0)
+	return result.String()
+}
+
+func writeDartSymbols(result *strings.Builder, symbols []Symbol, depth int) {
+	indent := spaceIndent(depth)
+	for _, symbol := range symbols {
+		// Top-level types are set apart from the declarations before them
+		if depth == 0 && isTypeKind(symbol.Type) && result.Len() > 0 && !strings.HasSuffix(result.String(), "\n\n") {
+			result.WriteString("\n")
+		}
+		if doc := symbol.Documentation.String(); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				writeStrings(result, strings.TrimRight(indent+"/// "+line, " "), "\n")
+			}
+		}
+
+		signature := symbol.Signature.String()
+		switch symbol.Type {
+		case "class", "mixin", "extension", "enum":
+			writeStrings(result, indent, signature, " { // line ")
+			writeUint(result, uint(symbol.Line))
+			result.WriteString("\n")
+			writeDartSymbols(result, symbol.Children, depth+1)
+			writeStrings(result, indent, "}\n")
+			if depth == 0 {
+				result.WriteString("\n")
+			}
+			continue
+		case "enum_member":
+			writeStrings(result, indent, signature, ", // line ")
+		default:
+			if symbol.Complexity > 0 {
+				writeStrings(result, indent, signature, " { ... } // line ")
+			} else {
+				writeStrings(result, indent, signature, "; // line ")
+			}
+		}
+		writeUint(result, uint(symbol.Line))
+		result.WriteString("\n")
+	}
+}
@@ -0,0 +1,139 @@
+//go:build !outline_nolang_dart
+
+package languages
+
+import (
+	"strings"
+	"testing"
+
+	dart "github.com/UserNobody14/tree-sitter-dart/bindings/go"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+const dartCode = `import 'package:flutter/material.dart';
+
+/// The largest count
+const int maxCount = 10;
+
+/// A counter widget.
+class Counter extends StatefulWidget {
+  /// Creates a counter
+  const Counter({super.key, required this.start});
+  Counter.named(this.start) : super();
+  factory Counter.fromJson(Map<String, dynamic> json) => Counter(start: json['s']);
+
+  final int start;
+  int _count = 0;
+
+  @override
+  State<Counter> createState() => _CounterState();
+
+  int get count => _count;
+  static void reset() {}
+}
+
+mixin Logging on Object {
+  void log(String message) {}
+}
+
+extension StringX on String {
+  bool get isBlank => trim().isEmpty;
+}
+
+enum Color { red, green }
+
+void main() {
+  if (maxCount > 1 && true) {
+    runApp(const Counter(start: 1));
+  }
+}
+`
+
+func TestDartOutline(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(dart.Language()), []byte(dartCode))
+	result := ExtractDartOutline(tree.RootNode(), []byte(dartCode))
+
+	for _, want := range []string{
+		"import 'package:flutter/material.dart';\n",
+		"/// The largest count\nconst int maxCount = 10; // line 4\n",
+		"/// A counter widget.\nclass Counter extends StatefulWidget { // line 7\n",
+		"  /// Creates a counter\n  const Counter({super.key, required this.start}); // line 9\n",
+		"  Counter.named(this.start); // line 10\n",
+		"  factory Counter.fromJson(Map<String, dynamic> json) { ... } // line 11\n",
+		"  State<Counter> createState() { ... } // line 17\n",
+		"  static void reset() { ... } // line 20\n",
+		"mixin Logging on Object { // line 23\n",
+		"extension StringX on String { // line 27\n",
+		"  red, // line 31\n",
+		"void main() { ... } // line 33\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestDartSymbols(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(dart.Language()), []byte(dartCode))
+	symbols := ExtractDartSymbols(tree.RootNode(), []byte(dartCode))
+
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Type+" "+symbol.Name)
+	}
+	want := "constant maxCount, class Counter, mixin Logging, extension StringX, enum Color, function main"
+	if got := strings.Join(names, ", "); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	counter := symbols[1]
+	if counter.Documentation.String() != "A counter widget." {
+		t.Errorf("Expected the doc comment of Counter, got %q", counter.Documentation.String())
+	}
+	var members []string
+	for _, member := range counter.Children {
+		members = append(members, member.Type+" "+member.Name)
+	}
+	want = "constructor Counter, constructor Counter.named, constructor Counter.fromJson, field start, field _count, method createState, method count, method reset"
+	if got := strings.Join(members, ", "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if counter.Children[4].IsPublic {
+		t.Error("Expected _count to be private")
+	}
+	if doc := counter.Children[5].Documentation.String(); doc != "" {
+		t.Errorf("Expected no doc comment on createState, got %q", doc)
+	}
+
+	main := symbols[5]
+	if main.Complexity != 3 || main.Lines != 5 {
+		t.Errorf("Expected main to have complexity 3 over 5 lines, got %d over %d", main.Complexity, main.Lines)
+	}
+}
+
+func TestDartSwitchExpressionMetrics(t *testing.T) {
+	code := `String name(int x, int defaultValue) => switch (x) {
+  defaultValue => 'default',
+  1 => 'one',
+};
+
+String other(int x) => switch (x) {
+  1 => 'one',
+  _ => 'other',
+};
+`
+	tree := parseSource(t, sitter.NewLanguage(dart.Language()), []byte(code))
+	symbols := ExtractDartSymbols(tree.RootNode(), []byte(code))
+	if len(symbols) != 2 {
+		t.Fatalf("Expected 2 symbols, got %d", len(symbols))
+	}
+
+	// A case named like the default keyword still adds a path, while the
+	// _ wildcard does not
+	if symbols[0].Complexity != 3 {
+		t.Errorf("Expected name to have complexity 3, got %d", symbols[0].Complexity)
+	}
+	if symbols[1].Complexity != 2 {
+		t.Errorf("Expected other to have complexity 2, got %d", symbols[1].Complexity)
+	}
+}
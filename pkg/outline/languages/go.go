@@ -3,6 +3,7 @@ package languages
 import (
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/tree-sitter/go-tree-sitter"
 )
@@ -320,9 +321,129 @@ func processFunction(node *tree_sitter.Node, content []byte, result *strings.Bui
 // ExtractGoOutline extracts Go outline directly from the code
 func ExtractGoOutline(root *tree_sitter.Node, content []byte) string {
 	var result = new(strings.Builder)
+	result.Grow(outlineSizeHint(content))
 
 	// Function to process a node and its children
 	processNode(root, 0, content, result)
 
 	return result.String()
 }
+
+var goSymbols = &symbolSpec{
+	rules: map[string]symbolRule{
+		"function_declaration": {kind: "function", body: "body"},
+		"method_declaration":   {kind: "method", body: "body"},
+		"type_spec": {
+			kindOf:       goTypeKind,
+			signatureEnd: goTypeSignatureEnd,
+			members:      goTypeMembers,
+			prefix:       literal("type "),
+		},
+		"type_alias":        {kind: "type", prefix: literal("type ")},
+		"const_spec":        {kind: "constant", name: goSpecNames, prefix: literal("const ")},
+		"var_spec":          {kind: "variable", name: goSpecNames, prefix: literal("var ")},
+		"field_declaration": {kind: "field", name: goFieldName},
+		"method_elem":       {kind: "method"},
+	},
+	containers: map[string]bool{
+		"type_declaration":  true,
+		"const_declaration": true,
+		"var_declaration":   true,
+		"var_spec_list":     true,
+	},
+	wrappers: map[string]bool{
+		"type_declaration":  true,
+		"const_declaration": true,
+		"var_declaration":   true,
+	},
+	public: func(node *tree_sitter.Node, name string, content []byte) bool {
+		return isGoExported(name)
+	},
+	complexity: goComplexity,
+}
+
+var goComplexity = &complexitySpec{
+	branches:  kinds("if_statement", "for_statement", "expression_case", "type_case", "communication_case"),
+	operators: kinds("&&", "||"),
+}
+
+func goTypeKind(node *tree_sitter.Node, content []byte) string {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return "type"
+	}
+	switch typeNode.Kind() {
+	case "struct_type":
+		return "struct"
+	case "interface_type":
+		return "interface"
+	}
+	return "type"
+}
+
+// goTypeSignatureEnd stops struct and interface signatures after their
+// keyword, leaving the member list to the children
+func goTypeSignatureEnd(node *tree_sitter.Node) uint {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return node.EndByte()
+	}
+	switch typeNode.Kind() {
+	case "struct_type", "interface_type":
+		return typeNode.Child(0).EndByte()
+	}
+	return node.EndByte()
+}
+
+func goTypeMembers(node *tree_sitter.Node) *tree_sitter.Node {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return nil
+	}
+	switch typeNode.Kind() {
+	case "struct_type":
+		return typeNode.NamedChild(0)
+	case "interface_type":
+		return typeNode
+	}
+	return nil
+}
+
+// goSpecNames joins the names of a declaration that declares several at
+// once, such as "X, Y int"
+func goSpecNames(node *tree_sitter.Node, content []byte) string {
+	cursor := node.Walk()
+	defer cursor.Close()
+
+	var names []string
+	for _, nameNode := range node.ChildrenByFieldName("name", cursor) {
+		if nameNode.IsNamed() {
+			names = append(names, getNodeText(&nameNode, content))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// goFieldName names embedded fields after their type
+func goFieldName(node *tree_sitter.Node, content []byte) string {
+	if names := goSpecNames(node, content); names != "" {
+		return names
+	}
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		return strings.TrimPrefix(getNodeText(typeNode, content), "*")
+	}
+	return ""
+}
+
+// isGoExported reports whether a name starts with an upper-case letter
+func isGoExported(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+// ExtractGoSymbols extracts the declarations of a Go file as symbols
+func ExtractGoSymbols(root *tree_sitter.Node, content []byte) []Symbol {
+	return extractSymbols(root, content, goSymbols)
+}
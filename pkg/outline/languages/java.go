@@ -1,14 +1,13 @@
 package languages
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/tree-sitter/go-tree-sitter"
 )
 
 func processJavaNode(node *tree_sitter.Node, indentLevel int, content []byte, result *strings.Builder) {
-	indent := strings.Repeat("\t", indentLevel)
+	indent := tabIndent(indentLevel)
 
 	switch node.Kind() {
 	case "program":
@@ -46,12 +45,12 @@ func processJavaNode(node *tree_sitter.Node, indentLevel int, content []byte, re
 
 func processJavaPackage(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
 	packageText := getNodeText(node, content)
-	result.WriteString(fmt.Sprintf("%s%s\n\n", indent, packageText))
+	writeStrings(result, indent, packageText, "\n\n")
 }
 
 func processJavaImport(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
 	importText := getNodeText(node, content)
-	result.WriteString(fmt.Sprintf("%s%s\n", indent, importText))
+	writeStrings(result, indent, importText, "\n")
 }
 
 func processJavaClass(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string, indentLevel int) {
@@ -85,15 +84,12 @@ func processJavaClass(node *tree_sitter.Node, content []byte, result *strings.Bu
 
 	// Get documentation comment if present
 	doc := findDocComment(node, content, "java")
-	if doc != "" {
-		docLines := strings.Split(doc, "\n")
-		for _, line := range docLines {
-			result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-		}
-	}
+	writeDocComment(result, indent, doc)
 
 	lineNum := getNodeLineNumber(node)
-	result.WriteString(fmt.Sprintf("%s%sclass %s%s%s { // line %d\n", indent, modifierText, name, superclassText, interfacesText, lineNum))
+	writeStrings(result, indent, modifierText, "class ", name, superclassText, interfacesText, " { // line ")
+	writeUint(result, lineNum)
+	result.WriteString("\n")
 
 	// Process class body
 	bodyNode := node.ChildByFieldName("body")
@@ -104,7 +100,7 @@ func processJavaClass(node *tree_sitter.Node, content []byte, result *strings.Bu
 		}
 	}
 
-	result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+	writeStrings(result, indent, "}\n\n")
 }
 
 func processJavaInterface(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string, indentLevel int) {
@@ -134,15 +130,12 @@ func processJavaInterface(node *tree_sitter.Node, content []byte, result *string
 
 	// Get documentation comment if present
 	doc := findDocComment(node, content, "java")
-	if doc != "" {
-		docLines := strings.Split(doc, "\n")
-		for _, line := range docLines {
-			result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-		}
-	}
+	writeDocComment(result, indent, doc)
 
 	lineNum := getNodeLineNumber(node)
-	result.WriteString(fmt.Sprintf("%s%sinterface %s%s { // line %d\n", indent, modifierText, name, extendsText, lineNum))
+	writeStrings(result, indent, modifierText, "interface ", name, extendsText, " { // line ")
+	writeUint(result, lineNum)
+	result.WriteString("\n")
 
 	// Process interface body
 	bodyNode := node.ChildByFieldName("body")
@@ -153,7 +146,7 @@ func processJavaInterface(node *tree_sitter.Node, content []byte, result *string
 		}
 	}
 
-	result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+	writeStrings(result, indent, "}\n\n")
 }
 
 func processJavaEnum(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string, indentLevel int) {
@@ -173,15 +166,12 @@ func processJavaEnum(node *tree_sitter.Node, content []byte, result *strings.Bui
 
 	// Get documentation comment if present
 	doc := findDocComment(node, content, "java")
-	if doc != "" {
-		docLines := strings.Split(doc, "\n")
-		for _, line := range docLines {
-			result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-		}
-	}
+	writeDocComment(result, indent, doc)
 
 	lineNum := getNodeLineNumber(node)
-	result.WriteString(fmt.Sprintf("%s%senum %s { // line %d\n", indent, modifierText, name, lineNum))
+	writeStrings(result, indent, modifierText, "enum ", name, " { // line ")
+	writeUint(result, lineNum)
+	result.WriteString("\n")
 
 	// Process enum body - constants and methods
 	bodyNode := node.ChildByFieldName("body")
@@ -190,7 +180,7 @@ func processJavaEnum(node *tree_sitter.Node, content []byte, result *strings.Bui
 			child := bodyNode.NamedChild(i)
 			if child.Kind() == "enum_constant" {
 				constantName := getNodeText(child, content)
-				result.WriteString(fmt.Sprintf("%s\t%s,\n", indent, constantName))
+				writeStrings(result, indent, "\t", constantName, ",\n")
 			} else if child.Kind() == "enum_body_declarations" {
 				// Process methods and other declarations inside the enum
 				for j := uint(0); j < child.NamedChildCount(); j++ {
@@ -203,7 +193,7 @@ func processJavaEnum(node *tree_sitter.Node, content []byte, result *strings.Bui
 		}
 	}
 
-	result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+	writeStrings(result, indent, "}\n\n")
 }
 
 func processJavaMethod(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -247,15 +237,12 @@ func processJavaMethod(node *tree_sitter.Node, content []byte, result *strings.B
 
 	// Get documentation comment if present
 	doc := findDocComment(node, content, "java")
-	if doc != "" {
-		docLines := strings.Split(doc, "\n")
-		for _, line := range docLines {
-			result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-		}
-	}
+	writeDocComment(result, indent, doc)
 
 	lineNum := getNodeLineNumber(node)
-	result.WriteString(fmt.Sprintf("%s%s%s %s%s%s { //... } // line %d\n\n", indent, modifierText, typeText, name, parametersText, throwsText, lineNum))
+	writeStrings(result, indent, modifierText, typeText, " ", name, parametersText, throwsText, " { //... } // line ")
+	writeUint(result, lineNum)
+	result.WriteString("\n\n")
 }
 
 func processJavaConstructor(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -292,15 +279,12 @@ func processJavaConstructor(node *tree_sitter.Node, content []byte, result *stri
 
 	// Get documentation comment if present
 	doc := findDocComment(node, content, "java")
-	if doc != "" {
-		docLines := strings.Split(doc, "\n")
-		for _, line := range docLines {
-			result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-		}
-	}
+	writeDocComment(result, indent, doc)
 
 	lineNum := getNodeLineNumber(node)
-	result.WriteString(fmt.Sprintf("%s%s%s%s%s { //... } // line %d\n\n", indent, modifierText, name, parametersText, throwsText, lineNum))
+	writeStrings(result, indent, modifierText, name, parametersText, throwsText, " { //... } // line ")
+	writeUint(result, lineNum)
+	result.WriteString("\n\n")
 }
 
 func processJavaField(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -334,7 +318,9 @@ func processJavaField(node *tree_sitter.Node, content []byte, result *strings.Bu
 				}
 
 				lineNum := getNodeLineNumber(node)
-				result.WriteString(fmt.Sprintf("%s%s%s %s%s; // line %d\n", indent, modifierText, typeText, name, valueText, lineNum))
+				writeStrings(result, indent, modifierText, typeText, " ", name, valueText, "; // line ")
+				writeUint(result, lineNum)
+				result.WriteString("\n")
 			}
 		}
 	}
@@ -363,8 +349,87 @@ func getJavaModifiers(node *tree_sitter.Node, content []byte) []string {
 // ExtractJavaOutline extracts Java outline directly from the code
 func ExtractJavaOutline(root *tree_sitter.Node, content []byte) string {
 	var result = new(strings.Builder)
+	result.Grow(outlineSizeHint(content))
 
 	processJavaNode(root, 0, content, result)
 
 	return result.String()
 }
+
+var javaSymbols = &symbolSpec{
+	rules: map[string]symbolRule{
+		"class_declaration":           {kind: "class", body: "body", members: membersIn("body")},
+		"interface_declaration":       {kind: "interface", body: "body", members: membersIn("body")},
+		"enum_declaration":            {kind: "enum", body: "body", members: membersIn("body")},
+		"record_declaration":          {kind: "record", body: "body", members: membersIn("body")},
+		"annotation_type_declaration": {kind: "interface", body: "body", members: membersIn("body")},
+		"method_declaration":          {kind: "method", body: "body"},
+		"constructor_declaration":     {kind: "constructor", body: "body"},
+		"compact_constructor_declaration": {
+			kind: "constructor",
+			body: "body",
+		},
+		"annotation_type_element_declaration": {kind: "method"},
+		"field_declaration":                   {kind: "field", name: javaDeclaratorName, signatureEnd: javaDeclaratorEnd},
+		"constant_declaration":                {kind: "constant", name: javaDeclaratorName, signatureEnd: javaDeclaratorEnd},
+		"enum_constant":                       {kind: "enum_member", body: "body"},
+	},
+	containers: map[string]bool{
+		"enum_body_declarations": true,
+	},
+	public:     isJavaPublic,
+	complexity: javaComplexity,
+}
+
+var javaComplexity = &complexitySpec{
+	branches: kinds("if_statement", "for_statement", "enhanced_for_statement", "while_statement", "do_statement",
+		"catch_clause", "ternary_expression", "switch_label", "switch_rule"),
+	operators: kinds("&&", "||"),
+}
+
+// javaDeclaratorName names a field after its first declarator
+func javaDeclaratorName(node *tree_sitter.Node, content []byte) string {
+	declarator := node.ChildByFieldName("declarator")
+	if declarator == nil {
+		return ""
+	}
+	if nameNode := declarator.ChildByFieldName("name"); nameNode != nil {
+		return getNodeText(nameNode, content)
+	}
+	return ""
+}
+
+// javaDeclaratorEnd stops field signatures before their initializer
+func javaDeclaratorEnd(node *tree_sitter.Node) uint {
+	if declarator := node.ChildByFieldName("declarator"); declarator != nil {
+		if nameNode := declarator.ChildByFieldName("name"); nameNode != nil {
+			return nameNode.EndByte()
+		}
+	}
+	return node.EndByte()
+}
+
+// isJavaPublic treats public and protected members as part of the API, as
+// well as everything declared in an interface or annotation type
+func isJavaPublic(node *tree_sitter.Node, name string, content []byte) bool {
+	if node.Kind() == "enum_constant" {
+		return true
+	}
+	if parent := node.Parent(); parent != nil {
+		switch parent.Kind() {
+		case "interface_body", "annotation_type_body":
+			return true
+		}
+	}
+	for _, modifier := range getJavaModifiers(node, content) {
+		if modifier == "public" || modifier == "protected" {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractJavaSymbols extracts the declarations of a Java file as symbols
+func ExtractJavaSymbols(root *tree_sitter.Node, content []byte) []Symbol {
+	return extractSymbols(root, content, javaSymbols)
+}
@@ -1,6 +1,9 @@
+//go:build !outline_nolang_java
+
 package languages
 
 import (
+	"strconv"
 	"strings"
 	"testing"
 
@@ -344,3 +347,34 @@ public abstract class Animal {
 
 	t.Logf("Java abstract class outline result:\n%s", result)
 }
+
+func BenchmarkJavaOutlineLargeFile(b *testing.B) {
+	var code strings.Builder
+	code.WriteString("package com.example.bench;\n\nimport java.util.List;\n\n")
+	for i := 0; i < 200; i++ {
+		code.WriteString("/**\n * Service number " + strconv.Itoa(i) + "\n */\n")
+		code.WriteString("public class Service" + strconv.Itoa(i) + " extends Base implements Runnable {\n")
+		code.WriteString("    private final List<String> names = new ArrayList<>();\n")
+		for j := 0; j < 10; j++ {
+			code.WriteString("    /** Handles request " + strconv.Itoa(j) + " */\n")
+			code.WriteString("    public String handle" + strconv.Itoa(j) + "(String input, int count) throws Exception {\n        return input;\n    }\n")
+		}
+		code.WriteString("}\n\n")
+	}
+	content := []byte(code.String())
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+	if err := parser.SetLanguage(sitter.NewLanguage(java.Language())); err != nil {
+		b.Fatalf("Error setting language: %v", err)
+	}
+	tree := parser.Parse(content, nil)
+	defer tree.Close()
+	root := tree.RootNode()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ExtractJavaOutline(root, content)
+	}
+}
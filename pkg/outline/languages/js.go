@@ -9,6 +9,7 @@ import (
 // ExtractJSOutline extracts JavaScript outline directly from the code
 func ExtractJSOutline(root *sitter.Node, content []byte) string {
 	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
 
 	// Function to process a node and its children
 	var processNode func(node *sitter.Node, indentLevel int)
@@ -386,3 +387,153 @@ func ExtractJSOutline(root *sitter.Node, content []byte) string {
 	processNode(root, 0)
 	return result.String()
 }
+
+// jsSymbolRules are shared by the JavaScript and TypeScript symbol specs
+var jsSymbolRules = map[string]symbolRule{
+	"function_declaration":           {kind: "function", body: "body"},
+	"generator_function_declaration": {kind: "function", body: "body"},
+	"class_declaration":              {kind: "class", body: "body", members: membersIn("body")},
+	"method_definition":              {kindOf: jsMethodKind, body: "body"},
+	"field_definition":               {kind: "field", name: jsFieldName},
+	"variable_declarator": {
+		kindOf:       jsVariableKind,
+		name:         jsVariableName,
+		signatureEnd: jsVariableSignatureEnd,
+		prefix:       jsDeclarationKeyword,
+	},
+}
+
+var jsSymbols = &symbolSpec{
+	rules: jsSymbolRules,
+	containers: map[string]bool{
+		"export_statement":     true,
+		"lexical_declaration":  true,
+		"variable_declaration": true,
+	},
+	wrappers: map[string]bool{
+		"export_statement":     true,
+		"lexical_declaration":  true,
+		"variable_declaration": true,
+	},
+	public:     isJSPublic,
+	complexity: jsComplexity,
+}
+
+// jsComplexity is shared by JavaScript and TypeScript
+var jsComplexity = &complexitySpec{
+	branches: kinds("if_statement", "for_statement", "for_in_statement", "while_statement", "do_statement",
+		"switch_case", "catch_clause", "ternary_expression"),
+	operators: kinds("&&", "||", "??"),
+}
+
+func jsMethodKind(node *sitter.Node, content []byte) string {
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil && getNodeText(nameNode, content) == "constructor" {
+		return "constructor"
+	}
+	return "method"
+}
+
+func jsFieldName(node *sitter.Node, content []byte) string {
+	if property := node.ChildByFieldName("property"); property != nil {
+		return getNodeText(property, content)
+	}
+	return ""
+}
+
+// jsVariableName skips destructuring patterns, which don't declare a single
+// named symbol
+func jsVariableName(node *sitter.Node, content []byte) string {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil || nameNode.Kind() != "identifier" {
+		return ""
+	}
+	return getNodeText(nameNode, content)
+}
+
+func jsVariableKind(node *sitter.Node, content []byte) string {
+	if value := node.ChildByFieldName("value"); value != nil {
+		switch value.Kind() {
+		case "arrow_function", "function_expression", "function", "generator_function":
+			return "function"
+		case "class":
+			return "class"
+		}
+	}
+	if jsDeclarationKeyword(node, content) == "const " {
+		return "constant"
+	}
+	return "variable"
+}
+
+// jsVariableSignatureEnd keeps the parameters of function values and drops
+// every other initializer
+func jsVariableSignatureEnd(node *sitter.Node) uint {
+	value := node.ChildByFieldName("value")
+	if value == nil {
+		return node.EndByte()
+	}
+	switch value.Kind() {
+	case "arrow_function", "function_expression", "function", "generator_function":
+		if body := value.ChildByFieldName("body"); body != nil {
+			return body.StartByte()
+		}
+	}
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		return typeNode.EndByte()
+	}
+	return node.ChildByFieldName("name").EndByte()
+}
+
+// jsDeclarationKeyword returns the const, let or var keyword of the
+// declaration a declarator belongs to
+func jsDeclarationKeyword(node *sitter.Node, content []byte) string {
+	parent := node.Parent()
+	if parent == nil || parent.ChildCount() == 0 {
+		return ""
+	}
+	switch getNodeText(parent.Child(0), content) {
+	case "const":
+		return "const "
+	case "let":
+		return "let "
+	case "var":
+		return "var "
+	}
+	return ""
+}
+
+// isJSPublic treats exported top-level declarations and class members that
+// aren't private as public
+func isJSPublic(node *sitter.Node, name string, content []byte) bool {
+	if strings.HasPrefix(name, "#") {
+		return false
+	}
+
+	parent := node.Parent()
+	for parent != nil && (parent.Kind() == "lexical_declaration" || parent.Kind() == "variable_declaration") {
+		parent = parent.Parent()
+	}
+	if parent == nil {
+		return false
+	}
+
+	switch parent.Kind() {
+	case "export_statement":
+		return true
+	case "program", "statement_block", "ambient_declaration":
+		return false
+	}
+
+	for i := uint(0); i < node.ChildCount(); i++ {
+		child := node.Child(i)
+		if child.Kind() == "accessibility_modifier" && getNodeText(child, content) == "private" {
+			return false
+		}
+	}
+	return true
+}
+
+// ExtractJSSymbols extracts the declarations of a JavaScript file as symbols
+func ExtractJSSymbols(root *sitter.Node, content []byte) []Symbol {
+	return extractSymbols(root, content, jsSymbols)
+}
@@ -1,3 +1,5 @@
+//go:build !outline_nolang_javascript
+
 package languages
 
 import (
@@ -0,0 +1,78 @@
+package languages
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// byteRange is a half-open range of source offsets
+type byteRange struct {
+	start, end int
+}
+
+// CountLines classifies the lines of a file as code or comments. A line
+// with both counts as code, and blank lines count as neither. Docstrings
+// count as comments, which is why the symbols of the file are needed.
+func CountLines(root *sitter.Node, content []byte, symbols []Symbol) (code, comments int) {
+	ranges := commentRanges(root, nil)
+	ranges = docRanges(symbols, ranges)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	next := 0
+	for offset := 0; offset < len(content); {
+		end := len(content)
+		if i := bytes.IndexByte(content[offset:], '\n'); i >= 0 {
+			end = offset + i
+		}
+
+		hasCode, hasComment := false, false
+		for i := offset; i < end; i++ {
+			for next < len(ranges) && ranges[next].end <= i {
+				next++
+			}
+			switch {
+			case next < len(ranges) && ranges[next].start <= i:
+				hasComment = true
+			case content[i] != ' ' && content[i] != '\t' && content[i] != '\r':
+				hasCode = true
+			}
+		}
+
+		if hasCode {
+			code++
+		} else if hasComment {
+			comments++
+		}
+		offset = end + 1
+	}
+
+	return code, comments
+}
+
+// commentRanges collects the ranges of every comment node below node
+func commentRanges(node *sitter.Node, ranges []byteRange) []byteRange {
+	if strings.Contains(node.Kind(), "comment") {
+		return append(ranges, byteRange{int(node.StartByte()), int(node.EndByte())})
+	}
+	for i := uint(0); i < node.ChildCount(); i++ {
+		ranges = commentRanges(node.Child(i), ranges)
+	}
+	return ranges
+}
+
+// docRanges adds the documentation of symbols and their members, which
+// covers docstrings that are not comment nodes
+func docRanges(symbols []Symbol, ranges []byteRange) []byteRange {
+	for _, symbol := range symbols {
+		if doc := symbol.Documentation; !doc.IsZero() {
+			ranges = append(ranges, byteRange{doc.Start(), doc.End()})
+		}
+		ranges = docRanges(symbol.Children, ranges)
+	}
+	return ranges
+}
@@ -0,0 +1,360 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Lua has no classes: modules and "classes" are tables, and their functions
+// are declared one by one as function M.new() or M.helper = function(). The
+// symbols of a file are therefore collected by hand, nesting the functions
+// and fields assigned to a table declared in the file under that table.
+
+var luaComplexity = &complexitySpec{
+	// The and and or operators are anonymous nodes of a binary expression
+	// rather than its operator field
+	branches: kinds("if_statement", "elseif_statement", "while_statement", "repeat_statement", "for_statement",
+		"and", "or"),
+}
+
+// ExtractLuaSymbols extracts the functions, tables and variables of a Lua
+// file as symbols
+func ExtractLuaSymbols(root *sitter.Node, content []byte) []Symbol {
+	module := luaModule(root, content)
+
+	var symbols []Symbol
+	// tables holds the index in symbols of each table declared at the top
+	// level, which later assignments add members to
+	tables := map[string]int{}
+
+	add := func(symbol Symbol, owner string) {
+		if i, ok := tables[owner]; ok {
+			symbol.Name = strings.TrimPrefix(symbol.Name, owner)[1:]
+			symbol.IsPublic = symbols[i].IsPublic
+			if symbol.Type == "function" {
+				symbol.Type = "method"
+			} else if symbol.Type == "variable" || symbol.Type == "table" {
+				symbol.Type = "field"
+			}
+			symbols[i].Children = append(symbols[i].Children, symbol)
+			return
+		}
+		if symbol.Type == "table" && !strings.ContainsAny(symbol.Name, ".:") {
+			tables[symbol.Name] = len(symbols)
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		child := root.NamedChild(i)
+		switch child.Kind() {
+		case "function_declaration":
+			name := child.ChildByFieldName("name")
+			if name == nil {
+				continue
+			}
+			symbol := luaFunction(child, child, content, getNodeText(name, content))
+			symbol.IsPublic = !luaIsLocal(child)
+			add(symbol, luaOwner(name, content))
+		case "variable_declaration":
+			for _, assigned := range luaAssignments(child, content, module) {
+				add(assigned.symbol, "")
+			}
+		case "assignment_statement":
+			for _, assigned := range luaAssignments(child, content, module) {
+				add(assigned.symbol, assigned.owner)
+			}
+		}
+	}
+	return symbols
+}
+
+// luaAssigned is a symbol defined by an assignment, with the table it is
+// assigned into, if any
+type luaAssigned struct {
+	symbol Symbol
+	owner  string
+}
+
+// luaAssignments builds one symbol per name a local declaration or an
+// assignment defines. Requires are left out, as they are imports.
+func luaAssignments(statement *sitter.Node, content []byte, module string) []luaAssigned {
+	local := statement.Kind() == "variable_declaration"
+	assignment := statement
+	if local {
+		assignment = statement.NamedChild(0)
+		if assignment == nil {
+			return nil
+		}
+	}
+
+	var targets, values []*sitter.Node
+	var constant bool
+	for i := uint(0); i < assignment.NamedChildCount(); i++ {
+		list := assignment.NamedChild(i)
+		switch list.Kind() {
+		case "variable_list":
+			for j := uint(0); j < list.NamedChildCount(); j++ {
+				switch item := list.NamedChild(j); item.Kind() {
+				case "attribute":
+					constant = constant || getNodeText(item, content) == "<const>"
+				default:
+					targets = append(targets, item)
+				}
+			}
+		case "expression_list":
+			for j := uint(0); j < list.NamedChildCount(); j++ {
+				values = append(values, list.NamedChild(j))
+			}
+		}
+	}
+	if assignment.Kind() == "variable_list" {
+		// A declaration without a value, as in local x
+		targets = targets[:0]
+		for j := uint(0); j < assignment.NamedChildCount(); j++ {
+			if item := assignment.NamedChild(j); item.Kind() != "attribute" {
+				targets = append(targets, item)
+			}
+		}
+	}
+
+	var assigned []luaAssigned
+	for i, target := range targets {
+		var value *sitter.Node
+		if i < len(values) {
+			value = values[i]
+		}
+		if value != nil && luaIsRequire(value, content) {
+			continue
+		}
+
+		name := getNodeText(target, content)
+		var symbol Symbol
+		switch {
+		case value != nil && value.Kind() == "function_definition":
+			symbol = luaFunction(statement, value, content, name)
+		case value != nil && value.Kind() == "table_constructor":
+			symbol = luaSymbol(statement, content, "table", name, luaTableSignature(statement, value, content))
+			symbol.Children = luaFields(value, content)
+		case constant:
+			symbol = luaSymbol(statement, content, "constant", name, newText(content, statement.StartByte(), statement.EndByte(), signatureText))
+		default:
+			symbol = luaSymbol(statement, content, "variable", name, newText(content, statement.StartByte(), statement.EndByte(), signatureText))
+		}
+		symbol.IsPublic = !local || name == module
+		for j := range symbol.Children {
+			symbol.Children[j].IsPublic = symbol.IsPublic
+		}
+		assigned = append(assigned, luaAssigned{symbol, luaOwner(target, content)})
+	}
+	return assigned
+}
+
+// luaFields builds the symbols of the named fields of a table constructor
+func luaFields(table *sitter.Node, content []byte) []Symbol {
+	var fields []Symbol
+	for i := uint(0); i < table.NamedChildCount(); i++ {
+		field := table.NamedChild(i)
+		name, value := field.ChildByFieldName("name"), field.ChildByFieldName("value")
+		if field.Kind() != "field" || name == nil || name.Kind() != "identifier" {
+			continue
+		}
+		if value != nil && value.Kind() == "function_definition" {
+			symbol := luaFunction(field, value, content, getNodeText(name, content))
+			symbol.Type = "method"
+			fields = append(fields, symbol)
+			continue
+		}
+		fields = append(fields, luaSymbol(field, content, "field", getNodeText(name, content), newText(content, field.StartByte(), field.EndByte(), signatureText)))
+	}
+	return fields
+}
+
+// luaFunction builds the symbol of a function declared by node, whose
+// parameters and body are those of function. The signature stops after the
+// parameters.
+func luaFunction(node, function *sitter.Node, content []byte, name string) Symbol {
+	end := function.EndByte()
+	if parameters := function.ChildByFieldName("parameters"); parameters != nil {
+		end = parameters.EndByte()
+	}
+	symbol := luaSymbol(node, content, "function", name, newText(content, node.StartByte(), end, signatureText))
+	if body := function.ChildByFieldName("body"); body != nil {
+		addMetrics(&symbol, body, content, luaComplexity)
+	}
+	return symbol
+}
+
+// luaTableSignature shows a table declaration with the contents of the
+// table elided, as in local M = { ... }
+func luaTableSignature(statement, table *sitter.Node, content []byte) Text {
+	if table.NamedChildCount() == 0 {
+		return newText(content, statement.StartByte(), statement.EndByte(), signatureText)
+	}
+	return NewText(collapseWhitespace(string(content[statement.StartByte():table.StartByte()])) + " { ... }")
+}
+
+func luaSymbol(node *sitter.Node, content []byte, kind, name string, signature Text) Symbol {
+	start, end := node.StartPosition(), node.EndPosition()
+	doc := adjacentComments(node, content)
+	doc.form = dashCommentText
+	return Symbol{
+		Type:          kind,
+		Name:          name,
+		Signature:     signature,
+		Documentation: doc,
+		Line:          int(start.Row) + 1,
+		Column:        int(start.Column) + 1,
+		EndLine:       int(end.Row) + 1,
+		EndColumn:     int(end.Column) + 1,
+		Source:        newText(content, node.StartByte(), node.EndByte(), rawText),
+	}
+}
+
+// luaOwner returns the table a function or value is assigned into, as in M
+// for M.new and M:norm, or "" for a plain name
+func luaOwner(name *sitter.Node, content []byte) string {
+	if table := name.ChildByFieldName("table"); table != nil {
+		return getNodeText(table, content)
+	}
+	return ""
+}
+
+// luaIsLocal reports whether a declaration starts with local
+func luaIsLocal(node *sitter.Node) bool {
+	return node.ChildCount() > 0 && node.Child(0).Kind() == "local"
+}
+
+// luaIsRequire reports whether a value is a call to require
+func luaIsRequire(value *sitter.Node, content []byte) bool {
+	if value.Kind() != "function_call" {
+		return false
+	}
+	name := value.ChildByFieldName("name")
+	return name != nil && getNodeText(name, content) == "require"
+}
+
+// luaModule returns the name of the table a file returns at the end, which
+// is the module other files get from require
+func luaModule(root *sitter.Node, content []byte) string {
+	last := luaReturn(root)
+	if last == nil {
+		return ""
+	}
+	if values := last.NamedChild(0); values != nil && values.NamedChildCount() == 1 {
+		if value := values.NamedChild(0); value.Kind() == "identifier" {
+			return getNodeText(value, content)
+		}
+	}
+	return ""
+}
+
+// luaReturn returns the return statement that ends a file, if any
+func luaReturn(root *sitter.Node) *sitter.Node {
+	for i := root.NamedChildCount(); i > 0; i-- {
+		child := root.NamedChild(i - 1)
+		if child.Kind() == "return_statement" {
+			return child
+		}
+		if !strings.Contains(child.Kind(), "comment") {
+			return nil
+		}
+	}
+	return nil
+}
+
+// ExtractLuaOutline renders the requires, declarations and module return of
+// a Lua file as pseudo-source, with function bodies elided
+func ExtractLuaOutline(root *sitter.Node, content []byte) string {
+	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
+
+	requires := false
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		child := root.NamedChild(i)
+		if child.Kind() == "variable_declaration" && luaRequires(child, content) {
+			writeStrings(&result, collapseWhitespace(getNodeText(child, content)), "\n")
+			requires = true
+		}
+	}
+	if requires {
+		result.WriteString("\n")
+	}
+
+	writeLuaSymbols(&result, ExtractLuaSymbols(root, content), 0)
+
+	if last := luaReturn(root); last != nil {
+		if !strings.HasSuffix(result.String(), "\n\n") && result.Len() > 0 {
+			result.WriteString("\n")
+		}
+		statement := collapseWhitespace(getNodeText(last, content))
+		if values := last.NamedChild(0); values != nil && values.NamedChildCount() == 1 &&
+			values.NamedChild(0).Kind() == "table_constructor" && values.NamedChild(0).NamedChildCount() > 0 {
+			statement = "return { ... }"
+		}
+		writeStrings(&result, statement, " -- line ")
+		writeUint(&result, getNodeLineNumber(last))
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// luaRequires reports whether a local declaration only requires modules, as
+// in local json = require("json")
+func luaRequires(node *sitter.Node, content []byte) bool {
+	assignment := node.NamedChild(0)
+	if assignment == nil || assignment.Kind() != "assignment_statement" {
+		return false
+	}
+	found := false
+	for i := uint(0); i < assignment.NamedChildCount(); i++ {
+		list := assignment.NamedChild(i)
+		if list.Kind() != "expression_list" {
+			continue
+		}
+		for j := uint(0); j < list.NamedChildCount(); j++ {
+			if !luaIsRequire(list.NamedChild(j), content) {
+				return false
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+func writeLuaSymbols(result *strings.Builder, symbols []Symbol, depth int) {
+	indent := spaceIndent(depth)
+	for i, symbol := range symbols {
+		// Names declared together, as in local a, b = 1, 2, share one line
+		if i > 0 && symbol.Source.Start() == symbols[i-1].Source.Start() {
+			continue
+		}
+		isTable := symbol.Type == "table" && len(symbol.Children) > 0
+
+		// Tables with members are set apart from the declarations before them
+		if depth == 0 && isTable && result.Len() > 0 && !strings.HasSuffix(result.String(), "\n\n") {
+			result.WriteString("\n")
+		}
+		if doc := symbol.Documentation.String(); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				writeStrings(result, strings.TrimRight(indent+"--- "+line, " "), "\n")
+			}
+		}
+
+		writeStrings(result, indent, symbol.Signature.String())
+		if isFunctionKind(symbol.Type) {
+			result.WriteString(" ... end")
+		}
+		result.WriteString(" -- line ")
+		writeUint(result, uint(symbol.Line))
+		result.WriteString("\n")
+
+		if isTable {
+			writeLuaSymbols(result, symbol.Children, depth+1)
+			if depth == 0 {
+				result.WriteString("\n")
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+//go:build !outline_nolang_lua
+
+package languages
+
+import (
+	"strings"
+	"testing"
+
+	lua "github.com/tree-sitter-grammars/tree-sitter-lua/bindings/go"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+const luaCode = `--- Points in the plane
+local M = {}
+local json = require("json")
+
+local VERSION <const> = "1.0"
+count = 0
+
+--- Creates a point
+-- @param x number
+function M.new(x, y)
+  return setmetatable({ x = x, y = y }, M)
+end
+
+function M:norm()
+  if self.x > 0 and self.y > 0 then
+    return 1
+  end
+  return 0
+end
+
+M.helper = function(a) return a end
+
+local function clamp(v)
+  return v
+end
+
+local defaults = {
+  size = 10,
+  reset = function(self) end,
+}
+
+return M
+`
+
+func TestLuaOutline(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(lua.Language()), []byte(luaCode))
+	result := ExtractLuaOutline(tree.RootNode(), []byte(luaCode))
+
+	for _, want := range []string{
+		"local json = require(\"json\")\n\n",
+		"--- Points in the plane\nlocal M = {} -- line 2\n",
+		"  --- Creates a point\n  --- @param x number\n  function M.new(x, y) ... end -- line 10\n",
+		"  function M:norm() ... end -- line 14\n",
+		"  M.helper = function(a) ... end -- line 21\n",
+		"local VERSION <const> = \"1.0\" -- line 5\n",
+		"local function clamp(v) ... end -- line 23\n",
+		"local defaults = { ... } -- line 27\n  size = 10 -- line 28\n  reset = function(self) ... end -- line 29\n",
+		"return M -- line 32\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestLuaSymbols(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(lua.Language()), []byte(luaCode))
+	symbols := ExtractLuaSymbols(tree.RootNode(), []byte(luaCode))
+
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Type+" "+symbol.Name)
+	}
+	want := "table M, constant VERSION, variable count, function clamp, table defaults"
+	if got := strings.Join(names, ", "); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	module := symbols[0]
+	if !module.IsPublic {
+		t.Error("Expected the returned table to be public")
+	}
+	var members []string
+	for _, member := range module.Children {
+		members = append(members, member.Type+" "+member.Name)
+		if !member.IsPublic {
+			t.Errorf("Expected %s to be public with its module", member.Name)
+		}
+	}
+	want = "method new, method norm, method helper"
+	if got := strings.Join(members, ", "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if doc := module.Children[0].Documentation.String(); doc != "Creates a point\n@param x number" {
+		t.Errorf("Expected the doc comment of new, got %q", doc)
+	}
+	if norm := module.Children[1]; norm.Complexity != 3 {
+		t.Errorf("Expected norm to have complexity 3, got %d", norm.Complexity)
+	}
+
+	if symbols[1].IsPublic || !symbols[2].IsPublic || symbols[3].IsPublic {
+		t.Error("Expected locals to be private and globals public")
+	}
+}
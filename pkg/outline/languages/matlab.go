@@ -0,0 +1,530 @@
+package languages
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MATLAB has no tree-sitter grammar either, and is scanned line by line like
+// Crystal. Its blocks are closed by end too, with two twists: end also means
+// the last index inside parentheses and braces, and the functions of a file
+// either all end with end or none of them do.
+
+var (
+	matlabWordPattern     = regexp.MustCompile(`[A-Za-z_]\w*`)
+	matlabFunctionPattern = regexp.MustCompile(`^function\s+(?:(?:\[[^\]]*\]|\w+)\s*=\s*)?([\w.]+)`)
+	matlabClassPattern    = regexp.MustCompile(`^classdef\s*(?:\([^)]*\)\s*)?(\w+)`)
+	matlabDeclaredPattern = regexp.MustCompile(`^(?:(?:\[[^\]]*\]|\w+)\s*=\s*)?(\w+)`)
+	matlabPrivatePattern  = regexp.MustCompile(`(?i)\b(Get)?Access\s*=\s*'?(private|protected)\b`)
+)
+
+// matlabBlocks are the keywords that open a block closed by end wherever a
+// statement starts
+var matlabBlocks = map[string]bool{
+	"if": true, "for": true, "parfor": true, "while": true, "switch": true, "try": true, "spmd": true,
+}
+
+// matlabSections are the blocks of a classdef that hold its members, with the
+// kind of symbol each line of them declares
+var matlabSections = map[string]string{
+	"properties":  "property",
+	"methods":     "method",
+	"events":      "event",
+	"enumeration": "enum_member",
+}
+
+// matlabEnds are the words that close a block: end, and the Octave forms
+// that name the block they close
+var matlabEnds = map[string]bool{
+	"end": true, "endfunction": true, "endif": true, "endfor": true, "endparfor": true, "endwhile": true,
+	"endswitch": true, "end_try_catch": true, "endclassdef": true, "endproperties": true, "endmethods": true,
+	"endevents": true, "endenumeration": true, "endspmd": true,
+}
+
+// matlabLine is one line of a MATLAB file, with its comment cut off and the
+// contents of its strings blanked like a crystalLine. continues is set when
+// the line ends with ..., continuing the statement on the next line.
+type matlabLine struct {
+	code      string
+	start     int
+	number    int
+	comment   bool
+	continues bool
+}
+
+// matlabLexer carries the nesting of %{ ... %} block comments from one line
+// to the next
+type matlabLexer struct {
+	block int
+}
+
+// scan returns the code of a line, whether the line is a comment on its own,
+// and whether it continues on the next line
+func (l *matlabLexer) scan(line string) (string, bool, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "%{" {
+		l.block++
+		return "", true, false
+	}
+	if l.block > 0 {
+		if trimmed == "%}" {
+			l.block--
+		}
+		return "", true, false
+	}
+
+	code := []byte(line)
+	var quote byte
+	continues := false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if quote != 0 {
+			if c != quote {
+				code[i] = ' '
+			} else if i+1 < len(code) && code[i+1] == quote {
+				// A doubled quote stands for itself
+				code[i], code[i+1] = ' ', ' '
+				i++
+			} else {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '%':
+			code = code[:i]
+		case c == '.' && strings.HasPrefix(string(code[i:]), "..."):
+			code = code[:i]
+			continues = true
+		case c == '"' || c == '\'' && !matlabTranspose(code, i):
+			quote = c
+		}
+	}
+	return string(code), strings.HasPrefix(trimmed, "%"), continues
+}
+
+// matlabTranspose reports whether the quote at i is the transpose operator,
+// as in x', rather than the start of a character vector
+func matlabTranspose(code []byte, i int) bool {
+	if i == 0 {
+		return false
+	}
+	c := code[i-1]
+	return c == '_' || c == ')' || c == ']' || c == '}' || c == '.' || c == '\'' ||
+		c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// matlabLines splits content into lines and scans each of them
+func matlabLines(content []byte) []matlabLine {
+	var lexer matlabLexer
+	var lines []matlabLine
+	start := 0
+	for number := 1; start <= len(content); number++ {
+		end := start
+		for end < len(content) && content[end] != '\n' {
+			end++
+		}
+		code, comment, continues := lexer.scan(strings.TrimSuffix(string(content[start:end]), "\r"))
+		lines = append(lines, matlabLine{code: code, start: start, number: number, comment: comment, continues: continues})
+		start = end + 1
+	}
+	return lines
+}
+
+// matlabDecl is a declaration being collected, with its members. section
+// is the header of the classdef block a member is declared in, as in
+// properties (Access = private), and block is set for functions with a body.
+type matlabDecl struct {
+	Symbol
+	members []*matlabDecl
+	section string
+	block   bool
+}
+
+// matlabScope is a block opened by a keyword and not yet closed. decl is the
+// declaration the block belongs to, if it is listed, and for the sections
+// of a classdef, the class.
+type matlabScope struct {
+	keyword string
+	decl    *matlabDecl
+	section string
+}
+
+// percentCommentText is commentText for comments introduced by %, as in
+// MATLAB, including %{ ... %} blocks
+const percentCommentText = lastSharedText + 1
+
+// stripPercents removes the percent signs that start each line of a
+// comment, along with the lines that open and close a %{ ... %} block
+func stripPercents(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "%{" || line == "%}" {
+			continue
+		}
+		lines = append(lines, strings.TrimLeft(line, "%"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ExtractMATLABSymbols extracts the functions and classdef members of a
+// MATLAB file as symbols
+func ExtractMATLABSymbols(content []byte) []Symbol {
+	return matlabSymbolTree(scanMATLAB(content))
+}
+
+// scanMATLAB collects the declarations of a MATLAB file. Functions are first
+// assumed to be closed by end, and if that leaves blocks open at the end of
+// the file, each function is taken to run up to the next one instead.
+func scanMATLAB(content []byte) []*matlabDecl {
+	lines := matlabLines(content)
+	decls, balanced := scanMATLABLines(content, lines, true)
+	if !balanced {
+		decls, _ = scanMATLABLines(content, lines, false)
+	}
+	return decls
+}
+
+// scanMATLABLines collects the declarations of the lines of a file, and
+// reports whether every block was closed. functionEnds tells whether
+// functions are closed by end.
+func scanMATLABLines(content []byte, lines []matlabLine, functionEnds bool) ([]*matlabDecl, bool) {
+	var roots []*matlabDecl
+	var scopes []matlabScope
+	docStart, docEnd := -1, -1
+
+	// help is the last function or class declared, whose help text follows
+	// its declaration. local is set once the main function or class of the
+	// file is declared, or a statement shows the file is a script, making
+	// the functions after it local functions.
+	var help *matlabDecl
+	helpStart, helpEnd := -1, -1
+	local := false
+	depth := 0
+
+	closeHelp := func() {
+		if help != nil && helpStart >= 0 {
+			help.Documentation = newText(content, uint(helpStart), uint(helpEnd), percentCommentText)
+		}
+		help, helpStart, helpEnd = nil, -1, -1
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line.comment && depth == 0 {
+			text := string(content[line.start : line.start+lineLength(content, line.start)])
+			start := line.start + len(text) - len(strings.TrimLeft(text, " \t"))
+			end := line.start + len(strings.TrimRight(text, " \t\r"))
+			if help != nil {
+				if helpStart < 0 {
+					helpStart = start
+				}
+				helpEnd = end
+				continue
+			}
+			if docStart < 0 {
+				docStart = start
+			}
+			docEnd = end
+			continue
+		}
+		closeHelp()
+
+		// A statement continued with ... is read as one line
+		code, last := line.code, i
+		for lines[last].continues && last+1 < len(lines) {
+			last++
+			code += " " + lines[last].code
+		}
+
+		// Statements are separated by commas and semicolons outside of
+		// brackets, and may span several lines
+		statementStart, statementDepth := 0, depth
+		for j := 0; j <= len(code); j++ {
+			if j < len(code) {
+				switch code[j] {
+				case '(', '[', '{':
+					depth++
+					continue
+				case ')', ']', '}':
+					depth = max(depth-1, 0)
+					continue
+				case ',', ';':
+					if depth > 0 {
+						continue
+					}
+				default:
+					continue
+				}
+			}
+
+			segment := code[statementStart:j]
+			column := statementStart + len(segment) - len(strings.TrimLeft(segment, " \t"))
+			text := strings.TrimSpace(segment)
+			nested := statementDepth > 0
+			statementStart, statementDepth = j+1, depth
+			if text == "" || nested {
+				continue
+			}
+
+			decl, parent := matlabStatement(content, lines, i, column, text, functionEnds, &scopes, &local)
+			if decl == nil {
+				continue
+			}
+			if docStart >= 0 && docEnd < line.start+column && strings.TrimSpace(string(content[docEnd:line.start+column])) == "" {
+				decl.Documentation = newText(content, uint(docStart), uint(docEnd), percentCommentText)
+			}
+			if decl.block || decl.Type == "class" {
+				help = decl
+			}
+			if parent != nil {
+				parent.members = append(parent.members, decl)
+			} else {
+				roots = append(roots, decl)
+			}
+		}
+		i = last
+		docStart, docEnd = -1, -1
+	}
+	closeHelp()
+
+	// Blocks left open run to the end of the file, which is expected of
+	// functions without end only
+	balanced := true
+	end := len(strings.TrimRight(string(content), " \t\r\n"))
+	for _, scope := range scopes {
+		if functionEnds || scope.keyword != "function" {
+			balanced = false
+		}
+		if scope.decl != nil && matlabSections[scope.keyword] == "" {
+			scope.decl.close(content, end, lines)
+		}
+	}
+	return roots, balanced
+}
+
+// matlabStatement updates the open blocks with a statement starting at
+// column of the line at index i, and returns the declaration it makes, if
+// any, along with the class it is a member of
+func matlabStatement(content []byte, lines []matlabLine, i, column int, text string, functionEnds bool, scopes *[]matlabScope, local *bool) (*matlabDecl, *matlabDecl) {
+	keyword := matlabWordPattern.FindString(text)
+	if !strings.HasPrefix(text, keyword) {
+		keyword = ""
+	}
+
+	var top matlabScope
+	if len(*scopes) > 0 {
+		top = (*scopes)[len(*scopes)-1]
+	}
+	pop := func() {
+		*scopes = (*scopes)[:len(*scopes)-1]
+		top = matlabScope{}
+		if len(*scopes) > 0 {
+			top = (*scopes)[len(*scopes)-1]
+		}
+	}
+	push := func(scope matlabScope) {
+		*scopes = append(*scopes, scope)
+	}
+
+	switch {
+	case matlabEnds[keyword] && text == keyword:
+		if len(*scopes) > 0 {
+			if top.decl != nil && matlabSections[top.keyword] == "" {
+				top.decl.close(content, lines[i].start+column+len(keyword), lines)
+			}
+			pop()
+		}
+		return nil, nil
+
+	case keyword == "function":
+		// Without end, a function runs up to the next one
+		if !functionEnds && top.keyword == "function" {
+			if top.decl != nil {
+				top.decl.close(content, matlabLastCode(lines, i), lines)
+			}
+			pop()
+		}
+
+		match := matlabFunctionPattern.FindStringSubmatch(text)
+		inMethods := top.keyword == "methods" && top.decl != nil
+		if match == nil || len(*scopes) > 0 && !inMethods {
+			push(matlabScope{keyword: keyword})
+			return nil, nil
+		}
+
+		decl := matlabDeclaration(content, lines, i, column, text, match[1], "function")
+		decl.block = true
+		push(matlabScope{keyword: keyword, decl: decl})
+		if !inMethods {
+			decl.IsPublic = !*local
+			*local = true
+			return decl, nil
+		}
+		decl.Type = "method"
+		if decl.Name == top.decl.Name {
+			decl.Type = "constructor"
+		}
+		decl.section = top.section
+		decl.IsPublic = !matlabPrivatePattern.MatchString(top.section)
+		return decl, top.decl
+
+	case keyword == "classdef" && len(*scopes) == 0:
+		var decl *matlabDecl
+		if match := matlabClassPattern.FindStringSubmatch(text); match != nil {
+			decl = matlabDeclaration(content, lines, i, column, text, match[1], "class")
+		}
+		*local = true
+		push(matlabScope{keyword: keyword, decl: decl})
+		return decl, nil
+
+	case matlabSections[keyword] != "" && top.keyword == "classdef":
+		push(matlabScope{keyword: keyword, decl: top.decl, section: collapseWhitespace(text)})
+		return nil, nil
+
+	case keyword == "arguments" && top.keyword == "function" || matlabBlocks[keyword]:
+		push(matlabScope{keyword: keyword})
+		return nil, nil
+
+	case matlabSections[top.keyword] != "" && top.decl != nil:
+		// Properties, events and enumeration members are named first, and
+		// methods without a body are declared by their signature
+		match := matlabDeclaredPattern.FindStringSubmatch(text)
+		if match == nil || top.keyword != "methods" && !strings.HasPrefix(text, match[1]) {
+			return nil, nil
+		}
+		decl := matlabDeclaration(content, lines, i, column, text, match[1], matlabSections[top.keyword])
+		decl.section = top.section
+		decl.IsPublic = !matlabPrivatePattern.MatchString(top.section)
+		return decl, top.decl
+	}
+
+	if len(*scopes) == 0 {
+		*local = true
+	}
+	return nil, nil
+}
+
+// matlabDeclaration builds the declaration of name made by statement text,
+// found at column of the line at index i. A statement continued with ...
+// runs on over the next lines.
+func matlabDeclaration(content []byte, lines []matlabLine, i, column int, text, name, kind string) *matlabDecl {
+	start := lines[i].start + column
+	end := start + len(text)
+	signature := string(content[start:end])
+	if lines[i].continues {
+		signature = lines[i].code[column:]
+		for j := i; lines[j].continues && j+1 < len(lines); j++ {
+			code := strings.TrimRight(lines[j+1].code, " \t;,")
+			signature += " " + string(content[lines[j+1].start:lines[j+1].start+len(code)])
+			end = lines[j+1].start + len(code)
+		}
+	}
+
+	decl := &matlabDecl{}
+	decl.Type = kind
+	decl.Name = name
+	decl.IsPublic = true
+	decl.Line = lines[i].number
+	decl.Column = column + 1
+	decl.Signature = NewText(collapseWhitespace(strings.TrimSpace(signature)))
+	decl.Source = newText(content, uint(start), uint(end), rawText)
+	decl.close(content, end, lines)
+	return decl
+}
+
+// matlabLastCode returns the end of the last line of code before the line
+// at index i, where a function without end stops
+func matlabLastCode(lines []matlabLine, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if code := strings.TrimRight(lines[j].code, " \t"); code != "" && !lines[j].comment {
+			return lines[j].start + len(code)
+		}
+	}
+	return lines[i].start
+}
+
+// close ends a declaration at end
+func (d *matlabDecl) close(content []byte, end int, lines []matlabLine) {
+	d.Source = newText(content, uint(d.Source.Start()), uint(end), rawText)
+	d.EndLine = lineOfMATLAB(lines, end)
+	d.EndColumn = end - lineStart(content, end) + 1
+}
+
+// lineOfMATLAB returns the number of the line that offset is on
+func lineOfMATLAB(lines []matlabLine, offset int) int {
+	number := 1
+	for _, line := range lines {
+		if line.start > offset {
+			break
+		}
+		number = line.number
+	}
+	return number
+}
+
+// matlabSymbolTree turns collected declarations into symbols
+func matlabSymbolTree(decls []*matlabDecl) []Symbol {
+	symbols := make([]Symbol, 0, len(decls))
+	for _, decl := range decls {
+		symbol := decl.Symbol
+		symbol.Children = matlabSymbolTree(decl.members)
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// ExtractMATLABOutline renders the functions and classdef blocks of a MATLAB
+// file as pseudo-source, with function bodies elided and class members
+// grouped in the sections they are declared in
+func ExtractMATLABOutline(content []byte) string {
+	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
+	writeMATLABDecls(&result, scanMATLAB(content), 0)
+	return result.String()
+}
+
+func writeMATLABDecls(result *strings.Builder, decls []*matlabDecl, depth int) {
+	indent := spaceIndent(depth)
+	section := ""
+	for _, decl := range decls {
+		if decl.section != section {
+			if section != "" {
+				writeStrings(result, indent, "end\n")
+			}
+			section = decl.section
+			writeStrings(result, indent, section, "\n")
+		}
+		memberIndent := indent
+		if section != "" {
+			memberIndent += "  "
+		}
+
+		// Classes are set apart from the functions around them
+		if decl.Type == "class" && result.Len() > 0 && !strings.HasSuffix(result.String(), "\n\n") {
+			result.WriteString("\n")
+		}
+		if doc := decl.Documentation.String(); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				writeStrings(result, strings.TrimRight(memberIndent+"% "+line, " "), "\n")
+			}
+		}
+
+		writeStrings(result, memberIndent, decl.Signature.String())
+		if decl.block {
+			result.WriteString(" ... end")
+		}
+		result.WriteString(" % line ")
+		writeUint(result, uint(decl.Line))
+		result.WriteString("\n")
+
+		if decl.Type == "class" {
+			writeMATLABDecls(result, decl.members, depth+1)
+			writeStrings(result, indent, "end\n\n")
+		}
+	}
+	if section != "" {
+		writeStrings(result, indent, "end\n")
+	}
+}
@@ -0,0 +1,133 @@
+package languages
+
+import (
+	"strings"
+	"testing"
+)
+
+const matlabClassCode = `% Greeter says hello
+classdef (Sealed) Greeter < handle
+    % GREETER Greets people by name.
+    %   g = Greeter("Ada") creates a greeter.
+
+    properties
+        % The name to greet
+        Name string = "world" % trailing
+        Count (1,1) double = 0
+    end
+
+    properties (Access = private)
+        Cache = {'a', 'end'}
+    end
+
+    events
+        Greeted
+    end
+
+    methods
+        function obj = Greeter(name)
+            obj.Name = name;
+        end
+
+        function msg = greet(obj, other, ...
+                             punctuation)
+            % GREET Returns a greeting.
+            if isempty(other)
+                other = obj.Name(1:end);
+            end
+            msg = "Hello, " + other + punctuation';
+        end
+    end
+
+    methods (Static, Access = private)
+        r = compute(x)
+    end
+
+    enumeration
+        Small (1)
+        Large (2)
+    end
+end
+
+function out = helper(x)
+    out = x';
+end
+`
+
+const matlabFunctionCode = `function result = process(data)
+%PROCESS Doubles the data.
+for k = 1:numel(data)
+    data(k) = twice(data(k));
+end
+result = data;
+
+function y = twice(x)
+y = 2 * x;
+`
+
+func TestMATLABOutline(t *testing.T) {
+	result := ExtractMATLABOutline([]byte(matlabClassCode))
+
+	for _, want := range []string{
+		"% GREETER Greets people by name.\n% g = Greeter(\"Ada\") creates a greeter.\nclassdef (Sealed) Greeter < handle % line 2\n",
+		"  properties\n    % The name to greet\n    Name string = \"world\" % line 8\n    Count (1,1) double = 0 % line 9\n  end\n",
+		"  properties (Access = private)\n    Cache = {'a', 'end'} % line 13\n  end\n",
+		"  events\n    Greeted % line 17\n  end\n",
+		"    function obj = Greeter(name) ... end % line 21\n",
+		"    % GREET Returns a greeting.\n    function msg = greet(obj, other, punctuation) ... end % line 25\n",
+		"  methods (Static, Access = private)\n    r = compute(x) % line 36\n  end\n",
+		"    Small (1) % line 40\n",
+		"end\n\nfunction out = helper(x) ... end % line 45\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestMATLABSymbols(t *testing.T) {
+	symbols := ExtractMATLABSymbols([]byte(matlabClassCode))
+	if len(symbols) != 2 || symbols[0].Name != "Greeter" || symbols[1].Name != "helper" {
+		t.Fatalf("Expected Greeter and helper, got %+v", symbols)
+	}
+
+	greeter := symbols[0]
+	if greeter.EndLine != 43 {
+		t.Errorf("Expected Greeter to end on line 43, got %d", greeter.EndLine)
+	}
+	var members []string
+	for _, member := range greeter.Children {
+		members = append(members, member.Type+" "+member.Name)
+	}
+	want := "property Name, property Count, property Cache, event Greeted, constructor Greeter, method greet, method compute, enum_member Small, enum_member Large"
+	if got := strings.Join(members, ", "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if greeter.Children[2].IsPublic || greeter.Children[6].IsPublic {
+		t.Error("Expected the members of private sections to be private")
+	}
+	if greet := greeter.Children[5]; greet.Line != 25 || greet.EndLine != 32 {
+		t.Errorf("Expected greet to span lines 25 to 32, got %d to %d", greet.Line, greet.EndLine)
+	}
+	if symbols[1].IsPublic {
+		t.Error("Expected the local function helper to be private")
+	}
+}
+
+func TestMATLABFunctionsWithoutEnd(t *testing.T) {
+	symbols := ExtractMATLABSymbols([]byte(matlabFunctionCode))
+	if len(symbols) != 2 {
+		t.Fatalf("Expected two functions, got %+v", symbols)
+	}
+
+	process, twice := symbols[0], symbols[1]
+	if process.Documentation.String() != "PROCESS Doubles the data." {
+		t.Errorf("Expected the help text of process, got %q", process.Documentation.String())
+	}
+	if process.EndLine != 6 || twice.EndLine != 9 {
+		t.Errorf("Expected the functions to end on lines 6 and 9, got %d and %d", process.EndLine, twice.EndLine)
+	}
+	if !process.IsPublic || twice.IsPublic {
+		t.Error("Expected only the main function to be public")
+	}
+}
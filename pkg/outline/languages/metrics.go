@@ -0,0 +1,102 @@
+package languages
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// complexitySpec lists the syntax that adds a path through a function
+type complexitySpec struct {
+	// branches are node kinds that each add one path, such as if
+	// statements, loops, case clauses and conditional expressions
+	branches map[string]bool
+
+	// operators are the short-circuiting operators of binary expressions
+	operators map[string]bool
+}
+
+// kinds builds a set of node kinds or operators
+func kinds(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// isFunctionKind reports whether symbols of the kind have a body that
+// metrics are computed for
+func isFunctionKind(kind string) bool {
+	switch kind {
+	case "function", "method", "constructor":
+		return true
+	}
+	return false
+}
+
+// functionBody returns the body of a function declaration, or nil for a
+// bare signature. Functions assigned to variables keep their body in the
+// assigned value.
+func functionBody(node *sitter.Node, field string) *sitter.Node {
+	if field != "" {
+		return node.ChildByFieldName(field)
+	}
+	if value := node.ChildByFieldName("value"); value != nil {
+		return value.ChildByFieldName("body")
+	}
+	return nil
+}
+
+// addMetrics sets the cyclomatic complexity and body line count of a
+// function symbol
+func addMetrics(symbol *Symbol, body *sitter.Node, content []byte, spec *complexitySpec) {
+	symbol.Lines = int(body.EndPosition().Row-body.StartPosition().Row) + 1
+	symbol.Complexity = 1 + countBranches(body, content, spec)
+}
+
+// countBranches counts the decision points below node. Nested functions
+// and lambdas are counted as part of the function that contains them.
+func countBranches(node *sitter.Node, content []byte, spec *complexitySpec) int {
+	count := 0
+
+	cursor := node.Walk()
+	defer cursor.Close()
+
+	for {
+		current := cursor.Node()
+		if spec.branches[current.Kind()] && !isDefaultClause(current, content) {
+			count++
+		} else if operator := current.ChildByFieldName("operator"); operator != nil && spec.operators[getNodeText(operator, content)] {
+			count++
+		}
+
+		if cursor.GotoFirstChild() {
+			continue
+		}
+		for !cursor.GotoNextSibling() {
+			if !cursor.GotoParent() || cursor.Node().Id() == node.Id() {
+				return count
+			}
+		}
+	}
+}
+
+// isDefaultClause reports whether a case clause is the default one, which
+// does not add a path of its own. Default clauses start with the default
+// keyword, within the label of Java clauses, and the default of a Dart
+// switch expression is its _ wildcard.
+func isDefaultClause(node *sitter.Node, content []byte) bool {
+	first := node.Child(0)
+	if first != nil && first.Kind() == "switch_label" {
+		first = first.Child(0)
+	}
+	if first == nil {
+		return false
+	}
+	switch first.Kind() {
+	case "default", "default_keyword":
+		return true
+	case "constant_pattern":
+		return getNodeText(first, content) == "_"
+	}
+	return false
+}
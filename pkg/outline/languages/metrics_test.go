@@ -0,0 +1,88 @@
+//go:build !outline_nolang_go && !outline_nolang_python
+
+package languages
+
+import (
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	golang "github.com/tree-sitter/tree-sitter-go/bindings/go"
+	python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+)
+
+func TestGoFunctionMetrics(t *testing.T) {
+	code := []byte(`package main
+
+func Classify(n int) string {
+	if n < 0 || n > 100 {
+		return "out of range"
+	}
+	for i := 0; i < n; i++ {
+		switch {
+		case i%2 == 0:
+			continue
+		case i%3 == 0 && i > 10:
+			return "three"
+		default:
+			return "other"
+		}
+	}
+	return "none"
+}
+
+func Simple() {}
+
+type T struct{}
+`)
+
+	tree := parseSource(t, sitter.NewLanguage(golang.Language()), code)
+	symbols := ExtractGoSymbols(tree.RootNode(), code)
+	if len(symbols) != 3 {
+		t.Fatalf("Expected 3 symbols, got %d", len(symbols))
+	}
+
+	// if, ||, for, two cases and && add six paths to the function
+	classify := symbols[0]
+	if classify.Complexity != 7 {
+		t.Errorf("Expected Classify to have complexity 7, got %d", classify.Complexity)
+	}
+	if classify.Lines != 16 {
+		t.Errorf("Expected Classify to span 16 lines, got %d", classify.Lines)
+	}
+
+	if simple := symbols[1]; simple.Complexity != 1 || simple.Lines != 1 {
+		t.Errorf("Expected Simple to have complexity 1 over 1 line, got %d over %d", simple.Complexity, simple.Lines)
+	}
+	if typ := symbols[2]; typ.Complexity != 0 || typ.Lines != 0 {
+		t.Errorf("Expected no metrics for a type, got %+v", typ)
+	}
+}
+
+func TestPythonMethodMetrics(t *testing.T) {
+	code := []byte(`class Parser:
+    def parse(self, tokens):
+        result = [t for t in tokens if t]
+        while tokens and not self.done:
+            try:
+                self.step()
+            except ValueError:
+                break
+        return result if result else None
+`)
+
+	tree := parseSource(t, sitter.NewLanguage(python.Language()), code)
+	symbols := ExtractPythonSymbols(tree.RootNode(), code)
+	if len(symbols) != 1 || len(symbols[0].Children) != 1 {
+		t.Fatalf("Expected a class with one method, got %+v", symbols)
+	}
+
+	// for and if in the comprehension, while, and, except and the
+	// conditional expression
+	parse := symbols[0].Children[0]
+	if parse.Complexity != 7 {
+		t.Errorf("Expected parse to have complexity 7, got %d", parse.Complexity)
+	}
+	if parse.Lines != 7 {
+		t.Errorf("Expected parse to span 7 lines, got %d", parse.Lines)
+	}
+}
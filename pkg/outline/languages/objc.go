@@ -0,0 +1,518 @@
+package languages
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Objective-C is parsed with the C grammar, which has no rules for its
+// @interface, @implementation and @protocol blocks and recovers from them
+// with error nodes that swallow the C declarations around them. Those
+// blocks are therefore found by scanning the source, and blanked out before
+// the rest of the file is parsed again as plain C.
+
+var (
+	objcBlockHeader   = regexp.MustCompile(`^@(interface|implementation|protocol)\s+(\w+)\s*(\(\s*(\w*)\s*\))?`)
+	objcSelectorPart  = regexp.MustCompile(`(\w+)\s*:`)
+	objcIdentifier    = regexp.MustCompile(`\w+`)
+	objcBlockName     = regexp.MustCompile(`\(\s*\^\s*(\w+)\s*\)`)
+	objcTrailingMacro = regexp.MustCompile(`\s+[A-Z_][A-Z0-9_]*(\([^()]*\))?$`)
+)
+
+// objcBlock is an @interface, @implementation or @protocol block, or a
+// single-line directive such as @class that is blanked out with them
+type objcBlock struct {
+	start, end int
+	symbol     *Symbol
+}
+
+// objcScanner finds the Objective-C blocks of a file
+type objcScanner struct {
+	content    []byte
+	lineStarts []int
+	blocks     []objcBlock
+	imports    []string
+}
+
+func scanObjC(content []byte) *objcScanner {
+	s := &objcScanner{content: content, lineStarts: []int{0}}
+	for i, b := range content {
+		if b == '\n' {
+			s.lineStarts = append(s.lineStarts, i+1)
+		}
+	}
+
+	for i := 0; i < len(content); {
+		switch {
+		case (content[i] == '#' || content[i] == '@') && s.atLineStart(i):
+			if s.directive(i, "import") {
+				end := s.lineEnd(i)
+				s.imports = append(s.imports, collapseWhitespace(string(content[i:end])))
+				if content[i] == '@' {
+					s.blocks = append(s.blocks, objcBlock{start: i, end: end})
+				}
+				i = end
+				continue
+			}
+			if end, ok := s.block(i); ok {
+				i = end
+				continue
+			}
+			i++
+		default:
+			i = s.skip(i)
+		}
+	}
+	return s
+}
+
+// block reads the block or directive starting at i, returning where it ends
+func (s *objcScanner) block(i int) (int, bool) {
+	switch {
+	case s.directive(i, "class"):
+		end := s.find(i, ";") + 1
+		s.blocks = append(s.blocks, objcBlock{start: i, end: end})
+		return end, true
+	case s.directive(i, "interface"), s.directive(i, "implementation"), s.directive(i, "protocol"):
+	default:
+		return i, false
+	}
+
+	headerEnd := i
+	for headerEnd < len(s.content) && s.content[headerEnd] != '\n' && s.content[headerEnd] != '{' && !s.atComment(headerEnd) {
+		headerEnd++
+	}
+	header := strings.TrimSpace(string(s.content[i:headerEnd]))
+	if strings.HasSuffix(header, ";") {
+		// A forward declaration, as in @protocol Delegate;
+		end := i + strings.Index(string(s.content[i:]), ";") + 1
+		s.blocks = append(s.blocks, objcBlock{start: i, end: end})
+		return end, true
+	}
+
+	match := objcBlockHeader.FindStringSubmatch(header)
+	if match == nil {
+		return i, false
+	}
+	end := s.blockEnd(headerEnd)
+
+	kind := "class"
+	switch {
+	case match[1] == "protocol":
+		kind = "protocol"
+	case match[3] != "":
+		// Categories, and class extensions without a name of their own
+		kind = "extension"
+	}
+	symbol := s.symbol(kind, match[2], i, end, newText(s.content, uint(i), uint(i+len(header)), signatureText))
+	symbol.IsPublic = !(kind == "extension" && match[4] == "")
+	symbol.Children = s.members(headerEnd, end, symbol.IsPublic)
+
+	s.blocks = append(s.blocks, objcBlock{start: i, end: end, symbol: &symbol})
+	return end, true
+}
+
+// blockEnd returns the end of the @end line closing a block whose header
+// ends at i, or the end of the file for an unterminated block
+func (s *objcScanner) blockEnd(i int) int {
+	for i < len(s.content) {
+		if s.content[i] == '@' && s.atLineStart(i) && s.directive(i, "end") {
+			return i + len("@end")
+		}
+		i = s.skip(i)
+	}
+	return len(s.content)
+}
+
+// members reads the instance variables, properties and methods between the
+// header of a block and its @end
+func (s *objcScanner) members(i, end int, public bool) []Symbol {
+	var members []Symbol
+	for {
+		i = s.skipSpace(i, end)
+		if i >= end || s.directive(i, "end") {
+			return members
+		}
+
+		switch c := s.content[i]; {
+		case c == '{':
+			closing := s.matchBrace(i)
+			members = append(members, s.ivars(i+1, closing-1)...)
+			i = closing
+		case c == '@' && s.directive(i, "property"):
+			stop := s.find(i, ";")
+			property := s.symbol("property", s.declaredName(i, stop), i, stop+1, newText(s.content, uint(i), uint(stop), signatureText))
+			property.IsPublic = public
+			members = append(members, property)
+			i = stop + 1
+		case c == '@':
+			// Section markers such as @optional, and @synthesize lists
+			if s.directive(i, "synthesize") || s.directive(i, "dynamic") {
+				i = s.find(i, ";") + 1
+			} else {
+				i = s.lineEnd(i)
+			}
+		case c == '-' || c == '+':
+			stop := s.find(i, ";{")
+			methodEnd := stop + 1
+			if stop < len(s.content) && s.content[stop] == '{' {
+				methodEnd = s.matchBrace(stop)
+			}
+			method := s.symbol("method", s.selector(i+1, stop), i, methodEnd, newText(s.content, uint(i), uint(stop), signatureText))
+			method.IsPublic = public
+			members = append(members, method)
+			i = methodEnd
+		case c == '#':
+			i = s.lineEnd(i)
+		default:
+			// C functions and variables inside an @implementation
+			stop := s.find(i, ";{")
+			if stop < len(s.content) && s.content[stop] == '{' {
+				i = s.matchBrace(stop)
+			} else {
+				i = stop + 1
+			}
+		}
+	}
+}
+
+// ivars reads the instance variables declared between start and end. They
+// are protected unless declared after @public.
+func (s *objcScanner) ivars(start, end int) []Symbol {
+	var ivars []Symbol
+	public := false
+	for i := s.skipSpace(start, end); i < end; i = s.skipSpace(i, end) {
+		if s.content[i] == '@' {
+			public = s.directive(i, "public")
+			i = s.lineEnd(i)
+			continue
+		}
+		stop := s.find(i, ";")
+		if stop > end {
+			break
+		}
+		ivar := s.symbol("field", s.declaredName(i, stop), i, stop+1, newText(s.content, uint(i), uint(stop), signatureText))
+		ivar.IsPublic = public
+		ivars = append(ivars, ivar)
+		i = stop + 1
+	}
+	return ivars
+}
+
+// declaredName returns the name a property or variable declaration between
+// start and end declares, skipping availability macros after it
+func (s *objcScanner) declaredName(start, end int) string {
+	text := collapseWhitespace(string(s.content[start:end]))
+	if match := objcBlockName.FindStringSubmatch(text); match != nil {
+		return match[1]
+	}
+	for {
+		trimmed := objcTrailingMacro.ReplaceAllString(text, "")
+		if trimmed == text || !objcIdentifier.MatchString(trimmed) {
+			break
+		}
+		text = trimmed
+	}
+	names := objcIdentifier.FindAllString(text, -1)
+	if len(names) == 0 {
+		return ""
+	}
+	return names[len(names)-1]
+}
+
+// selector returns the selector of a method whose declaration runs from
+// start, after the - or +, to end: initWithX:y: or count
+func (s *objcScanner) selector(start, end int) string {
+	// Drop the return and parameter types, which may nest parentheses
+	var rest strings.Builder
+	depth := 0
+	for _, c := range string(s.content[start:end]) {
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0:
+			rest.WriteRune(c)
+		}
+	}
+
+	parts := objcSelectorPart.FindAllStringSubmatch(rest.String(), -1)
+	if len(parts) == 0 {
+		return objcIdentifier.FindString(rest.String())
+	}
+	var selector strings.Builder
+	for _, part := range parts {
+		writeStrings(&selector, part[1], ":")
+	}
+	return selector.String()
+}
+
+func (s *objcScanner) symbol(kind, name string, start, end int, signature Text) Symbol {
+	line, column := s.position(start)
+	endLine, endColumn := s.position(end)
+	return Symbol{
+		Type:          kind,
+		Name:          name,
+		Signature:     signature,
+		Documentation: s.doc(start),
+		Line:          line,
+		Column:        column,
+		EndLine:       endLine,
+		EndColumn:     endColumn,
+		Source:        newText(s.content, uint(start), uint(end), rawText),
+	}
+}
+
+// doc returns the comments on the lines directly above offset
+func (s *objcScanner) doc(offset int) Text {
+	line := s.line(offset) - 1
+	first := -1
+	for l := line - 1; l >= 0; l-- {
+		text := bytes.TrimSpace(s.lineBytes(l))
+		switch {
+		case bytes.HasPrefix(text, []byte("//")):
+			first = l
+			continue
+		case bytes.HasSuffix(text, []byte("*/")):
+			for l > 0 && !bytes.Contains(s.lineBytes(l), []byte("/*")) {
+				l--
+			}
+			first = l
+			continue
+		}
+		break
+	}
+	if first < 0 {
+		return Text{}
+	}
+	end := s.lineStarts[line] - 1
+	return newText(s.content, uint(s.lineStarts[first]), uint(end), commentText)
+}
+
+// line returns the 1-based line of offset
+func (s *objcScanner) line(offset int) int {
+	return sort.Search(len(s.lineStarts), func(i int) bool { return s.lineStarts[i] > offset })
+}
+
+// position returns the 1-based line and column of offset
+func (s *objcScanner) position(offset int) (int, int) {
+	line := s.line(offset)
+	return line, offset - s.lineStarts[line-1] + 1
+}
+
+// lineBytes returns the text of a 0-based line without its line break
+func (s *objcScanner) lineBytes(line int) []byte {
+	if line < 0 {
+		return nil
+	}
+	end := len(s.content)
+	if line+1 < len(s.lineStarts) {
+		end = s.lineStarts[line+1] - 1
+	}
+	return s.content[s.lineStarts[line]:end]
+}
+
+// lineEnd returns the offset of the line break ending the line of i
+func (s *objcScanner) lineEnd(i int) int {
+	if end := bytes.IndexByte(s.content[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(s.content)
+}
+
+// atLineStart reports whether only whitespace precedes i on its line
+func (s *objcScanner) atLineStart(i int) bool {
+	start := s.lineStarts[s.line(i)-1]
+	return len(bytes.TrimSpace(s.content[start:i])) == 0
+}
+
+// directive reports whether an @ or # at i starts the directive word
+func (s *objcScanner) directive(i int, word string) bool {
+	if s.content[i] != '@' && s.content[i] != '#' {
+		return false
+	}
+	rest := s.content[i+1:]
+	if !bytes.HasPrefix(rest, []byte(word)) {
+		return false
+	}
+	return len(rest) == len(word) || !isIdentifierByte(rest[len(word)])
+}
+
+func (s *objcScanner) atComment(i int) bool {
+	return bytes.HasPrefix(s.content[i:], []byte("//")) || bytes.HasPrefix(s.content[i:], []byte("/*"))
+}
+
+// skip returns the offset after the comment or literal at i, or after the
+// byte at i
+func (s *objcScanner) skip(i int) int {
+	content := s.content
+	switch {
+	case bytes.HasPrefix(content[i:], []byte("//")):
+		return s.lineEnd(i)
+	case bytes.HasPrefix(content[i:], []byte("/*")):
+		if end := bytes.Index(content[i+2:], []byte("*/")); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return len(content)
+	case content[i] == '"' || content[i] == '\'':
+		for j := i + 1; j < len(content); j++ {
+			switch content[j] {
+			case '\\':
+				j++
+			case content[i], '\n':
+				return j + 1
+			}
+		}
+		return len(content)
+	}
+	return i + 1
+}
+
+// skipSpace skips whitespace and comments from i, up to end
+func (s *objcScanner) skipSpace(i, end int) int {
+	for i < end {
+		switch {
+		case s.content[i] == ' ' || s.content[i] == '\t' || s.content[i] == '\n' || s.content[i] == '\r':
+			i++
+		case s.atComment(i):
+			i = s.skip(i)
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// find returns the offset of the first of stops outside parentheses,
+// comments and literals from i, or the end of the file
+func (s *objcScanner) find(i int, stops string) int {
+	depth := 0
+	for i < len(s.content) {
+		switch c := s.content[i]; {
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case depth == 0 && strings.IndexByte(stops, c) >= 0:
+			return i
+		}
+		i = s.skip(i)
+	}
+	return len(s.content)
+}
+
+// matchBrace returns the offset after the brace closing the one at i
+func (s *objcScanner) matchBrace(i int) int {
+	depth := 0
+	for i < len(s.content) {
+		switch s.content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+		i = s.skip(i)
+	}
+	return len(s.content)
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// MaskObjC returns a copy of content with its Objective-C blocks blanked
+// out, leaving the C the C grammar can parse. Offsets are unchanged, so the
+// tree can be read against the original content.
+func MaskObjC(content []byte) []byte {
+	s := scanObjC(content)
+	masked := bytes.Clone(content)
+	for _, block := range s.blocks {
+		for i := block.start; i < block.end; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+	return masked
+}
+
+// ExtractObjCSymbols extracts the classes, categories and protocols of an
+// Objective-C file with their members, along with its C declarations. root
+// is the C tree of the content masked by MaskObjC.
+func ExtractObjCSymbols(root *sitter.Node, content []byte) []Symbol {
+	s := scanObjC(content)
+
+	symbols := ExtractCSymbols(root, content)
+	for _, block := range s.blocks {
+		if block.symbol != nil {
+			symbols = append(symbols, *block.symbol)
+		}
+	}
+	sort.SliceStable(symbols, func(i, j int) bool { return symbols[i].Line < symbols[j].Line })
+	return symbols
+}
+
+// ExtractObjCOutline renders the imports, blocks and C declarations of an
+// Objective-C file, with method bodies elided. root is the C tree of the
+// content masked by MaskObjC.
+func ExtractObjCOutline(root *sitter.Node, content []byte) string {
+	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
+
+	s := scanObjC(content)
+	for _, line := range s.imports {
+		writeStrings(&result, line, "\n")
+	}
+	if len(s.imports) > 0 {
+		result.WriteString("\n")
+	}
+
+	blocks := s.blocks
+	writeBlocks := func(before uint) {
+		for len(blocks) > 0 && uint(blocks[0].start) < before {
+			if blocks[0].symbol != nil {
+				writeObjCBlock(&result, blocks[0].symbol)
+			}
+			blocks = blocks[1:]
+		}
+	}
+
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		child := root.NamedChild(i)
+		writeBlocks(child.StartByte())
+		processCNode(child, 0, content, &result)
+	}
+	writeBlocks(uint(len(content)) + 1)
+	return result.String()
+}
+
+func writeObjCBlock(result *strings.Builder, block *Symbol) {
+	if result.Len() > 0 && !strings.HasSuffix(result.String(), "\n\n") {
+		result.WriteString("\n")
+	}
+	writeDocComment(result, "", block.Documentation.String())
+	writeStrings(result, block.Signature.String(), " // line ")
+	writeUint(result, uint(block.Line))
+	result.WriteString("\n")
+
+	for _, member := range block.Children {
+		writeDocComment(result, "\t", member.Documentation.String())
+		writeStrings(result, "\t", member.Signature.String())
+		if bytes.HasSuffix(member.Source.Bytes(), []byte("}")) {
+			result.WriteString(" { ... } // line ")
+		} else {
+			result.WriteString("; // line ")
+		}
+		writeUint(result, uint(member.Line))
+		result.WriteString("\n")
+	}
+	result.WriteString("@end\n\n")
+}
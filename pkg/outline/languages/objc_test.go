@@ -0,0 +1,122 @@
+//go:build !outline_nolang_objc
+
+package languages
+
+import (
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+)
+
+const objcCode = `#import <Foundation/Foundation.h>
+
+static const int kLimit = 10;
+
+/// A point in the plane
+@interface Point : NSObject <NSCopying> {
+    int _count;
+}
+@property (nonatomic, copy, nullable) NSString *name;
+@property (nonatomic, copy) void (^onChange)(Point *point);
+/// Creates a point
+- (instancetype)initWithX:(CGFloat)x y:(CGFloat)y NS_DESIGNATED_INITIALIZER;
++ (Point *)origin;
+@end
+
+@interface Point (Geometry)
+- (CGFloat)distanceTo:(Point *)other;
+@end
+
+@interface Point ()
+@property (nonatomic) BOOL dirty;
+@end
+
+@protocol Drawable <NSObject>
+@optional
+- (void)draw;
+@end
+
+@class Other;
+
+@implementation Point
+
+- (instancetype)initWithX:(CGFloat)x y:(CGFloat)y {
+    if ((self = [super init])) {
+        _name = @"}"; // a brace in a string
+    }
+    return self;
+}
+
++ (Point *)origin {
+    return [[Point alloc] initWithX:0 y:0];
+}
+
+@end
+
+int helper(int a) {
+    return a > 0 ? a : -a;
+}
+`
+
+func TestObjCOutline(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(c.Language()), MaskObjC([]byte(objcCode)))
+	result := ExtractObjCOutline(tree.RootNode(), []byte(objcCode))
+
+	for _, want := range []string{
+		"#import <Foundation/Foundation.h>\n\n",
+		"static const int kLimit = 10; // line 3\n",
+		"// A point in the plane\n@interface Point : NSObject <NSCopying> // line 6\n\tint _count; // line 7\n",
+		"\t@property (nonatomic, copy, nullable) NSString *name; // line 9\n",
+		"\t// Creates a point\n\t- (instancetype)initWithX:(CGFloat)x y:(CGFloat)y NS_DESIGNATED_INITIALIZER; // line 12\n",
+		"@interface Point (Geometry) // line 16\n\t- (CGFloat)distanceTo:(Point *)other; // line 17\n@end\n",
+		"@protocol Drawable <NSObject> // line 24\n\t- (void)draw; // line 26\n@end\n",
+		"@implementation Point // line 31\n\t- (instancetype)initWithX:(CGFloat)x y:(CGFloat)y { ... } // line 33\n\t+ (Point *)origin { ... } // line 40\n@end\n",
+		"int helper(int a) { //... } // line 46\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "Other") {
+		t.Errorf("Expected forward declarations to be left out, got:\n%s", result)
+	}
+}
+
+func TestObjCSymbols(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(c.Language()), MaskObjC([]byte(objcCode)))
+	symbols := ExtractObjCSymbols(tree.RootNode(), []byte(objcCode))
+
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Type+" "+symbol.Name)
+	}
+	want := "variable kLimit, class Point, extension Point, extension Point, protocol Drawable, class Point, function helper"
+	if got := strings.Join(names, ", "); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	point := symbols[1]
+	if point.Documentation.String() != "A point in the plane" {
+		t.Errorf("Expected the doc comment of Point, got %q", point.Documentation.String())
+	}
+	var members []string
+	for _, member := range point.Children {
+		members = append(members, member.Type+" "+member.Name)
+	}
+	want = "field _count, property name, property onChange, method initWithX:y:, method origin"
+	if got := strings.Join(members, ", "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if point.Children[0].IsPublic || !point.Children[1].IsPublic {
+		t.Error("Expected instance variables to be protected and properties public")
+	}
+
+	if symbols[3].IsPublic || symbols[3].Children[0].IsPublic {
+		t.Error("Expected the class extension to be private")
+	}
+	if implementation := symbols[5]; implementation.Line != 31 || implementation.EndLine != 44 {
+		t.Errorf("Expected the implementation to span lines 31-44, got %d-%d", implementation.Line, implementation.EndLine)
+	}
+}
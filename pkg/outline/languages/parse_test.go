@@ -0,0 +1,21 @@
+package languages
+
+import (
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// parseSource parses code with the grammar of language. The parser and the
+// tree are closed when the test ends.
+func parseSource(t *testing.T, language *sitter.Language, code []byte) *sitter.Tree {
+	t.Helper()
+	parser := sitter.NewParser()
+	t.Cleanup(parser.Close)
+	if err := parser.SetLanguage(language); err != nil {
+		t.Fatalf("Failed to set language: %v", err)
+	}
+	tree := parser.Parse(code, nil)
+	t.Cleanup(tree.Close)
+	return tree
+}
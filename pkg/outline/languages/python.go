@@ -10,6 +10,7 @@ import (
 // ExtractPythonOutline extracts Python outline directly from the code
 func ExtractPythonOutline(root *sitter.Node, content []byte) string {
 	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
 
 	// Function to process a node and its children
 	var processNode func(node *sitter.Node, indentLevel int)
@@ -190,3 +191,92 @@ func ExtractPythonOutline(root *sitter.Node, content []byte) string {
 	processNode(root, 0)
 	return result.String()
 }
+
+var pythonSymbols = &symbolSpec{
+	rules: map[string]symbolRule{
+		"function_definition": {kind: "function", body: "body"},
+		"class_definition":    {kind: "class", body: "body", members: membersIn("body")},
+		"assignment": {
+			kindOf: pythonAssignmentKind,
+			name:   pythonAssignmentName,
+		},
+	},
+	containers: map[string]bool{
+		"decorated_definition": true,
+		"expression_statement": true,
+	},
+	wrappers: map[string]bool{
+		"decorated_definition": true,
+		"expression_statement": true,
+	},
+	public: func(node *sitter.Node, name string, content []byte) bool {
+		return isPythonPublic(name)
+	},
+	doc:        pythonDocstring,
+	complexity: pythonComplexity,
+}
+
+var pythonComplexity = &complexitySpec{
+	branches: kinds("if_statement", "elif_clause", "for_statement", "while_statement", "except_clause",
+		"conditional_expression", "case_clause", "for_in_clause", "if_clause"),
+	operators: kinds("and", "or"),
+}
+
+// isPythonPublic treats names with a leading underscore as private, except
+// for dunder methods such as __init__
+func isPythonPublic(name string) bool {
+	if strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") {
+		return true
+	}
+	return !strings.HasPrefix(name, "_")
+}
+
+// pythonAssignmentName names simple assignments, leaving out tuple unpacking
+// and attribute assignments
+func pythonAssignmentName(node *sitter.Node, content []byte) string {
+	left := node.ChildByFieldName("left")
+	if left == nil || left.Kind() != "identifier" {
+		return ""
+	}
+	return getNodeText(left, content)
+}
+
+func pythonAssignmentKind(node *sitter.Node, content []byte) string {
+	// Assignments in a class body are its attributes
+	if statement := node.Parent(); statement != nil {
+		if block := statement.Parent(); block != nil && block.Kind() == "block" {
+			if owner := block.Parent(); owner != nil && owner.Kind() == "class_definition" {
+				return "field"
+			}
+		}
+	}
+
+	name := pythonAssignmentName(node, content)
+	if name == strings.ToUpper(name) {
+		return "constant"
+	}
+	return "variable"
+}
+
+// pythonDocstring returns the string literal that opens a function or class
+// body
+func pythonDocstring(node *sitter.Node, content []byte) Text {
+	body := node.ChildByFieldName("body")
+	if body == nil || body.NamedChildCount() == 0 {
+		return Text{}
+	}
+
+	first := body.NamedChild(0)
+	if first.Kind() != "expression_statement" || first.NamedChildCount() == 0 {
+		return Text{}
+	}
+	if docstring := first.NamedChild(0); docstring.Kind() == "string" {
+		return newText(content, docstring.StartByte(), docstring.EndByte(), commentText)
+	}
+	return Text{}
+}
+
+// ExtractPythonSymbols extracts the declarations of a Python file as symbols
+func ExtractPythonSymbols(root *sitter.Node, content []byte) []Symbol {
+	return extractSymbols(root, content, pythonSymbols)
+}
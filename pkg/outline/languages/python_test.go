@@ -1,3 +1,5 @@
+//go:build !outline_nolang_python
+
 package languages
 
 import (
@@ -0,0 +1,118 @@
+package languages
+
+import (
+	"bytes"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Reference is an identifier naming a symbol, found in the syntax tree so
+// that mentions in comments and strings are left out
+type Reference struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Symbol is the qualified name of the innermost symbol the reference is
+	// in, empty at the top level of the file
+	Symbol string `json:"symbol,omitempty"`
+	// Declaration is set when the identifier is the name being declared
+	// rather than a use of it
+	Declaration bool `json:"declaration,omitempty"`
+	// Text is the source line of the reference, without indentation
+	Text string `json:"text"`
+}
+
+// maxReferenceText bounds the source line kept with a reference, so that
+// minified code does not flood the output
+const maxReferenceText = 160
+
+// FindReferences lists the identifiers of a file that are spelled name. The
+// symbols of the file are used to find the declaration each reference is
+// in, and may be nil.
+func FindReferences(root *sitter.Node, content []byte, symbols []Symbol, name string) []Reference {
+	if name == "" || !bytes.Contains(content, []byte(name)) {
+		return nil
+	}
+
+	var references []Reference
+	walkIdentifiers(root, content, func(node *sitter.Node, text string) {
+		if text == name {
+			references = append(references, newReference(node, content, symbols))
+		}
+	})
+	return references
+}
+
+// Identifier is an identifier of a file with the name it spells
+type Identifier struct {
+	Name string `json:"name"`
+	Reference
+}
+
+// FindIdentifiers lists every identifier of a file, declared names included,
+// in the order they appear
+func FindIdentifiers(root *sitter.Node, content []byte, symbols []Symbol) []Identifier {
+	var identifiers []Identifier
+	walkIdentifiers(root, content, func(node *sitter.Node, text string) {
+		identifiers = append(identifiers, Identifier{Name: text, Reference: newReference(node, content, symbols)})
+	})
+	return identifiers
+}
+
+// walkIdentifiers calls visit with every identifier node below root and its
+// text
+func walkIdentifiers(root *sitter.Node, content []byte, visit func(node *sitter.Node, text string)) {
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		// Grammars name their identifier nodes differently (type_identifier,
+		// field_identifier, simple_identifier...), but always as identifiers
+		if strings.Contains(node.Kind(), "identifier") {
+			visit(node, getNodeText(node, content))
+			return
+		}
+		for i := uint(0); i < node.ChildCount(); i++ {
+			walk(node.Child(i))
+		}
+	}
+	walk(root)
+}
+
+func newReference(node *sitter.Node, content []byte, symbols []Symbol) Reference {
+	start := int(node.StartByte())
+	position := node.StartPosition()
+
+	lineStart := bytes.LastIndexByte(content[:start], '\n') + 1
+	lineEnd := bytes.IndexByte(content[start:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(content)
+	} else {
+		lineEnd += start
+	}
+	text := strings.TrimSpace(string(content[lineStart:lineEnd]))
+	if len(text) > maxReferenceText {
+		text = strings.ToValidUTF8(text[:maxReferenceText], "") + "..."
+	}
+
+	return Reference{
+		Line:        int(position.Row) + 1,
+		Column:      start - lineStart + 1,
+		Symbol:      enclosingSymbol(symbols, start, ""),
+		Declaration: isDeclaredName(node),
+		Text:        text,
+	}
+}
+
+// isDeclaredName reports whether node is the name its parent declares,
+// which every grammar exposes as the name or declarator field
+func isDeclaredName(node *sitter.Node) bool {
+	parent := node.Parent()
+	if parent == nil {
+		return false
+	}
+	for _, field := range []string{"name", "declarator"} {
+		if declared := parent.ChildByFieldName(field); declared != nil && declared.StartByte() == node.StartByte() && declared.EndByte() == node.EndByte() {
+			return true
+		}
+	}
+	return false
+}
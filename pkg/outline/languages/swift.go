@@ -40,6 +40,8 @@ func processSwiftNode(node *tree_sitter.Node, indentLevel int, content []byte, r
 		processSwiftExtension(node, content, result, indent)
 	case "typealias_declaration":
 		processSwiftTypealias(node, content, result, indent)
+	case "macro_declaration":
+		processSwiftMacro(node, content, result, indent)
 	}
 
 	// Only process top-level nodes, not all children recursively
@@ -56,11 +58,13 @@ func processSwiftClass(node *tree_sitter.Node, content []byte, result *strings.B
 	var inheritance []string
 	var modifiers []string
 
-	// Check if this is actually a struct, enum, or extension by looking at the source text
-	nodeText := getNodeText(node, content)
-	isStruct := strings.Contains(nodeText, "struct ")
-	isEnum := strings.Contains(nodeText, "enum ")
-	isExtension := strings.Contains(nodeText, "extension ")
+	// The grammar uses class_declaration for classes, structs, actors, enums
+	// and extensions; the declaration_kind field tells them apart
+	declType := "class"
+	if kindNode := node.ChildByFieldName("declaration_kind"); kindNode != nil {
+		declType = getNodeText(kindNode, content)
+	}
+	isExtension := declType == "extension"
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(uint(i))
@@ -107,15 +111,6 @@ func processSwiftClass(node *tree_sitter.Node, content []byte, result *strings.B
 		result.WriteString(fmt.Sprintf("%s%s\n", indent, comment))
 	}
 
-	declType := "class"
-	if isStruct {
-		declType = "struct"
-	} else if isEnum {
-		declType = "enum"
-	} else if isExtension {
-		declType = "extension"
-	}
-
 	classDecl := declType + " " + name
 	if len(modifiers) > 0 {
 		classDecl = strings.Join(modifiers, " ") + " " + classDecl
@@ -124,7 +119,7 @@ func processSwiftClass(node *tree_sitter.Node, content []byte, result *strings.B
 		classDecl += ": " + strings.Join(inheritance, ", ")
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s {\n", indent, classDecl))
+	result.WriteString(fmt.Sprintf("%s%s { // line %d\n", indent, classDecl, getNodeLineNumber(node)))
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(uint(i))
@@ -181,7 +176,7 @@ func processSwiftStruct(node *tree_sitter.Node, content []byte, result *strings.
 		structDecl += ": " + strings.Join(protocols, ", ")
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s {\n", indent, structDecl))
+	result.WriteString(fmt.Sprintf("%s%s { // line %d\n", indent, structDecl, getNodeLineNumber(node)))
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(uint(i))
@@ -235,7 +230,7 @@ func processSwiftProtocol(node *tree_sitter.Node, content []byte, result *string
 		protocolDecl += ": " + strings.Join(inheritance, ", ")
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s {\n", indent, protocolDecl))
+	result.WriteString(fmt.Sprintf("%s%s { // line %d\n", indent, protocolDecl, getNodeLineNumber(node)))
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(uint(i))
@@ -284,7 +279,7 @@ func processSwiftEnum(node *tree_sitter.Node, content []byte, result *strings.Bu
 		enumDecl += ": " + rawType
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s {\n", indent, enumDecl))
+	result.WriteString(fmt.Sprintf("%s%s { // line %d\n", indent, enumDecl, getNodeLineNumber(node)))
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(uint(i))
@@ -347,7 +342,7 @@ func processSwiftFunction(node *tree_sitter.Node, content []byte, result *string
 		funcDecl += " -> " + returnType
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s\n", indent, funcDecl))
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, funcDecl, getNodeLineNumber(node)))
 }
 
 func processSwiftInit(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -385,7 +380,7 @@ func processSwiftInit(node *tree_sitter.Node, content []byte, result *strings.Bu
 		initDecl = strings.Join(modifiers, " ") + " " + initDecl
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s\n", indent, initDecl))
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, initDecl, getNodeLineNumber(node)))
 }
 
 func processSwiftDeinit(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -394,20 +389,23 @@ func processSwiftDeinit(node *tree_sitter.Node, content []byte, result *strings.
 		result.WriteString(fmt.Sprintf("%s%s\n", indent, comment))
 	}
 
-	result.WriteString(fmt.Sprintf("%sdeinit\n", indent))
+	result.WriteString(fmt.Sprintf("%sdeinit // line %d\n", indent, getNodeLineNumber(node)))
 }
 
 func processSwiftProperty(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
 	var name string
 	var propType string
+	var binding string
 	var modifiers []string
-	var isComputed bool
+	var accessors string
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(uint(i))
 		childType := child.Kind()
 
 		switch childType {
+		case "value_binding_pattern":
+			binding = getNodeText(child, content)
 		case "pattern":
 			if child.Kind() == "pattern" {
 				for j := 0; j < int(child.NamedChildCount()); j++ {
@@ -430,7 +428,9 @@ func processSwiftProperty(node *tree_sitter.Node, content []byte, result *string
 				}
 			}
 		case "computed_property":
-			isComputed = true
+			accessors = extractSwiftComputedAccessors(child, content)
+		case "willset_didset_block":
+			accessors = extractSwiftObservers(child)
 		case "modifiers":
 			for j := 0; j < int(child.NamedChildCount()); j++ {
 				modChild := child.NamedChild(uint(j))
@@ -445,17 +445,77 @@ func processSwiftProperty(node *tree_sitter.Node, content []byte, result *string
 	}
 
 	propDecl := name
+	if binding != "" {
+		propDecl = binding + " " + propDecl
+	}
 	if len(modifiers) > 0 {
 		propDecl = strings.Join(modifiers, " ") + " " + propDecl
 	}
 	if propType != "" {
 		propDecl += ": " + propType
 	}
-	if isComputed {
-		propDecl += " { get set }"
+	if accessors != "" {
+		propDecl += " " + accessors
+	}
+
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, propDecl, getNodeLineNumber(node)))
+}
+
+// extractSwiftComputedAccessors describes the accessors of a computed property,
+// e.g. "{ get async throws }" or "{ get set }". A body without explicit accessor
+// clauses is the read-only shorthand and is reported as "{ get }".
+func extractSwiftComputedAccessors(node *tree_sitter.Node, content []byte) string {
+	var accessors []string
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(uint(i))
+
+		var specifierKind string
+		switch child.Kind() {
+		case "computed_getter":
+			specifierKind = "getter_specifier"
+		case "computed_setter":
+			specifierKind = "setter_specifier"
+		case "computed_modify":
+			specifierKind = "modify_specifier"
+		default:
+			continue
+		}
+
+		for j := 0; j < int(child.NamedChildCount()); j++ {
+			specifier := child.NamedChild(uint(j))
+			if specifier.Kind() == specifierKind {
+				accessors = append(accessors, strings.Join(strings.Fields(getNodeText(specifier, content)), " "))
+				break
+			}
+		}
+	}
+
+	if len(accessors) == 0 {
+		accessors = append(accessors, "get")
+	}
+
+	return "{ " + strings.Join(accessors, " ") + " }"
+}
+
+// extractSwiftObservers describes the willSet/didSet observers of a stored property
+func extractSwiftObservers(node *tree_sitter.Node) string {
+	var observers []string
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		switch node.NamedChild(uint(i)).Kind() {
+		case "willset_clause":
+			observers = append(observers, "willSet")
+		case "didset_clause":
+			observers = append(observers, "didSet")
+		}
+	}
+
+	if len(observers) == 0 {
+		return ""
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s\n", indent, propDecl))
+	return "{ " + strings.Join(observers, " ") + " }"
 }
 
 func processSwiftSubscript(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -509,7 +569,7 @@ func processSwiftSubscript(node *tree_sitter.Node, content []byte, result *strin
 		subscriptDecl += " -> " + returnType
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s\n", indent, subscriptDecl))
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, subscriptDecl, getNodeLineNumber(node)))
 }
 
 func processSwiftExtension(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -545,7 +605,7 @@ func processSwiftExtension(node *tree_sitter.Node, content []byte, result *strin
 		extensionDecl += ": " + strings.Join(protocols, ", ")
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s {\n", indent, extensionDecl))
+	result.WriteString(fmt.Sprintf("%s%s { // line %d\n", indent, extensionDecl, getNodeLineNumber(node)))
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(uint(i))
@@ -598,7 +658,68 @@ func processSwiftTypealias(node *tree_sitter.Node, content []byte, result *strin
 		typealiasDecl += " = " + aliasType
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s\n", indent, typealiasDecl))
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, typealiasDecl, getNodeLineNumber(node)))
+}
+
+func processSwiftMacro(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
+	var name string
+	var typeParams string
+	var params []string
+	var returnType string
+	var definition string
+	var modifiers []string
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(uint(i))
+		childType := child.Kind()
+
+		switch childType {
+		case "simple_identifier":
+			if name == "" {
+				name = getNodeText(child, content)
+			}
+		case "type_parameters":
+			typeParams = getNodeText(child, content)
+		case "parameter":
+			param := extractSwiftParameter(child, content)
+			if param != "" {
+				params = append(params, param)
+			}
+		case "user_type", "type_identifier", "optional_type", "tuple_type", "array_type", "dictionary_type":
+			if returnType == "" {
+				returnType = getNodeText(child, content)
+			}
+		case "macro_definition":
+			// Keep the definition only when it points at an external implementation,
+			// inline expansion bodies are implementation details
+			if body := child.ChildByFieldName("body"); body != nil && body.Kind() == "external_macro_definition" {
+				definition = strings.Join(strings.Fields(getNodeText(body, content)), " ")
+			}
+		case "modifiers":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				modChild := child.NamedChild(uint(j))
+				modifiers = append(modifiers, getNodeText(modChild, content))
+			}
+		}
+	}
+
+	comment := findDocComment(node, content, "swift")
+	if comment != "" {
+		result.WriteString(fmt.Sprintf("%s%s\n", indent, comment))
+	}
+
+	macroDecl := "macro " + name + typeParams + "(" + strings.Join(params, ", ") + ")"
+	if len(modifiers) > 0 {
+		macroDecl = strings.Join(modifiers, " ") + " " + macroDecl
+	}
+	if returnType != "" {
+		macroDecl += " -> " + returnType
+	}
+	if definition != "" {
+		macroDecl += " = " + definition
+	}
+
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, macroDecl, getNodeLineNumber(node)))
 }
 
 func processSwiftClassBody(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -778,7 +899,7 @@ func processSwiftProtocolFunction(node *tree_sitter.Node, content []byte, result
 		funcDecl += " -> " + returnType
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s\n", indent, funcDecl))
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, funcDecl, getNodeLineNumber(node)))
 }
 
 func processSwiftProtocolProperty(node *tree_sitter.Node, content []byte, result *strings.Builder, indent string) {
@@ -829,7 +950,7 @@ func processSwiftProtocolProperty(node *tree_sitter.Node, content []byte, result
 		propDecl += " " + requirements
 	}
 
-	result.WriteString(fmt.Sprintf("%s%s\n", indent, propDecl))
+	result.WriteString(fmt.Sprintf("%s%s // line %d\n", indent, propDecl, getNodeLineNumber(node)))
 }
 
 func extractSwiftParameter(node *tree_sitter.Node, content []byte) string {
@@ -860,6 +981,7 @@ func extractSwiftParameter(node *tree_sitter.Node, content []byte) string {
 // ExtractSwiftOutline extracts Swift outline directly from the code
 func ExtractSwiftOutline(root *tree_sitter.Node, content []byte) string {
 	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
 
 	// Only process direct children of the source file
 	for i := 0; i < int(root.NamedChildCount()); i++ {
@@ -869,3 +991,106 @@ func ExtractSwiftOutline(root *tree_sitter.Node, content []byte) string {
 
 	return result.String()
 }
+
+var swiftSymbols = &symbolSpec{
+	rules: map[string]symbolRule{
+		"class_declaration":             {kindOf: swiftDeclarationKind, body: "body", members: membersIn("body")},
+		"protocol_declaration":          {kind: "protocol", body: "body", members: membersIn("body")},
+		"function_declaration":          {kind: "function", body: "body"},
+		"protocol_function_declaration": {kind: "method"},
+		"init_declaration":              {kind: "constructor", name: literal("init"), body: "body"},
+		"subscript_declaration":         {kind: "method", name: literal("subscript"), signatureEnd: swiftSubscriptSignatureEnd},
+		"property_declaration":          {kind: "property", name: swiftPropertyName, signatureEnd: swiftPropertySignatureEnd},
+		"protocol_property_declaration": {kind: "property", name: swiftPropertyName},
+		"typealias_declaration":         {kind: "type"},
+		"associatedtype_declaration":    {kind: "type"},
+		"enum_entry":                    {kind: "enum_member"},
+		"macro_declaration":             {kind: "macro", name: swiftMacroName, signatureEnd: swiftMacroSignatureEnd},
+	},
+	public:     isSwiftPublic,
+	complexity: swiftComplexity,
+}
+
+var swiftComplexity = &complexitySpec{
+	branches: kinds("if_statement", "guard_statement", "for_statement", "while_statement", "repeat_while_statement",
+		"switch_entry", "catch_block", "ternary_expression", "conjunction_expression", "disjunction_expression",
+		"nil_coalescing_expression"),
+}
+
+func swiftDeclarationKind(node *tree_sitter.Node, content []byte) string {
+	if kindNode := node.ChildByFieldName("declaration_kind"); kindNode != nil {
+		return getNodeText(kindNode, content)
+	}
+	return "class"
+}
+
+// swiftPropertyName reads the identifier bound by a property's pattern,
+// which in protocols also spans the var keyword
+func swiftPropertyName(node *tree_sitter.Node, content []byte) string {
+	pattern := node.ChildByFieldName("name")
+	if pattern == nil {
+		return ""
+	}
+	if bound := pattern.ChildByFieldName("bound_identifier"); bound != nil {
+		return getNodeText(bound, content)
+	}
+	return getNodeText(pattern, content)
+}
+
+func swiftMacroName(node *tree_sitter.Node, content []byte) string {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if child := node.NamedChild(i); child.Kind() == "simple_identifier" {
+			return getNodeText(child, content)
+		}
+	}
+	return ""
+}
+
+// swiftMacroSignatureEnd leaves out the = #externalMacro(...) definition
+func swiftMacroSignatureEnd(node *tree_sitter.Node) uint {
+	if definition := node.ChildByFieldName("definition"); definition != nil {
+		return definition.StartByte()
+	}
+	return node.EndByte()
+}
+
+// swiftPropertySignatureEnd stops after the type annotation, or the name
+// when there is none, so initializers and accessor bodies are left out
+func swiftPropertySignatureEnd(node *tree_sitter.Node) uint {
+	end := node.EndByte()
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		end = nameNode.EndByte()
+	}
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if child := node.NamedChild(i); child.Kind() == "type_annotation" {
+			end = child.EndByte()
+		}
+	}
+	return end
+}
+
+func swiftSubscriptSignatureEnd(node *tree_sitter.Node) uint {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if child := node.NamedChild(i); child.Kind() == "computed_property" {
+			return child.StartByte()
+		}
+	}
+	return node.EndByte()
+}
+
+// isSwiftPublic treats everything not marked private or fileprivate as
+// visible, since Swift defaults to module-wide internal access
+func isSwiftPublic(node *tree_sitter.Node, name string, content []byte) bool {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		child := node.NamedChild(i)
+		if child.Kind() == "modifiers" && strings.Contains(getNodeText(child, content), "private") {
+			return false
+		}
+	}
+	return true
+}
+
+// ExtractSwiftSymbols extracts the declarations of a Swift file as symbols
+func ExtractSwiftSymbols(root *tree_sitter.Node, content []byte) []Symbol {
+	return extractSymbols(root, content, swiftSymbols)
+}
@@ -0,0 +1,442 @@
+package languages
+
+import (
+	"bytes"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Symbol is a declaration found in a source file. Lines and columns are
+// 1-based, and columns count bytes.
+type Symbol struct {
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+	Signature     Text   `json:"signature,omitzero"`
+	Documentation Text   `json:"documentation,omitzero"`
+	Line          int    `json:"line"`
+	Column        int    `json:"column"`
+	EndLine       int    `json:"endLine"`
+	EndColumn     int    `json:"endColumn"`
+	IsPublic      bool   `json:"isPublic"`
+
+	// Complexity is the cyclomatic complexity of a function body, and Lines
+	// the number of lines it spans. Both are zero for other symbols and for
+	// functions without a body.
+	Complexity int `json:"complexity,omitempty"`
+	Lines      int `json:"lines,omitempty"`
+
+	Children []Symbol `json:"children,omitempty"`
+
+	// Source is the full text of the declaration
+	Source Text `json:"-"`
+}
+
+// textForm selects how the bytes of a Text are cleaned up when it is turned
+// into a string
+type textForm uint8
+
+const (
+	rawText textForm = iota
+	signatureText
+	commentText
+
+	// dashCommentText is commentText for comments introduced by --, as in
+	// Lua, whose markers cleanComment leaves alone so that the underlines
+	// of docstring headings survive
+	dashCommentText
+
+	// semicolonCommentText is commentText for comments introduced by one or
+	// more semicolons, as in Clojure
+	semicolonCommentText
+
+	// lastSharedText ends the forms above. Forms of a single language, such
+	// as percentCommentText, follow it and are declared with the extractor
+	// of that language.
+	lastSharedText = semicolonCommentText
+)
+
+// Text is a range of the source a symbol was extracted from. It references
+// the original content rather than copying it, and is only turned into a
+// string when read, so that indexing thousands of symbols doesn't duplicate
+// large parts of the file.
+type Text struct {
+	src    []byte
+	prefix string
+	start  uint32
+	end    uint32
+	form   textForm
+}
+
+// NewText returns a Text holding s itself rather than a range of a file
+func NewText(s string) Text {
+	return Text{src: []byte(s), end: uint32(len(s))}
+}
+
+func newText(content []byte, start, end uint, form textForm) Text {
+	return Text{src: content, start: uint32(start), end: uint32(end), form: form}
+}
+
+// Start returns the byte offset where the text begins in the source
+func (t Text) Start() int {
+	return int(t.start)
+}
+
+// End returns the byte offset just past the end of the text in the source
+func (t Text) End() int {
+	return int(t.end)
+}
+
+// Bytes returns the raw source range. The slice shares memory with the
+// content the symbol was extracted from and must not be modified.
+func (t Text) Bytes() []byte {
+	if t.src == nil {
+		return nil
+	}
+	return t.src[t.start:t.end]
+}
+
+// IsZero reports whether the text is empty
+func (t Text) IsZero() bool {
+	return t.start == t.end && t.prefix == ""
+}
+
+// String materializes the text. Signatures have their whitespace collapsed
+// onto one line, and documentation has its comment markers removed.
+func (t Text) String() string {
+	if t.IsZero() {
+		return ""
+	}
+
+	raw := string(t.Bytes())
+	switch t.form {
+	case signatureText:
+		return strings.TrimRight(collapseWhitespace(t.prefix+raw), " {:")
+	case commentText:
+		return cleanComment(raw)
+	case dashCommentText:
+		return cleanComment(stripDashes(raw))
+	case semicolonCommentText:
+		return cleanComment(stripSemicolons(raw))
+	case percentCommentText:
+		return cleanComment(stripPercents(raw))
+	default:
+		return t.prefix + raw
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (t Text) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (t *Text) UnmarshalText(data []byte) error {
+	*t = NewText(string(data))
+	return nil
+}
+
+// cleanComment strips comment delimiters and decoration from the lines of
+// a doc comment or docstring
+func cleanComment(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		for _, marker := range []string{"/**", "/*!", "/*", "///", "//!", "//", "#", `"""`, "'''"} {
+			if strings.HasPrefix(line, marker) {
+				line = line[len(marker):]
+				break
+			}
+		}
+		for _, marker := range []string{"*/", `"""`, "'''"} {
+			line = strings.TrimSuffix(line, marker)
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		lines = append(lines, line)
+	}
+
+	// Drop the blank lines left behind by delimiters on their own line
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stripDashes removes the -- that starts each line of a comment, along with
+// the brackets of a long comment such as --[[ ... ]]
+func stripDashes(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "--") {
+			line = strings.TrimPrefix(strings.TrimLeft(line, "-"), "[[")
+		}
+		lines[i] = strings.TrimSuffix(line, "]]")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stripSemicolons removes the semicolons that start each line of a comment
+func stripSemicolons(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimLeft(strings.TrimSpace(line), ";")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// symbolRule describes how one kind of syntax node becomes a symbol
+type symbolRule struct {
+	kind string
+
+	// kindOf overrides kind for nodes whose kind depends on their content.
+	// Returning "" skips the node.
+	kindOf func(node *sitter.Node, content []byte) string
+
+	// name returns the symbol name. Nil reads the "name" field. Returning ""
+	// skips the node.
+	name func(node *sitter.Node, content []byte) string
+
+	// body names the field the signature stops at
+	body string
+
+	// members returns the node whose children are nested symbols. Nil means
+	// the symbol has no members.
+	members func(node *sitter.Node) *sitter.Node
+
+	// signatureEnd overrides where the signature stops
+	signatureEnd func(node *sitter.Node) uint
+
+	// prefix returns text prepended to the signature, for keywords that
+	// live on an enclosing node
+	prefix func(node *sitter.Node, content []byte) string
+}
+
+// symbolSpec describes how to find the symbols of one language
+type symbolSpec struct {
+	rules map[string]symbolRule
+
+	// containers are walked into without producing a symbol of their own,
+	// or when their rule skips them
+	containers map[string]bool
+
+	// wrappers are containers that own the doc comment of the declaration
+	// they wrap, such as export statements or decorated definitions
+	wrappers map[string]bool
+
+	// public reports whether a symbol is visible outside its file, package or
+	// enclosing type
+	public func(node *sitter.Node, name string, content []byte) bool
+
+	// doc overrides how documentation is found. Nil uses the comments
+	// directly above the declaration.
+	doc func(node *sitter.Node, content []byte) Text
+
+	// complexity describes the branches counted for function metrics
+	complexity *complexitySpec
+}
+
+// membersIn returns a members function reading the named field
+func membersIn(field string) func(node *sitter.Node) *sitter.Node {
+	return func(node *sitter.Node) *sitter.Node {
+		return node.ChildByFieldName(field)
+	}
+}
+
+// literal returns a name or prefix function that always yields text
+func literal(text string) func(node *sitter.Node, content []byte) string {
+	return func(*sitter.Node, []byte) string {
+		return text
+	}
+}
+
+// nodeName is a name function reading the text of the node itself
+func nodeName(node *sitter.Node, content []byte) string {
+	return getNodeText(node, content)
+}
+
+// extractSymbols walks the tree and returns its top-level symbols, with
+// members nested as children
+func extractSymbols(root *sitter.Node, content []byte, spec *symbolSpec) []Symbol {
+	return collectSymbols(root, content, spec, "")
+}
+
+func collectSymbols(node *sitter.Node, content []byte, spec *symbolSpec, owner string) []Symbol {
+	var symbols []Symbol
+
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		child := node.NamedChild(i)
+		if rule, ok := spec.rules[child.Kind()]; ok {
+			if symbol, ok := buildSymbol(child, rule, content, spec, owner); ok {
+				symbols = append(symbols, symbol)
+				continue
+			}
+		}
+
+		// Containers are also walked when a rule declined the node, such as
+		// a C declaration that only defines a struct
+		if spec.containers[child.Kind()] {
+			symbols = append(symbols, collectSymbols(child, content, spec, owner)...)
+		}
+	}
+
+	return symbols
+}
+
+func buildSymbol(node *sitter.Node, rule symbolRule, content []byte, spec *symbolSpec, owner string) (Symbol, bool) {
+	var name string
+	if rule.name != nil {
+		name = rule.name(node, content)
+	} else if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		name = getNodeText(nameNode, content)
+	}
+	if name == "" {
+		return Symbol{}, false
+	}
+
+	kind := rule.kind
+	if rule.kindOf != nil {
+		kind = rule.kindOf(node, content)
+	}
+	if kind == "" {
+		return Symbol{}, false
+	}
+	if kind == "function" && isTypeKind(owner) {
+		kind = "method"
+	}
+
+	signatureEnd := node.EndByte()
+	if rule.signatureEnd != nil {
+		signatureEnd = rule.signatureEnd(node)
+	} else if rule.body != "" {
+		if body := node.ChildByFieldName(rule.body); body != nil {
+			signatureEnd = body.StartByte()
+		}
+	}
+
+	signature := newText(content, node.StartByte(), signatureEnd, signatureText)
+	if rule.prefix != nil {
+		signature.prefix = rule.prefix(node, content)
+	}
+
+	var doc Text
+	if spec.doc != nil {
+		doc = spec.doc(node, content)
+	}
+	if doc.IsZero() {
+		doc = precedingComments(node, content, spec)
+	}
+
+	start, end := node.StartPosition(), node.EndPosition()
+	endByte := node.EndByte()
+	if end.Column == 0 && end.Row > start.Row {
+		// Preprocessor directives end after the line break that terminates
+		// them, which would put the symbol's end on the next line
+		endByte = uint(len(bytes.TrimRight(content[:endByte], "\r\n")))
+		end.Row--
+		end.Column = endByte - uint(bytes.LastIndexByte(content[:endByte], '\n')+1)
+	}
+
+	symbol := Symbol{
+		Type:          kind,
+		Name:          name,
+		Signature:     signature,
+		Documentation: doc,
+		Line:          int(start.Row) + 1,
+		Column:        int(start.Column) + 1,
+		EndLine:       int(end.Row) + 1,
+		EndColumn:     int(end.Column) + 1,
+		IsPublic:      spec.public == nil || spec.public(node, name, content),
+		Source:        newText(content, node.StartByte(), endByte, rawText),
+	}
+
+	if spec.complexity != nil && isFunctionKind(kind) {
+		if body := functionBody(node, rule.body); body != nil {
+			addMetrics(&symbol, body, content, spec.complexity)
+		}
+	}
+
+	if rule.members != nil {
+		if members := rule.members(node); members != nil {
+			symbol.Children = collectSymbols(members, content, spec, kind)
+		}
+	}
+
+	return symbol, true
+}
+
+// isTypeKind reports whether symbols of the kind hold methods
+func isTypeKind(kind string) bool {
+	switch kind {
+	case "class", "struct", "interface", "protocol", "enum", "extension", "actor", "record", "union", "mixin":
+		return true
+	}
+	return false
+}
+
+// precedingComments returns the comments directly above a declaration,
+// stopping at the first blank line. Comments above a wrapper such as an
+// export statement belong to the declaration it wraps.
+func precedingComments(node *sitter.Node, content []byte, spec *symbolSpec) Text {
+	for {
+		if doc := adjacentComments(node, content); !doc.IsZero() {
+			return doc
+		}
+
+		parent := node.Parent()
+		if parent == nil || !spec.wrappers[parent.Kind()] || !isFirstDeclaration(parent, node) {
+			return Text{}
+		}
+		node = parent
+	}
+}
+
+func adjacentComments(node *sitter.Node, content []byte) Text {
+	var first *sitter.Node
+	last := node
+
+	for prev := node.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if !strings.Contains(prev.Kind(), "comment") {
+			break
+		}
+		if prev.EndPosition().Row+1 < last.StartPosition().Row {
+			break
+		}
+		first = prev
+		last = prev
+	}
+
+	if first == nil {
+		return Text{}
+	}
+	commentEnd := node.PrevNamedSibling()
+	return newText(content, first.StartByte(), commentEnd.EndByte(), commentText)
+}
+
+// isFirstDeclaration reports whether child is the first non-comment named
+// child of parent
+func isFirstDeclaration(parent, child *sitter.Node) bool {
+	for i := uint(0); i < parent.NamedChildCount(); i++ {
+		named := parent.NamedChild(i)
+		if strings.Contains(named.Kind(), "comment") {
+			continue
+		}
+		return named.Id() == child.Id()
+	}
+	return false
+}
+
+// mergeSymbolRules returns the rules of base with those of extra added
+func mergeSymbolRules(base, extra map[string]symbolRule) map[string]symbolRule {
+	merged := make(map[string]symbolRule, len(base)+len(extra))
+	for kind, rule := range base {
+		merged[kind] = rule
+	}
+	for kind, rule := range extra {
+		merged[kind] = rule
+	}
+	return merged
+}
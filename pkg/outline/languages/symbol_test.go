@@ -0,0 +1,170 @@
+//go:build !outline_nolang_go && !outline_nolang_python
+
+package languages
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	golang "github.com/tree-sitter/tree-sitter-go/bindings/go"
+	python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+)
+
+func TestGoSymbols(t *testing.T) {
+	code := []byte(`package shapes
+
+// Point is a location on a plane.
+// It is immutable.
+type Point struct {
+	X, Y int
+	label string
+}
+
+// Distance returns how far apart p and q are
+func (p Point) Distance(q Point) float64 {
+	return 0
+}
+
+func helper() {}
+`)
+
+	tree := parseSource(t, sitter.NewLanguage(golang.Language()), code)
+	symbols := ExtractGoSymbols(tree.RootNode(), code)
+
+	if len(symbols) != 3 {
+		t.Fatalf("Expected 3 symbols, got %d: %+v", len(symbols), symbols)
+	}
+
+	point := symbols[0]
+	if point.Type != "struct" || point.Name != "Point" || !point.IsPublic {
+		t.Errorf("Unexpected symbol for Point: %+v", point)
+	}
+	if got := point.Documentation.String(); got != "Point is a location on a plane.\nIt is immutable." {
+		t.Errorf("Unexpected documentation for Point: %q", got)
+	}
+	if got := point.Signature.String(); got != "type Point struct" {
+		t.Errorf("Unexpected signature for Point: %q", got)
+	}
+	if point.Line != 5 || point.EndLine != 8 {
+		t.Errorf("Expected Point to span lines 5-8, got %d-%d", point.Line, point.EndLine)
+	}
+
+	var fields []string
+	for _, field := range point.Children {
+		fields = append(fields, field.Name)
+	}
+	if strings.Join(fields, "; ") != "X, Y; label" {
+		t.Fatalf("Expected fields \"X, Y\" and label, got %q", fields)
+	}
+	if point.Children[1].IsPublic {
+		t.Error("Expected unexported field to not be public")
+	}
+
+	distance := symbols[1]
+	if distance.Type != "method" || distance.Name != "Distance" {
+		t.Errorf("Unexpected symbol for Distance: %+v", distance)
+	}
+	if got := distance.Signature.String(); got != "func (p Point) Distance(q Point) float64" {
+		t.Errorf("Unexpected signature for Distance: %q", got)
+	}
+
+	if helper := symbols[2]; helper.IsPublic || !helper.Documentation.IsZero() {
+		t.Errorf("Expected helper to be private and undocumented: %+v", helper)
+	}
+}
+
+func TestPythonSymbolsUseDocstrings(t *testing.T) {
+	code := []byte(`class Greeter:
+    """Says hello."""
+
+    def greet(self, name):
+        """Greet someone by name."""
+        return "hi " + name
+
+    def _secret(self):
+        pass
+`)
+
+	tree := parseSource(t, sitter.NewLanguage(python.Language()), code)
+	symbols := ExtractPythonSymbols(tree.RootNode(), code)
+
+	if len(symbols) != 1 || symbols[0].Name != "Greeter" {
+		t.Fatalf("Expected a single Greeter class, got %+v", symbols)
+	}
+
+	greeter := symbols[0]
+	if got := greeter.Documentation.String(); got != "Says hello." {
+		t.Errorf("Unexpected class docstring: %q", got)
+	}
+	if len(greeter.Children) != 2 {
+		t.Fatalf("Expected 2 methods, got %+v", greeter.Children)
+	}
+	if greet := greeter.Children[0]; greet.Type != "method" || greet.Documentation.String() != "Greet someone by name." {
+		t.Errorf("Unexpected symbol for greet: %+v", greet)
+	}
+	if greeter.Children[1].IsPublic {
+		t.Error("Expected _secret to not be public")
+	}
+}
+
+func TestTextReferencesSource(t *testing.T) {
+	code := []byte(`package main
+
+func Run(
+	name string,
+) error {
+	return nil
+}
+`)
+
+	tree := parseSource(t, sitter.NewLanguage(golang.Language()), code)
+	symbols := ExtractGoSymbols(tree.RootNode(), code)
+	if len(symbols) != 1 {
+		t.Fatalf("Expected 1 symbol, got %d", len(symbols))
+	}
+
+	signature := symbols[0].Signature
+	raw := signature.Bytes()
+	if &raw[0] != &code[signature.Start()] {
+		t.Error("Expected signature bytes to share memory with the source")
+	}
+	if got := signature.String(); got != "func Run( name string, ) error" {
+		t.Errorf("Expected whitespace to be collapsed, got %q", got)
+	}
+	if !strings.HasSuffix(string(symbols[0].Source.Bytes()), "return nil\n}") {
+		t.Errorf("Expected source to cover the whole declaration, got %q", symbols[0].Source.Bytes())
+	}
+}
+
+func TestSymbolJSON(t *testing.T) {
+	symbol := Symbol{
+		Type:      "function",
+		Name:      "Run",
+		Signature: NewText("func Run() error"),
+		Line:      3,
+		Column:    1,
+		EndLine:   5,
+		EndColumn: 2,
+		IsPublic:  true,
+	}
+
+	data, err := json.Marshal(symbol)
+	if err != nil {
+		t.Fatalf("Failed to marshal symbol: %v", err)
+	}
+
+	want := `{"type":"function","name":"Run","signature":"func Run() error","line":3,"column":1,"endLine":5,"endColumn":2,"isPublic":true}`
+	if string(data) != want {
+		t.Errorf("Unexpected JSON:\n got %s\nwant %s", data, want)
+	}
+
+	var decoded Symbol
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal symbol: %v", err)
+	}
+	if decoded.Signature.String() != "func Run() error" {
+		t.Errorf("Expected signature to round-trip, got %q", decoded.Signature.String())
+	}
+}
@@ -1,7 +1,6 @@
 package languages
 
 import (
-	"fmt"
 	"strings"
 
 	sitter "github.com/tree-sitter/go-tree-sitter"
@@ -10,11 +9,12 @@ import (
 // ExtractTSOutline extracts TypeScript outline directly from the code
 func ExtractTSOutline(root *sitter.Node, content []byte) string {
 	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
 
 	// Function to process a node and its children
 	var processNode func(node *sitter.Node, indentLevel int)
 	processNode = func(node *sitter.Node, indentLevel int) {
-		indent := strings.Repeat(" ", indentLevel*2)
+		indent := spaceIndent(indentLevel)
 
 		// Process based on node type
 		switch node.Kind() {
@@ -28,7 +28,7 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 		case "import_statement":
 			// Handle import statements
 			importText := getNodeText(node, content)
-			result.WriteString(fmt.Sprintf("%s\n", importText))
+			writeStrings(&result, importText, "\n")
 
 		case "function_declaration", "generator_function_declaration":
 			// For TypeScript functions
@@ -52,18 +52,15 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 				// Get documentation comment if present
 				doc := findDocComment(node, content, "typescript")
-				if doc != "" {
-					docLines := strings.Split(doc, "\n")
-					for _, line := range docLines {
-						result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-					}
-				}
+				writeDocComment(&result, indent, doc)
 
 				// Write function declaration
 				lineNum := getNodeLineNumber(node)
-				result.WriteString(fmt.Sprintf("%sfunction %s%s%s { // line %d\n", indent, name, paramText, returnText, lineNum))
-				result.WriteString(fmt.Sprintf("%s  // ...\n", indent))
-				result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+				writeStrings(&result, indent, "function ", name, paramText, returnText, " { // line ")
+				writeUint(&result, lineNum)
+				result.WriteString("\n")
+				writeStrings(&result, indent, "  // ...\n")
+				writeStrings(&result, indent, "}\n\n")
 			}
 
 		case "method_definition":
@@ -106,18 +103,15 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 				// Get documentation comment if present
 				doc := findDocComment(node, content, "typescript")
-				if doc != "" {
-					docLines := strings.Split(doc, "\n")
-					for _, line := range docLines {
-						result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-					}
-				}
+				writeDocComment(&result, indent, doc)
 
 				// Write method definition
 				lineNum := getNodeLineNumber(node)
-				result.WriteString(fmt.Sprintf("%s%s%s%s%s { // line %d\n", indent, prefix, name, paramText, returnText, lineNum))
-				result.WriteString(fmt.Sprintf("%s  // ...\n", indent))
-				result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+				writeStrings(&result, indent, prefix, name, paramText, returnText, " { // line ")
+				writeUint(&result, lineNum)
+				result.WriteString("\n")
+				writeStrings(&result, indent, "  // ...\n")
+				writeStrings(&result, indent, "}\n\n")
 			}
 
 		case "class_declaration":
@@ -138,16 +132,13 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 				// Get documentation comment if present
 				doc := findDocComment(node, content, "typescript")
-				if doc != "" {
-					docLines := strings.Split(doc, "\n")
-					for _, line := range docLines {
-						result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-					}
-				}
+				writeDocComment(&result, indent, doc)
 
 				// Write class declaration
 				lineNum := getNodeLineNumber(node)
-				result.WriteString(fmt.Sprintf("%sclass %s%s { // line %d\n", indent, name, heritageText, lineNum))
+				writeStrings(&result, indent, "class ", name, heritageText, " { // line ")
+				writeUint(&result, lineNum)
+				result.WriteString("\n")
 
 				// Process class body
 				bodyNode := node.ChildByFieldName("body")
@@ -158,7 +149,7 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 					}
 				}
 
-				result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+				writeStrings(&result, indent, "}\n\n")
 			}
 
 		case "interface_declaration":
@@ -176,16 +167,13 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 				// Get documentation comment if present
 				doc := findDocComment(node, content, "typescript")
-				if doc != "" {
-					docLines := strings.Split(doc, "\n")
-					for _, line := range docLines {
-						result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-					}
-				}
+				writeDocComment(&result, indent, doc)
 
 				// Write interface declaration
 				lineNum := getNodeLineNumber(node)
-				result.WriteString(fmt.Sprintf("%sinterface %s%s { // line %d\n", indent, name, extendsText, lineNum))
+				writeStrings(&result, indent, "interface ", name, extendsText, " { // line ")
+				writeUint(&result, lineNum)
+				result.WriteString("\n")
 
 				// Process interface body for property and method signatures
 				bodyNode := node.ChildByFieldName("body")
@@ -213,10 +201,10 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 								// Get doc comment
 								propDoc := findDocComment(child, content, "typescript")
 								if propDoc != "" {
-									result.WriteString(fmt.Sprintf("%s  // %s\n", indent, propDoc))
+									writeStrings(&result, indent, "  // ", propDoc, "\n")
 								}
 
-								result.WriteString(fmt.Sprintf("%s  %s%s: %s;\n", indent, propName, optional, propType))
+								writeStrings(&result, indent, "  ", propName, optional, ": ", propType, ";\n")
 							}
 						} else if child.Kind() == "method_signature" {
 							nameNode := child.ChildByFieldName("name")
@@ -239,16 +227,16 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 								// Get doc comment
 								methodDoc := findDocComment(child, content, "typescript")
 								if methodDoc != "" {
-									result.WriteString(fmt.Sprintf("%s  // %s\n", indent, methodDoc))
+									writeStrings(&result, indent, "  // ", methodDoc, "\n")
 								}
 
-								result.WriteString(fmt.Sprintf("%s  %s%s%s;\n", indent, methodName, paramText, returnText))
+								writeStrings(&result, indent, "  ", methodName, paramText, returnText, ";\n")
 							}
 						}
 					}
 				}
 
-				result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+				writeStrings(&result, indent, "}\n\n")
 			}
 
 		case "export_statement":
@@ -287,22 +275,21 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 						// Get documentation comment if present
 						doc := findDocComment(node, content, "typescript")
-						if doc != "" {
-							docLines := strings.Split(doc, "\n")
-							for _, line := range docLines {
-								result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-							}
-						}
+						writeDocComment(&result, indent, doc)
 
 						// Write export function declaration
 						lineNum := getNodeLineNumber(firstChild)
 						if isDefault {
-							result.WriteString(fmt.Sprintf("%sexport default function %s%s%s { // line %d\n", indent, name, paramText, returnText, lineNum))
+							writeStrings(&result, indent, "export default function ", name, paramText, returnText, " { // line ")
+							writeUint(&result, lineNum)
+							result.WriteString("\n")
 						} else {
-							result.WriteString(fmt.Sprintf("%sexport function %s%s%s { // line %d\n", indent, name, paramText, returnText, lineNum))
+							writeStrings(&result, indent, "export function ", name, paramText, returnText, " { // line ")
+							writeUint(&result, lineNum)
+							result.WriteString("\n")
 						}
-						result.WriteString(fmt.Sprintf("%s  // ...\n", indent))
-						result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+						writeStrings(&result, indent, "  // ...\n")
+						writeStrings(&result, indent, "}\n\n")
 					}
 
 				case "class_declaration":
@@ -322,19 +309,18 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 						// Get documentation comment if present
 						doc := findDocComment(node, content, "typescript")
-						if doc != "" {
-							docLines := strings.Split(doc, "\n")
-							for _, line := range docLines {
-								result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-							}
-						}
+						writeDocComment(&result, indent, doc)
 
 						// Write export class declaration
 						lineNum := getNodeLineNumber(firstChild)
 						if isDefault {
-							result.WriteString(fmt.Sprintf("%sexport default class %s%s { // line %d\n", indent, name, heritageText, lineNum))
+							writeStrings(&result, indent, "export default class ", name, heritageText, " { // line ")
+							writeUint(&result, lineNum)
+							result.WriteString("\n")
 						} else {
-							result.WriteString(fmt.Sprintf("%sexport class %s%s { // line %d\n", indent, name, heritageText, lineNum))
+							writeStrings(&result, indent, "export class ", name, heritageText, " { // line ")
+							writeUint(&result, lineNum)
+							result.WriteString("\n")
 						}
 
 						// Process class body
@@ -346,7 +332,7 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 							}
 						}
 
-						result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+						writeStrings(&result, indent, "}\n\n")
 					}
 
 				case "interface_declaration":
@@ -363,16 +349,13 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 						// Get documentation comment if present
 						doc := findDocComment(node, content, "typescript")
-						if doc != "" {
-							docLines := strings.Split(doc, "\n")
-							for _, line := range docLines {
-								result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-							}
-						}
+						writeDocComment(&result, indent, doc)
 
 						// Write export interface declaration
 						lineNum := getNodeLineNumber(firstChild)
-						result.WriteString(fmt.Sprintf("%sexport interface %s%s { // line %d\n", indent, name, extendsText, lineNum))
+						writeStrings(&result, indent, "export interface ", name, extendsText, " { // line ")
+						writeUint(&result, lineNum)
+						result.WriteString("\n")
 
 						// Process interface body for property and method signatures
 						bodyNode := firstChild.ChildByFieldName("body")
@@ -400,10 +383,10 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 										// Get doc comment
 										propDoc := findDocComment(child, content, "typescript")
 										if propDoc != "" {
-											result.WriteString(fmt.Sprintf("%s  // %s\n", indent, propDoc))
+											writeStrings(&result, indent, "  // ", propDoc, "\n")
 										}
 
-										result.WriteString(fmt.Sprintf("%s  %s%s: %s;\n", indent, propName, optional, propType))
+										writeStrings(&result, indent, "  ", propName, optional, ": ", propType, ";\n")
 									}
 								} else if child.Kind() == "method_signature" {
 									nameNode := child.ChildByFieldName("name")
@@ -426,16 +409,16 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 										// Get doc comment
 										methodDoc := findDocComment(child, content, "typescript")
 										if methodDoc != "" {
-											result.WriteString(fmt.Sprintf("%s  // %s\n", indent, methodDoc))
+											writeStrings(&result, indent, "  // ", methodDoc, "\n")
 										}
 
-										result.WriteString(fmt.Sprintf("%s  %s%s%s;\n", indent, methodName, paramText, returnText))
+										writeStrings(&result, indent, "  ", methodName, paramText, returnText, ";\n")
 									}
 								}
 							}
 						}
 
-						result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+						writeStrings(&result, indent, "}\n\n")
 					}
 
 				case "type_alias_declaration":
@@ -448,15 +431,12 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 						// Get documentation comment if present
 						doc := findDocComment(node, content, "typescript")
-						if doc != "" {
-							docLines := strings.Split(doc, "\n")
-							for _, line := range docLines {
-								result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-							}
-						}
+						writeDocComment(&result, indent, doc)
 
 						lineNum := getNodeLineNumber(firstChild)
-						result.WriteString(fmt.Sprintf("%sexport type %s = %s; // line %d\n\n", indent, name, typeValue, lineNum))
+						writeStrings(&result, indent, "export type ", name, " = ", typeValue, "; // line ")
+						writeUint(&result, lineNum)
+						result.WriteString("\n\n")
 					}
 
 				case "lexical_declaration", "variable_declaration":
@@ -497,22 +477,21 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 									// Get documentation comment if present
 									doc := findDocComment(node, content, "typescript")
-									if doc != "" {
-										docLines := strings.Split(doc, "\n")
-										for _, line := range docLines {
-											result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-										}
-									}
+									writeDocComment(&result, indent, doc)
 
 									// Write export function
 									lineNum := getNodeLineNumber(firstChild)
 									if valueNode.Kind() == "arrow_function" {
-										result.WriteString(fmt.Sprintf("%sexport %s %s = %s%s => { // line %d\n", indent, declType, name, paramText, returnText, lineNum))
+										writeStrings(&result, indent, "export ", declType, " ", name, " = ", paramText, returnText, " => { // line ")
+										writeUint(&result, lineNum)
+										result.WriteString("\n")
 									} else {
-										result.WriteString(fmt.Sprintf("%sexport %s %s = function%s%s { // line %d\n", indent, declType, name, paramText, returnText, lineNum))
+										writeStrings(&result, indent, "export ", declType, " ", name, " = function", paramText, returnText, " { // line ")
+										writeUint(&result, lineNum)
+										result.WriteString("\n")
 									}
-									result.WriteString(fmt.Sprintf("%s  // ...\n", indent))
-									result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+									writeStrings(&result, indent, "  // ...\n")
+									writeStrings(&result, indent, "}\n\n")
 								} else {
 									// Handle other exported variable declarations
 									name := getNodeText(nameNode, content)
@@ -525,7 +504,9 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 										}
 									}
 									lineNum := getNodeLineNumber(firstChild)
-									result.WriteString(fmt.Sprintf("%sexport %s %s; // line %d\n\n", indent, declType, name, lineNum))
+									writeStrings(&result, indent, "export ", declType, " ", name, "; // line ")
+									writeUint(&result, lineNum)
+									result.WriteString("\n\n")
 								}
 							}
 						}
@@ -535,19 +516,25 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 					// Handle export { ... } statements
 					exportText := getNodeText(node, content)
 					lineNum := getNodeLineNumber(node)
-					result.WriteString(fmt.Sprintf("%s%s // line %d\n\n", indent, exportText, lineNum))
+					writeStrings(&result, indent, exportText, " // line ")
+					writeUint(&result, lineNum)
+					result.WriteString("\n\n")
 
 				default:
 					// Handle other export patterns like export * from '...'
 					exportText := getNodeText(node, content)
 					lineNum := getNodeLineNumber(node)
-					result.WriteString(fmt.Sprintf("%s%s // line %d\n\n", indent, exportText, lineNum))
+					writeStrings(&result, indent, exportText, " // line ")
+					writeUint(&result, lineNum)
+					result.WriteString("\n\n")
 				}
 			} else {
 				// Fallback for other export patterns
 				exportText := getNodeText(node, content)
 				lineNum := getNodeLineNumber(node)
-				result.WriteString(fmt.Sprintf("%s%s // line %d\n\n", indent, exportText, lineNum))
+				writeStrings(&result, indent, exportText, " // line ")
+				writeUint(&result, lineNum)
+				result.WriteString("\n\n")
 			}
 
 		case "lexical_declaration", "variable_declaration":
@@ -588,29 +575,28 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 							// Get documentation comment if present
 							doc := findDocComment(node, content, "typescript")
-							if doc != "" {
-								docLines := strings.Split(doc, "\n")
-								for _, line := range docLines {
-									result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-								}
-							}
+							writeDocComment(&result, indent, doc)
 
 							// Write function
 							lineNum := getNodeLineNumber(node)
 							if valueNode.Kind() == "arrow_function" {
-								result.WriteString(fmt.Sprintf("%s%s %s = %s%s => { // line %d\n", indent, declType, name, paramText, returnText, lineNum))
+								writeStrings(&result, indent, declType, " ", name, " = ", paramText, returnText, " => { // line ")
+								writeUint(&result, lineNum)
+								result.WriteString("\n")
 							} else {
-								result.WriteString(fmt.Sprintf("%s%s %s = function%s%s { // line %d\n", indent, declType, name, paramText, returnText, lineNum))
+								writeStrings(&result, indent, declType, " ", name, " = function", paramText, returnText, " { // line ")
+								writeUint(&result, lineNum)
+								result.WriteString("\n")
 							}
-							result.WriteString(fmt.Sprintf("%s  // ...\n", indent))
-							result.WriteString(fmt.Sprintf("%s}\n\n", indent))
+							writeStrings(&result, indent, "  // ...\n")
+							writeStrings(&result, indent, "}\n\n")
 						} else if valueNode.Kind() == "call_expression" {
 							// Check if this is a require() call
 							functionNode := valueNode.ChildByFieldName("function")
 							if functionNode != nil && getNodeText(functionNode, content) == "require" {
 								// This is a require statement, include it in the outline
 								requireText := getNodeText(node, content)
-								result.WriteString(fmt.Sprintf("%s\n", requireText))
+								writeStrings(&result, requireText, "\n")
 							}
 						}
 					}
@@ -628,15 +614,12 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 
 				// Get documentation comment if present
 				doc := findDocComment(node, content, "typescript")
-				if doc != "" {
-					docLines := strings.Split(doc, "\n")
-					for _, line := range docLines {
-						result.WriteString(fmt.Sprintf("%s// %s\n", indent, strings.TrimSpace(line)))
-					}
-				}
+				writeDocComment(&result, indent, doc)
 
 				lineNum := getNodeLineNumber(node)
-				result.WriteString(fmt.Sprintf("%stype %s = %s; // line %d\n\n", indent, name, typeValue, lineNum))
+				writeStrings(&result, indent, "type ", name, " = ", typeValue, "; // line ")
+				writeUint(&result, lineNum)
+				result.WriteString("\n\n")
 			}
 		}
 	}
@@ -644,3 +627,53 @@ func ExtractTSOutline(root *sitter.Node, content []byte) string {
 	processNode(root, 0)
 	return result.String()
 }
+
+var tsSymbols = &symbolSpec{
+	rules: mergeSymbolRules(jsSymbolRules, map[string]symbolRule{
+		"abstract_class_declaration": {kind: "class", body: "body", members: membersIn("body")},
+		"interface_declaration":      {kind: "interface", body: "body", members: membersIn("body")},
+		"type_alias_declaration":     {kind: "type"},
+		"enum_declaration":           {kind: "enum", body: "body", members: membersIn("body")},
+		"internal_module":            {kind: "namespace", body: "body", members: membersIn("body")},
+		"module":                     {kind: "namespace", body: "body", members: membersIn("body")},
+		"function_signature":         {kind: "function"},
+		"public_field_definition":    {kind: "field", signatureEnd: tsFieldSignatureEnd},
+		"property_signature":         {kind: "property"},
+		"method_signature":           {kind: "method"},
+		"abstract_method_signature":  {kind: "method"},
+		"property_identifier":        {kind: "enum_member", name: nodeName},
+		"enum_assignment":            {kind: "enum_member"},
+	}),
+	containers: map[string]bool{
+		"export_statement":     true,
+		"lexical_declaration":  true,
+		"variable_declaration": true,
+		"ambient_declaration":  true,
+		"expression_statement": true,
+	},
+	wrappers: map[string]bool{
+		"export_statement":     true,
+		"lexical_declaration":  true,
+		"variable_declaration": true,
+		"ambient_declaration":  true,
+		"expression_statement": true,
+	},
+	public:     isJSPublic,
+	complexity: jsComplexity,
+}
+
+// tsFieldSignatureEnd drops class field initializers but keeps their type
+func tsFieldSignatureEnd(node *sitter.Node) uint {
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		return typeNode.EndByte()
+	}
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		return nameNode.EndByte()
+	}
+	return node.EndByte()
+}
+
+// ExtractTSSymbols extracts the declarations of a TypeScript file as symbols
+func ExtractTSSymbols(root *sitter.Node, content []byte) []Symbol {
+	return extractSymbols(root, content, tsSymbols)
+}
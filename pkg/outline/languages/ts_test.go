@@ -1,6 +1,9 @@
+//go:build !outline_nolang_typescript
+
 package languages
 
 import (
+	"strconv"
 	"strings"
 	"testing"
 
@@ -94,3 +97,35 @@ class UserManager implements Manager {
 		t.Error("Expected class with implements to be included")
 	}
 }
+
+func BenchmarkTypeScriptOutlineLargeFile(b *testing.B) {
+	var code strings.Builder
+	code.WriteString("import { Injectable } from './di';\n\n")
+	for i := 0; i < 200; i++ {
+		n := strconv.Itoa(i)
+		code.WriteString("/** Model number " + n + " */\n")
+		code.WriteString("export interface Model" + n + " {\n  id: number;\n  name?: string;\n  load(id: number): Promise<void>;\n}\n\n")
+		code.WriteString("export class Service" + n + " extends Base implements Model" + n + " {\n")
+		for j := 0; j < 10; j++ {
+			code.WriteString("  /** Handles request */\n  handle" + strconv.Itoa(j) + "(input: string, count: number): string {\n    return input;\n  }\n")
+		}
+		code.WriteString("}\n\n")
+		code.WriteString("export const helper" + n + " = (x: number): number => x * 2;\n\n")
+	}
+	content := []byte(code.String())
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+	if err := parser.SetLanguage(sitter.NewLanguage(typescript.LanguageTypescript())); err != nil {
+		b.Fatalf("Error setting language: %v", err)
+	}
+	tree := parser.Parse(content, nil)
+	defer tree.Close()
+	root := tree.RootNode()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ExtractTSOutline(root, content)
+	}
+}
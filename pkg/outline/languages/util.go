@@ -1,6 +1,7 @@
 package languages
 
 import (
+	"strconv"
 	"strings"
 
 	sitter "github.com/tree-sitter/go-tree-sitter"
@@ -16,6 +17,68 @@ func getNodeLineNumber(node *sitter.Node) uint {
 	return node.StartPosition().Row + 1
 }
 
+const (
+	tabs   = "\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t"
+	spaces = "                                "
+)
+
+// tabIndent returns the indentation for a nesting level in tab-indented
+// outlines, without allocating for all but absurdly deep nesting
+func tabIndent(level int) string {
+	if level <= len(tabs) {
+		return tabs[:level]
+	}
+	return strings.Repeat("\t", level)
+}
+
+// spaceIndent is tabIndent for outlines indented by two spaces per level
+func spaceIndent(level int) string {
+	if level*2 <= len(spaces) {
+		return spaces[:level*2]
+	}
+	return strings.Repeat("  ", level)
+}
+
+// writeStrings appends each part to result in order
+func writeStrings(result *strings.Builder, parts ...string) {
+	for _, part := range parts {
+		result.WriteString(part)
+	}
+}
+
+// writeUint appends the decimal form of n to result
+func writeUint(result *strings.Builder, n uint) {
+	var buf [20]byte
+	result.Write(strconv.AppendUint(buf[:0], uint64(n), 10))
+}
+
+// writeDocComment appends each line of doc as a // comment at indent
+func writeDocComment(result *strings.Builder, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	for {
+		line, rest, more := strings.Cut(doc, "\n")
+		writeStrings(result, indent, "// ", strings.TrimSpace(line), "\n")
+		if !more {
+			return
+		}
+		doc = rest
+	}
+}
+
+// outlineSizeHint estimates the size of an outline from its source, so that
+// builders can be grown once up front
+func outlineSizeHint(content []byte) int {
+	return len(content)/4 + 64
+}
+
+// collapseWhitespace joins multi-line source text into a single line with
+// runs of whitespace reduced to one space
+func collapseWhitespace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
 // findDocComment finds and aggregates documentation comments preceding a node
 func findDocComment(node *sitter.Node, content []byte, language string) string {
 	if node.Parent() == nil {
@@ -0,0 +1,230 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// zigContainers maps the kinds of container expressions to the kind of the
+// symbol a declaration assigning one defines, as in const Point = struct {}
+var zigContainers = map[string]string{
+	"struct_declaration":    "struct",
+	"enum_declaration":      "enum",
+	"union_declaration":     "union",
+	"opaque_declaration":    "type",
+	"error_set_declaration": "enum",
+}
+
+var zigSymbols = &symbolSpec{
+	rules: map[string]symbolRule{
+		"function_declaration": {kind: "function", body: "body"},
+		"variable_declaration": {
+			kindOf:       zigVariableKind,
+			name:         zigVariableName,
+			members:      zigContainer,
+			signatureEnd: zigVariableSignatureEnd,
+		},
+		"container_field": {kindOf: zigFieldKind},
+		"test_declaration": {
+			kind:         "function",
+			name:         zigTestName,
+			signatureEnd: zigBlockStart,
+		},
+		"comptime_declaration": {
+			kind:         "comptime",
+			name:         literal("comptime"),
+			signatureEnd: zigBlockStart,
+		},
+	},
+	public:     isZigPublic,
+	complexity: zigComplexity,
+}
+
+var zigComplexity = &complexitySpec{
+	branches: kinds("if_statement", "if_expression", "for_statement", "for_expression", "while_statement",
+		"while_expression", "switch_case", "catch_expression"),
+	operators: kinds("and", "or", "orelse"),
+}
+
+// ExtractZigSymbols extracts the declarations of a Zig file as symbols
+func ExtractZigSymbols(root *sitter.Node, content []byte) []Symbol {
+	return extractSymbols(root, content, zigSymbols)
+}
+
+// zigValue returns the value a variable declaration is initialized with
+func zigValue(node *sitter.Node) *sitter.Node {
+	for i := node.NamedChildCount(); i > 0; i-- {
+		child := node.NamedChild(i - 1)
+		if !strings.Contains(child.Kind(), "comment") {
+			if child.Kind() == "identifier" && i == 1 {
+				return nil
+			}
+			return child
+		}
+	}
+	return nil
+}
+
+// zigContainer returns the struct, enum, union or opaque type a declaration
+// defines, whose fields and declarations are its members
+func zigContainer(node *sitter.Node) *sitter.Node {
+	if value := zigValue(node); value != nil && zigContainers[value.Kind()] != "" {
+		return value
+	}
+	return nil
+}
+
+// zigIsImport reports whether a declaration only imports a file or package,
+// as in const std = @import("std")
+func zigIsImport(node *sitter.Node, content []byte) bool {
+	value := zigValue(node)
+	return value != nil && value.Kind() == "builtin_function" && strings.HasPrefix(getNodeText(value, content), "@import(")
+}
+
+func zigVariableKind(node *sitter.Node, content []byte) string {
+	if zigIsImport(node, content) {
+		return ""
+	}
+	if container := zigContainer(node); container != nil {
+		return zigContainers[container.Kind()]
+	}
+	for i := uint(0); i < node.ChildCount(); i++ {
+		if node.Child(i).Kind() == "var" {
+			return "variable"
+		}
+	}
+	return "constant"
+}
+
+func zigVariableName(node *sitter.Node, content []byte) string {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if child := node.NamedChild(i); child.Kind() == "identifier" {
+			return getNodeText(child, content)
+		}
+	}
+	return ""
+}
+
+// zigVariableSignatureEnd stops the signature of a container before its
+// members, and that of other declarations before the semicolon
+func zigVariableSignatureEnd(node *sitter.Node) uint {
+	if container := zigContainer(node); container != nil {
+		for i := uint(0); i < container.ChildCount(); i++ {
+			if child := container.Child(i); child.Kind() == "{" {
+				return child.StartByte()
+			}
+		}
+	}
+	if n := node.ChildCount(); n > 0 && node.Child(n-1).Kind() == ";" {
+		return node.Child(n - 1).StartByte()
+	}
+	return node.EndByte()
+}
+
+// zigBlockStart stops the signature of a test or comptime block before its
+// block
+func zigBlockStart(node *sitter.Node) uint {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		if child := node.NamedChild(i); child.Kind() == "block" {
+			return child.StartByte()
+		}
+	}
+	return node.EndByte()
+}
+
+// zigFieldKind makes the fields of an enum its members
+func zigFieldKind(node *sitter.Node, content []byte) string {
+	if parent := node.Parent(); parent != nil && parent.Kind() == "enum_declaration" {
+		return "enum_member"
+	}
+	return "field"
+}
+
+// zigTestName names a test by its description, or by the declaration it
+// tests, as in test "parses input" and test parse
+func zigTestName(node *sitter.Node, content []byte) string {
+	for i := uint(0); i < node.NamedChildCount(); i++ {
+		switch child := node.NamedChild(i); child.Kind() {
+		case "string":
+			return strings.Trim(getNodeText(child, content), `"`)
+		case "identifier":
+			return getNodeText(child, content)
+		}
+	}
+	return "test"
+}
+
+// isZigPublic reports whether a declaration is marked pub. Fields are
+// visible wherever their container is, while tests and comptime blocks are
+// never part of the API.
+func isZigPublic(node *sitter.Node, name string, content []byte) bool {
+	switch node.Kind() {
+	case "container_field":
+		return true
+	case "test_declaration", "comptime_declaration":
+		return false
+	}
+	return node.ChildCount() > 0 && node.Child(0).Kind() == "pub"
+}
+
+// ExtractZigOutline renders the imports and declarations of a Zig file as
+// pseudo-source, with bodies elided
+func ExtractZigOutline(root *sitter.Node, content []byte) string {
+	var result strings.Builder
+	result.Grow(outlineSizeHint(content))
+
+	imports := false
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		child := root.NamedChild(i)
+		if child.Kind() == "variable_declaration" && zigIsImport(child, content) {
+			writeStrings(&result, collapseWhitespace(getNodeText(child, content)), "\n")
+			imports = true
+		}
+	}
+	if imports {
+		result.WriteString("\n")
+	}
+
+	writeZigSymbols(&result, ExtractZigSymbols(root, content), 0)
+	return result.String()
+}
+
+func writeZigSymbols(result *strings.Builder, symbols []Symbol, depth int) {
+	indent := strings.Repeat("    ", depth)
+	for _, symbol := range symbols {
+		isContainer := symbol.Type == "struct" || symbol.Type == "enum" || symbol.Type == "union"
+
+		// Containers are set apart from the declarations before them
+		if depth == 0 && isContainer && result.Len() > 0 && !strings.HasSuffix(result.String(), "\n\n") {
+			result.WriteString("\n")
+		}
+		if doc := symbol.Documentation.String(); doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				writeStrings(result, strings.TrimRight(indent+"/// "+line, " "), "\n")
+			}
+		}
+
+		signature := symbol.Signature.String()
+		switch {
+		case isContainer:
+			writeStrings(result, indent, signature, " { // line ")
+			writeUint(result, uint(symbol.Line))
+			result.WriteString("\n")
+			writeZigSymbols(result, symbol.Children, depth+1)
+			writeStrings(result, indent, "};\n")
+			if depth == 0 {
+				result.WriteString("\n")
+			}
+			continue
+		case symbol.Type == "field" || symbol.Type == "enum_member":
+			writeStrings(result, indent, signature, ", // line ")
+		case symbol.Complexity > 0 || symbol.Type == "comptime" || strings.HasPrefix(signature, "test"):
+			writeStrings(result, indent, signature, " { ... } // line ")
+		default:
+			writeStrings(result, indent, strings.TrimSuffix(signature, ";"), "; // line ")
+		}
+		writeUint(result, uint(symbol.Line))
+		result.WriteString("\n")
+	}
+}
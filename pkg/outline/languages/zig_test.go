@@ -0,0 +1,116 @@
+//go:build !outline_nolang_zig
+
+package languages
+
+import (
+	"strings"
+	"testing"
+
+	zig "github.com/tree-sitter-grammars/tree-sitter-zig/bindings/go"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+const zigCode = `const std = @import("std");
+
+/// Maximum size
+pub const max_size: usize = 1024;
+var counter: u32 = 0;
+
+/// A point in space
+pub const Point = struct {
+    x: f32,
+    y: f32 = 0,
+
+    /// Creates a point
+    pub fn init(x: f32, y: f32) Point {
+        return .{ .x = x, .y = y };
+    }
+
+    fn norm(self: Point) f32 {
+        if (self.x > 0 and self.y > 0) return 1;
+        return 0;
+    }
+};
+
+pub const Color = enum(u8) {
+    red,
+    green,
+};
+
+const Value = union(enum) {
+    int: i64,
+    float: f64,
+};
+
+comptime {
+    std.debug.assert(max_size > 0);
+}
+
+pub extern "c" fn printf(format: [*:0]const u8, ...) c_int;
+
+test "point init" {
+    const p = Point.init(1, 2);
+    try std.testing.expect(p.x == 1);
+}
+`
+
+func TestZigOutline(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(zig.Language()), []byte(zigCode))
+	result := ExtractZigOutline(tree.RootNode(), []byte(zigCode))
+
+	for _, want := range []string{
+		"const std = @import(\"std\");\n",
+		"/// Maximum size\npub const max_size: usize = 1024; // line 4\n",
+		"var counter: u32 = 0; // line 5\n",
+		"/// A point in space\npub const Point = struct { // line 8\n",
+		"    x: f32, // line 9\n",
+		"    /// Creates a point\n    pub fn init(x: f32, y: f32) Point { ... } // line 13\n",
+		"};\n",
+		"pub const Color = enum(u8) { // line 23\n    red, // line 24\n",
+		"const Value = union(enum) { // line 28\n",
+		"comptime { ... } // line 33\n",
+		"pub extern \"c\" fn printf(format: [*:0]const u8, ...) c_int; // line 37\n",
+		"test \"point init\" { ... } // line 39\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestZigSymbols(t *testing.T) {
+	tree := parseSource(t, sitter.NewLanguage(zig.Language()), []byte(zigCode))
+	symbols := ExtractZigSymbols(tree.RootNode(), []byte(zigCode))
+
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Type+" "+symbol.Name)
+	}
+	want := "constant max_size, variable counter, struct Point, enum Color, union Value, comptime comptime, function printf, function point init"
+	if got := strings.Join(names, ", "); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	if !symbols[0].IsPublic || symbols[1].IsPublic {
+		t.Error("Expected only pub declarations to be public")
+	}
+
+	point := symbols[2]
+	var members []string
+	for _, member := range point.Children {
+		members = append(members, member.Type+" "+member.Name)
+	}
+	want = "field x, field y, method init, method norm"
+	if got := strings.Join(members, ", "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if norm := point.Children[3]; norm.IsPublic || norm.Complexity != 3 {
+		t.Errorf("Expected norm to be private with complexity 3, got %v and %d", norm.IsPublic, norm.Complexity)
+	}
+	if kind := symbols[3].Children[0].Type; kind != "enum_member" {
+		t.Errorf("Expected enum fields to be enum members, got %s", kind)
+	}
+	if symbols[7].IsPublic {
+		t.Error("Expected test blocks to be private")
+	}
+}
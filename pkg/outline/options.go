@@ -0,0 +1,181 @@
+package outline
+
+import (
+	"fmt"
+	"time"
+)
+
+// Options limits how much work a single extraction may do
+type Options struct {
+	// MaxBytes is the largest file that is parsed in full. Zero disables
+	// truncation.
+	MaxBytes int
+
+	// TruncateBytes is how much of a file larger than MaxBytes is parsed. The
+	// cut is moved back to the last line break so that the declarations
+	// before it stay intact.
+	TruncateBytes int
+
+	// Timeout bounds the wall-clock time spent parsing. Zero means no limit.
+	Timeout time.Duration
+
+	// MaxMemory bounds how far the resident set size may grow while parsing,
+	// in bytes. It is measured for the whole process, so concurrent
+	// extractions share it. Zero means no limit.
+	MaxMemory uint64
+
+	// Detail selects how much of each symbol the outline shows
+	Detail Detail
+
+	// Summarize collapses long runs of near-identical members, such as
+	// generated getters and setters, enum cases or test functions, into a
+	// comment giving their number and first names
+	Summarize bool
+
+	// Redact replaces the values of string and number literals, such as
+	// constant values and default parameters, with a placeholder, so that
+	// outlines of configuration-heavy files can be shared without leaking
+	// tokens or endpoints
+	Redact bool
+
+	// NoDocs leaves the doc comments and docstrings of symbols out of
+	// outlines, for languages with a symbol tree
+	NoDocs bool
+
+	// PublicOnly keeps only the exported symbols and members. Outlines are
+	// then rendered from the symbol tree, as with Kinds.
+	PublicOnly bool
+
+	// Kinds keeps only the symbols of these kinds, such as "function" or
+	// "class", and the symbols enclosing them. Outlines are then rendered
+	// from the symbol tree, one line per symbol, for every language but
+	// HTML, whose outlines ignore it.
+	Kinds []string
+
+	// StartLine and EndLine keep only the symbols whose declaration spans
+	// lines in that range, with their enclosing symbols, for outlines of one
+	// region of a large file. Outlines are then rendered from the symbol
+	// tree, as with Kinds. Zero leaves that end of the range open.
+	StartLine int
+	EndLine   int
+
+	// Sort reorders the symbols of outlines, and their members, by name or
+	// kind rather than keeping them in declaration order. Outlines are then
+	// rendered from the symbol tree, as with Kinds.
+	Sort SortOrder
+
+	// Positions selects how declarations are annotated with the lines they
+	// span
+	Positions Positions
+
+	// MaxTokens is the estimated number of tokens outlines must fit in, for
+	// language model consumers. Documentation, then private symbols and
+	// fields, then nested members are left out until they do, and outlines
+	// still too long are cut. Zero means no limit.
+	MaxTokens int
+
+	// MaxDepth limits outlines to symbols nested this many levels deep, 1
+	// being the top-level declarations alone. Outlines are then rendered
+	// from the symbol tree, as with Kinds. Zero means no limit.
+	MaxDepth int
+
+	// HeaderLanguage is the language .h headers are outlined as, c, cpp or
+	// objc, instead of the one their content suggests. See HeaderLanguage.
+	HeaderLanguage string
+
+	// Languages overrides some of these options for the files of the
+	// languages it lists, by name
+	Languages map[string]LanguageOptions
+}
+
+// LanguageOptions are the options that can be set for the files of one
+// language. Fields left nil keep the value of the options they are layered
+// on, so that limits and filters set for a call still apply.
+type LanguageOptions struct {
+	Detail    *Detail
+	NoDocs    *bool
+	Summarize *bool
+	Redact    *bool
+}
+
+// DefaultOptions returns the options that keep bundled and minified
+// artifacts from dominating the runtime and memory of an extraction. Each
+// call returns a new value, which callers are free to change.
+func DefaultOptions() Options {
+	return Options{
+		MaxBytes:      1 << 20,
+		TruncateBytes: 256 << 10,
+	}
+}
+
+// forLanguage returns the options that apply to the files of a language,
+// with the settings of the language layered on o
+func (o Options) forLanguage(language string) Options {
+	override, ok := o.Languages[language]
+	if !ok {
+		return o
+	}
+	if override.Detail != nil {
+		o.Detail = *override.Detail
+	}
+	if override.NoDocs != nil {
+		o.NoDocs = *override.NoDocs
+	}
+	if override.Summarize != nil {
+		o.Summarize = *override.Summarize
+	}
+	if override.Redact != nil {
+		o.Redact = *override.Redact
+	}
+	return o
+}
+
+// fromSymbolTree reports whether outlines leave symbols out or move them,
+// which only outlines rendered from the symbol tree can do
+func (o Options) fromSymbolTree() bool {
+	return o.PublicOnly || len(o.Kinds) > 0 || o.StartLine > 0 || o.EndLine > 0 || o.Sort != SortLine || o.MaxDepth > 0
+}
+
+// truncation records that only a prefix of a file was outlined
+type truncation struct {
+	parsed int
+	total  int
+	reason string
+}
+
+func (t *truncation) String() string {
+	if t.reason != "" {
+		return fmt.Sprintf("\n... outline truncated: parsed the first %s of %s (%s)\n", formatBytes(t.parsed), formatBytes(t.total), t.reason)
+	}
+	return fmt.Sprintf("\n... outline truncated: parsed the first %s of %s\n", formatBytes(t.parsed), formatBytes(t.total))
+}
+
+// truncate returns the part of content that should be parsed, and a non-nil
+// truncation when that is less than the whole file
+func (o Options) truncate(content []byte) ([]byte, *truncation) {
+	if o.MaxBytes <= 0 || len(content) <= o.MaxBytes {
+		return content, nil
+	}
+
+	limit := o.TruncateBytes
+	if limit <= 0 || limit > o.MaxBytes {
+		limit = o.MaxBytes
+	}
+
+	// Minified files may have no line break at all, in which case the cut
+	// stays where it is and the last declaration is left incomplete
+	parsed := cutAtLineBreak(content, limit)
+
+	return parsed, &truncation{parsed: len(parsed), total: len(content)}
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d bytes", n)
+	}
+}
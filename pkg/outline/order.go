@@ -0,0 +1,69 @@
+package outline
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SortOrder selects the order outlines list symbols in, each symbol's
+// members being sorted the same way under it
+type SortOrder int
+
+const (
+	// SortLine keeps symbols in the order they are declared in
+	SortLine SortOrder = iota
+	// SortName orders symbols alphabetically by name, ignoring case
+	SortName
+	// SortKind groups symbols by kind, in the order of their kind names,
+	// keeping the declaration order within each group
+	SortKind
+)
+
+var sortNames = map[SortOrder]string{
+	SortLine: "line",
+	SortName: "name",
+	SortKind: "kind",
+}
+
+// SortNames lists the names ParseSort accepts
+func SortNames() []string {
+	return []string{"line", "name", "kind"}
+}
+
+// ParseSort returns the sort order with the given name
+func ParseSort(name string) (SortOrder, error) {
+	for order, orderName := range sortNames {
+		if strings.EqualFold(name, orderName) {
+			return order, nil
+		}
+	}
+	return SortLine, fmt.Errorf("unknown sort order %q (expected %s)", name, strings.Join(SortNames(), ", "))
+}
+
+func (s SortOrder) String() string {
+	if name, ok := sortNames[s]; ok {
+		return name
+	}
+	return "SortOrder(" + strconv.Itoa(int(s)) + ")"
+}
+
+// sortSymbols returns the symbols, and their members, in the given order.
+// Symbols that compare equal stay in declaration order.
+func sortSymbols(symbols []SymbolInfo, order SortOrder) []SymbolInfo {
+	if order == SortLine {
+		return symbols
+	}
+	sorted := slices.Clone(symbols)
+	for i := range sorted {
+		sorted[i].Children = sortSymbols(sorted[i].Children, order)
+	}
+	slices.SortStableFunc(sorted, func(a, b SymbolInfo) int {
+		if order == SortKind {
+			return strings.Compare(a.Type, b.Type)
+		}
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return sorted
+}
@@ -0,0 +1,55 @@
+package outline
+
+import "testing"
+
+func TestParseSort(t *testing.T) {
+	for _, name := range SortNames() {
+		order, err := ParseSort(name)
+		if err != nil {
+			t.Fatalf("ParseSort(%q) failed: %v", name, err)
+		}
+		if order.String() != name {
+			t.Errorf("Expected %q to round-trip, got %q", name, order.String())
+		}
+	}
+	if _, err := ParseSort("size"); err == nil {
+		t.Error("Expected an error for an unknown sort order")
+	}
+}
+
+func TestSortSymbols(t *testing.T) {
+	symbols := []SymbolInfo{
+		{Name: "zeta", Type: "function"},
+		{Name: "Shape", Type: "class", Children: []SymbolInfo{{Name: "width", Type: "field"}, {Name: "Area", Type: "method"}}},
+		{Name: "alpha", Type: "function"},
+	}
+
+	sorted := sortSymbols(symbols, SortName)
+	if sorted[0].Name != "alpha" || sorted[1].Name != "Shape" || sorted[2].Name != "zeta" {
+		t.Errorf("Expected alpha, Shape, zeta, got %+v", sorted)
+	}
+	if sorted[1].Children[0].Name != "Area" {
+		t.Errorf("Expected the members to be sorted too, got %+v", sorted[1].Children)
+	}
+	if symbols[0].Name != "zeta" || symbols[1].Children[0].Name != "width" {
+		t.Error("Expected the original symbols to be left alone")
+	}
+
+	sorted = sortSymbols(symbols, SortKind)
+	if sorted[0].Name != "Shape" || sorted[1].Name != "zeta" || sorted[2].Name != "alpha" {
+		t.Errorf("Expected the class, then the functions in declaration order, got %+v", sorted)
+	}
+}
+
+func TestExtractOutlineSort(t *testing.T) {
+	requireLanguages(t, "go")
+
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Sort: SortName})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	expected := "func (g *Greeter) Greet() string // line 10\ntype Greeter struct // line 4\n  count int // line 6\n  Name string // line 5\nfunc helper() // line 14\n"
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
@@ -1,97 +1,293 @@
 package outline
 
 import (
+	"bytes"
 	"fmt"
-	swift "github.com/alex-pinkus/tree-sitter-swift/bindings/go"
+	"regexp"
+
 	sitter "github.com/tree-sitter/go-tree-sitter"
-	c "github.com/tree-sitter/tree-sitter-c/bindings/go"
-	cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
-	golang "github.com/tree-sitter/tree-sitter-go/bindings/go"
-	java "github.com/tree-sitter/tree-sitter-java/bindings/go"
-	javascript "github.com/tree-sitter/tree-sitter-javascript/bindings/go"
-	python "github.com/tree-sitter/tree-sitter-python/bindings/go"
-	typescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
 
 	"github.com/sourceradar/outline/pkg/outline/languages"
 )
 
-// SymbolInfo represents information about a code symbol (for internal use)
-type SymbolInfo struct {
-	Type          string       `json:"type"`
-	Name          string       `json:"name"`
-	Signature     string       `json:"signature,omitempty"`
-	Documentation string       `json:"documentation,omitempty"`
-	Line          int          `json:"line"`
-	Column        int          `json:"column"`
-	EndLine       int          `json:"endLine"`
-	EndColumn     int          `json:"endColumn"`
-	IsPublic      bool         `json:"isPublic"`
-	Children      []SymbolInfo `json:"children,omitempty"`
+// SymbolInfo is a declaration found in a source file, with its members as
+// children. Its signature and documentation reference the content it was
+// extracted from, so that content must not be modified while they are used.
+type SymbolInfo = languages.Symbol
+
+// Text is a lazily materialized range of source content
+type Text = languages.Text
+
+// receiverPattern extracts the type a Go method is declared on
+var receiverPattern = regexp.MustCompile(`^func\s*\(\s*(?:\w+\s+)?\*?\s*(\w+)`)
+
+// MethodReceiver returns the type a Go method is declared on, since Go
+// methods are declared outside their type and extracted as top-level
+// symbols. It returns "" for any other symbol.
+func MethodReceiver(symbol SymbolInfo) string {
+	if symbol.Type != "method" {
+		return ""
+	}
+	match := receiverPattern.FindStringSubmatch(symbol.Signature.String())
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// NameSpan returns the byte offsets in content of the name of a symbol in
+// its declaration, or of the first line of the declaration when the name
+// isn't spelled in it
+func NameSpan(content []byte, symbol SymbolInfo) (start, end int) {
+	start = byteOffset(content, symbol.Line, symbol.Column)
+	end = max(byteOffset(content, symbol.EndLine, symbol.EndColumn), start)
+
+	declaration := content[start:end]
+	if symbol.Name != "" {
+		if at := indexWord(declaration, symbol.Name); at >= 0 {
+			return start + at, start + at + len(symbol.Name)
+		}
+	}
+	if newline := bytes.IndexByte(declaration, '\n'); newline >= 0 {
+		return start, start + newline
+	}
+	return start, end
+}
+
+// indexWord returns the offset of the first occurrence of word in s that
+// isn't part of a longer identifier, or -1
+func indexWord(s []byte, word string) int {
+	for offset := 0; offset < len(s); {
+		at := bytes.Index(s[offset:], []byte(word))
+		if at < 0 {
+			return -1
+		}
+		at += offset
+		end := at + len(word)
+		if (at == 0 || !isWordByte(s[at-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return at
+		}
+		offset = at + 1
+	}
+	return -1
+}
+
+// isWordByte reports whether b can be part of an identifier
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// byteOffset returns the offset in content of a 1-based line and byte column
+func byteOffset(content []byte, line, column int) int {
+	offset := 0
+	for ; line > 1; line-- {
+		newline := bytes.IndexByte(content[offset:], '\n')
+		if newline < 0 {
+			return len(content)
+		}
+		offset += newline + 1
+	}
+	if line < 1 {
+		return 0
+	}
+	return min(offset+max(column-1, 0), len(content))
 }
 
 // ExtractOutline analyzes the syntax tree to generate a compact outline
 func ExtractOutline(content []byte, language string) (string, error) {
-	// Parse content
-	parser, err := createParserForLanguage(language)
+	return ExtractOutlineWithOptions(content, language, DefaultOptions())
+}
+
+// ExtractOutlineWithOptions is ExtractOutline with explicit limits
+func ExtractOutlineWithOptions(content []byte, language string, opts Options) (string, error) {
+	opts = opts.forLanguage(language)
+	if opts.MaxTokens > 0 {
+		result, _, err := fitTokens(content, language, opts)
+		return result, err
+	}
+	return outlineWithOptions(content, language, opts)
+}
+
+// outlineWithOptions outlines content within the limits of opts, whatever
+// the length of the outline
+func outlineWithOptions(content []byte, language string, opts Options) (string, error) {
+	total := len(content)
+	content, truncated := opts.truncate(content)
+
+	// HTML documents are outlined through the code they embed
+	if language == "html" {
+		result, err := extractHTMLOutline(content, opts)
+		if err != nil {
+			return "", err
+		}
+		result = applyPositions(result, opts.Positions, nil)
+		if truncated == nil {
+			return result, nil
+		}
+		return result + truncated.String(), nil
+	}
+
+	// Languages without a grammar are outlined from their source
+	if text, ok := lookupTextLanguage(language); ok {
+		result := renderText(content, language, text, opts)
+		if truncated != nil {
+			result += truncated.String()
+		}
+		return result, nil
+	}
 
+	if opts.NoDocs {
+		content = stripDocumentation(content, language, opts)
+	}
+	tree, parsed, reason, err := parseContent(content, language, opts)
 	if err != nil {
-		return "", fmt.Errorf("error creating parser: %v", err)
+		return "", err
 	}
+	defer tree.Close()
+
+	var symbols []SymbolInfo
+	if support, ok := lookupLanguage(language); ok && opts.Positions == PositionsRange {
+		symbols = support.symbols(tree.RootNode(), parsed)
+	}
+	result, err := renderOutline(tree.RootNode(), parsed, language, symbols, opts)
+	if err != nil {
+		return "", err
+	}
+
+	if reason != "" {
+		truncated = &truncation{parsed: len(parsed), total: total, reason: reason}
+	}
+	if truncated != nil {
+		result += truncated.String()
+	}
+	return result, nil
+}
+
+// renderOutline renders a parsed tree as opts asks for, with the positions
+// of opts. The ranges of PositionsRange are those of symbols.
+func renderOutline(root *sitter.Node, parsed []byte, language string, symbols []SymbolInfo, opts Options) (string, error) {
+	result, err := renderTree(root, parsed, language, opts)
+	if err != nil {
+		return "", err
+	}
+	if opts.Positions != PositionsLine {
+		var ends map[int]int
+		if opts.Positions == PositionsRange {
+			ends = symbolEnds(symbols, make(map[int]int))
+		}
+		result = applyPositions(result, opts.Positions, ends)
+	}
+	return result, nil
+}
+
+// ExtractSymbols returns the declarations in content as a symbol tree. The
+// same limits as ExtractOutline apply, so very large files only yield the
+// symbols of the part that was parsed.
+func ExtractSymbols(content []byte, language string) ([]SymbolInfo, error) {
+	return ExtractSymbolsWithOptions(content, language, DefaultOptions())
+}
+
+// ExtractSymbolsWithOptions is ExtractSymbols with explicit limits
+func ExtractSymbolsWithOptions(content []byte, language string, opts Options) ([]SymbolInfo, error) {
+	opts = opts.forLanguage(language)
+	var symbols []SymbolInfo
+	var err error
+	if text, ok := lookupTextLanguage(language); ok {
+		content, _ = opts.truncate(content)
+		symbols = text.symbols(content)
+	} else {
+		err = withSymbols(content, language, opts, func(_ *sitter.Node, _ []byte, extracted []SymbolInfo) {
+			symbols = extracted
+		})
+	}
+	symbols = filterSymbols(symbols, opts)
+	if opts.NoDocs {
+		clearDocumentation(symbols)
+	}
+	return symbols, err
+}
+
+// withSymbols parses content within the limits of opts and passes the tree
+// and the symbols found in it to fn. The tree is closed once fn returns.
+func withSymbols(content []byte, language string, opts Options, fn func(root *sitter.Node, parsed []byte, symbols []SymbolInfo)) error {
+	support, ok := lookupLanguage(language)
+	if !ok {
+		if _, ok := lookupTextLanguage(language); ok {
+			return fmt.Errorf("%s files have symbols but no syntax tree", language)
+		}
+		if IsSupported(language) {
+			return fmt.Errorf("%s files have an outline but no symbol tree", language)
+		}
+		return fmt.Errorf("unsupported language: %s", language)
+	}
+
+	content, _ = opts.truncate(content)
+	tree, parsed, _, err := parseContent(content, language, opts)
+	if err != nil {
+		return err
+	}
+	defer tree.Close()
 
-	tree := parser.Parse(content, nil)
 	root := tree.RootNode()
+	fn(root, parsed, support.symbols(root, parsed))
+	return nil
+}
+
+// parseContent parses content with a pooled parser within the budget of
+// opts, returning the tree and the part of content it covers
+func parseContent(content []byte, language string, opts Options) (*sitter.Tree, []byte, string, error) {
+	parser, err := acquireParser(language)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("error creating parser: %v", err)
+	}
+	defer releaseParser(language, parser)
+
+	support, _ := lookupLanguage(language)
+	return parseSource(parser, support, content, nil, opts)
+}
+
+// parseSource is parseWithBudget for the grammar of support, which parses
+// the preprocessed copy of content for the languages that have one. The part
+// of content the tree covers is still returned from content itself.
+func parseSource(parser *sitter.Parser, support languageSupport, content []byte, oldTree *sitter.Tree, opts Options) (*sitter.Tree, []byte, string, error) {
+	if support.preprocess == nil {
+		return parseWithBudget(parser, content, oldTree, opts)
+	}
+	tree, parsed, reason, err := parseWithBudget(parser, support.preprocess(content), oldTree, opts)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return tree, content[:len(parsed)], reason, nil
+}
 
-	switch language {
-	case "go":
-		return languages.ExtractGoOutline(root, content), nil
-	case "java":
-		return languages.ExtractJavaOutline(root, content), nil
-	case "javascript":
-		return languages.ExtractJSOutline(root, content), nil
-	case "swift":
-		return languages.ExtractSwiftOutline(root, content), nil
-	case "typescript":
-		return languages.ExtractTSOutline(root, content), nil
-	case "python":
-		return languages.ExtractPythonOutline(root, content), nil
-	case "c":
-		return languages.ExtractCOutline(root, content), nil
-	case "cpp":
-		return languages.ExtractCppOutline(root, content), nil
-	default:
+// extractOutline outlines content in full, without any limits, rendered as
+// opts asks for
+func extractOutline(content []byte, language string, opts Options) (string, error) {
+	unlimited := opts
+	unlimited.MaxBytes, unlimited.TruncateBytes = 0, 0
+	unlimited.Timeout, unlimited.MaxMemory = 0, 0
+	unlimited.MaxTokens = 0
+	return ExtractOutlineWithOptions(content, language, unlimited)
+}
+
+// outlineTree renders the outline of an already parsed syntax tree
+func outlineTree(root *sitter.Node, content []byte, language string) (string, error) {
+	support, ok := lookupLanguage(language)
+	if !ok {
 		return "", fmt.Errorf("unsupported language: %s", language)
 	}
+	return support.outline(root, content), nil
 }
 
 func createParserForLanguage(language string) (*sitter.Parser, error) {
-	var err error
-	parser := sitter.NewParser()
-
-	switch language {
-	case "go":
-		err = parser.SetLanguage(sitter.NewLanguage(golang.Language()))
-	case "java":
-		err = parser.SetLanguage(sitter.NewLanguage(java.Language()))
-	case "javascript":
-		err = parser.SetLanguage(sitter.NewLanguage(javascript.Language()))
-	case "swift":
-		err = parser.SetLanguage(sitter.NewLanguage(swift.Language()))
-	case "typescript":
-		err = parser.SetLanguage(sitter.NewLanguage(typescript.LanguageTypescript()))
-	case "tsx":
-		err = parser.SetLanguage(sitter.NewLanguage(typescript.LanguageTSX()))
-	case "python":
-		language = "python"
-		err = parser.SetLanguage(sitter.NewLanguage(python.Language()))
-	case "c":
-		err = parser.SetLanguage(sitter.NewLanguage(c.Language()))
-	case "cpp":
-		err = parser.SetLanguage(sitter.NewLanguage(cpp.Language()))
-	default:
+	support, ok := lookupLanguage(language)
+	if !ok {
 		return nil, fmt.Errorf("unsupported language: %s", language)
 	}
 
-	if err != nil {
+	parser := sitter.NewParser()
+	if err := parser.SetLanguage(support.grammar()); err != nil {
+		parser.Close()
 		return nil, fmt.Errorf("error setting language parser: %v", err)
 	}
 
@@ -0,0 +1,412 @@
+package outline
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const sampleGo = `package sample
+
+// Greeter says hello
+type Greeter struct {
+	Name string
+}
+
+// Greet returns a greeting
+func (g *Greeter) Greet() string {
+	return "hello " + g.Name
+}
+`
+
+func TestExtractOutlineConcurrentParserReuse(t *testing.T) {
+	requireLanguages(t, "go")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := ExtractOutline([]byte(sampleGo), "go")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !strings.Contains(result, "func (g *Greeter) Greet() string") {
+				t.Errorf("Unexpected outline:\n%s", result)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestExtractOutlineUnsupportedLanguage(t *testing.T) {
+	if _, err := ExtractOutline([]byte("x"), "cobol"); err == nil {
+		t.Error("Expected error for unsupported language")
+	}
+}
+
+func TestExtractOutlineTruncatesLargeFiles(t *testing.T) {
+	requireLanguages(t, "go")
+
+	var content strings.Builder
+	content.WriteString("package big\n\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&content, "func F%d() {}\n\n", i)
+	}
+
+	opts := Options{MaxBytes: 1024, TruncateBytes: 512}
+	result, err := ExtractOutlineWithOptions([]byte(content.String()), "go", opts)
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions() error: %v", err)
+	}
+
+	if !strings.Contains(result, "func F0()") {
+		t.Errorf("Expected leading declarations to be outlined:\n%s", result)
+	}
+	if strings.Contains(result, "func F199()") {
+		t.Errorf("Expected trailing declarations to be cut:\n%s", result)
+	}
+	if !strings.Contains(result, "outline truncated: parsed the first") {
+		t.Errorf("Expected truncation marker:\n%s", result)
+	}
+
+	small, err := ExtractOutlineWithOptions([]byte(sampleGo), "go", opts)
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions() error: %v", err)
+	}
+	if strings.Contains(small, "truncated") {
+		t.Errorf("Did not expect small file to be truncated:\n%s", small)
+	}
+}
+
+func TestOptionsTruncateMinified(t *testing.T) {
+	content := []byte(strings.Repeat("var a=1;", 100))
+	opts := Options{MaxBytes: 100, TruncateBytes: 50}
+
+	cut, truncated := opts.truncate(content)
+	if len(cut) != 50 {
+		t.Errorf("Expected a hard cut at 50 bytes without line breaks, got %d", len(cut))
+	}
+	if truncated == nil || truncated.total != len(content) {
+		t.Errorf("Expected truncation of %d bytes, got %+v", len(content), truncated)
+	}
+}
+
+func TestExtractOutlineTimeoutBudget(t *testing.T) {
+	requireLanguages(t, "go")
+
+	var content strings.Builder
+	content.WriteString("package big\n\n")
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&content, "func F%d(a, b int) int { if a > b { return a }; return b }\n", i)
+	}
+
+	opts := Options{Timeout: time.Nanosecond}
+	result, err := ExtractOutlineWithOptions([]byte(content.String()), "go", opts)
+	if err != nil {
+		if !errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("Expected ErrBudgetExceeded, got %v", err)
+		}
+		return
+	}
+	if !strings.Contains(result, "timeout of 1ns exceeded") {
+		t.Errorf("Expected a partial outline marked with the timeout:\n%s", result[max(0, len(result)-200):])
+	}
+}
+
+func TestCutAtLineBreak(t *testing.T) {
+	content := []byte("one\ntwo\nthree\n")
+
+	if got := string(cutAtLineBreak(content, 10)); got != "one\ntwo\n" {
+		t.Errorf("cutAtLineBreak(10) = %q", got)
+	}
+	if got := string(cutAtLineBreak(content, 100)); got != string(content) {
+		t.Errorf("cutAtLineBreak(100) = %q", got)
+	}
+	if got := string(cutAtLineBreak(content, 2)); got != "on" {
+		t.Errorf("cutAtLineBreak(2) = %q", got)
+	}
+}
+
+// requireLanguages skips a test needing languages that this build leaves
+// out, as builds with outline_nolang_ tags do
+func requireLanguages(t *testing.T, languages ...string) {
+	t.Helper()
+	for _, language := range languages {
+		if !IsSupported(language) {
+			t.Skipf("%s is left out of this build", language)
+		}
+	}
+}
+
+func TestSupportedLanguages(t *testing.T) {
+	languages := SupportedLanguages()
+	for _, language := range []string{"apex", "c", "cpp", "crystal", "cuda", "go", "html", "java", "javascript", "matlab", "python", "swift", "tsx", "typescript"} {
+		if IsSupported(language) != slices.Contains(languages, language) {
+			t.Errorf("Expected SupportedLanguages() to include %s when it is supported, got %v", language, languages)
+		}
+	}
+	if IsSupported("cobol") {
+		t.Error("Did not expect cobol to be supported")
+	}
+}
+
+// featureSamples hold, for every language, a documented declaration, a
+// member and a private declaration. Languages whose private symbols are only
+// told by convention mark them as such.
+var featureSamples = map[string]struct {
+	code         string
+	conventional bool
+}{
+	"apex":       {code: "/** A shop */\npublic class Shop {\n    private Integer count;\n}\n"},
+	"c":          {code: "struct shop {\n    int count;\n};\n\n/* Helps */\nstatic int helper(void) {\n    return 0;\n}\n"},
+	"clojure":    {code: "(defprotocol Shop\n  \"Sells things.\"\n  (open [this]))\n\n(defn- helper [] nil)\n"},
+	"cpp":        {code: "class Shop {\n    int count;\n};\n\n/* Helps */\nint helper() {\n    return 0;\n}\n"},
+	"crystal":    {code: "# A shop\nclass Shop\n  private def helper\n  end\nend\n"},
+	"cuda":       {code: "class Shop {\n    int count;\n};\n\n/* Helps */\n__global__ void helper() {}\n"},
+	"dart":       {code: "/// A shop\nclass Shop {\n  int _count = 0;\n}\n"},
+	"go":         {code: detailSample},
+	"java":       {code: "/** A shop */\npublic class Shop {\n    private int count;\n}\n"},
+	"javascript": {code: "export class Shop {\n  open() {}\n}\n\n/** Helps */\nfunction helper() {}\n"},
+	"lua":        {code: "local M = {}\n\n--- Opens the shop\nfunction M.open() end\n\nlocal function helper() end\n\nreturn M\n", conventional: true},
+	"matlab":     {code: "classdef Shop\n    % SHOP Sells things.\n    methods (Access = private)\n        function helper(obj)\n        end\n    end\nend\n"},
+	"objc":       {code: "/// A shop\n@interface Shop : NSObject {\n    int _count;\n}\n@end\n"},
+	"python":     {code: "class Shop:\n    \"\"\"Sells things.\"\"\"\n\n    def _helper(self):\n        pass\n", conventional: true},
+	"swift":      {code: "/// A shop\nclass Shop {\n    private var count = 0\n}\n"},
+	"tsx":        {code: "export class Shop {\n  open() {}\n}\n\n/** Helps */\nfunction helper() {}\n"},
+	"typescript": {code: "export class Shop {\n  private count = 0;\n}\n\n/** Helps */\nfunction helper() {}\n"},
+	"zig":        {code: "/// A shop\npub const Shop = struct {\n    count: u32,\n\n    fn helper(self: Shop) u32 {\n        return self.count;\n    }\n};\n"},
+}
+
+func TestLanguageFeatures(t *testing.T) {
+	for _, language := range SupportedLanguages() {
+		if language == "html" {
+			continue
+		}
+		sample, ok := featureSamples[language]
+		if !ok {
+			t.Errorf("Expected a sample of %s to check its features against", language)
+			continue
+		}
+		symbols, err := ExtractSymbols([]byte(sample.code), language)
+		if err != nil {
+			t.Fatalf("ExtractSymbols(%s) failed: %v", language, err)
+		}
+
+		// The features are what the symbols show
+		var want Features
+		var private bool
+		var walk func(symbols []SymbolInfo)
+		walk = func(symbols []SymbolInfo) {
+			for _, symbol := range symbols {
+				want.Docs = want.Docs || symbol.Documentation.String() != ""
+				want.Nesting = want.Nesting || len(symbol.Children) > 0
+				private = private || !symbol.IsPublic
+				walk(symbol.Children)
+			}
+		}
+		walk(symbols)
+		want.Visibility = private && !sample.conventional
+
+		if features, ok := LanguageFeatures(language); !ok || features != want {
+			t.Errorf("LanguageFeatures(%q) = %+v, %t, while its symbols show %+v", language, features, ok, want)
+		}
+	}
+
+	if features, ok := LanguageFeatures("html"); ok != IsSupported("html") || features != (Features{}) {
+		t.Errorf("Expected no features for html, got %+v", features)
+	}
+	if _, ok := LanguageFeatures("cobol"); ok {
+		t.Error("Did not expect features for cobol")
+	}
+}
+
+func TestHeaderLanguage(t *testing.T) {
+	// Headers only resolve to the languages compiled into this build
+	expected := "c"
+	if IsSupported("cpp") {
+		expected = "cpp"
+	}
+	if language := HeaderLanguage("cpp", Options{}); language != expected {
+		t.Errorf("Expected %s, got %s", expected, language)
+	}
+	if language := HeaderLanguage("c", Options{HeaderLanguage: "objc"}); language != "objc" {
+		t.Errorf("Expected the header language of the options to win, got %s", language)
+	}
+}
+
+func TestExtractSymbols(t *testing.T) {
+	requireLanguages(t, "go")
+
+	symbols, err := ExtractSymbols([]byte(sampleGo), "go")
+	if err != nil {
+		t.Fatalf("ExtractSymbols failed: %v", err)
+	}
+
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Type+" "+symbol.Name)
+	}
+	if !slices.Contains(names, "method Greet") {
+		t.Errorf("Expected method Greet among %v", names)
+	}
+
+	if _, err := ExtractSymbols([]byte("<p></p>"), "html"); err == nil {
+		t.Error("Expected an error for a language without symbol support")
+	}
+}
+
+func TestNameSpan(t *testing.T) {
+	requireLanguages(t, "go")
+
+	content := []byte(sampleGo)
+	symbols, err := ExtractSymbols(content, "go")
+	if err != nil {
+		t.Fatalf("ExtractSymbols failed: %v", err)
+	}
+
+	for _, symbol := range symbols {
+		start, end := NameSpan(content, symbol)
+		if got := string(content[start:end]); got != symbol.Name {
+			t.Errorf("Expected the span of %s to be its name, got %q", symbol.Name, got)
+		}
+	}
+
+	start, end := NameSpan(content, SymbolInfo{Name: "missing", Line: 4, Column: 1, EndLine: 6, EndColumn: 2})
+	if got := string(content[start:end]); got != "type Greeter struct {" {
+		t.Errorf("Expected the first line of a declaration not naming the symbol, got %q", got)
+	}
+}
+
+func TestNameSpanWholeWord(t *testing.T) {
+	content := []byte("var counter, count_, count = 1, 2, 3\n")
+	start, end := NameSpan(content, SymbolInfo{Name: "count", Line: 1, Column: 1, EndLine: 1, EndColumn: 38})
+	if start != 21 || end != 26 {
+		t.Errorf("Expected the span of count alone, got %d-%d (%q)", start, end, content[start:end])
+	}
+}
+
+func TestExtractOutlineTextLanguage(t *testing.T) {
+	requireLanguages(t, "crystal")
+
+	result, err := ExtractOutline([]byte("class Greeter\n  def greet(name : String) : String\n    name\n  end\nend\n"), "crystal")
+	if err != nil {
+		t.Fatalf("ExtractOutline failed: %v", err)
+	}
+	if !strings.Contains(result, "def greet(name : String) : String ... end # line 2") {
+		t.Errorf("Expected the greet method in outline, got:\n%s", result)
+	}
+
+	symbols, err := ExtractSymbols([]byte("class Greeter\nend\n"), "crystal")
+	if err != nil || len(symbols) != 1 || symbols[0].Name != "Greeter" {
+		t.Errorf("Expected the Greeter symbol, got %+v, %v", symbols, err)
+	}
+	if _, err := ExtractCoverage([]byte("class Greeter\nend\n"), "crystal"); err == nil {
+		t.Error("Expected an error for coverage, which needs a syntax tree")
+	}
+}
+
+func TestTextLanguageOptions(t *testing.T) {
+	requireLanguages(t, "crystal")
+
+	content := []byte(`# A greeter.
+class Greeter
+  def greet(name : String) : String
+    name
+  end
+
+  private def helper
+  end
+end
+
+def main
+end
+`)
+	outline := func(opts Options) string {
+		t.Helper()
+		result, err := ExtractOutlineWithOptions(content, "crystal", opts)
+		if err != nil {
+			t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+		}
+		return result
+	}
+
+	if result := outline(Options{Kinds: []string{"function"}}); result != "def main # line 11\n" {
+		t.Errorf("Expected only main, got:\n%s", result)
+	}
+	if result := outline(Options{PublicOnly: true, MaxDepth: 1}); strings.Contains(result, "helper") || !strings.Contains(result, "class Greeter # line 2") {
+		t.Errorf("Expected the public symbols, got:\n%s", result)
+	}
+	if result := outline(Options{Positions: PositionsRange}); !strings.Contains(result, "# lines 2-9") {
+		t.Errorf("Expected the range of Greeter, got:\n%s", result)
+	}
+	if result := outline(Options{NoDocs: true}); strings.Contains(result, "A greeter.") {
+		t.Errorf("Expected the doc comment to be left out, got:\n%s", result)
+	}
+
+	analysis, err := Analyze(content, "crystal", Options{Kinds: []string{"method"}}, AnalysisRequest{Symbols: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(analysis.Symbols) != 1 || len(analysis.Symbols[0].Children) != 2 {
+		t.Errorf("Expected Greeter and its methods, got %+v", analysis.Symbols)
+	}
+}
+
+func TestMATLABOptions(t *testing.T) {
+	requireLanguages(t, "matlab")
+
+	content := []byte(`% TOOLS Helpers.
+function y = twice(x)
+% TWICE Doubles x.
+  y = 2 * x;
+end
+
+function z = half(x)
+  z = x / 2;
+end
+`)
+	result, err := ExtractOutlineWithOptions(content, "matlab", Options{Kinds: []string{"function"}, Sort: SortName, Positions: PositionsRange})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if want := "function z = half(x) % lines 7-9\nfunction y = twice(x) % lines 2-5\n"; result != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, result)
+	}
+
+	// The comment above twice mustn't take the place of its help text
+	result, err = ExtractOutlineWithOptions(content, "matlab", Options{NoDocs: true})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if strings.Contains(result, "TWICE") || strings.Contains(result, "TOOLS") {
+		t.Errorf("Expected no help text, got:\n%s", result)
+	}
+
+	symbols, err := ExtractSymbolsWithOptions(content, "matlab", Options{PublicOnly: true})
+	if err != nil || len(symbols) != 1 || symbols[0].Name != "twice" {
+		t.Errorf("Expected only twice, got %+v, %v", symbols, err)
+	}
+}
+
+func BenchmarkExtractOutlineSmallFile(b *testing.B) {
+	content := []byte(sampleGo)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ExtractOutline(content, "go"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package outline
+
+import (
+	"runtime"
+	"sync"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// parserPool keeps idle parsers for one language. A tree-sitter parser can
+// only parse one document at a time, so concurrent callers each take their
+// own parser and hand it back when they're done.
+type parserPool struct {
+	idle chan *sitter.Parser
+}
+
+var (
+	parserPoolsMu sync.Mutex
+	parserPools   = make(map[string]*parserPool)
+)
+
+func getParserPool(language string) *parserPool {
+	parserPoolsMu.Lock()
+	defer parserPoolsMu.Unlock()
+
+	pool, ok := parserPools[language]
+	if !ok {
+		pool = &parserPool{idle: make(chan *sitter.Parser, runtime.GOMAXPROCS(0))}
+		parserPools[language] = pool
+	}
+	return pool
+}
+
+// acquireParser returns an idle parser for the language, creating one if
+// every pooled parser is in use
+func acquireParser(language string) (*sitter.Parser, error) {
+	select {
+	case parser := <-getParserPool(language).idle:
+		return parser, nil
+	default:
+		return createParserForLanguage(language)
+	}
+}
+
+// releaseParser returns a parser to its pool, closing it if the pool is full
+func releaseParser(language string, parser *sitter.Parser) {
+	parser.Reset()
+
+	select {
+	case getParserPool(language).idle <- parser:
+	default:
+		parser.Close()
+	}
+}
@@ -0,0 +1,89 @@
+package outline
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Positions selects how outlines annotate the line each symbol is declared
+// on
+type Positions int
+
+const (
+	// PositionsLine ends declarations with a comment giving their first
+	// line, as in "// line 12"
+	PositionsLine Positions = iota
+	// PositionsNone leaves the annotations out
+	PositionsNone
+	// PositionsRange gives the first and last line of each symbol, as in
+	// "// lines 12-30"
+	PositionsRange
+)
+
+var positionNames = map[Positions]string{
+	PositionsLine:  "line",
+	PositionsNone:  "none",
+	PositionsRange: "range",
+}
+
+// PositionNames lists the names ParsePositions accepts
+func PositionNames() []string {
+	return []string{"none", "line", "range"}
+}
+
+// ParsePositions returns the position annotations with the given name
+func ParsePositions(name string) (Positions, error) {
+	for positions, positionName := range positionNames {
+		if strings.EqualFold(name, positionName) {
+			return positions, nil
+		}
+	}
+	return PositionsLine, fmt.Errorf("unknown positions %q (expected %s)", name, strings.Join(PositionNames(), ", "))
+}
+
+func (p Positions) String() string {
+	if name, ok := positionNames[p]; ok {
+		return name
+	}
+	return "Positions(" + strconv.Itoa(int(p)) + ")"
+}
+
+// positionMarker matches the line annotations of every language, whatever
+// its comment syntax. They end the line, except in Python where a
+// docstring may follow.
+var positionMarker = regexp.MustCompile(`(?m) (//|#|--|;|%) line (\d+)($| """)`)
+
+// applyPositions rewrites the line annotations of an outline. ends maps the
+// first line of each symbol to its last, and annotations of lines it lacks
+// are left as they are.
+func applyPositions(text string, positions Positions, ends map[int]int) string {
+	switch positions {
+	case PositionsNone:
+		return positionMarker.ReplaceAllString(text, "$3")
+	case PositionsRange:
+		return positionMarker.ReplaceAllStringFunc(text, func(marker string) string {
+			match := positionMarker.FindStringSubmatch(marker)
+			line, _ := strconv.Atoi(match[2])
+			end, ok := ends[line]
+			if !ok || end <= line {
+				return marker
+			}
+			return fmt.Sprintf(" %s lines %d-%d%s", match[1], line, end, match[3])
+		})
+	}
+	return text
+}
+
+// symbolEnds maps the first line of every symbol to its last. When several
+// symbols start on one line, the outermost one is kept.
+func symbolEnds(symbols []SymbolInfo, ends map[int]int) map[int]int {
+	for _, symbol := range symbols {
+		if _, ok := ends[symbol.Line]; !ok {
+			ends[symbol.Line] = symbol.EndLine
+		}
+		symbolEnds(symbol.Children, ends)
+	}
+	return ends
+}
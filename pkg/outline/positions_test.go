@@ -0,0 +1,55 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePositions(t *testing.T) {
+	for _, name := range PositionNames() {
+		positions, err := ParsePositions(name)
+		if err != nil {
+			t.Fatalf("ParsePositions(%q) failed: %v", name, err)
+		}
+		if positions.String() != name {
+			t.Errorf("Expected %q to round-trip, got %q", name, positions.String())
+		}
+	}
+	if _, err := ParsePositions("column"); err == nil {
+		t.Error("Expected an error for unknown positions")
+	}
+}
+
+func TestApplyPositions(t *testing.T) {
+	text := "def greet(name): # line 3 \"\"\"Say hello\"\"\"\n    ...\nlocal function f() -- line 7\nsee line 9\n"
+
+	expected := "def greet(name): \"\"\"Say hello\"\"\"\n    ...\nlocal function f()\nsee line 9\n"
+	if result := applyPositions(text, PositionsNone, nil); result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+
+	expected = "def greet(name): # lines 3-5 \"\"\"Say hello\"\"\"\n    ...\nlocal function f() -- line 7\nsee line 9\n"
+	if result := applyPositions(text, PositionsRange, map[int]int{3: 5, 7: 7}); result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
+
+func TestExtractOutlinePositions(t *testing.T) {
+	requireLanguages(t, "go")
+
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Positions: PositionsNone})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if strings.Contains(result, "line") || !strings.Contains(result, "Greet()") {
+		t.Errorf("Expected the outline without line annotations, got:\n%s", result)
+	}
+
+	result, err = ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Positions: PositionsRange})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if !strings.Contains(result, "// lines 10-12") || !strings.Contains(result, "// line 14") {
+		t.Errorf("Expected line ranges, got:\n%s", result)
+	}
+}
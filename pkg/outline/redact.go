@@ -0,0 +1,132 @@
+package outline
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// redactedMark fills the literals of a redacted copy of the source. Keeping
+// their length leaves the offsets of the syntax tree valid, so the outline
+// is rendered from the copy as usual, and each run of marks is then
+// replaced by the placeholder.
+const redactedMark = '\x00'
+
+// redactedPlaceholder stands for a literal value in a redacted outline
+const redactedPlaceholder = "***"
+
+var redactedRun = regexp.MustCompile("\x00+")
+
+// redactLiterals returns a copy of content in which the value of every
+// string and number literal is replaced by marks. Import paths, include
+// files and literals used as types, such as array lengths, are kept since
+// they are structure rather than data.
+func redactLiterals(root *sitter.Node, content []byte) []byte {
+	redacted := bytes.Clone(content)
+
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		kind := node.Kind()
+		switch {
+		case isStringLiteral(kind):
+			if !keepsLiteral(node) {
+				start, end := stringValue(content, node)
+				mark(redacted, start, end)
+			}
+			// The contents of a string are redacted with it
+			return
+		case isNumberLiteral(kind):
+			if !keepsLiteral(node) {
+				mark(redacted, int(node.StartByte()), int(node.EndByte()))
+			}
+			return
+		}
+		for i := uint(0); i < node.ChildCount(); i++ {
+			walk(node.Child(i))
+		}
+	}
+	walk(root)
+	return redacted
+}
+
+// restoreRedacted replaces the marks left in an outline by placeholders
+func restoreRedacted(outline string) string {
+	if !strings.ContainsRune(outline, redactedMark) {
+		return outline
+	}
+	return redactedRun.ReplaceAllString(outline, redactedPlaceholder)
+}
+
+func mark(content []byte, start, end int) {
+	for i := start; i < end && i < len(content); i++ {
+		content[i] = redactedMark
+	}
+}
+
+func isStringLiteral(kind string) bool {
+	switch kind {
+	case "string", "string_literal", "interpreted_string_literal", "raw_string_literal",
+		"template_string", "concatenated_string", "line_string_literal", "multi_line_string_literal",
+		"text_block", "character_literal", "char_literal", "rune_literal":
+		return true
+	}
+	return false
+}
+
+func isNumberLiteral(kind string) bool {
+	switch kind {
+	case "number", "number_literal", "integer", "float", "int_literal", "float_literal",
+		"imaginary_literal", "integer_literal", "decimal_integer_literal", "hex_integer_literal",
+		"octal_integer_literal", "binary_integer_literal", "decimal_floating_point_literal",
+		"hex_floating_point_literal", "real_literal":
+		return true
+	}
+	return false
+}
+
+// keepsLiteral reports whether a literal names something rather than holding
+// a value: an import path, an included file, a linkage specification or a
+// literal type or array length
+func keepsLiteral(node *sitter.Node) bool {
+	parent := node.Parent()
+	if parent == nil {
+		return false
+	}
+	kind := parent.Kind()
+	for _, structural := range []string{"import", "include", "linkage"} {
+		if strings.Contains(kind, structural) {
+			return true
+		}
+	}
+	return kind == "type" || strings.HasSuffix(kind, "_type") || kind == "array_declarator"
+}
+
+// stringValue returns the range of a string literal between its quotes, so
+// that the redacted string keeps its prefix and delimiters, like r"..." or
+// """...""", and stays readable as a string
+func stringValue(content []byte, node *sitter.Node) (int, int) {
+	start, end := int(node.StartByte()), int(node.EndByte())
+	text := content[start:end]
+
+	open := bytes.IndexAny(text, "\"'`")
+	if open < 0 {
+		return start, end
+	}
+	quote := text[open]
+	n := 1
+	for open+n < len(text) && text[open+n] == quote && n < 3 {
+		n++
+	}
+	// An empty string is two quotes, not an opening triple quote
+	if n == 2 {
+		return start, start
+	}
+
+	closing := len(text)
+	for m := 0; m < n && closing > open+n && text[closing-1] == quote; m++ {
+		closing--
+	}
+	return start + open + n, start + closing
+}
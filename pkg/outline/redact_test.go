@@ -0,0 +1,72 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		language string
+		code     string
+		want     []string
+		leaked   []string
+	}{
+		{
+			language: "go",
+			code:     "package c\n\nimport \"net/http\"\n\nconst Token = \"sk-abc123\"\n\nconst Port = 8080\n\nvar buf [32]byte\n",
+			want:     []string{`import "net/http"`, `Token = "***"`, "Port = ***", "buf [32]byte"},
+			leaked:   []string{"sk-abc123", "8080"},
+		},
+		{
+			language: "python",
+			code:     "def connect(host=\"db.internal\", port: int = 5432, key=r'raw'):\n    pass\n",
+			want:     []string{`host="***"`, "port: int = ***", "key=r'***'"},
+			leaked:   []string{"db.internal", "5432", "raw"},
+		},
+		{
+			language: "c",
+			code:     "#include \"config.h\"\nstatic const char *url = \"http://internal\";\n",
+			want:     []string{`#include "config.h"`, `url = "***"`},
+			leaked:   []string{"internal"},
+		},
+	}
+
+	for _, test := range tests {
+		if !IsSupported(test.language) {
+			continue
+		}
+		for _, detail := range []Detail{DetailCompact, DetailFull} {
+			result, err := ExtractOutlineWithOptions([]byte(test.code), test.language, Options{Detail: detail, Redact: true})
+			if err != nil {
+				t.Fatalf("%s: %v", test.language, err)
+			}
+			// Every level must redact, but only compact outlines show imports
+			for _, want := range test.want {
+				if detail != DetailCompact {
+					break
+				}
+				if !strings.Contains(result, want) {
+					t.Errorf("%s (%s): expected %q in outline, got:\n%s", test.language, detail, want, result)
+				}
+			}
+			for _, leaked := range test.leaked {
+				if strings.Contains(result, leaked) {
+					t.Errorf("%s (%s): expected %q to be redacted, got:\n%s", test.language, detail, leaked, result)
+				}
+			}
+		}
+	}
+}
+
+func TestRedactOff(t *testing.T) {
+	requireLanguages(t, "go")
+
+	result, err := ExtractOutlineWithOptions([]byte("const Token = \"sk-abc123\"\n"), "go", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "sk-abc123") {
+		t.Errorf("Expected literals to be kept without Redact, got:\n%s", result)
+	}
+}
@@ -0,0 +1,36 @@
+package outline
+
+import (
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// Reference is an identifier spelled like a searched name, along with the
+// symbol it is in
+type Reference = languages.Reference
+
+// Identifier is an identifier of a file with the name it spells
+type Identifier = languages.Identifier
+
+// FindReferences lists the identifiers of content spelled name. Unlike a
+// text search, mentions in comments and string literals are not reported,
+// nor are longer identifiers that contain name.
+func FindReferences(content []byte, language, name string) ([]Reference, error) {
+	var references []Reference
+	err := withSymbols(content, language, DefaultOptions(), func(root *sitter.Node, parsed []byte, symbols []SymbolInfo) {
+		references = languages.FindReferences(root, parsed, symbols, name)
+	})
+	return references, err
+}
+
+// FindIdentifiers lists every identifier of content in the order they
+// appear, skipping comments and string literals like FindReferences. The
+// names declared are included, with Declaration set.
+func FindIdentifiers(content []byte, language string) ([]Identifier, error) {
+	var identifiers []Identifier
+	err := withSymbols(content, language, DefaultOptions(), func(root *sitter.Node, parsed []byte, symbols []SymbolInfo) {
+		identifiers = languages.FindIdentifiers(root, parsed, symbols)
+	})
+	return identifiers, err
+}
@@ -0,0 +1,81 @@
+package outline
+
+import "testing"
+
+const referencesSample = `package sample
+
+// Parse reads a config; Parse is not called here
+func Parse(s string) Config {
+	return Config{Name: s}
+}
+
+type Config struct {
+	Name string
+}
+
+func (c Config) Reload() Config {
+	message := "Parse"
+	ParseAll(message)
+	return Parse(c.Name)
+}
+`
+
+func TestFindReferences(t *testing.T) {
+	requireLanguages(t, "go")
+
+	references, err := FindReferences([]byte(referencesSample), "go", "Parse")
+	if err != nil {
+		t.Fatalf("FindReferences failed: %v", err)
+	}
+
+	expected := []Reference{
+		{Line: 4, Column: 6, Symbol: "Parse", Declaration: true, Text: "func Parse(s string) Config {"},
+		{Line: 15, Column: 9, Symbol: "Reload", Text: "return Parse(c.Name)"},
+	}
+	if len(references) != len(expected) {
+		t.Fatalf("Expected %d references, got %d: %+v", len(expected), len(references), references)
+	}
+	for i, want := range expected {
+		if references[i] != want {
+			t.Errorf("Reference %d: expected %+v, got %+v", i, want, references[i])
+		}
+	}
+}
+
+func TestFindReferencesTypeAndField(t *testing.T) {
+	requireLanguages(t, "go")
+
+	references, err := FindReferences([]byte(referencesSample), "go", "Name")
+	if err != nil {
+		t.Fatalf("FindReferences failed: %v", err)
+	}
+
+	// The composite literal key, the field declaration and the selector
+	if len(references) != 3 {
+		t.Fatalf("Expected 3 references, got %d: %+v", len(references), references)
+	}
+	if !references[1].Declaration || references[1].Symbol != "Config.Name" {
+		t.Errorf("Expected the field declaration second, got %+v", references[1])
+	}
+}
+
+func TestFindIdentifiers(t *testing.T) {
+	requireLanguages(t, "go")
+
+	identifiers, err := FindIdentifiers([]byte(referencesSample), "go")
+	if err != nil {
+		t.Fatalf("FindIdentifiers failed: %v", err)
+	}
+
+	counts := map[string]int{}
+	for _, identifier := range identifiers {
+		counts[identifier.Name]++
+	}
+	// The Parse in the comment and the string literal are skipped
+	if counts["Parse"] != 2 || counts["ParseAll"] != 1 || counts["Config"] != 5 {
+		t.Errorf("Unexpected identifier counts: %v", counts)
+	}
+	if first := identifiers[0]; first.Name != "sample" || first.Line != 1 {
+		t.Errorf("Expected the package name first, got %+v", first)
+	}
+}
@@ -0,0 +1,150 @@
+package outline
+
+import (
+	"sort"
+	"sync"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/sourceradar/outline/pkg/outline/languages"
+)
+
+// languageSupport ties a tree-sitter grammar to the extractors that read its
+// trees. Each grammar registers itself from its own file, guarded by an
+// outline_nolang_<name> build tag so embedders can leave it out of the
+// binary.
+type languageSupport struct {
+	grammar func() *sitter.Language
+	outline func(root *sitter.Node, content []byte) string
+	symbols func(root *sitter.Node, content []byte) []languages.Symbol
+
+	// features are what symbols reports for the language
+	features Features
+
+	// preprocess, when set, returns the copy of content the grammar parses,
+	// for languages borrowing the grammar of another with the syntax it
+	// lacks masked. Offsets must be kept, since the extractors read the tree
+	// of the copy against the original content.
+	preprocess func(content []byte) []byte
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]languageSupport)
+
+	// textLanguages have no grammar and are outlined straight from their
+	// source
+	textLanguages = make(map[string]textLanguage)
+)
+
+// textLanguage ties a language without a tree-sitter grammar to the
+// extractors that scan its source. Its symbols support everything but what
+// needs a syntax tree, such as coverage, annotations and references.
+type textLanguage struct {
+	outline func(content []byte) string
+	symbols func(content []byte) []languages.Symbol
+
+	// features are what symbols reports for the language
+	features Features
+}
+
+func registerLanguage(name string, support languageSupport) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[name] = support
+}
+
+func registerTextLanguage(name string, text textLanguage) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	textLanguages[name] = text
+}
+
+func lookupLanguage(name string) (languageSupport, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	support, ok := registry[name]
+	return support, ok
+}
+
+func lookupTextLanguage(name string) (textLanguage, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	text, ok := textLanguages[name]
+	return text, ok
+}
+
+// SupportedLanguages returns the sorted names of the languages compiled into
+// this build. HTML is included whenever JavaScript is, since its outline is
+// built from the embedded scripts.
+func SupportedLanguages() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry)+len(textLanguages)+1)
+	for name := range registry {
+		names = append(names, name)
+	}
+	for name := range textLanguages {
+		names = append(names, name)
+	}
+	if _, ok := registry["javascript"]; ok {
+		names = append(names, "html")
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsSupported reports whether the language is compiled into this build
+func IsSupported(language string) bool {
+	if language == "html" {
+		_, ok := lookupLanguage("javascript")
+		return ok
+	}
+	if _, ok := lookupTextLanguage(language); ok {
+		return true
+	}
+	_, ok := lookupLanguage(language)
+	return ok
+}
+
+// HeaderLanguage returns the language a .h header is outlined as, given the
+// one its content suggests: opts.HeaderLanguage when set, and C when this
+// build leaves the suggested language out
+func HeaderLanguage(suggested string, opts Options) string {
+	if opts.HeaderLanguage != "" {
+		return opts.HeaderLanguage
+	}
+	if !IsSupported(suggested) {
+		return "c"
+	}
+	return suggested
+}
+
+// Features are what the extractor of a language reports besides its outline
+type Features struct {
+	// Docs is set when symbols carry their doc comments or docstrings
+	Docs bool
+	// Nesting is set when members are nested under the symbol declaring them
+	Nesting bool
+	// Visibility is set when public symbols are told from private ones by
+	// rules the language enforces, rather than by naming conventions
+	Visibility bool
+}
+
+// LanguageFeatures returns the features of a language compiled into this
+// build. HTML has no symbol tree and none of them.
+func LanguageFeatures(language string) (Features, bool) {
+	if !IsSupported(language) {
+		return Features{}, false
+	}
+	if text, ok := lookupTextLanguage(language); ok {
+		return text.features, true
+	}
+	support, _ := lookupLanguage(language)
+	return support.features, true
+}
@@ -0,0 +1,27 @@
+package outline
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+)
+
+// residentMemory returns the resident set size of the process in bytes, or
+// zero when it cannot be read
+func residentMemory() uint64 {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+
+	fields := bytes.Fields(statm)
+	if len(fields) < 2 {
+		return 0
+	}
+
+	pages, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize())
+}
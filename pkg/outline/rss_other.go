@@ -0,0 +1,9 @@
+//go:build !linux
+
+package outline
+
+// residentMemory is not implemented on this platform, so memory budgets are
+// not enforced
+func residentMemory() uint64 {
+	return 0
+}
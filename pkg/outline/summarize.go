@@ -0,0 +1,294 @@
+package outline
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// summarizeMin is the fewest similar members collapsed into a summary
+const summarizeMin = 10
+
+// summarizeNames is how many names a summary lists
+const summarizeNames = 3
+
+// elision is a run of similar sibling symbols rendered as a single comment
+type elision struct {
+	label string
+	// members are indexes into the sibling symbols, in source order
+	members []int
+}
+
+// summary returns the comment that stands for the members of e
+func (e elision) summary(symbols []SymbolInfo, comment string) string {
+	names := make([]string, 0, summarizeNames)
+	for _, member := range e.members[:summarizeNames] {
+		names = append(names, symbols[member].Name)
+	}
+	return summaryComment(comment, len(e.members), e.label, names)
+}
+
+func summaryComment(comment string, count int, label string, names []string) string {
+	return fmt.Sprintf("%s %d %s elided (%s, ...)", comment, count, label, strings.Join(names, ", "))
+}
+
+// findElisions groups siblings that are alike, such as getters, setters,
+// enum cases, test functions or fields of the same shape. Only consecutive
+// siblings are grouped, but kinds may interleave, so that getters and
+// setters declared in pairs are still collapsed into one entry each.
+func findElisions(symbols []SymbolInfo) []elision {
+	var result []elision
+	flush := func(run []int) {
+		groups := make(map[string]*elision)
+		var order []string
+		for _, i := range run {
+			key, label := similarityKey(symbols[i])
+			group, ok := groups[key]
+			if !ok {
+				group = &elision{label: label}
+				groups[key] = group
+				order = append(order, key)
+			}
+			group.members = append(group.members, i)
+		}
+		for _, key := range order {
+			if len(groups[key].members) >= summarizeMin {
+				result = append(result, *groups[key])
+			}
+		}
+	}
+
+	var run []int
+	for i, symbol := range symbols {
+		if key, _ := similarityKey(symbol); key != "" {
+			run = append(run, i)
+			continue
+		}
+		flush(run)
+		run = nil
+	}
+	flush(run)
+	return result
+}
+
+var (
+	literalPattern    = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\b\d[\w.]*`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// similarityKey returns the group a symbol is summarized with, and how such
+// symbols are called in the summary. Symbols that are never summarized get
+// an empty key.
+func similarityKey(symbol SymbolInfo) (string, string) {
+	switch symbol.Type {
+	case "enum_member":
+		return "enum_member", "enum cases"
+
+	case "function", "method":
+		// Go methods of different types are not alike
+		scope := MethodReceiver(symbol) + "."
+		switch {
+		case isTestName(symbol.Name):
+			return scope + "test", "tests"
+		case hasWordPrefix(symbol.Name, "get"), hasWordPrefix(symbol.Name, "is"), hasWordPrefix(symbol.Name, "has"):
+			return scope + "getter", "getters"
+		case hasWordPrefix(symbol.Name, "set"):
+			return scope + "setter", "setters"
+		}
+
+	case "field", "property", "constant", "variable":
+		// Declarations alike but for their name and value, such as the
+		// constants of a generated table
+		shape := symbol.Signature.String()
+		if shape == "" {
+			shape = symbol.Type
+		}
+		shape = strings.Replace(shape, symbol.Name, "_", 1)
+		shape = literalPattern.ReplaceAllString(shape, "0")
+		shape = whitespacePattern.ReplaceAllString(shape, " ")
+		plural := symbol.Type + "s"
+		if symbol.Type == "property" {
+			plural = "properties"
+		}
+		return symbol.Type + ":" + shape, "similar " + plural
+	}
+	return "", ""
+}
+
+// isTestName reports whether name is that of a Go, pytest, JUnit or XCTest
+// test function
+func isTestName(name string) bool {
+	return hasWordPrefix(name, "Test") || hasWordPrefix(name, "test")
+}
+
+// hasWordPrefix reports whether name starts with the word prefix, followed
+// by an upper case letter or an underscore, as in getFoo or get_foo
+func hasWordPrefix(name, prefix string) bool {
+	if len(name) <= len(prefix) || !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	next := rune(name[len(prefix)])
+	return next == '_' || unicode.IsUpper(next)
+}
+
+// summarizeOutline collapses the runs of similar symbols in a compact
+// outline. Each language renders its compact outline its own way, so the
+// entries of the symbols are located in the text by the line comment they
+// end with, or for fields and enum cases that have none, by their name. An
+// entry spans the doc comment above it and the lines indented below it.
+func summarizeOutline(text string, symbols []SymbolInfo, comment string) string {
+	type group struct {
+		label string
+	}
+	var groups []*group
+	memberOf := make(map[*SymbolInfo]*group)
+	var collect func(symbols []SymbolInfo)
+	collect = func(symbols []SymbolInfo) {
+		for _, e := range findElisions(symbols) {
+			g := &group{label: e.label}
+			for _, member := range e.members {
+				memberOf[&symbols[member]] = g
+			}
+			groups = append(groups, g)
+		}
+		for i := range symbols {
+			if memberOf[&symbols[i]] == nil {
+				collect(symbols[i].Children)
+			}
+		}
+	}
+	collect(symbols)
+	if len(groups) == 0 {
+		return text
+	}
+
+	// A line shared by a member and a symbol that stays, as in a one-line
+	// enum, is never removed
+	byLine := make(map[int]*SymbolInfo)
+	kept := make(map[int]bool)
+	byName := make(map[string][]*SymbolInfo)
+	var index func(symbols []SymbolInfo)
+	index = func(symbols []SymbolInfo) {
+		for i := range symbols {
+			symbol := &symbols[i]
+			if memberOf[symbol] == nil {
+				kept[symbol.Line] = true
+			} else {
+				byLine[symbol.Line] = symbol
+				if isDataMember(symbol.Type) || symbol.Type == "constant" || symbol.Type == "variable" {
+					byName[symbol.Name] = append(byName[symbol.Name], symbol)
+				}
+			}
+			index(symbol.Children)
+		}
+	}
+	index(symbols)
+
+	marker := regexp.MustCompile(regexp.QuoteMeta(comment) + ` line (\d+)`)
+	lines := strings.Split(text, "\n")
+
+	// Find the lines each member spans
+	type entry struct {
+		member     *SymbolInfo
+		start, end int
+	}
+	var entries []entry
+	found := make(map[*SymbolInfo]bool)
+	for i := 0; i < len(lines); i++ {
+		var member *SymbolInfo
+		if match := marker.FindStringSubmatch(lines[i]); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			if !kept[line] {
+				member = byLine[line]
+			}
+		} else {
+			member = memberNamed(strings.TrimSpace(lines[i]), byName)
+		}
+		if member == nil || found[member] {
+			continue
+		}
+		found[member] = true
+
+		indent := indentOf(lines[i])
+		start := i
+		for start > 0 && indentOf(lines[start-1]) == indent && strings.HasPrefix(strings.TrimSpace(lines[start-1]), comment) {
+			start--
+		}
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+			if depth := indentOf(lines[end]); depth < indent || depth == indent && !isCloser(lines[end]) {
+				break
+			}
+			end++
+		}
+		entries = append(entries, entry{member: member, start: start, end: end})
+		i = end - 1
+	}
+
+	// Groups too few of whose members were found are left alone
+	counts := make(map[*group][]*SymbolInfo)
+	for _, e := range entries {
+		g := memberOf[e.member]
+		counts[g] = append(counts[g], e.member)
+	}
+
+	var result []string
+	summarized := make(map[*group]bool)
+	pos := 0
+	for _, e := range entries {
+		g := memberOf[e.member]
+		members := counts[g]
+		if len(members) < summarizeMin {
+			continue
+		}
+		result = append(result, lines[pos:e.start]...)
+		pos = e.end
+		if summarized[g] {
+			// Drop the blank line that separated the entry from the previous
+			// one
+			if pos < len(lines) && strings.TrimSpace(lines[pos]) == "" && len(result) > 0 && strings.TrimSpace(result[len(result)-1]) == "" {
+				pos++
+			}
+			continue
+		}
+		summarized[g] = true
+
+		names := make([]string, 0, summarizeNames)
+		for _, member := range members[:summarizeNames] {
+			names = append(names, member.Name)
+		}
+		indent := lines[e.start][:indentOf(lines[e.start])]
+		result = append(result, indent+summaryComment(comment, len(members), g.label, names))
+	}
+	result = append(result, lines[pos:]...)
+	return strings.Join(result, "\n")
+}
+
+// memberNamed returns the field or constant a compact outline line declares,
+// as in "Red," or "Timeout = 5"
+func memberNamed(line string, byName map[string][]*SymbolInfo) *SymbolInfo {
+	end := strings.IndexFunc(line, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$'
+	})
+	if end < 0 {
+		end = len(line)
+	}
+	candidates := byName[line[:end]]
+	if len(candidates) == 0 {
+		return nil
+	}
+	byName[line[:end]] = candidates[1:]
+	return candidates[0]
+}
+
+// isCloser reports whether line only closes a block opened above it
+func isCloser(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, ")") || strings.HasPrefix(trimmed, "]")
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -0,0 +1,99 @@
+package outline
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// generatedBean declares twelve getter and setter pairs between a field and
+// a method that stay
+func generatedBean() string {
+	var b strings.Builder
+	b.WriteString("public class Bean {\n    private int id;\n")
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&b, "    /** Returns field %d */\n    public String getField%d() { return \"\"; }\n", i, i)
+		fmt.Fprintf(&b, "    public void setField%d(String v) { }\n", i)
+	}
+	b.WriteString("    public void process() { }\n}\n")
+	return b.String()
+}
+
+func TestSummarizeCompact(t *testing.T) {
+	result, err := ExtractOutlineWithOptions([]byte(generatedBean()), "java", Options{Summarize: true})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"private int id; // line 2\n",
+		"\t// 12 getters elided (getField0, getField1, getField2, ...)\n",
+		"\t// 12 setters elided (setField0, setField1, setField2, ...)\n",
+		"public void process()",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in outline, got:\n%s", want, result)
+		}
+	}
+	for _, unwanted := range []string{"getField5", "Returns field"} {
+		if strings.Contains(result, unwanted) {
+			t.Errorf("Expected %q to be elided, got:\n%s", unwanted, result)
+		}
+	}
+}
+
+func TestSummarizeSignatures(t *testing.T) {
+	requireLanguages(t, "go")
+
+	var b strings.Builder
+	b.WriteString("package sample\n\nimport \"testing\"\n\n")
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&b, "func TestCase%d(t *testing.T) {}\n", i)
+	}
+	b.WriteString("func Helper() {}\n")
+
+	result, err := ExtractOutlineWithOptions([]byte(b.String()), "go", Options{Detail: DetailSignatures, Summarize: true})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+
+	expected := "// 10 tests elided (TestCase0, TestCase1, TestCase2, ...)\nfunc Helper() // line 15\n"
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
+
+func TestSummarizeEnumCases(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("enum Color {\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&b, "  C%d,\n", i)
+	}
+	b.WriteString("}\n")
+
+	result, err := ExtractOutlineWithOptions([]byte(b.String()), "java", Options{Summarize: true})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+
+	expected := "enum Color { // line 1\n\t// 30 enum cases elided (C0, C1, C2, ...)\n}\n"
+	if !strings.Contains(result, expected) {
+		t.Errorf("Expected %q in outline, got:\n%s", expected, result)
+	}
+}
+
+func TestSummarizeKeepsShortRuns(t *testing.T) {
+	requireLanguages(t, "go")
+
+	summarized, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Summarize: true})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	plain, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if summarized != plain {
+		t.Errorf("Expected the outline to be unchanged, got:\n%s", summarized)
+	}
+}
@@ -0,0 +1,70 @@
+package outline
+
+import (
+	"fmt"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// SyntaxError is a part of a file the parser could not make sense of. The
+// outline of a file with syntax errors may be missing declarations around
+// them.
+type SyntaxError struct {
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+	// Message describes the error, such as a missing token
+	Message string
+}
+
+// maxSyntaxErrors bounds the errors reported for one file, since a single
+// mistake often makes the rest of it unparseable
+const maxSyntaxErrors = 10
+
+// ExtractSyntaxErrors lists the syntax errors in content, in source order
+func ExtractSyntaxErrors(content []byte, language string) ([]SyntaxError, error) {
+	if _, ok := lookupLanguage(language); !ok {
+		return nil, fmt.Errorf("unsupported language: %s", language)
+	}
+
+	opts := DefaultOptions()
+	content, _ = opts.truncate(content)
+	tree, _, _, err := parseContent(content, language, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Close()
+
+	var errs []SyntaxError
+	collectSyntaxErrors(tree.RootNode(), &errs)
+	return errs, nil
+}
+
+// collectSyntaxErrors records the outermost error and missing nodes below
+// node
+func collectSyntaxErrors(node *sitter.Node, errs *[]SyntaxError) {
+	if len(*errs) >= maxSyntaxErrors || !node.HasError() {
+		return
+	}
+
+	if node.IsError() || node.IsMissing() {
+		start, end := node.StartPosition(), node.EndPosition()
+		message := "syntax error"
+		if node.IsMissing() {
+			message = fmt.Sprintf("missing %q", node.Kind())
+		}
+		*errs = append(*errs, SyntaxError{
+			Line:      int(start.Row) + 1,
+			Column:    int(start.Column) + 1,
+			EndLine:   int(end.Row) + 1,
+			EndColumn: int(end.Column) + 1,
+			Message:   message,
+		})
+		return
+	}
+
+	for i := uint(0); i < node.ChildCount(); i++ {
+		collectSyntaxErrors(node.Child(i), errs)
+	}
+}
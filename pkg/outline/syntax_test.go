@@ -0,0 +1,24 @@
+package outline
+
+import "testing"
+
+func TestExtractSyntaxErrors(t *testing.T) {
+	requireLanguages(t, "go", "python")
+
+	errs, err := ExtractSyntaxErrors([]byte("package main\n\nfunc ok() {}\n"), "go")
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("Expected no syntax errors, got %+v, %v", errs, err)
+	}
+
+	errs, err = ExtractSyntaxErrors([]byte("def broken(:\n    pass\n\ndef fine():\n    pass\n"), "python")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) == 0 || errs[0].Line != 1 || errs[0].Message == "" {
+		t.Errorf("Expected a syntax error on line 1, got %+v", errs)
+	}
+
+	if _, err := ExtractSyntaxErrors(nil, "cobol"); err == nil {
+		t.Error("Expected an error for an unsupported language")
+	}
+}
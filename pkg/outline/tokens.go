@@ -0,0 +1,142 @@
+package outline
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EstimateTokens approximates the number of tokens text takes in the
+// tokenizers of common embedding and language models, at about four bytes
+// per token
+func EstimateTokens(text []byte) int {
+	return (len(text) + 3) / 4
+}
+
+// tokenReductions are the steps taken, in order, to bring an outline within
+// Options.MaxTokens, from the least to the most information lost
+var tokenReductions = []func(opts *Options){
+	// Documentation first, since it is often as long as the declarations
+	func(opts *Options) { opts.NoDocs = true },
+	// Then private symbols and fields, keeping the exported API
+	func(opts *Options) { opts.Detail, opts.PublicOnly = DetailSignatures, true },
+	// Then members nested deeper than their type, and finally the members
+	// of types
+	func(opts *Options) { opts.MaxDepth = shallower(opts.MaxDepth, 2) },
+	func(opts *Options) { opts.MaxDepth = 1 },
+}
+
+// shallower returns the lower of two depth limits, zero being no limit
+func shallower(depth, limit int) int {
+	if depth > 0 && depth < limit {
+		return depth
+	}
+	return limit
+}
+
+// FitOutline is ExtractOutlineWithOptions, also reporting whether the
+// outline had to be reduced or cut to fit opts.MaxTokens
+func FitOutline(content []byte, language string, opts Options) (string, bool, error) {
+	opts = opts.forLanguage(language)
+	if opts.MaxTokens <= 0 {
+		result, err := outlineWithOptions(content, language, opts)
+		return result, false, err
+	}
+	return fitTokens(content, language, opts)
+}
+
+// FitSymbols is ExtractSymbolsWithOptions with the symbols reduced the way
+// outlines are until their JSON is estimated to fit opts.MaxTokens. Symbols
+// still too many after every reduction are cut from the end. It reports
+// whether any were.
+func FitSymbols(content []byte, language string, opts Options) ([]SymbolInfo, bool, error) {
+	opts = opts.forLanguage(language)
+	opts.Languages = nil
+	symbols, err := ExtractSymbolsWithOptions(content, language, opts)
+	if err != nil || opts.MaxTokens <= 0 {
+		return symbols, false, err
+	}
+	if symbolTokens(symbols) <= opts.MaxTokens {
+		return symbols, false, nil
+	}
+	for _, reduce := range tokenReductions {
+		reduce(&opts)
+		if symbols, err = ExtractSymbolsWithOptions(content, language, opts); err != nil {
+			return nil, false, err
+		}
+		if symbolTokens(symbols) <= opts.MaxTokens {
+			return symbols, true, nil
+		}
+	}
+	for len(symbols) > 0 && symbolTokens(symbols) > opts.MaxTokens {
+		symbols = symbols[:len(symbols)-1]
+	}
+	return symbols, true, nil
+}
+
+// symbolTokens estimates the tokens symbols take as JSON
+func symbolTokens(symbols []SymbolInfo) int {
+	data, _ := json.Marshal(symbols)
+	return EstimateTokens(data)
+}
+
+// fitTokens outlines content with less and less detail until the outline
+// is estimated to fit opts.MaxTokens, and reports whether it had to. An
+// outline still too long after every reduction is cut at a line break, with
+// a note saying so.
+func fitTokens(content []byte, language string, opts Options) (string, bool, error) {
+	// The per-language options are already applied
+	opts.Languages = nil
+	result, err := outlineWithOptions(content, language, opts)
+	if err != nil {
+		return "", false, err
+	}
+	if EstimateTokens([]byte(result)) <= opts.MaxTokens {
+		return result, false, nil
+	}
+	for _, reduce := range tokenReductions {
+		reduce(&opts)
+		if result, err = outlineWithOptions(content, language, opts); err != nil {
+			return "", false, err
+		}
+		if EstimateTokens([]byte(result)) <= opts.MaxTokens {
+			return result, true, nil
+		}
+	}
+	return truncateTokens(result, opts.MaxTokens), true, nil
+}
+
+// truncateTokens cuts text at the last line break that leaves room for a
+// note within max tokens. Budgets too small for the note with every line
+// left out get a shorter one, or nothing at all.
+func truncateTokens(text string, max int) string {
+	if EstimateTokens([]byte(text)) <= max {
+		return text
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+	for i := len(lines); i >= 0; i-- {
+		note := fmt.Sprintf("\n... outline truncated: %d more lines left out to fit %d tokens\n", len(lines)-i, max)
+		kept := text[:len(strings.Join(lines[:i], ""))]
+		if EstimateTokens([]byte(kept+note)) <= max {
+			return kept + note
+		}
+	}
+	if note := "... outline truncated\n"; EstimateTokens([]byte(note)) <= max {
+		return note
+	}
+	return ""
+}
+
+// limitDepth drops the symbols nested more than depth levels deep
+func limitDepth(symbols []SymbolInfo, depth int) []SymbolInfo {
+	limited := make([]SymbolInfo, len(symbols))
+	for i, symbol := range symbols {
+		if depth <= 1 {
+			symbol.Children = nil
+		} else {
+			symbol.Children = limitDepth(symbol.Children, depth-1)
+		}
+		limited[i] = symbol
+	}
+	return limited
+}
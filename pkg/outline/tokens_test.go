@@ -0,0 +1,121 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractOutlineMaxTokens(t *testing.T) {
+	requireLanguages(t, "go")
+
+	full, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailFull})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	result, err := ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailFull, MaxTokens: 1000})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if result != full {
+		t.Errorf("Expected an outline within the budget to be left alone, got:\n%s", result)
+	}
+
+	// Dropping the documentation is enough for this budget
+	result, err = ExtractOutlineWithOptions([]byte(detailSample), "go", Options{Detail: DetailFull, MaxTokens: EstimateTokens([]byte(full)) - 5})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if strings.Contains(result, "says hello") || !strings.Contains(result, "count int") {
+		t.Errorf("Expected the docs to be dropped first, got:\n%s", result)
+	}
+
+	result, err = ExtractOutlineWithOptions([]byte(detailSample), "go", Options{MaxTokens: 30})
+	if err != nil {
+		t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+	}
+	if strings.Contains(result, "helper") || !strings.Contains(result, "Greeter") {
+		t.Errorf("Expected the private function to be dropped, got:\n%s", result)
+	}
+	if EstimateTokens([]byte(result)) > 30 {
+		t.Errorf("Expected at most 30 tokens, got %d:\n%s", EstimateTokens([]byte(result)), result)
+	}
+}
+
+func TestTruncateTokens(t *testing.T) {
+	text := strings.Repeat("func Example() // line 1\n", 20)
+	result := truncateTokens(text, 50)
+	if EstimateTokens([]byte(result)) > 50 {
+		t.Errorf("Expected at most 50 tokens, got %d:\n%s", EstimateTokens([]byte(result)), result)
+	}
+	if !strings.HasPrefix(result, "func Example() // line 1\n") || !strings.Contains(result, "more lines left out to fit 50 tokens") {
+		t.Errorf("Expected whole lines followed by a note, got:\n%s", result)
+	}
+
+	// Budgets smaller than the note get a shorter one, or none
+	for max, want := range map[int]string{8: "... outline truncated\n", 3: "", 0: ""} {
+		if result := truncateTokens(text, max); result != want {
+			t.Errorf("Expected %q for a budget of %d tokens, got %q", want, max, result)
+		}
+	}
+}
+
+func TestExtractOutlineMaxTokensBelowNote(t *testing.T) {
+	requireLanguages(t, "java")
+
+	content := []byte("public class Greeter {\n    public String greet(String name) {\n        return name;\n    }\n}\n")
+	for _, max := range []int{1, 5, 10} {
+		result, err := ExtractOutlineWithOptions(content, "java", Options{MaxTokens: max})
+		if err != nil {
+			t.Fatalf("ExtractOutlineWithOptions failed: %v", err)
+		}
+		if tokens := EstimateTokens([]byte(result)); tokens > max {
+			t.Errorf("Expected at most %d tokens, got %d:\n%s", max, tokens, result)
+		}
+	}
+}
+
+func TestLimitDepth(t *testing.T) {
+	symbols := []SymbolInfo{{Name: "Outer", Children: []SymbolInfo{{Name: "Inner", Children: []SymbolInfo{{Name: "Deep"}}}}}}
+	limited := limitDepth(symbols, 2)
+	if len(limited[0].Children) != 1 || len(limited[0].Children[0].Children) != 0 {
+		t.Errorf("Expected two levels, got %+v", limited)
+	}
+	if len(symbols[0].Children[0].Children) != 1 {
+		t.Error("Expected the original symbols to be left alone")
+	}
+}
+
+func TestFitOutlineAndSymbols(t *testing.T) {
+	requireLanguages(t, "go")
+
+	_, reduced, err := FitOutline([]byte(detailSample), "go", Options{MaxTokens: 1000})
+	if err != nil {
+		t.Fatalf("FitOutline failed: %v", err)
+	}
+	if reduced {
+		t.Error("Expected an outline within the budget not to be reduced")
+	}
+	result, reduced, err := FitOutline([]byte(detailSample), "go", Options{MaxTokens: 30})
+	if err != nil {
+		t.Fatalf("FitOutline failed: %v", err)
+	}
+	if !reduced || strings.Contains(result, "helper") {
+		t.Errorf("Expected a reduced outline, got:\n%s", result)
+	}
+
+	symbols, reduced, err := FitSymbols([]byte(detailSample), "go", Options{MaxTokens: 100000})
+	if err != nil {
+		t.Fatalf("FitSymbols failed: %v", err)
+	}
+	if reduced || len(symbols) == 0 {
+		t.Errorf("Expected every symbol within the budget, got %+v", symbols)
+	}
+	budget := symbolTokens(symbols) - 1
+	symbols, reduced, err = FitSymbols([]byte(detailSample), "go", Options{MaxTokens: budget})
+	if err != nil {
+		t.Fatalf("FitSymbols failed: %v", err)
+	}
+	if !reduced || symbolTokens(symbols) > budget {
+		t.Errorf("Expected symbols reduced to %d tokens, got %d", budget, symbolTokens(symbols))
+	}
+}